@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+const (
+	// catalogMatchThreshold is the trigram similarity an exercise name needs
+	// against a catalog name or alias before it is linked automatically.
+	catalogMatchThreshold = 0.6
+	// catalogMachineBonus is added when the exercise was logged on a machine
+	// the catalog entry is linked to.
+	catalogMachineBonus = 0.2
+	catalogBatchSize    = 500
+)
+
+// mapExercisesToCatalog links free-text exercises to the catalog entry their
+// name matches with confidence. Every exercise is looked at once: rows
+// without a confident match are marked checked and stay custom.
+func mapExercisesToCatalog(ctx context.Context, db *sql.DB) (int64, error) {
+	query := `
+		WITH pending AS (
+			SELECT id, machine_id, LOWER(regexp_replace(BTRIM(name), '\s+', ' ', 'g')) AS norm
+			FROM exercises
+			WHERE catalog_id IS NULL AND catalog_checked_at IS NULL
+			ORDER BY created_at
+			LIMIT $1
+		),
+		terms AS (
+			SELECT id AS catalog_id, LOWER(name) AS term FROM exercise_catalog
+			UNION ALL
+			SELECT c.id, LOWER(a) FROM exercise_catalog c, unnest(c.aliases) a
+		),
+		scored AS (
+			SELECT p.id, t.catalog_id,
+				similarity(t.term, p.norm) + CASE WHEN cm.machine_id IS NULL THEN 0 ELSE $3 END AS score
+			FROM pending p
+			CROSS JOIN terms t
+			LEFT JOIN exercise_catalog_machines cm ON cm.catalog_id = t.catalog_id AND cm.machine_id = p.machine_id
+		),
+		matches AS (
+			SELECT DISTINCT ON (id) id, catalog_id
+			FROM scored
+			WHERE score >= $2
+			ORDER BY id, score DESC, catalog_id
+		)
+		UPDATE exercises e
+		SET catalog_id = m.catalog_id,
+		    catalog_checked_at = NOW()
+		FROM pending p
+		LEFT JOIN matches m ON m.id = p.id
+		WHERE e.id = p.id
+	`
+
+	var total int64
+	for {
+		result, err := db.ExecContext(ctx, query, catalogBatchSize, catalogMatchThreshold, catalogMachineBonus)
+		if err != nil {
+			return total, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += affected
+		if affected < catalogBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func runCatalogMapping(ctx context.Context, db *sql.DB) {
+	checked, err := mapExercisesToCatalog(ctx, db)
+	if err != nil {
+		log.Printf("catalog mapping error: %v", err)
+		return
+	}
+	if checked > 0 {
+		log.Printf("catalog mapping checked %d exercises", checked)
+	}
+}
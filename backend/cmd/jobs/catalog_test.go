@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMapExercisesToCatalogRunsUntilDrained(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE exercises e").
+		WithArgs(catalogBatchSize, catalogMatchThreshold, catalogMachineBonus).
+		WillReturnResult(sqlmock.NewResult(0, catalogBatchSize))
+	mock.ExpectExec("UPDATE exercises e").
+		WithArgs(catalogBatchSize, catalogMatchThreshold, catalogMachineBonus).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	checked, err := mapExercisesToCatalog(context.Background(), db)
+	if err != nil {
+		t.Fatalf("mapExercisesToCatalog error: %v", err)
+	}
+	if checked != catalogBatchSize+3 {
+		t.Fatalf("checked = %d, want %d", checked, catalogBatchSize+3)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestMapExercisesToCatalogError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE exercises e").WillReturnError(errors.New("boom"))
+
+	if _, err := mapExercisesToCatalog(context.Background(), db); err == nil {
+		t.Fatalf("expected error")
+	}
+}
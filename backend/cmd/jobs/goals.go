@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"fitonex/backend/internal/analytics"
+	"fitonex/backend/internal/store/goals"
+)
+
+// goalEvaluationBatchSize caps how many users' goals are evaluated per run.
+// Listing goals evaluates them too; this catches users who are not looking.
+const goalEvaluationBatchSize = 500
+
+func runGoalEvaluation(ctx context.Context, db *sql.DB, emitter *analytics.Emitter) {
+	store := goals.New(db)
+	userIDs, err := store.UsersDue(goalEvaluationBatchSize)
+	if err != nil {
+		log.Printf("goal evaluation error: %v", err)
+		return
+	}
+
+	completed := 0
+	now := time.Now().UTC()
+	for _, userID := range userIDs {
+		done, err := store.Evaluate(userID, now)
+		if err != nil {
+			log.Printf("goal evaluation error for user %s: %v", userID, err)
+			continue
+		}
+		for _, goal := range done {
+			emitter.EmitEvent(ctx, goal.UserID, "goal_completed", map[string]any{
+				"goal_id": goal.ID,
+				"type":    goal.Type,
+				"target":  goal.Target,
+			})
+		}
+		completed += len(done)
+	}
+	if completed > 0 {
+		log.Printf("goal evaluation completed %d goals", completed)
+	}
+}
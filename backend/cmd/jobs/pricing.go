@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"log/slog"
+	"os"
 	"time"
 
+	"fitonex/backend/internal/analytics"
 	"fitonex/backend/internal/config"
+	"fitonex/backend/internal/store/catalog"
 
 	_ "github.com/lib/pq"
 )
@@ -29,10 +33,19 @@ func main() {
 		log.Fatalf("failed to ping database: %v", err)
 	}
 
+	emitter := analytics.NewEmitter(cfg.AnalyticsSink, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
 	log.Println("starting pricing cache job")
 	if err := recomputePriceCache(context.Background(), db); err != nil {
 		log.Printf("initial price cache error: %v", err)
 	}
+	if err := catalog.New(db).SyncCurated(); err != nil {
+		log.Printf("catalog sync error: %v", err)
+	}
+	runCatalogMapping(context.Background(), db)
+	runSessionCleanup(db)
+	runTrainingLoadRefresh(db)
+	runGoalEvaluation(context.Background(), db, emitter)
 
 	ticker := time.NewTicker(pricingInterval)
 	defer ticker.Stop()
@@ -42,6 +55,10 @@ func main() {
 		if err := recomputePriceCache(ctx, db); err != nil {
 			log.Printf("price cache error: %v", err)
 		}
+		runCatalogMapping(ctx, db)
+		runSessionCleanup(db)
+		runTrainingLoadRefresh(db)
+		runGoalEvaluation(ctx, db, emitter)
 		cancel()
 	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/records"
+	"fitonex/backend/internal/store/sessions"
+	"fitonex/backend/internal/strength"
+)
+
+const (
+	// sessionIdleTimeout is how long a live session can go without a logged
+	// set or other change before it is treated as abandoned.
+	sessionIdleTimeout = 4 * time.Hour
+	sessionBatchSize   = 100
+)
+
+// finishAbandonedSessions closes sessions users forgot to finish. Sessions
+// with sets become workouts ending at their last activity, and their
+// personal records are detected like any other logged workout.
+func finishAbandonedSessions(db *sql.DB, now time.Time) (int, int, error) {
+	workouts, discarded, err := sessions.New(db).FinishAbandoned(now.Add(-sessionIdleTimeout), sessionBatchSize)
+
+	recordStore := records.New(db)
+	for _, finished := range workouts {
+		workout := finished.Workout
+		for _, exercise := range workout.Exercises {
+			exercise := exercise
+			_, recordErr := recordStore.Insert(workout.UserID, strength.RecordKey(exercise.MachineID, exercise.Name), func(current []models.PersonalRecord) []models.PersonalRecord {
+				return strength.Detect(exercise, current)
+			})
+			if recordErr != nil {
+				log.Printf("session cleanup record error for session %s: %v", finished.SessionID, recordErr)
+			}
+		}
+	}
+
+	return len(workouts), discarded, err
+}
+
+func runSessionCleanup(db *sql.DB) {
+	finished, discarded, err := finishAbandonedSessions(db, time.Now().UTC())
+	if err != nil {
+		log.Printf("session cleanup error: %v", err)
+	}
+	if finished > 0 || discarded > 0 {
+		log.Printf("session cleanup finished %d and discarded %d abandoned sessions", finished, discarded)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFinishAbandonedSessionsDiscardsEmptySessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	started := now.Add(-6 * time.Hour)
+
+	mock.ExpectQuery("SELECT id, user_id\\s+FROM workout_sessions").
+		WithArgs(now.Add(-sessionIdleTimeout), sessionBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow("session-1", "user-1"))
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM workout_sessions WHERE id = \\$1 AND user_id = \\$2 FOR UPDATE").
+		WithArgs("session-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "name", "type", "gym_id", "status", "exercises", "started_at", "paused_at",
+			"paused_seconds", "last_activity_at", "finished_at", "auto_finished", "workout_id",
+		}).AddRow("session-1", "user-1", "Workout", "", nil, models.SessionStatusActive, []byte("[]"), started, nil,
+			0, started, nil, false, nil))
+	mock.ExpectExec("UPDATE workout_sessions").
+		WithArgs("session-1", "user-1", "Workout", "", nil, models.SessionStatusDiscarded, []byte("[]"),
+			nil, 0, started, started, true, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	finished, discarded, err := finishAbandonedSessions(db, now)
+	if err != nil {
+		t.Fatalf("finishAbandonedSessions: %v", err)
+	}
+	if finished != 0 || discarded != 1 {
+		t.Fatalf("finished = %d, discarded = %d, want 0 and 1", finished, discarded)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
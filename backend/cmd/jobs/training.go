@@ -0,0 +1,22 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+
+	"fitonex/backend/internal/store/training"
+)
+
+// trainingLoadBatchSize caps how many users' changed days are rebuilt per run.
+// The insights endpoint refreshes on read, so this only keeps aggregates warm.
+const trainingLoadBatchSize = 500
+
+func runTrainingLoadRefresh(db *sql.DB) {
+	refreshed, err := training.New(db).RefreshPending(trainingLoadBatchSize)
+	if err != nil {
+		log.Printf("training load refresh error: %v", err)
+	}
+	if refreshed > 0 {
+		log.Printf("training load refreshed for %d users", refreshed)
+	}
+}
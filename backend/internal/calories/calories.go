@@ -0,0 +1,188 @@
+// Package calories estimates energy expenditure from MET values. Kilocalories
+// per minute are MET × 3.5 × body weight (kg) / 200.
+package calories
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"fitonex/backend/internal/models"
+)
+
+// DefaultBodyWeightKg is used when the user has not recorded their weight.
+const DefaultBodyWeightKg = 70
+
+// Set timing assumed when a set has no logged duration or rest.
+const (
+	DefaultSetSeconds  = 40
+	DefaultRestSeconds = 60
+)
+
+//go:embed met.json
+var metData []byte
+
+// Table maps workout types, body parts and machine names to MET values.
+// Keys are lowercase.
+type Table struct {
+	Default      float64            `json:"default"`
+	WorkoutTypes map[string]float64 `json:"workout_types"`
+	BodyParts    map[string]float64 `json:"body_parts"`
+	Machines     map[string]float64 `json:"machines"`
+}
+
+var defaultTable = mustParse(metData)
+
+// Default returns the built-in MET table.
+func Default() *Table {
+	return defaultTable
+}
+
+// Parse reads a MET table from JSON, lowercasing its keys.
+func Parse(data []byte) (*Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, err
+	}
+	table.WorkoutTypes = lowerKeys(table.WorkoutTypes)
+	table.BodyParts = lowerKeys(table.BodyParts)
+	table.Machines = lowerKeys(table.Machines)
+	return &table, nil
+}
+
+func mustParse(data []byte) *Table {
+	table, err := Parse(data)
+	if err != nil {
+		panic("calories: invalid MET table: " + err.Error())
+	}
+	return table
+}
+
+// WorkoutMET returns the MET for a workout type, or the table default.
+func (t *Table) WorkoutMET(workoutType string) float64 {
+	if met, ok := t.WorkoutTypes[normalize(workoutType)]; ok {
+		return met
+	}
+	return t.Default
+}
+
+// ExerciseMET returns the MET for an exercise. A machine's own MET wins, then
+// the table's entry for the machine name, then its body part; exercises
+// without a machine use the workout type.
+func (t *Table) ExerciseMET(machine *models.Machine, workoutType string) float64 {
+	if machine == nil {
+		return t.WorkoutMET(workoutType)
+	}
+	if machine.MET != nil && *machine.MET > 0 {
+		return *machine.MET
+	}
+	if met, ok := t.Machines[normalize(machine.Name)]; ok {
+		return met
+	}
+	if met, ok := t.BodyParts[normalize(machine.BodyPart)]; ok {
+		return met
+	}
+	return t.WorkoutMET(workoutType)
+}
+
+// Estimate sets the calorie estimate on a workout's totals and each of its
+// exercises. Exercise time comes from logged set durations and rests, or
+// DefaultSetSeconds and DefaultRestSeconds; the workout MET is their
+// time-weighted mean. When the workout has a duration, its calories cover the
+// whole duration and exercise estimates are scaled to add up to it.
+// machines is keyed by machine ID. AttachExercises must have run first.
+func (t *Table) Estimate(workout *models.Workout, bodyWeightKg float64, weightSource string, machines map[string]models.Machine) {
+	if workout.Totals == nil {
+		return
+	}
+
+	var (
+		seconds = make([]float64, len(workout.Exercises))
+		kcal    = make([]float64, len(workout.Exercises))
+		total   float64
+		metTime float64
+		active  float64
+	)
+	for i, exercise := range workout.Exercises {
+		var machine *models.Machine
+		if exercise.MachineID != nil {
+			if found, ok := machines[*exercise.MachineID]; ok {
+				machine = &found
+			}
+		}
+		met := t.ExerciseMET(machine, workout.Type)
+		seconds[i] = exerciseSeconds(exercise.Sets)
+		kcal[i] = kcalPerMinute(met, bodyWeightKg) * seconds[i] / 60
+		total += kcal[i]
+		metTime += met * seconds[i]
+		active += seconds[i]
+	}
+
+	met := t.WorkoutMET(workout.Type)
+	if active > 0 {
+		met = metTime / active
+	}
+	if minutes := float64(workout.Totals.DurationMinutes); minutes > 0 {
+		scaled := kcalPerMinute(met, bodyWeightKg) * minutes
+		if total > 0 {
+			for i := range kcal {
+				kcal[i] *= scaled / total
+			}
+		}
+		total = scaled
+	}
+
+	for i := range workout.Exercises {
+		if seconds[i] == 0 {
+			continue
+		}
+		value := round1(kcal[i])
+		workout.Exercises[i].EstimatedKcal = &value
+	}
+	workout.Totals.Calories = &models.CalorieEstimate{
+		Kcal:             round1(total),
+		MET:              round1(met),
+		BodyWeightKg:     bodyWeightKg,
+		BodyWeightSource: weightSource,
+	}
+}
+
+func exerciseSeconds(sets []models.Set) float64 {
+	var seconds float64
+	for _, set := range sets {
+		switch {
+		case set.DurationSeconds != nil:
+			seconds += float64(*set.DurationSeconds)
+		default:
+			seconds += DefaultSetSeconds
+			if set.RestSeconds == nil {
+				seconds += DefaultRestSeconds
+			}
+		}
+		if set.RestSeconds != nil {
+			seconds += float64(*set.RestSeconds)
+		}
+	}
+	return seconds
+}
+
+func kcalPerMinute(met, bodyWeightKg float64) float64 {
+	return met * 3.5 * bodyWeightKg / 200
+}
+
+func normalize(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func lowerKeys(values map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(values))
+	for key, value := range values {
+		result[normalize(key)] = value
+	}
+	return result
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
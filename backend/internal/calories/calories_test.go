@@ -0,0 +1,72 @@
+package calories
+
+import (
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestEstimateWorkout(t *testing.T) {
+	treadmillID := "m1"
+	run := 600
+	workout := models.Workout{
+		Type: "strength",
+		Exercises: []models.Exercise{
+			{MachineID: &treadmillID, Sets: []models.Set{{DurationSeconds: &run}}},
+			{Name: "Push-up", Sets: []models.Set{{Reps: 10}, {Reps: 10}, {Reps: 10}}},
+		},
+		Totals: &models.WorkoutTotals{},
+	}
+	machines := map[string]models.Machine{treadmillID: {ID: treadmillID, Name: "Treadmill", BodyPart: "Cardio"}}
+
+	Default().Estimate(&workout, 80, models.BodyWeightSourceMetrics, machines)
+	// 10 minutes at MET 8 plus three default sets (5 minutes) at MET 5.
+	if got := workout.Totals.Calories; got == nil || got.Kcal != 147 || got.MET != 7 || got.BodyWeightKg != 80 {
+		t.Fatalf("unexpected estimate %+v", got)
+	}
+	if *workout.Exercises[0].EstimatedKcal != 112 || *workout.Exercises[1].EstimatedKcal != 35 {
+		t.Fatalf("unexpected exercise estimates %v %v", *workout.Exercises[0].EstimatedKcal, *workout.Exercises[1].EstimatedKcal)
+	}
+
+	// A longer logged duration scales the estimate to cover it.
+	workout.Totals.DurationMinutes = 30
+	Default().Estimate(&workout, 80, models.BodyWeightSourceMetrics, machines)
+	if workout.Totals.Calories.Kcal != 294 || *workout.Exercises[0].EstimatedKcal != 224 || *workout.Exercises[1].EstimatedKcal != 70 {
+		t.Fatalf("unexpected scaled estimate %+v", workout.Totals.Calories)
+	}
+}
+
+func TestEstimateWithoutExercisesUsesWorkoutType(t *testing.T) {
+	workout := models.Workout{Type: " Running ", Totals: &models.WorkoutTotals{DurationMinutes: 20}}
+	Default().Estimate(&workout, 70, models.BodyWeightSourceDefault, nil)
+	if got := workout.Totals.Calories; got.MET != 9.8 || got.Kcal != 240.1 {
+		t.Fatalf("unexpected estimate %+v", got)
+	}
+}
+
+func TestExerciseMETPrecedence(t *testing.T) {
+	table, err := Parse([]byte(`{"default": 4, "workout_types": {"Yoga": 2.5}, "body_parts": {"Legs": 6}, "machines": {"Leg  Press": 5.5}}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	override := 7.5
+	cases := []struct {
+		name    string
+		machine *models.Machine
+		want    float64
+	}{
+		{"machine override", &models.Machine{Name: "Leg Press", BodyPart: "Legs", MET: &override}, 7.5},
+		{"machine name", &models.Machine{Name: "leg press", BodyPart: "Legs"}, 5.5},
+		{"body part", &models.Machine{Name: "Hack Squat", BodyPart: "legs"}, 6},
+		{"workout type", &models.Machine{Name: "Mat", BodyPart: "Other"}, 2.5},
+		{"no machine", nil, 2.5},
+	}
+	for _, c := range cases {
+		if got := table.ExerciseMET(c.machine, "yoga"); got != c.want {
+			t.Fatalf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+	if got := table.WorkoutMET("unknown"); got != 4 {
+		t.Fatalf("expected the default MET, got %v", got)
+	}
+}
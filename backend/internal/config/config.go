@@ -23,11 +23,14 @@ type Config struct {
 	S3Bucket     string
 	S3Region     string
 	MaxUploadMB  int
+	ReviewPhotoLimit int
+	ReviewPhotoMaxMB int
 
 	ModerationEnabled bool
 	CDNBaseURL        string
 	CacheTTLNearby    time.Duration
 	CacheTTLGym       time.Duration
+	CacheTTLTiles     time.Duration
 	AnalyticsSink     string
 	FeatureFlags      map[string]float64
 	AlertWebhookURL   string
@@ -65,11 +68,14 @@ func Load() (*Config, error) {
 		S3Bucket:    getEnv("S3_BUCKET", "fitonex"),
 		S3Region:    getEnv("S3_REGION", "us-east-1"),
 		MaxUploadMB: getEnvInt("MAX_UPLOAD_MB", 100),
+		ReviewPhotoLimit: getEnvInt("REVIEW_PHOTO_LIMIT", 5),
+		ReviewPhotoMaxMB: getEnvInt("REVIEW_PHOTO_MAX_MB", 10),
 
 		ModerationEnabled: getEnvBool("MODERATION_ENABLED", true),
 		CDNBaseURL:        getEnv("CDN_BASE_URL", ""),
 		CacheTTLNearby:    getEnvDuration("CACHE_TTL_NEARBY", 60*time.Second),
 		CacheTTLGym:       getEnvDuration("CACHE_TTL_GYM", 5*time.Minute),
+		CacheTTLTiles:     getEnvDuration("CACHE_TTL_TILES", 30*time.Minute),
 		AnalyticsSink:     getEnv("ANALYTICS_SINK", "stdout"),
 		AlertWebhookURL:   getEnv("ALERT_WEBHOOK_URL", ""),
 		FeatureFlagsRaw:   getEnv("FEATURE_FLAGS_JSON", `{"video_upload":100,"map_filters":50}`),
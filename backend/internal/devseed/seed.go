@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"fitonex/backend/internal/store/catalog"
 )
 
 // Seed populates the database with development fixtures.
@@ -23,9 +25,18 @@ func Seed(ctx context.Context, db *sql.DB) error {
 	if err := seedGymPrices(ctx, db); err != nil {
 		return err
 	}
+	if err := seedGymHours(ctx, db); err != nil {
+		return err
+	}
+	if err := seedGymAmenities(ctx, db); err != nil {
+		return err
+	}
 	if err := seedGymReviews(ctx, db); err != nil {
 		return err
 	}
+	if err := catalog.New(db).SyncCurated(); err != nil {
+		return fmt.Errorf("seed exercise catalog: %w", err)
+	}
 	return nil
 }
 
@@ -35,18 +46,19 @@ func seedUsers(ctx context.Context, db *sql.DB) error {
 		ID    string
 		Email string
 		Name  string
+		Role  string
 	}{
-		{"11111111-1111-1111-1111-111111111111", "alex@example.com", "Alex Johnson"},
-		{"22222222-2222-2222-2222-222222222222", "blake@example.com", "Blake Rivera"},
-		{"33333333-3333-3333-3333-333333333333", "casey@example.com", "Casey Morgan"},
+		{"11111111-1111-1111-1111-111111111111", "alex@example.com", "Alex Johnson", "admin"},
+		{"22222222-2222-2222-2222-222222222222", "blake@example.com", "Blake Rivera", "user"},
+		{"33333333-3333-3333-3333-333333333333", "casey@example.com", "Casey Morgan", "user"},
 	}
 
 	for _, user := range users {
 		if _, err := db.ExecContext(ctx, `
-			INSERT INTO users (id, email, name, password)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO users (id, email, name, password, role)
+			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (id) DO NOTHING
-		`, user.ID, user.Email, user.Name, hashedPassword); err != nil {
+		`, user.ID, user.Email, user.Name, hashedPassword, user.Role); err != nil {
 			return fmt.Errorf("seed users: %w", err)
 		}
 	}
@@ -168,6 +180,71 @@ func seedGymPrices(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
+func seedGymHours(ctx context.Context, db *sql.DB) error {
+	// Capitol Hill Strength never closes.
+	for i := 1; i <= 5; i++ {
+		gymID := fmt.Sprintf("44444444-4444-4444-4444-44444444444%d", i)
+		if _, err := db.ExecContext(ctx, `
+			UPDATE gyms SET timezone = 'America/Los_Angeles', open_24_7 = $2 WHERE id = $1
+		`, gymID, i == 2); err != nil {
+			return fmt.Errorf("seed gym timezones: %w", err)
+		}
+	}
+
+	// Weekdays run from 0 (Sunday) to 6; Ballard Ironworks stays open past
+	// midnight on Friday and Saturday.
+	hours := []struct {
+		GymID         string
+		Weekdays      []int
+		Opens, Closes string
+	}{
+		{"44444444-4444-4444-4444-444444444441", []int{1, 2, 3, 4, 5}, "05:00", "23:00"},
+		{"44444444-4444-4444-4444-444444444441", []int{0, 6}, "07:00", "21:00"},
+		{"44444444-4444-4444-4444-444444444443", []int{0, 1, 2, 3, 4}, "06:00", "22:00"},
+		{"44444444-4444-4444-4444-444444444443", []int{5, 6}, "06:00", "01:00"},
+		{"44444444-4444-4444-4444-444444444444", []int{1, 2, 3, 4, 5}, "06:00", "12:00"},
+		{"44444444-4444-4444-4444-444444444444", []int{1, 2, 3, 4, 5}, "16:00", "22:00"},
+		{"44444444-4444-4444-4444-444444444445", []int{1, 2, 3, 4, 5}, "05:30", "21:00"},
+	}
+	for _, entry := range hours {
+		for _, weekday := range entry.Weekdays {
+			if _, err := db.ExecContext(ctx, `
+				INSERT INTO gym_hours (gym_id, weekday, opens_at, closes_at)
+				VALUES ($1, $2, $3::time, $4::time)
+				ON CONFLICT (gym_id, weekday, opens_at) DO NOTHING
+			`, entry.GymID, weekday, entry.Opens, entry.Closes); err != nil {
+				return fmt.Errorf("seed gym hours: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func seedGymAmenities(ctx context.Context, db *sql.DB) error {
+	amenities := map[string][]string{
+		"44444444-4444-4444-4444-444444444441": {"lockers", "showers", "towels", "wifi"},
+		"44444444-4444-4444-4444-444444444442": {"classes", "lockers", "personal_training", "showers"},
+		"44444444-4444-4444-4444-444444444443": {"parking", "lockers", "showers"},
+		"44444444-4444-4444-4444-444444444444": {"classes", "pool", "sauna", "steam_room", "childcare"},
+		"44444444-4444-4444-4444-444444444445": {"accessible", "personal_training", "showers", "wifi"},
+	}
+
+	for gymID, list := range amenities {
+		for _, amenity := range list {
+			if _, err := db.ExecContext(ctx, `
+				INSERT INTO gym_amenities (gym_id, amenity)
+				VALUES ($1, $2)
+				ON CONFLICT (gym_id, amenity) DO NOTHING
+			`, gymID, amenity); err != nil {
+				return fmt.Errorf("seed gym amenities: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func seedGymReviews(ctx context.Context, db *sql.DB) error {
 	reviews := []struct {
 		ID, GymID, UserID, Comment string
@@ -189,11 +266,26 @@ func seedGymReviews(ctx context.Context, db *sql.DB) error {
 		if _, err := db.ExecContext(ctx, `
 			INSERT INTO gym_reviews (id, gym_id, user_id, rating, comment)
 			VALUES ($1, $2, $3, $4, $5)
-			ON CONFLICT (id) DO NOTHING
+			ON CONFLICT DO NOTHING
 		`, review.ID, review.GymID, review.UserID, review.Rating, sql.NullString{String: review.Comment, Valid: review.Comment != ""}); err != nil {
 			return fmt.Errorf("seed gym reviews: %w", err)
 		}
 	}
 
+	// Seeded reviews bypass the store, so the rating summary is rebuilt here.
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO gym_review_stats (gym_id, review_count, rating_sum, updated_at)
+		SELECT gym_id, COUNT(*), SUM(rating), NOW()
+		FROM gym_reviews
+		WHERE deleted_at IS NULL
+		GROUP BY gym_id
+		ON CONFLICT (gym_id) DO UPDATE
+		SET review_count = EXCLUDED.review_count,
+		    rating_sum = EXCLUDED.rating_sum,
+		    updated_at = EXCLUDED.updated_at
+	`); err != nil {
+		return fmt.Errorf("seed gym review stats: %w", err)
+	}
+
 	return nil
 }
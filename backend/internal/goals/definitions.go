@@ -0,0 +1,231 @@
+package goals
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	maxWeeks        = 52
+	maxWorkoutCount = 1000
+	maxStreakDays   = 3650
+	maxLiftKg       = 1000
+	maxLiftReps     = 50
+)
+
+// WeeklyTrainingDays is met by training on Target days a week, for Weeks
+// weeks in a row. A day counts when a workout or exercise is logged on it.
+type WeeklyTrainingDays struct{}
+
+// Type implements Definition.
+func (WeeklyTrainingDays) Type() string {
+	return models.GoalWeeklyTrainingDays
+}
+
+// Unit implements Definition.
+func (WeeklyTrainingDays) Unit() string {
+	return "days_per_week"
+}
+
+// Validate implements Definition.
+func (WeeklyTrainingDays) Validate(goal *models.Goal) error {
+	if !whole(goal.Target) || goal.Target > 7 {
+		return errors.New("target must be a whole number of days between 1 and 7")
+	}
+	if goal.Weeks == nil {
+		weeks := 1
+		goal.Weeks = &weeks
+	}
+	if *goal.Weeks < 1 || *goal.Weeks > maxWeeks {
+		return errors.New("weeks must be between 1 and 52")
+	}
+	return nil
+}
+
+// Evaluate implements Definition. Current is the days trained this week.
+// Weeks before this one that fell short reset the run; this week only counts
+// once it reaches the target.
+func (WeeklyTrainingDays) Evaluate(goal models.Goal, source Source, now time.Time) (Progress, error) {
+	days, err := source.TrainingDays(goal.UserID, startDay(goal))
+	if err != nil {
+		return Progress{}, err
+	}
+
+	perWeek := make(map[time.Time]int)
+	for _, day := range days {
+		perWeek[weekStart(day)]++
+	}
+
+	target := int(goal.Target)
+	weeks := *goal.Weeks
+	current := weekStart(now)
+	run := 0
+	for week := weekStart(goal.StartedAt); !week.After(current); week = week.AddDate(0, 0, 7) {
+		if perWeek[week] >= target {
+			run++
+			if run >= weeks {
+				return Progress{Current: float64(perWeek[current]), Percent: 100, Completed: true}, nil
+			}
+			continue
+		}
+		if !week.Equal(current) {
+			run = 0
+		}
+	}
+
+	// A week still short of the target adds its days as partial progress.
+	done := run * target
+	if perWeek[current] < target {
+		done += perWeek[current]
+	}
+	return Progress{
+		Current: float64(perWeek[current]),
+		Percent: float64(done) / float64(weeks*target) * 100,
+	}, nil
+}
+
+// WorkoutCount is met by logging Target workouts.
+type WorkoutCount struct{}
+
+// Type implements Definition.
+func (WorkoutCount) Type() string {
+	return models.GoalWorkoutCount
+}
+
+// Unit implements Definition.
+func (WorkoutCount) Unit() string {
+	return "workouts"
+}
+
+// Validate implements Definition.
+func (WorkoutCount) Validate(goal *models.Goal) error {
+	if !whole(goal.Target) || goal.Target > maxWorkoutCount {
+		return errors.New("target must be a whole number of workouts up to 1000")
+	}
+	return nil
+}
+
+// Evaluate implements Definition.
+func (WorkoutCount) Evaluate(goal models.Goal, source Source, now time.Time) (Progress, error) {
+	count, err := source.WorkoutCount(goal.UserID, startDay(goal))
+	if err != nil {
+		return Progress{}, err
+	}
+	return Progress{
+		Current:   float64(count),
+		Percent:   float64(count) / goal.Target * 100,
+		Completed: float64(count) >= goal.Target,
+	}, nil
+}
+
+// CheckinStreak is met by checking in Target days in a row.
+type CheckinStreak struct{}
+
+// Type implements Definition.
+func (CheckinStreak) Type() string {
+	return models.GoalCheckinStreak
+}
+
+// Unit implements Definition.
+func (CheckinStreak) Unit() string {
+	return "days"
+}
+
+// Validate implements Definition.
+func (CheckinStreak) Validate(goal *models.Goal) error {
+	if !whole(goal.Target) || goal.Target > maxStreakDays {
+		return errors.New("target must be a whole number of days up to 3650")
+	}
+	return nil
+}
+
+// Evaluate implements Definition. Current is the streak still alive, one
+// that includes today or yesterday.
+func (CheckinStreak) Evaluate(goal models.Goal, source Source, now time.Time) (Progress, error) {
+	days, err := source.CheckinDays(goal.UserID, startDay(goal))
+	if err != nil {
+		return Progress{}, err
+	}
+
+	best, run := 0, 0
+	for i, day := range days {
+		if i > 0 && day.Sub(days[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		best = max(best, run)
+	}
+
+	current := 0
+	if len(days) > 0 && !days[len(days)-1].Before(now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)) {
+		current = run
+	}
+	return Progress{
+		Current:   float64(current),
+		Percent:   float64(current) / goal.Target * 100,
+		Completed: float64(best) >= goal.Target,
+	}, nil
+}
+
+// Lift is met by a working set of at least Reps reps at Target kilograms on a
+// machine or named exercise.
+type Lift struct{}
+
+// Type implements Definition.
+func (Lift) Type() string {
+	return models.GoalLift
+}
+
+// Unit implements Definition.
+func (Lift) Unit() string {
+	return models.UnitKg
+}
+
+// Validate implements Definition.
+func (Lift) Validate(goal *models.Goal) error {
+	if goal.Target > maxLiftKg {
+		return errors.New("target must be at most 1000 kg")
+	}
+	if goal.MachineID == nil && (goal.ExerciseName == nil || strings.TrimSpace(*goal.ExerciseName) == "") {
+		return errors.New("machine_id or exercise_name is required")
+	}
+	if goal.Reps == nil {
+		reps := 1
+		goal.Reps = &reps
+	}
+	if *goal.Reps < 1 || *goal.Reps > maxLiftReps {
+		return errors.New("reps must be between 1 and 50")
+	}
+	return nil
+}
+
+// Evaluate implements Definition. Current is the heaviest qualifying set.
+func (Lift) Evaluate(goal models.Goal, source Source, now time.Time) (Progress, error) {
+	name := ""
+	if goal.ExerciseName != nil {
+		name = *goal.ExerciseName
+	}
+	best, err := source.BestLift(goal.UserID, goal.MachineID, name, *goal.Reps, startDay(goal))
+	if err != nil {
+		return Progress{}, err
+	}
+	return Progress{
+		Current:   best,
+		Percent:   best / goal.Target * 100,
+		Completed: best >= goal.Target-0.005,
+	}, nil
+}
+
+// startDay is the first day whose activity counts towards a goal.
+func startDay(goal models.Goal) time.Time {
+	return goal.StartedAt.UTC().Truncate(24 * time.Hour)
+}
+
+func whole(value float64) bool {
+	return value == math.Trunc(value)
+}
@@ -0,0 +1,129 @@
+package goals
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// ErrUnknownType is returned for a goal type that is not registered.
+var ErrUnknownType = errors.New("goals: unknown goal type")
+
+// Source loads the activity goals are evaluated against. since is inclusive
+// and days are returned as UTC midnights in ascending order.
+type Source interface {
+	TrainingDays(userID string, since time.Time) ([]time.Time, error)
+	WorkoutCount(userID string, since time.Time) (int, error)
+	CheckinDays(userID string, since time.Time) ([]time.Time, error)
+	BestLift(userID string, machineID *string, name string, minReps int, since time.Time) (float64, error)
+}
+
+// Progress is the outcome of evaluating a goal. Current is in the goal's unit
+// and Percent runs from 0 to 100.
+type Progress struct {
+	Current   float64
+	Percent   float64
+	Completed bool
+}
+
+// Definition describes one goal type: which fields it needs and how its
+// progress is measured.
+type Definition interface {
+	Type() string
+	Unit() string
+	// Validate checks the type-specific fields and fills their defaults.
+	Validate(goal *models.Goal) error
+	Evaluate(goal models.Goal, source Source, now time.Time) (Progress, error)
+}
+
+var definitions = map[string]Definition{}
+
+// Register makes a goal type available.
+func Register(definition Definition) {
+	definitions[definition.Type()] = definition
+}
+
+// Types lists the registered goal types in alphabetical order.
+func Types() []string {
+	types := make([]string, 0, len(definitions))
+	for name := range definitions {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func init() {
+	Register(WeeklyTrainingDays{})
+	Register(WorkoutCount{})
+	Register(CheckinStreak{})
+	Register(Lift{})
+}
+
+// Unit returns the unit a goal type is measured in, or "" when unknown.
+func Unit(goalType string) string {
+	if definition, ok := definitions[goalType]; ok {
+		return definition.Unit()
+	}
+	return ""
+}
+
+// Validate checks a goal against its type, fills defaults and sets its unit.
+func Validate(goal *models.Goal) error {
+	definition, ok := definitions[goal.Type]
+	if !ok {
+		return ErrUnknownType
+	}
+	if goal.Target <= 0 || math.IsNaN(goal.Target) || math.IsInf(goal.Target, 0) {
+		return errors.New("target must be greater than zero")
+	}
+	if err := definition.Validate(goal); err != nil {
+		return err
+	}
+	goal.Unit = definition.Unit()
+	return nil
+}
+
+// Evaluate updates an active goal's progress and status as of now. A goal
+// whose deadline day has ended without being met expires. It reports whether
+// this evaluation completed the goal.
+func Evaluate(goal *models.Goal, source Source, now time.Time) (bool, error) {
+	definition, ok := definitions[goal.Type]
+	if !ok {
+		return false, ErrUnknownType
+	}
+	goal.Unit = definition.Unit()
+	if goal.Status != models.GoalStatusActive {
+		return false, nil
+	}
+
+	progress, err := definition.Evaluate(*goal, source, now)
+	if err != nil {
+		return false, err
+	}
+
+	goal.Current = math.Round(progress.Current*100) / 100
+	goal.ProgressPercent = math.Round(math.Min(math.Max(progress.Percent, 0), 100)*10) / 10
+	goal.EvaluatedAt = &now
+
+	switch {
+	case progress.Completed:
+		goal.Status = models.GoalStatusCompleted
+		goal.ProgressPercent = 100
+		goal.CompletedAt = &now
+		return true, nil
+	case goal.Deadline != nil && !now.Before(goal.Deadline.AddDate(0, 0, 1)):
+		goal.Status = models.GoalStatusExpired
+	}
+	return false, nil
+}
+
+// weekStart returns the Monday starting the UTC week of t.
+func weekStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
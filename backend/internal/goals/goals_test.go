@@ -0,0 +1,182 @@
+package goals
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+type fakeSource struct {
+	trainingDays []time.Time
+	workouts     int
+	checkinDays  []time.Time
+	bestLift     float64
+	liftReps     int
+}
+
+func (f *fakeSource) TrainingDays(userID string, since time.Time) ([]time.Time, error) {
+	return after(f.trainingDays, since), nil
+}
+
+func (f *fakeSource) WorkoutCount(userID string, since time.Time) (int, error) {
+	return f.workouts, nil
+}
+
+func (f *fakeSource) CheckinDays(userID string, since time.Time) ([]time.Time, error) {
+	return after(f.checkinDays, since), nil
+}
+
+func (f *fakeSource) BestLift(userID string, machineID *string, name string, minReps int, since time.Time) (float64, error) {
+	f.liftReps = minReps
+	return f.bestLift, nil
+}
+
+// monday is the start of a UTC week.
+var monday = time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+
+func TestWeeklyTrainingDaysNeedsConsecutiveWeeks(t *testing.T) {
+	weeks := 2
+	goal := newGoal(models.GoalWeeklyTrainingDays, 3)
+	goal.Weeks = &weeks
+
+	source := &fakeSource{trainingDays: days(monday, 0, 2, 4, 7, 8)}
+	now := monday.AddDate(0, 0, 9)
+
+	completed, err := Evaluate(&goal, source, now)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	// One full week plus two of three days this week: 5 of 6.
+	if completed || goal.Current != 2 || goal.ProgressPercent != 83.3 || goal.Status != models.GoalStatusActive {
+		t.Fatalf("unexpected progress %+v", goal)
+	}
+
+	source.trainingDays = append(source.trainingDays, monday.AddDate(0, 0, 9))
+	completed, _ = Evaluate(&goal, source, now)
+	if !completed || goal.Status != models.GoalStatusCompleted || goal.ProgressPercent != 100 || goal.CompletedAt == nil {
+		t.Fatalf("expected completion, got %+v", goal)
+	}
+
+	// Evaluating a completed goal changes nothing.
+	if completed, _ := Evaluate(&goal, source, now.AddDate(0, 0, 1)); completed || !goal.CompletedAt.Equal(now) {
+		t.Fatalf("a completed goal must not complete again")
+	}
+}
+
+func TestWeeklyTrainingDaysShortWeekResetsRun(t *testing.T) {
+	weeks := 2
+	goal := newGoal(models.GoalWeeklyTrainingDays, 2)
+	goal.Weeks = &weeks
+
+	// Week one met, week two missed, nothing yet in week three.
+	source := &fakeSource{trainingDays: days(monday, 1, 3, 8)}
+	if _, err := Evaluate(&goal, source, monday.AddDate(0, 0, 14)); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if goal.ProgressPercent != 0 || goal.Current != 0 {
+		t.Fatalf("expected the run to reset, got %+v", goal)
+	}
+}
+
+func TestCheckinStreakTracksLiveStreak(t *testing.T) {
+	goal := newGoal(models.GoalCheckinStreak, 5)
+	source := &fakeSource{checkinDays: days(monday, 0, 1, 2, 5, 6)}
+
+	if _, err := Evaluate(&goal, source, monday.AddDate(0, 0, 7).Add(10*time.Hour)); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if goal.Current != 2 || goal.ProgressPercent != 40 {
+		t.Fatalf("unexpected streak %+v", goal)
+	}
+
+	// Two days without a check-in break the streak.
+	if _, err := Evaluate(&goal, source, monday.AddDate(0, 0, 8)); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if goal.Current != 0 {
+		t.Fatalf("expected a broken streak, got %+v", goal)
+	}
+}
+
+func TestLiftGoalAndDeadline(t *testing.T) {
+	name := "Bench Press"
+	goal := newGoal(models.GoalLift, 100)
+	goal.ExerciseName = &name
+	if err := Validate(&goal); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if *goal.Reps != 1 || goal.Unit != models.UnitKg {
+		t.Fatalf("unexpected defaults %+v", goal)
+	}
+
+	deadline := monday.AddDate(0, 0, 6)
+	goal.Deadline = &deadline
+	source := &fakeSource{bestLift: 92.5}
+
+	// Still active during the deadline day.
+	if _, err := Evaluate(&goal, source, deadline.Add(23*time.Hour)); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if goal.Status != models.GoalStatusActive || goal.ProgressPercent != 92.5 || source.liftReps != 1 {
+		t.Fatalf("unexpected progress %+v", goal)
+	}
+
+	if _, err := Evaluate(&goal, source, deadline.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if goal.Status != models.GoalStatusExpired {
+		t.Fatalf("expected the goal to expire, got %q", goal.Status)
+	}
+}
+
+func TestValidateRejectsBadGoals(t *testing.T) {
+	weeks := 60
+	cases := map[string]models.Goal{
+		"unknown type":      newGoal("marathon", 1),
+		"zero target":       newGoal(models.GoalWorkoutCount, 0),
+		"fractional days":   newGoal(models.GoalCheckinStreak, 2.5),
+		"eight days a week": newGoal(models.GoalWeeklyTrainingDays, 8),
+		"lift without name": newGoal(models.GoalLift, 100),
+		"too many weeks":    {Type: models.GoalWeeklyTrainingDays, Target: 3, Weeks: &weeks},
+	}
+	for name, goal := range cases {
+		if err := Validate(&goal); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+
+	goal := newGoal("marathon", 1)
+	if err := Validate(&goal); !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("expected ErrUnknownType, got %v", err)
+	}
+}
+
+func newGoal(goalType string, target float64) models.Goal {
+	return models.Goal{
+		UserID:    "u1",
+		Type:      goalType,
+		Target:    target,
+		Status:    models.GoalStatusActive,
+		StartedAt: monday.Add(9 * time.Hour),
+	}
+}
+
+func days(start time.Time, offsets ...int) []time.Time {
+	result := make([]time.Time, 0, len(offsets))
+	for _, offset := range offsets {
+		result = append(result, start.AddDate(0, 0, offset))
+	}
+	return result
+}
+
+func after(values []time.Time, since time.Time) []time.Time {
+	var result []time.Time
+	for _, value := range values {
+		if !value.Before(since) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,223 @@
+// Package gymhours validates gym opening hours and evaluates them at an
+// instant. The nearby search applies the same rules in SQL through the
+// gym_open_at function; the two must stay in step.
+package gymhours
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	endOfDay   = 24 * 60
+
+	// MaxIntervalsPerDay bounds how many intervals one day can hold.
+	MaxIntervalsPerDay = 6
+	// MaxHolidays bounds how many holidays a gym can publish.
+	MaxHolidays = 100
+)
+
+// Weekdays lists the weekly schedule keys in time.Weekday order.
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// ErrUnknownTimezone is returned for timezones that are not IANA names.
+var ErrUnknownTimezone = errors.New("timezone must be an IANA name such as Europe/Berlin")
+
+// Validate checks and normalizes opening hours in place: weekday keys are
+// lowercased, times are written as HH:MM, a closing time of 00:00 becomes
+// 24:00, and intervals and holidays are sorted.
+func Validate(hours *models.OpeningHours) error {
+	hours.Timezone = strings.TrimSpace(hours.Timezone)
+	if hours.Timezone == "" {
+		hours.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(hours.Timezone); err != nil || hours.Timezone == "Local" {
+		return ErrUnknownTimezone
+	}
+
+	weekly := make(map[string][]models.HoursInterval, len(hours.Weekly))
+	for day, intervals := range hours.Weekly {
+		key := strings.ToLower(strings.TrimSpace(day))
+		if weekdayIndex(key) < 0 {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+		if _, ok := weekly[key]; ok {
+			return fmt.Errorf("%s is listed twice", key)
+		}
+		normalized, err := normalizeDay(intervals)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if len(normalized) > 0 {
+			weekly[key] = normalized
+		}
+	}
+	hours.Weekly = weekly
+
+	if len(hours.Holidays) > MaxHolidays {
+		return fmt.Errorf("at most %d holidays can be listed", MaxHolidays)
+	}
+	seen := make(map[string]bool, len(hours.Holidays))
+	for i := range hours.Holidays {
+		holiday := &hours.Holidays[i]
+		date, err := time.Parse(dateLayout, strings.TrimSpace(holiday.Date))
+		if err != nil {
+			return fmt.Errorf("holiday date %q must be YYYY-MM-DD", holiday.Date)
+		}
+		holiday.Date = date.Format(dateLayout)
+		if seen[holiday.Date] {
+			return fmt.Errorf("holiday %s is listed twice", holiday.Date)
+		}
+		seen[holiday.Date] = true
+		holiday.Name = strings.TrimSpace(holiday.Name)
+		if len([]rune(holiday.Name)) > 80 {
+			return fmt.Errorf("holiday %s: name must be at most 80 characters", holiday.Date)
+		}
+		normalized, err := normalizeDay(holiday.Intervals)
+		if err != nil {
+			return fmt.Errorf("holiday %s: %w", holiday.Date, err)
+		}
+		holiday.Intervals = normalized
+	}
+	if hours.Holidays == nil {
+		hours.Holidays = []models.GymHoliday{}
+	}
+	sort.Slice(hours.Holidays, func(i, j int) bool { return hours.Holidays[i].Date < hours.Holidays[j].Date })
+
+	return nil
+}
+
+// Published reports whether the hours say anything about when the gym is
+// open. Gyms without published hours never match an open filter.
+func Published(hours models.OpeningHours) bool {
+	return hours.Open24x7 || len(hours.Weekly) > 0 || len(hours.Holidays) > 0
+}
+
+// OpenAt reports whether the gym is open at the instant. Wall-clock times
+// are read in the gym's timezone, so intervals keep their local meaning
+// across daylight saving changes; an interval running past midnight is
+// checked on the day it started.
+func OpenAt(hours models.OpeningHours, at time.Time) bool {
+	location, err := time.LoadLocation(hours.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for _, interval := range daySchedule(hours, today) {
+		opens, closes := minutes(interval.Opens), minutes(interval.Closes)
+		if minute >= opens && (closes < opens || minute < closes) {
+			return true
+		}
+	}
+	for _, interval := range daySchedule(hours, today.AddDate(0, 0, -1)) {
+		opens, closes := minutes(interval.Opens), minutes(interval.Closes)
+		if closes < opens && minute < closes {
+			return true
+		}
+	}
+	return false
+}
+
+// daySchedule returns the intervals that apply on a local date.
+func daySchedule(hours models.OpeningHours, date time.Time) []models.HoursInterval {
+	key := date.Format(dateLayout)
+	for _, holiday := range hours.Holidays {
+		if holiday.Date == key {
+			return holiday.Intervals
+		}
+	}
+	if hours.Open24x7 {
+		return []models.HoursInterval{{Opens: "00:00", Closes: "24:00"}}
+	}
+	return hours.Weekly[Weekdays[date.Weekday()]]
+}
+
+// normalizeDay parses, sorts and checks one day's intervals. Only the last
+// interval may run past midnight, and intervals may not overlap.
+func normalizeDay(intervals []models.HoursInterval) ([]models.HoursInterval, error) {
+	if len(intervals) > MaxIntervalsPerDay {
+		return nil, fmt.Errorf("at most %d intervals per day", MaxIntervalsPerDay)
+	}
+	normalized := make([]models.HoursInterval, 0, len(intervals))
+	for _, interval := range intervals {
+		opens, err := parseClock(interval.Opens, false)
+		if err != nil {
+			return nil, err
+		}
+		closes, err := parseClock(interval.Closes, true)
+		if err != nil {
+			return nil, err
+		}
+		if closes == 0 {
+			closes = endOfDay
+		}
+		if opens == closes {
+			return nil, errors.New("an interval cannot open and close at the same time; use open_24_7 for round-the-clock gyms")
+		}
+		normalized = append(normalized, models.HoursInterval{Opens: formatClock(opens), Closes: formatClock(closes)})
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i].Opens < normalized[j].Opens })
+
+	for i, interval := range normalized {
+		opens, closes := minutes(interval.Opens), minutes(interval.Closes)
+		if closes < opens && i != len(normalized)-1 {
+			return nil, errors.New("only the last interval of a day can run past midnight")
+		}
+		if i > 0 && opens < minutes(normalized[i-1].Closes) {
+			return nil, errors.New("intervals overlap")
+		}
+	}
+	return normalized, nil
+}
+
+func parseClock(value string, allowEndOfDay bool) (int, error) {
+	value = strings.TrimSpace(value)
+	if allowEndOfDay && value == "24:00" {
+		return endOfDay, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// minutes reads a normalized HH:MM clock time.
+func minutes(value string) int {
+	if value == "24:00" {
+		return endOfDay
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0
+	}
+	return parsed.Hour()*60 + parsed.Minute()
+}
+
+func weekdayIndex(name string) int {
+	for i, day := range Weekdays {
+		if day == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Weekday returns the time.Weekday for a schedule key.
+func Weekday(name string) time.Weekday {
+	return time.Weekday(weekdayIndex(name))
+}
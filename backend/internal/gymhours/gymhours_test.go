@@ -0,0 +1,185 @@
+package gymhours
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", name, err)
+	}
+	return location
+}
+
+func TestOpenAtAcrossMidnight(t *testing.T) {
+	// Friday night runs until 02:00 Saturday; Saturday opens at 09:00.
+	hours := models.OpeningHours{
+		Timezone: "Europe/Berlin",
+		Weekly: map[string][]models.HoursInterval{
+			"friday":   {{Opens: "06:00", Closes: "02:00"}},
+			"saturday": {{Opens: "09:00", Closes: "18:00"}},
+		},
+	}
+	if err := Validate(&hours); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	berlin := mustLocation(t, "Europe/Berlin")
+
+	cases := []struct {
+		at   time.Time
+		open bool
+	}{
+		{time.Date(2024, 5, 10, 23, 30, 0, 0, berlin), true},
+		{time.Date(2024, 5, 11, 1, 59, 0, 0, berlin), true},
+		{time.Date(2024, 5, 11, 2, 0, 0, 0, berlin), false},
+		{time.Date(2024, 5, 11, 8, 59, 0, 0, berlin), false},
+		{time.Date(2024, 5, 11, 9, 0, 0, 0, berlin), true},
+		// Thursday has no hours, so nothing spills into Friday morning.
+		{time.Date(2024, 5, 10, 1, 0, 0, 0, berlin), false},
+		// The same instant seen from UTC is still judged in Berlin time.
+		{time.Date(2024, 5, 10, 23, 30, 0, 0, berlin).UTC(), true},
+	}
+	for _, tc := range cases {
+		if got := OpenAt(hours, tc.at); got != tc.open {
+			t.Fatalf("OpenAt(%v) = %v, want %v", tc.at, got, tc.open)
+		}
+	}
+}
+
+func TestOpenAtAcrossDST(t *testing.T) {
+	hours := models.OpeningHours{
+		Timezone: "America/New_York",
+		Weekly: map[string][]models.HoursInterval{
+			"saturday": {{Opens: "22:00", Closes: "03:00"}},
+			"sunday":   {{Opens: "05:00", Closes: "12:00"}},
+		},
+	}
+	if err := Validate(&hours); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	// Clocks jump from 02:00 to 03:00 on 2024-03-10: 06:30 UTC is 01:30
+	// EST (open), 07:30 UTC is 03:30 EDT (closed), 09:00 UTC is 05:00 EDT.
+	// A fixed UTC offset would get the last two wrong.
+	spring := []struct {
+		at   time.Time
+		open bool
+	}{
+		{time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 3, 10, 7, 30, 0, 0, time.UTC), false},
+		{time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC), true},
+	}
+	for _, tc := range spring {
+		if got := OpenAt(hours, tc.at); got != tc.open {
+			t.Fatalf("spring OpenAt(%v) = %v, want %v", tc.at, got, tc.open)
+		}
+	}
+
+	// Clocks fall back from 02:00 to 01:00 on 2024-11-03. 06:30 UTC is the
+	// second 01:30 (EST) and still open; 07:30 UTC is 02:30 EST.
+	fall := []struct {
+		at   time.Time
+		open bool
+	}{
+		{time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 11, 3, 7, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 11, 3, 8, 30, 0, 0, time.UTC), false},
+		{time.Date(2024, 11, 3, 10, 0, 0, 0, time.UTC), true},
+	}
+	for _, tc := range fall {
+		if got := OpenAt(hours, tc.at); got != tc.open {
+			t.Fatalf("fall OpenAt(%v) = %v, want %v", tc.at, got, tc.open)
+		}
+	}
+}
+
+func TestHolidaysOverrideSchedule(t *testing.T) {
+	hours := models.OpeningHours{
+		Timezone: "UTC",
+		Open24x7: true,
+		Holidays: []models.GymHoliday{
+			{Date: "2024-12-25", Name: "Christmas"},
+			{Date: "2024-12-31", Intervals: []models.HoursInterval{{Opens: "20:00", Closes: "01:00"}}},
+		},
+	}
+	if err := Validate(&hours); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	cases := []struct {
+		at   time.Time
+		open bool
+	}{
+		{time.Date(2024, 12, 24, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 12, 26, 0, 30, 0, 0, time.UTC), true},
+		{time.Date(2024, 12, 31, 19, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 12, 31, 21, 0, 0, 0, time.UTC), true},
+		// The holiday's overnight interval carries into New Year's Day,
+		// which falls back to 24/7.
+		{time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC), true},
+		{time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC), true},
+	}
+	for _, tc := range cases {
+		if got := OpenAt(hours, tc.at); got != tc.open {
+			t.Fatalf("OpenAt(%v) = %v, want %v", tc.at, got, tc.open)
+		}
+	}
+}
+
+func TestValidateNormalizes(t *testing.T) {
+	hours := models.OpeningHours{
+		Weekly: map[string][]models.HoursInterval{
+			" Monday ": {{Opens: "17:00", Closes: "00:00"}, {Opens: "6:00", Closes: "12:00"}},
+			"tuesday":  {},
+		},
+		Holidays: []models.GymHoliday{{Date: "2024-12-31"}, {Date: "2024-12-25"}},
+	}
+	if err := Validate(&hours); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	monday := hours.Weekly["monday"]
+	if hours.Timezone != "UTC" || len(hours.Weekly) != 1 || len(monday) != 2 {
+		t.Fatalf("unexpected hours %+v", hours)
+	}
+	if monday[0] != (models.HoursInterval{Opens: "06:00", Closes: "12:00"}) || monday[1].Closes != "24:00" {
+		t.Fatalf("unexpected monday %+v", monday)
+	}
+	if hours.Holidays[0].Date != "2024-12-25" || hours.Holidays[0].Intervals == nil {
+		t.Fatalf("unexpected holidays %+v", hours.Holidays)
+	}
+	if !Published(hours) || Published(models.OpeningHours{Timezone: "UTC"}) {
+		t.Fatal("unexpected Published result")
+	}
+}
+
+func TestValidateRejectsBadHours(t *testing.T) {
+	interval := func(opens, closes string) []models.HoursInterval {
+		return []models.HoursInterval{{Opens: opens, Closes: closes}}
+	}
+	cases := map[string]models.OpeningHours{
+		"unknown timezone": {Timezone: "Mars/Olympus"},
+		"unknown weekday":  {Weekly: map[string][]models.HoursInterval{"funday": interval("06:00", "10:00")}},
+		"bad time":         {Weekly: map[string][]models.HoursInterval{"monday": interval("25:00", "10:00")}},
+		"empty interval":   {Weekly: map[string][]models.HoursInterval{"monday": interval("06:00", "06:00")}},
+		"overlap": {Weekly: map[string][]models.HoursInterval{"monday": {
+			{Opens: "06:00", Closes: "12:00"}, {Opens: "11:00", Closes: "14:00"},
+		}}},
+		"overnight not last": {Weekly: map[string][]models.HoursInterval{"monday": {
+			{Opens: "06:00", Closes: "01:00"}, {Opens: "20:00", Closes: "22:00"},
+		}}},
+		"bad holiday date":  {Holidays: []models.GymHoliday{{Date: "25/12/2024"}}},
+		"duplicate holiday": {Holidays: []models.GymHoliday{{Date: "2024-12-25"}, {Date: "2024-12-25"}}},
+	}
+	for name, hours := range cases {
+		if err := Validate(&hours); err == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/storage"
+	"fitonex/backend/internal/store/bodymetrics"
 )
 
 func (h *Handlers) ExportAccount(w http.ResponseWriter, r *http.Request) {
@@ -22,6 +26,10 @@ func (h *Handlers) ExportAccount(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export workouts"))
 		return
 	}
+	if err := h.attachWorkoutExercises(userID, workouts); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export workouts"))
+		return
+	}
 	checkins, err := h.store.Checkins.ExportByUser(userID)
 	if err != nil {
 		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export checkins"))
@@ -37,12 +45,60 @@ func (h *Handlers) ExportAccount(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export reviews"))
 		return
 	}
+	templates, err := h.store.Templates.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export templates"))
+		return
+	}
+	programs, err := h.store.Programs.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export programs"))
+		return
+	}
+	imports, err := h.store.Imports.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export imports"))
+		return
+	}
+	bodyMetrics, err := h.store.Body.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export body metrics"))
+		return
+	}
+	goalWeight, err := h.store.Body.GetGoal(userID)
+	if err != nil && !errors.Is(err, bodymetrics.ErrGoalNotFound) {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export goal weight"))
+		return
+	}
+	goals, err := h.store.Goals.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export goals"))
+		return
+	}
+	tracks, err := h.store.Tracks.ExportByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export tracks"))
+		return
+	}
+	gymClaims, err := h.store.Gyms.ExportClaimsByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to export gym claims"))
+		return
+	}
 	response := map[string]any{
 		"exported_at": time.Now().UTC(),
 		"workouts":   workouts,
+		"templates":  templates,
+		"programs":   programs,
+		"imports":    imports,
 		"checkins":   checkins,
 		"videos":     videos,
 		"reviews":    reviews,
+		"body_metrics": bodyMetrics,
+		"goals":        goals,
+		"cardio_tracks": tracks,
+		"gym_claims":    gymClaims,
+		"goal_weight":  goalWeight,
 	}
 	httpx.WriteJSON(w, http.StatusOK, response)
 }
@@ -57,17 +113,73 @@ func (h *Handlers) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to remove workouts"))
 		return
 	}
+
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		if service, err := storage.NewS3Service(h.config); err == nil {
+			h.SetObjectStorage(service)
+			storageSvc = service
+		} else {
+			log.Printf("failed to initialize storage deleting account %s: %v", userID, err)
+		}
+	}
+
 	_ = h.store.Checkins.DeleteByUser(userID)
+	if h.store.Programs != nil {
+		_ = h.store.Programs.DeleteByUser(userID)
+	}
+	if h.store.Templates != nil {
+		_ = h.store.Templates.DeleteByUser(userID)
+	}
+	if h.store.Imports != nil {
+		_ = h.store.Imports.DeleteByUser(userID)
+	}
 	if h.store.Videos != nil {
 		_ = h.store.Videos.AnonymizeByUser(userID)
 		_ = h.store.Videos.DeleteLikesByUser(userID)
 	}
 	if h.store.Gyms != nil {
 		_ = h.store.Gyms.AnonymizeReviewsByUser(userID)
+		_ = h.store.Gyms.DeleteReviewVotesByUser(userID)
+		// Photo rows are kept when storage is unavailable so their objects
+		// are not orphaned.
+		if storageSvc != nil {
+			if photos, err := h.store.Gyms.DeleteReviewPhotosByUser(userID); err == nil {
+				for i := range photos {
+					_ = storageSvc.DeleteObject(r.Context(), photos[i].ObjectKey)
+					deleteReviewPhotoVariants(r.Context(), storageSvc, &photos[i])
+				}
+			}
+		}
+		_ = h.store.Gyms.DeleteClaimsByUser(userID)
 	}
 	if h.store.Comments != nil {
 		_ = h.store.Comments.DeleteByUser(userID)
 	}
+	if h.store.Sync != nil {
+		_ = h.store.Sync.DeleteByUser(userID)
+	}
+	if h.store.Sessions != nil {
+		_ = h.store.Sessions.DeleteByUser(userID)
+	}
+	if h.store.Training != nil {
+		_ = h.store.Training.DeleteByUser(userID)
+	}
+	if h.store.Body != nil {
+		_ = h.store.Body.DeleteByUser(userID)
+	}
+	if h.store.Goals != nil {
+		_ = h.store.Goals.DeleteByUser(userID)
+	}
+	// Track rows are kept when storage is unavailable so the GPS files they
+	// point at are not orphaned.
+	if h.store.Tracks != nil && storageSvc != nil {
+		if keys, err := h.store.Tracks.DeleteByUser(userID); err == nil {
+			for _, key := range keys {
+				_ = storageSvc.DeleteObject(r.Context(), key)
+			}
+		}
+	}
 	_ = h.store.Users.ClearPremium(userID)
 	if err := h.store.Users.SoftDelete(userID); err != nil {
 		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to delete account"))
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"fitonex/backend/internal/auth"
+	"fitonex/backend/internal/httpx"
 	"fitonex/backend/internal/models"
 )
 
@@ -148,3 +149,19 @@ func (h *Handlers) AuthMiddleware(next http.Handler) http.Handler {
 
 	})
 }
+
+// AdminMiddleware only lets admins through. It runs after AuthMiddleware.
+func (h *Handlers) AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := currentUserFromContext(r)
+		if !ok {
+			httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+			return
+		}
+		if !user.IsAdmin() {
+			httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "admin access required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,612 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	"fitonex/backend/internal/store/bodymetrics"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	cmPerIn = 2.54
+
+	defaultBodyMetricLimit = 50
+	maxBodyMetricLimit     = 200
+	maxBodyMetricNotes     = 500
+
+	defaultTrendDays   = 90
+	maxTrendDays       = 730
+	defaultTrendWindow = 7
+	maxTrendWindow     = 90
+)
+
+// BodyMetricRequest represents a body measurement payload. Unit defaults to
+// the user's preferred unit for the type.
+type BodyMetricRequest struct {
+	Type       string     `json:"type"`
+	Value      float64    `json:"value"`
+	Unit       string     `json:"unit,omitempty"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// BodyWeightGoalRequest represents the goal weight payload
+type BodyWeightGoalRequest struct {
+	Target     float64  `json:"target"`
+	Start      *float64 `json:"start,omitempty"`
+	Unit       string   `json:"unit,omitempty"`
+	TargetDate string   `json:"target_date,omitempty"`
+}
+
+// UnitsRequest represents the unit preference payload
+type UnitsRequest struct {
+	WeightUnit string `json:"weight_unit"`
+	LengthUnit string `json:"length_unit"`
+}
+
+// bodyUnits are the units a user reads and enters body metrics in
+type bodyUnits struct {
+	weight string
+	length string
+}
+
+// forType returns the preferred unit for a metric type
+func (u bodyUnits) forType(metricType string) string {
+	switch models.BodyMetricBaseUnit(metricType) {
+	case models.UnitKg:
+		return u.weight
+	case models.UnitCm:
+		return u.length
+	}
+	return models.UnitPercent
+}
+
+// CreateBodyMetric records a body weight, body fat or circumference measurement
+func (h *Handlers) CreateBodyMetric(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req BodyMetricRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+	value, recordedAt, apiErr := normalizeBodyMetricRequest(&req, units, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	metric, err := h.store.Body.Create(userID, req.Type, value, recordedAt, req.Notes)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to record body metric"))
+		return
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "body_metric_recorded", map[string]any{
+			"type": metric.Type,
+		})
+	}
+
+	presentBodyMetric(metric, units)
+	httpx.WriteJSON(w, http.StatusCreated, metric)
+}
+
+// GetBodyMetrics lists the caller's measurements, newest first
+func (h *Handlers) GetBodyMetrics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	values := r.URL.Query()
+	var filter bodymetrics.MetricFilter
+	if metricType := optionalQueryParam(r, "type"); metricType != nil {
+		if models.BodyMetricBaseUnit(*metricType) == "" {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unknown body metric type")
+			return
+		}
+		filter.Type = metricType
+	}
+	if fromStr := strings.TrimSpace(values.Get("from")); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+			return
+		}
+		end := to.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	limit := defaultBodyMetricLimit
+	if limitParam := strings.TrimSpace(values.Get("limit")); limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(value, maxBodyMetricLimit)
+	}
+
+	var cursorPtr *pagination.TimeDescCursor
+	if cursorStr := strings.TrimSpace(values.Get("cursor")); cursorStr != "" {
+		cursor, err := pagination.DecodeCursor[pagination.TimeDescCursor](cursorStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
+		}
+		cursorPtr = &cursor
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	page, err := h.store.Body.List(userID, filter, limit, cursorPtr)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch body metrics"))
+		return
+	}
+	for i := range page.Items {
+		presentBodyMetric(&page.Items[i], units)
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, page)
+}
+
+// UpdateBodyMetric corrects a measurement's value, time or notes. The type
+// cannot change.
+func (h *Handlers) UpdateBodyMetric(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req BodyMetricRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	metricID := chi.URLParam(r, "id")
+	existing, err := h.store.Body.GetByID(userID, metricID)
+	if err != nil {
+		writeBodyMetricError(w, err, "failed to fetch body metric")
+		return
+	}
+	if req.Type == "" {
+		req.Type = existing.Type
+	}
+	if req.Type != existing.Type {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "type cannot be changed")
+		return
+	}
+	if req.RecordedAt == nil {
+		req.RecordedAt = &existing.RecordedAt
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+	value, recordedAt, apiErr := normalizeBodyMetricRequest(&req, units, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	metric, err := h.store.Body.Update(userID, metricID, value, recordedAt, req.Notes)
+	if err != nil {
+		writeBodyMetricError(w, err, "failed to update body metric")
+		return
+	}
+
+	presentBodyMetric(metric, units)
+	httpx.WriteJSON(w, http.StatusOK, metric)
+}
+
+// DeleteBodyMetric removes a measurement
+func (h *Handlers) DeleteBodyMetric(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.store.Body.Delete(userID, chi.URLParam(r, "id")); err != nil {
+		writeBodyMetricError(w, err, "failed to delete body metric")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBodyMetricTrend returns daily values of a metric with a moving average,
+// and the goal weight's progress for weight.
+func (h *Handlers) GetBodyMetricTrend(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	metricType, from, to, window, apiErr := parseBodyMetricTrendQuery(r, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	trend, err := h.store.Body.Trend(userID, metricType, from, to, window)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch body metric trend"))
+		return
+	}
+
+	unit := units.forType(metricType)
+	for i := range trend.Points {
+		trend.Points[i].Value = convertBodyValue(trend.Points[i].Value, trend.Unit, unit)
+		trend.Points[i].MovingAverage = convertBodyValue(trend.Points[i].MovingAverage, trend.Unit, unit)
+	}
+	if trend.Latest != nil {
+		latest := convertBodyValue(*trend.Latest, trend.Unit, unit)
+		trend.Latest = &latest
+	}
+	if trend.Change != nil {
+		change := convertBodyValue(*trend.Change, trend.Unit, unit)
+		trend.Change = &change
+	}
+	trend.Unit = unit
+	if trend.Goal != nil {
+		presentBodyWeightGoal(trend.Goal, units)
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, trend)
+}
+
+// GetBodyWeightGoal returns the caller's goal weight and progress towards it
+func (h *Handlers) GetBodyWeightGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	goal, err := h.store.Body.GetGoal(userID)
+	if err != nil {
+		writeBodyMetricError(w, err, "failed to fetch goal weight")
+		return
+	}
+
+	presentBodyWeightGoal(goal, units)
+	httpx.WriteJSON(w, http.StatusOK, goal)
+}
+
+// SetBodyWeightGoal creates or replaces the caller's goal weight
+func (h *Handlers) SetBodyWeightGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req BodyWeightGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(req.Unit))
+	if unit == "" {
+		unit = units.weight
+	}
+	if unit != models.UnitKg && unit != models.UnitLb {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit must be kg or lb")
+		return
+	}
+	targetKg := convertBodyValue(req.Target, unit, models.UnitKg)
+	if apiErr := validateBodyMetricValue(models.BodyMetricWeight, targetKg); apiErr != nil {
+		httpx.WriteAPIError(w, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target "+apiErr.Message))
+		return
+	}
+	var startKg *float64
+	if req.Start != nil {
+		value := convertBodyValue(*req.Start, unit, models.UnitKg)
+		if apiErr := validateBodyMetricValue(models.BodyMetricWeight, value); apiErr != nil {
+			httpx.WriteAPIError(w, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "start "+apiErr.Message))
+			return
+		}
+		startKg = &value
+	}
+	var targetDate *time.Time
+	if dateStr := strings.TrimSpace(req.TargetDate); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target_date must use YYYY-MM-DD format")
+			return
+		}
+		targetDate = &parsed
+	}
+
+	goal, err := h.store.Body.SetGoal(userID, targetKg, startKg, targetDate)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to set goal weight"))
+		return
+	}
+
+	presentBodyWeightGoal(goal, units)
+	httpx.WriteJSON(w, http.StatusOK, goal)
+}
+
+// DeleteBodyWeightGoal clears the caller's goal weight
+func (h *Handlers) DeleteBodyWeightGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.store.Body.DeleteGoal(userID); err != nil {
+		writeBodyMetricError(w, err, "failed to delete goal weight")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateUnits sets the units body metrics are shown and entered in
+func (h *Handlers) UpdateUnits(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UnitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	req.WeightUnit = strings.ToLower(strings.TrimSpace(req.WeightUnit))
+	req.LengthUnit = strings.ToLower(strings.TrimSpace(req.LengthUnit))
+	if req.WeightUnit != models.UnitKg && req.WeightUnit != models.UnitLb {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "weight_unit must be kg or lb")
+		return
+	}
+	if req.LengthUnit != models.UnitCm && req.LengthUnit != models.UnitIn {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "length_unit must be cm or in")
+		return
+	}
+
+	user, err := h.store.Users.UpdateUnits(userID, req.WeightUnit, req.LengthUnit)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to update units"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, user)
+}
+
+// bodyUnits reads the user's unit preferences
+func (h *Handlers) bodyUnits(userID string) (bodyUnits, error) {
+	user, err := h.store.Users.GetByID(userID)
+	if err != nil {
+		return bodyUnits{}, err
+	}
+	units := bodyUnits{weight: user.WeightUnit, length: user.LengthUnit}
+	if units.weight == "" {
+		units.weight = models.UnitKg
+	}
+	if units.length == "" {
+		units.length = models.UnitCm
+	}
+	return units, nil
+}
+
+// normalizeBodyMetricRequest validates a measurement and returns its value in
+// the type's base unit and when it was taken.
+func normalizeBodyMetricRequest(req *BodyMetricRequest, units bodyUnits, now time.Time) (float64, time.Time, *httpx.APIError) {
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	baseUnit := models.BodyMetricBaseUnit(req.Type)
+	if baseUnit == "" {
+		return 0, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "type must be weight, body_fat, waist, chest, hips, neck, arm, thigh or calf")
+	}
+
+	req.Unit = strings.ToLower(strings.TrimSpace(req.Unit))
+	if req.Unit == "" {
+		req.Unit = units.forType(req.Type)
+	}
+	if !compatibleBodyUnit(baseUnit, req.Unit) {
+		return 0, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit "+req.Unit+" cannot be used for "+req.Type)
+	}
+	value := convertBodyValue(req.Value, req.Unit, baseUnit)
+	if apiErr := validateBodyMetricValue(req.Type, value); apiErr != nil {
+		return 0, time.Time{}, apiErr
+	}
+
+	recordedAt := now
+	if req.RecordedAt != nil {
+		recordedAt = req.RecordedAt.UTC()
+		if recordedAt.After(now.Add(24 * time.Hour)) {
+			return 0, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "recorded_at cannot be in the future")
+		}
+	}
+
+	req.Notes = trimmedOptional(req.Notes)
+	if req.Notes != nil && len(*req.Notes) > maxBodyMetricNotes {
+		return 0, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "notes must be at most 500 characters")
+	}
+	return value, recordedAt, nil
+}
+
+// validateBodyMetricValue rejects values outside a plausible range, in the
+// type's base unit.
+func validateBodyMetricValue(metricType string, value float64) *httpx.APIError {
+	var limit float64
+	switch models.BodyMetricBaseUnit(metricType) {
+	case models.UnitKg:
+		limit = 700
+	case models.UnitPercent:
+		limit = 100
+	default:
+		limit = 500
+	}
+	if value <= 0 || value > limit || math.IsNaN(value) {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "value is out of range")
+	}
+	return nil
+}
+
+func parseBodyMetricTrendQuery(r *http.Request, now time.Time) (string, time.Time, time.Time, int, *httpx.APIError) {
+	values := r.URL.Query()
+
+	metricType := strings.ToLower(strings.TrimSpace(values.Get("type")))
+	if metricType == "" {
+		metricType = models.BodyMetricWeight
+	}
+	if models.BodyMetricBaseUnit(metricType) == "" {
+		return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unknown body metric type")
+	}
+
+	to := now.Truncate(24 * time.Hour)
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+	to = to.AddDate(0, 0, 1)
+
+	from := to.AddDate(0, 0, -defaultTrendDays)
+	if fromStr := strings.TrimSpace(values.Get("from")); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must not be after to")
+	}
+	if to.Sub(from) > maxTrendDays*24*time.Hour {
+		return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "range must be at most 730 days")
+	}
+
+	window := defaultTrendWindow
+	if windowParam := strings.TrimSpace(values.Get("window")); windowParam != "" {
+		value, err := strconv.Atoi(windowParam)
+		if err != nil || value <= 0 || value > maxTrendWindow {
+			return "", time.Time{}, time.Time{}, 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "window must be between 1 and 90")
+		}
+		window = value
+	}
+	return metricType, from, to, window, nil
+}
+
+func compatibleBodyUnit(baseUnit, unit string) bool {
+	switch baseUnit {
+	case models.UnitKg:
+		return unit == models.UnitKg || unit == models.UnitLb
+	case models.UnitCm:
+		return unit == models.UnitCm || unit == models.UnitIn
+	}
+	return unit == baseUnit
+}
+
+// convertBodyValue converts between kilograms and pounds or centimetres and
+// inches, rounded to two decimals. Other pairs are returned unchanged.
+func convertBodyValue(value float64, from, to string) float64 {
+	switch {
+	case from == models.UnitKg && to == models.UnitLb:
+		return convertWeight(value, to)
+	case from == models.UnitLb && to == models.UnitKg:
+		value /= lbPerKg
+	case from == models.UnitCm && to == models.UnitIn:
+		value /= cmPerIn
+	case from == models.UnitIn && to == models.UnitCm:
+		value *= cmPerIn
+	}
+	return math.Round(value*100) / 100
+}
+
+func presentBodyMetric(metric *models.BodyMetric, units bodyUnits) {
+	unit := units.forType(metric.Type)
+	metric.Value = convertBodyValue(metric.Value, metric.Unit, unit)
+	metric.Unit = unit
+}
+
+func presentBodyWeightGoal(goal *models.BodyWeightGoal, units bodyUnits) {
+	convert := func(value *float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		converted := convertBodyValue(*value, goal.Unit, units.weight)
+		return &converted
+	}
+	goal.Target = convertBodyValue(goal.Target, goal.Unit, units.weight)
+	goal.Start = convert(goal.Start)
+	goal.Current = convert(goal.Current)
+	goal.Remaining = convert(goal.Remaining)
+	goal.Unit = units.weight
+}
+
+func writeBodyMetricError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, bodymetrics.ErrMetricNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "body metric not found")
+	case errors.Is(err, bodymetrics.ErrGoalNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "goal weight not found")
+	default:
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+	}
+}
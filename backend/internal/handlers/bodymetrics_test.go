@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestNormalizeBodyMetricRequestConvertsUnits(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	imperial := bodyUnits{weight: models.UnitLb, length: models.UnitIn}
+
+	req := BodyMetricRequest{Type: " Weight ", Value: 180}
+	value, recordedAt, apiErr := normalizeBodyMetricRequest(&req, imperial, now)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if req.Type != models.BodyMetricWeight || req.Unit != models.UnitLb || value != 81.65 || !recordedAt.Equal(now) {
+		t.Fatalf("unexpected weight %v %v %+v", value, recordedAt, req)
+	}
+
+	req = BodyMetricRequest{Type: models.BodyMetricWaist, Value: 80, Unit: "cm"}
+	if value, _, apiErr = normalizeBodyMetricRequest(&req, imperial, now); apiErr != nil || value != 80 {
+		t.Fatalf("an explicit unit must win over the preference, got %v %v", value, apiErr)
+	}
+
+	req = BodyMetricRequest{Type: models.BodyMetricBodyFat, Value: 18.5}
+	if value, _, apiErr = normalizeBodyMetricRequest(&req, imperial, now); apiErr != nil || value != 18.5 || req.Unit != models.UnitPercent {
+		t.Fatalf("unexpected body fat %v %+v %v", value, req, apiErr)
+	}
+
+	future := now.Add(48 * time.Hour)
+	for name, req := range map[string]BodyMetricRequest{
+		"unknown type":    {Type: "shoe_size", Value: 10},
+		"wrong unit":      {Type: models.BodyMetricWeight, Value: 80, Unit: "cm"},
+		"zero value":      {Type: models.BodyMetricWeight},
+		"body fat > 100%": {Type: models.BodyMetricBodyFat, Value: 120},
+		"future":          {Type: models.BodyMetricWeight, Value: 80, RecordedAt: &future},
+	} {
+		if _, _, apiErr := normalizeBodyMetricRequest(&req, imperial, now); apiErr == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestConvertBodyValue(t *testing.T) {
+	cases := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{100, models.UnitKg, models.UnitLb, 220.46},
+		{220.46, models.UnitLb, models.UnitKg, 100},
+		{2.54, models.UnitCm, models.UnitIn, 1},
+		{10, models.UnitIn, models.UnitCm, 25.4},
+		{18.456, models.UnitPercent, models.UnitPercent, 18.46},
+	}
+	for _, c := range cases {
+		if got := convertBodyValue(c.value, c.from, c.to); got != c.want {
+			t.Fatalf("convertBodyValue(%v, %s, %s) = %v, want %v", c.value, c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestParseBodyMetricTrendQuery(t *testing.T) {
+	now := time.Date(2024, 5, 8, 15, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/body-metrics/trends", nil)
+	metricType, from, to, window, apiErr := parseBodyMetricTrendQuery(req, now)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if metricType != models.BodyMetricWeight || window != defaultTrendWindow || !to.Equal(time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)) || to.Sub(from) != defaultTrendDays*24*time.Hour {
+		t.Fatalf("unexpected defaults %s %v %v %d", metricType, from, to, window)
+	}
+
+	for _, raw := range []string{"type=shoe_size", "window=0", "window=91", "from=2024-05-10&to=2024-05-01", "from=2020-01-01&to=2024-01-01", "to=May"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/body-metrics/trends?"+raw, nil)
+		if _, _, _, _, apiErr := parseBodyMetricTrendQuery(req, now); apiErr == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
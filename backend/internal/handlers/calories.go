@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/calories"
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/bodymetrics"
+)
+
+const (
+	defaultSummaryDays = 7
+	maxSummaryDays     = 92
+)
+
+// GetDailySummary totals the caller's workouts and estimated calories per
+// UTC day, including days without workouts
+func (h *Handlers) GetDailySummary(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	from, to, apiErr := parseSummaryRange(r, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	workouts, err := h.store.Workouts.ListBetween(userID, from, to)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workouts"))
+		return
+	}
+	if err := h.attachWorkoutExercises(userID, workouts); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workout exercises"))
+		return
+	}
+	weightKg, weightSource, err := h.bodyWeightKg(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch body weight"))
+		return
+	}
+
+	summary := buildDailySummary(workouts, from, to)
+	summary.BodyWeightKg = weightKg
+	summary.BodyWeightSource = weightSource
+	httpx.WriteJSON(w, http.StatusOK, summary)
+}
+
+// attachCalories estimates calories for workouts whose totals are attached,
+// using the user's latest recorded weight. Calories are an estimate, so a
+// failure leaves them empty and is logged rather than failing the request.
+func (h *Handlers) attachCalories(userID string, workouts []models.Workout) {
+	if len(workouts) == 0 {
+		return
+	}
+	if err := h.estimateCalories(userID, workouts); err != nil {
+		log.Printf("failed to estimate calories for user %s: %v", userID, err)
+	}
+}
+
+func (h *Handlers) estimateCalories(userID string, workouts []models.Workout) error {
+	weightKg, weightSource, err := h.bodyWeightKg(userID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var machineIDs []string
+	for _, workout := range workouts {
+		for _, exercise := range workout.Exercises {
+			if exercise.MachineID != nil && !seen[*exercise.MachineID] {
+				seen[*exercise.MachineID] = true
+				machineIDs = append(machineIDs, *exercise.MachineID)
+			}
+		}
+	}
+	machines, err := h.store.Machines.GetByIDs(machineIDs)
+	if err != nil {
+		return err
+	}
+
+	table := calories.Default()
+	for i := range workouts {
+		// Nothing has been burned before a planned workout is performed.
+		if workouts[i].Planned {
+			continue
+		}
+		table.Estimate(&workouts[i], weightKg, weightSource, machines)
+	}
+	return nil
+}
+
+// bodyWeightKg returns the user's latest recorded weight, or the default
+// weight when they have none.
+func (h *Handlers) bodyWeightKg(userID string) (float64, string, error) {
+	metric, err := h.store.Body.Latest(userID, models.BodyMetricWeight)
+	if err != nil {
+		if errors.Is(err, bodymetrics.ErrMetricNotFound) {
+			return calories.DefaultBodyWeightKg, models.BodyWeightSourceDefault, nil
+		}
+		return 0, "", err
+	}
+	return metric.Value, models.BodyWeightSourceMetrics, nil
+}
+
+// parseSummaryRange reads the inclusive from and to dates, defaulting to the
+// last seven days, and returns them as [from, to + 1 day).
+func parseSummaryRange(r *http.Request, now time.Time) (time.Time, time.Time, *httpx.APIError) {
+	values := r.URL.Query()
+
+	to := now.Truncate(24 * time.Hour)
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+	to = to.AddDate(0, 0, 1)
+
+	from := to.AddDate(0, 0, -defaultSummaryDays)
+	if fromStr := strings.TrimSpace(values.Get("from")); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must not be after to")
+	}
+	if to.Sub(from) > maxSummaryDays*24*time.Hour {
+		return time.Time{}, time.Time{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "range must be at most 92 days")
+	}
+	return from, to, nil
+}
+
+// buildDailySummary groups workouts with totals attached into one entry per
+// day in [from, to).
+func buildDailySummary(workouts []models.Workout, from, to time.Time) models.DailySummaryResponse {
+	byDay := make(map[string]*models.DailySummary)
+	summary := models.DailySummaryResponse{
+		From: from.Format("2006-01-02"),
+		To:   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days: []models.DailySummary{},
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		summary.Days = append(summary.Days, models.DailySummary{Date: day.Format("2006-01-02")})
+	}
+	for i := range summary.Days {
+		byDay[summary.Days[i].Date] = &summary.Days[i]
+	}
+
+	for _, workout := range workouts {
+		day, ok := byDay[workout.CreatedAt.UTC().Format("2006-01-02")]
+		if !ok || workout.Totals == nil {
+			continue
+		}
+		day.Workouts++
+		day.DurationMinutes += workout.Totals.DurationMinutes
+		day.Sets += workout.Totals.Sets
+		day.VolumeKg += workout.Totals.VolumeKg
+		if workout.Totals.Calories != nil {
+			day.CaloriesKcal += workout.Totals.Calories.Kcal
+		}
+	}
+
+	for i := range summary.Days {
+		summary.Days[i].VolumeKg = math.Round(summary.Days[i].VolumeKg*100) / 100
+		summary.Days[i].CaloriesKcal = math.Round(summary.Days[i].CaloriesKcal*10) / 10
+		summary.CaloriesKcal += summary.Days[i].CaloriesKcal
+	}
+	summary.CaloriesKcal = math.Round(summary.CaloriesKcal*10) / 10
+	return summary
+}
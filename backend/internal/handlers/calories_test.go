@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestBuildDailySummary(t *testing.T) {
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+	workouts := []models.Workout{
+		{CreatedAt: from.Add(8 * time.Hour), Totals: &models.WorkoutTotals{DurationMinutes: 40, Sets: 12, VolumeKg: 1500.5, Calories: &models.CalorieEstimate{Kcal: 250.4}}},
+		{CreatedAt: from.Add(18 * time.Hour), Totals: &models.WorkoutTotals{DurationMinutes: 20, Sets: 4, Calories: &models.CalorieEstimate{Kcal: 100.3}}},
+		{CreatedAt: from.AddDate(0, 0, 2), Totals: &models.WorkoutTotals{DurationMinutes: 30}},
+	}
+
+	summary := buildDailySummary(workouts, from, to)
+	if summary.From != "2024-05-06" || summary.To != "2024-05-08" || len(summary.Days) != 3 {
+		t.Fatalf("unexpected range %+v", summary)
+	}
+	first := summary.Days[0]
+	if first.Workouts != 2 || first.DurationMinutes != 60 || first.Sets != 16 || first.VolumeKg != 1500.5 || first.CaloriesKcal != 350.7 {
+		t.Fatalf("unexpected first day %+v", first)
+	}
+	if summary.Days[1].Workouts != 0 || summary.Days[2].Workouts != 1 || summary.CaloriesKcal != 350.7 {
+		t.Fatalf("unexpected days %+v", summary.Days)
+	}
+}
+
+func TestParseSummaryRange(t *testing.T) {
+	now := time.Date(2024, 5, 8, 15, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/summary/daily", nil)
+	from, to, apiErr := parseSummaryRange(req, now)
+	if apiErr != nil || !to.Equal(time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)) || !from.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected default range %v %v %v", from, to, apiErr)
+	}
+
+	for _, raw := range []string{"from=2024-05-10&to=2024-05-01", "from=2024-01-01&to=2024-05-01", "to=May"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/summary/daily?"+raw, nil)
+		if _, _, apiErr := parseSummaryRange(req, now); apiErr == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/pagination"
+	catalogstore "fitonex/backend/internal/store/catalog"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultCatalogLimit = 20
+	maxCatalogLimit     = 100
+)
+
+// SearchCatalog lists catalog exercises, ranked by name and alias similarity
+// when a query is given and optionally narrowed by muscle and equipment.
+func (h *Handlers) SearchCatalog(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	limit := defaultCatalogLimit
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(value, maxCatalogLimit)
+	}
+
+	var cursor *pagination.ScoreDescCursor
+	if raw := strings.TrimSpace(values.Get("cursor")); raw != "" {
+		value, err := pagination.DecodeCursor[pagination.ScoreDescCursor](raw)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
+		}
+		cursor = &value
+	}
+
+	page, err := h.store.Catalog.Search(values.Get("query"), values.Get("muscle"), values.Get("equipment"), limit, cursor)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to search catalog"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, page)
+}
+
+// GetCatalogExercise returns one catalog exercise with its linked machines and videos
+func (h *Handlers) GetCatalogExercise(w http.ResponseWriter, r *http.Request) {
+	item, err := h.store.Catalog.GetByID(chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, catalogstore.ErrCatalogExerciseNotFound) {
+			httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "catalog exercise not found")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch catalog exercise"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, item)
+}
+
+// resolveCatalogExercise checks that a requested catalog_id exists. An empty
+// name is filled in from the catalog entry; a custom name is kept as typed.
+func (h *Handlers) resolveCatalogExercise(catalogID *string, name string) (*string, string, *httpx.APIError) {
+	catalogID = trimmedOptional(catalogID)
+	if catalogID == nil {
+		return nil, name, nil
+	}
+
+	item, err := h.store.Catalog.GetByID(*catalogID)
+	if err != nil {
+		if errors.Is(err, catalogstore.ErrCatalogExerciseNotFound) {
+			return nil, name, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "catalog_id does not match a catalog exercise")
+		}
+		return nil, name, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch catalog exercise")
+	}
+
+	if name == "" {
+		name = item.Name
+	}
+	return catalogID, name, nil
+}
@@ -11,21 +11,28 @@ import (
 	"fitonex/backend/internal/httpx"
 	"fitonex/backend/internal/models"
 	"fitonex/backend/internal/pagination"
+	exercisesstore "fitonex/backend/internal/store/exercises"
 
 	"github.com/go-chi/chi/v5"
 )
 
 const (
-	defaultExerciseLimit = 20
-	maxExerciseLimit     = 50
+	defaultExerciseLimit  = 20
+	maxExerciseLimit      = 50
+	maxSetDurationSeconds = 24 * 60 * 60
+	maxRestSeconds        = 60 * 60
+	maxGroupIDLength      = 64
 )
 
 // CreateExerciseRequest represents the create exercise request
 type CreateExerciseRequest struct {
 	Day       string      `json:"day"`
+	WorkoutID *string     `json:"workout_id,omitempty"`
 	GymID     *string     `json:"gym_id,omitempty"`
 	MachineID *string     `json:"machine_id,omitempty"`
+	CatalogID *string     `json:"catalog_id,omitempty"`
 	Name      string      `json:"name"`
+	Group     *models.ExerciseGroup `json:"group,omitempty"`
 	Sets      []models.Set `json:"sets"`
 }
 
@@ -46,6 +53,13 @@ func (h *Handlers) CreateExercise(w http.ResponseWriter, r *http.Request) {
     req.Day = strings.TrimSpace(req.Day)
     req.Name = strings.TrimSpace(req.Name)
 
+    catalogID, name, apiErr := h.resolveCatalogExercise(req.CatalogID, req.Name)
+    if apiErr != nil {
+        httpx.WriteAPIError(w, apiErr)
+        return
+    }
+    req.Name = name
+
     if req.Day == "" || req.Name == "" || len(req.Sets) == 0 {
         httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "day, name, and sets are required")
         return
@@ -57,49 +71,107 @@ func (h *Handlers) CreateExercise(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    for i, set := range req.Sets {
-        if set.Reps <= 0 {
-            httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "reps must be greater than 0")
-            return
-        }
-        if set.WeightKg != nil && *set.WeightKg < 0 {
-            httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "weight must be non-negative")
-            return
-        }
-        if set.RPE != nil && (*set.RPE < 1 || *set.RPE > 10) {
-            httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rpe must be between 1 and 10")
-            return
-        }
+    if apiErr := validateExerciseSets(req.Sets); apiErr != nil {
+        httpx.WriteAPIError(w, apiErr)
+        return
     }
-
-    var gymID *string
-    if req.GymID != nil {
-        trimmed := strings.TrimSpace(*req.GymID)
-        if trimmed != "" {
-            gymID = &trimmed
-        }
+    group, apiErr := normalizeExerciseGroup(req.Group)
+    if apiErr != nil {
+        httpx.WriteAPIError(w, apiErr)
+        return
     }
 
-    var machineID *string
-    if req.MachineID != nil {
-        trimmed := strings.TrimSpace(*req.MachineID)
-        if trimmed != "" {
-            machineID = &trimmed
-        }
-    }
+    gymID := trimmedOptional(req.GymID)
+    machineID := trimmedOptional(req.MachineID)
 
     now := time.Now().UTC()
     performedAt := day.Add(now.Sub(now.Truncate(24 * time.Hour)))
 
-    exercise, err := h.store.Exercises.Create(userID, performedAt, gymID, machineID, req.Name, req.Sets)
+    exercise, err := h.store.Exercises.Create(userID, trimmedOptional(req.WorkoutID), performedAt, gymID, machineID, catalogID, req.Name, group, req.Sets)
     if err != nil {
+        if errors.Is(err, exercisesstore.ErrWorkoutNotFound) {
+            httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "workout not found")
+            return
+        }
         httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to create exercise"))
         return
     }
 
+    exercise.PersonalRecords = h.detectPersonalRecords(r, userID, *exercise)
+
     httpx.WriteJSON(w, http.StatusCreated, exercise)
 }
 
+// UpdateExerciseRequest represents the update exercise request
+type UpdateExerciseRequest struct {
+	GymID     *string      `json:"gym_id,omitempty"`
+	MachineID *string               `json:"machine_id,omitempty"`
+	CatalogID *string               `json:"catalog_id,omitempty"`
+	Name      string                `json:"name"`
+	Group     *models.ExerciseGroup `json:"group,omitempty"`
+	Sets      []models.Set          `json:"sets"`
+}
+
+// UpdateExercise handles editing an exercise and its sets. Personal records
+// for the affected lifts are recalculated from the full history.
+func (h *Handlers) UpdateExercise(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	exerciseID := chi.URLParam(r, "id")
+
+	var req UpdateExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	catalogID, name, apiErr := h.resolveCatalogExercise(req.CatalogID, req.Name)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	req.Name = name
+	if req.Name == "" || len(req.Sets) == 0 {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "name and sets are required")
+		return
+	}
+
+	if apiErr := validateExerciseSets(req.Sets); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	group, apiErr := normalizeExerciseGroup(req.Group)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	previous, err := h.store.Exercises.GetByID(exerciseID, userID)
+	if err != nil {
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "exercise not found")
+		return
+	}
+
+	exercise, err := h.store.Exercises.Update(exerciseID, userID, trimmedOptional(req.GymID), trimmedOptional(req.MachineID), catalogID, req.Name, group, req.Sets)
+	if err != nil {
+		if errors.Is(err, exercisesstore.ErrExerciseNotFound) {
+			httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "exercise not found")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to update exercise"))
+		return
+	}
+
+	exercise.PersonalRecords = h.rebuildPersonalRecords(r, userID, *previous, *exercise)
+
+	httpx.WriteJSON(w, http.StatusOK, exercise)
+}
+
 // GetExercises handles getting exercises for a specific day
 func (h *Handlers) GetExercises(w http.ResponseWriter, r *http.Request) {
     userID, ok := userIDFromContext(r)
@@ -174,3 +246,74 @@ func (h *Handlers) GetExercise(w http.ResponseWriter, r *http.Request) {
 
     httpx.WriteJSON(w, http.StatusOK, exercise)
 }
+
+// validateExerciseSets checks each set and defaults a missing type to working.
+// A set needs reps, a duration or a distance so cardio machines can be logged.
+func validateExerciseSets(sets []models.Set) *httpx.APIError {
+	for i := range sets {
+		set := &sets[i]
+
+		switch set.Type {
+		case "":
+			set.Type = models.SetTypeWorking
+		case models.SetTypeWarmup, models.SetTypeWorking, models.SetTypeDropset, models.SetTypeFailure, models.SetTypeAMRAP:
+		default:
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "set type must be warmup, working, dropset, failure or amrap")
+		}
+
+		if set.Reps < 0 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "reps must be non-negative")
+		}
+		if set.DurationSeconds != nil && (*set.DurationSeconds <= 0 || *set.DurationSeconds > maxSetDurationSeconds) {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "duration_seconds must be between 1 and 86400")
+		}
+		if set.DistanceMeters != nil && *set.DistanceMeters <= 0 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "distance_meters must be greater than 0")
+		}
+		if set.Reps == 0 && set.DurationSeconds == nil && set.DistanceMeters == nil {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "each set needs reps, duration_seconds or distance_meters")
+		}
+		if set.Type == models.SetTypeAMRAP && set.Reps == 0 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "amrap sets must record reps")
+		}
+		if set.RestSeconds != nil && (*set.RestSeconds < 0 || *set.RestSeconds > maxRestSeconds) {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rest_seconds must be between 0 and 3600")
+		}
+		if set.WeightKg != nil && *set.WeightKg < 0 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "weight must be non-negative")
+		}
+		if set.RPE != nil && (*set.RPE < 1 || *set.RPE > 10) {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rpe must be between 1 and 10")
+		}
+	}
+	return nil
+}
+
+// normalizeExerciseGroup trims the group ID and checks the group type.
+func normalizeExerciseGroup(group *models.ExerciseGroup) (*models.ExerciseGroup, *httpx.APIError) {
+	if group == nil {
+		return nil, nil
+	}
+	normalized := models.ExerciseGroup{
+		ID:   strings.TrimSpace(group.ID),
+		Type: strings.ToLower(strings.TrimSpace(group.Type)),
+	}
+	if normalized.ID == "" || len(normalized.ID) > maxGroupIDLength {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "group id must be between 1 and 64 characters")
+	}
+	if normalized.Type != models.GroupTypeSuperset && normalized.Type != models.GroupTypeCircuit {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "group type must be superset or circuit")
+	}
+	return &normalized, nil
+}
+
+func trimmedOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestValidateExerciseSetsDefaultsTypeAndAllowsCardio(t *testing.T) {
+	seconds := 900
+	meters := 3000.0
+	sets := []models.Set{
+		{Reps: 8},
+		{DurationSeconds: &seconds, DistanceMeters: &meters},
+	}
+
+	if apiErr := validateExerciseSets(sets); apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if sets[0].Type != models.SetTypeWorking || sets[1].Type != models.SetTypeWorking {
+		t.Fatalf("expected default working type, got %q and %q", sets[0].Type, sets[1].Type)
+	}
+}
+
+func TestValidateExerciseSetsRejectsInvalidSets(t *testing.T) {
+	rest := -1
+	seconds := 60
+	cases := map[string]models.Set{
+		"unknown type":  {Reps: 5, Type: "heavy"},
+		"empty set":     {Type: models.SetTypeWorking},
+		"amrap no reps": {Type: models.SetTypeAMRAP, DurationSeconds: &seconds},
+		"negative rest": {Reps: 5, RestSeconds: &rest},
+	}
+	for name, set := range cases {
+		if apiErr := validateExerciseSets([]models.Set{set}); apiErr == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateWorkoutGroups(t *testing.T) {
+	superset := &models.ExerciseGroup{ID: "a", Type: models.GroupTypeSuperset}
+	circuit := &models.ExerciseGroup{ID: "a", Type: models.GroupTypeCircuit}
+
+	if apiErr := validateWorkoutGroups([]models.Exercise{{Group: superset}, {Group: superset}, {}}); apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if apiErr := validateWorkoutGroups([]models.Exercise{{Group: superset}}); apiErr == nil {
+		t.Fatal("expected a single-exercise group to be rejected")
+	}
+	if apiErr := validateWorkoutGroups([]models.Exercise{{Group: superset}, {Group: circuit}}); apiErr == nil {
+		t.Fatal("expected mixed group types to be rejected")
+	}
+}
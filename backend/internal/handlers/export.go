@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+)
+
+const (
+	lbPerKg = 2.20462262
+	// exportFlushRows is how many rows are buffered before the response is flushed.
+	exportFlushRows = 500
+)
+
+// exportQuery holds the shared export parameters
+type exportQuery struct {
+	Format string
+	Unit   string
+	From   *time.Time
+	To     *time.Time
+}
+
+// ExportSets streams every logged set as CSV or JSON Lines
+func (h *Handlers) ExportSets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	query, apiErr := parseExportQuery(r)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	out := newExportWriter(w, userID, query.Format, "sets", []string{
+		"performed_at", "workout_id", "exercise_id", "exercise", "machine_id", "machine",
+		"gym", "group_id", "group_type", "set_index", "set_type", "reps", "weight_" + query.Unit, "rpe",
+		"rest_seconds", "duration_seconds", "distance_meters", "notes",
+	})
+	err := h.store.Exercises.StreamSets(userID, query.From, query.To, func(row models.SetExportRow) error {
+		if row.Weight != nil {
+			weight := convertWeight(*row.Weight, query.Unit)
+			row.Weight = &weight
+		}
+		row.Unit = query.Unit
+
+		return out.write(row, []string{
+			row.PerformedAt.Format(time.RFC3339),
+			stringValue(row.WorkoutID),
+			row.ExerciseID,
+			csvText(row.ExerciseName),
+			stringValue(row.MachineID),
+			csvText(stringValue(row.MachineName)),
+			csvText(stringValue(row.GymName)),
+			stringValue(row.GroupID),
+			stringValue(row.GroupType),
+			strconv.Itoa(row.SetIndex),
+			row.SetType,
+			strconv.Itoa(row.Reps),
+			floatValue(row.Weight),
+			floatValue(row.RPE),
+			intValue(row.RestSeconds),
+			intValue(row.DurationSeconds),
+			floatValue(row.DistanceMeters),
+			csvText(stringValue(row.Notes)),
+		})
+	})
+	out.finish(err, "failed to export sets")
+}
+
+// ExportWorkouts streams workouts with their totals as CSV or JSON Lines
+func (h *Handlers) ExportWorkouts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	query, apiErr := parseExportQuery(r)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	out := newExportWriter(w, userID, query.Format, "workouts", []string{
+		"created_at", "id", "name", "type", "duration_minutes", "exercises",
+		"sets", "warmup_sets", "reps", "volume_" + query.Unit, "distance_meters", "description",
+	})
+	err := h.store.Workouts.Stream(userID, query.From, query.To, func(row models.WorkoutExportRow) error {
+		row.Volume = convertWeight(row.Volume, query.Unit)
+		row.Unit = query.Unit
+
+		return out.write(row, []string{
+			row.CreatedAt.Format(time.RFC3339),
+			row.ID,
+			csvText(row.Name),
+			row.Type,
+			strconv.Itoa(row.DurationMinutes),
+			strconv.Itoa(row.Exercises),
+			strconv.Itoa(row.Sets),
+			strconv.Itoa(row.WarmupSets),
+			strconv.Itoa(row.Reps),
+			strconv.FormatFloat(row.Volume, 'f', -1, 64),
+			strconv.FormatFloat(row.DistanceMeters, 'f', -1, 64),
+			csvText(row.Description),
+		})
+	})
+	out.finish(err, "failed to export workouts")
+}
+
+// parseExportQuery reads format, unit, from and to. from and to are inclusive
+// days; the returned range is [from, to+1day).
+func parseExportQuery(r *http.Request) (exportQuery, *httpx.APIError) {
+	values := r.URL.Query()
+	query := exportQuery{Format: models.ExportFormatCSV, Unit: models.UnitKg}
+
+	switch format := strings.ToLower(strings.TrimSpace(values.Get("format"))); format {
+	case "":
+	case models.ExportFormatCSV, models.ExportFormatJSONL:
+		query.Format = format
+	default:
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "format must be csv or jsonl")
+	}
+
+	switch unit := strings.ToLower(strings.TrimSpace(values.Get("unit"))); unit {
+	case "":
+	case models.UnitKg, models.UnitLb:
+		query.Unit = unit
+	default:
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit must be kg or lb")
+	}
+
+	if fromStr := strings.TrimSpace(values.Get("from")); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+		}
+		query.From = &from
+	}
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+		}
+		end := to.AddDate(0, 0, 1)
+		query.To = &end
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must not be after to")
+	}
+
+	return query, nil
+}
+
+// exportWriter writes rows straight to the response. Headers are sent with
+// the first row so a failure before any data can still return a JSON error.
+type exportWriter struct {
+	w        http.ResponseWriter
+	userID   string
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	pending  int
+}
+
+func newExportWriter(w http.ResponseWriter, userID, format, name string, header []string) *exportWriter {
+	return &exportWriter{
+		w:        w,
+		userID:   userID,
+		format:   format,
+		filename: fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format),
+		header:   header,
+	}
+}
+
+func (e *exportWriter) start() error {
+	e.started = true
+	if e.format == models.ExportFormatJSONL {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	}
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == models.ExportFormatJSONL {
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(e.header)
+}
+
+// write emits value as a JSON line or record as a CSV row.
+func (e *exportWriter) write(value any, record []string) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if e.json != nil {
+		err = e.json.Encode(value)
+	} else {
+		err = e.csv.Write(record)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.pending++
+	if e.pending >= exportFlushRows {
+		e.flush()
+	}
+	return nil
+}
+
+func (e *exportWriter) flush() {
+	e.pending = 0
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			e.abort(err)
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish completes the response. Once rows have been sent the status can no
+// longer change, so a late error aborts the stream.
+func (e *exportWriter) finish(err error, message string) {
+	if err != nil && !e.started {
+		httpx.WriteAPIError(e.w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+		return
+	}
+	if err != nil {
+		e.abort(err)
+	}
+	if !e.started {
+		if startErr := e.start(); startErr != nil {
+			e.abort(startErr)
+		}
+	}
+	e.flush()
+}
+
+// abort drops the connection so the client sees a failed download rather
+// than a file that silently ends early.
+func (e *exportWriter) abort(err error) {
+	log.Printf("export %s for user %s failed: %v", e.filename, e.userID, err)
+	panic(http.ErrAbortHandler)
+}
+
+func convertWeight(kg float64, unit string) float64 {
+	if unit == models.UnitLb {
+		return math.Round(kg*lbPerKg*100) / 100
+	}
+	return kg
+}
+
+// csvText neutralizes user-entered text that a spreadsheet would run as a
+// formula by prefixing it with a quote. JSON Lines rows are left as typed.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func intValue(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func floatValue(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
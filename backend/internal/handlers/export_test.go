@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestParseExportQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/export/sets?format=jsonl&unit=lb&from=2024-03-01&to=2024-03-31", nil)
+
+	query, apiErr := parseExportQuery(req)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if query.Format != models.ExportFormatJSONL || query.Unit != models.UnitLb {
+		t.Fatalf("unexpected query %+v", query)
+	}
+	if query.To == nil || query.To.Format("2006-01-02") != "2024-04-01" {
+		t.Fatalf("expected to to be exclusive next day, got %v", query.To)
+	}
+
+	for _, raw := range []string{"format=xml", "unit=stone", "from=03-01-2024", "from=2024-03-02&to=2024-03-01"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/export/sets?"+raw, nil)
+		if _, apiErr := parseExportQuery(req); apiErr == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestExportWriterCSV(t *testing.T) {
+	rec := httptest.NewRecorder()
+	out := newExportWriter(rec, "user-1", models.ExportFormatCSV, "sets", []string{"reps", "weight_lb"})
+
+	for _, record := range [][]string{{"5", "220.46"}, {"3", ""}} {
+		if err := out.write(nil, record); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	out.finish(nil, "failed")
+
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Fatalf("content type = %q", got)
+	}
+	if got, want := rec.Body.String(), "reps,weight_lb\n5,220.46\n3,\n"; got != want {
+		t.Fatalf("body = %q, want %q", got, want)
+	}
+}
+
+func TestExportWriterJSONLinesAndEmpty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	out := newExportWriter(rec, "user-1", models.ExportFormatJSONL, "workouts", nil)
+	if err := out.write(map[string]int{"sets": 3}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out.finish(nil, "failed")
+	if got := rec.Body.String(); got != "{\"sets\":3}\n" {
+		t.Fatalf("body = %q", got)
+	}
+
+	empty := httptest.NewRecorder()
+	newExportWriter(empty, "user-1", models.ExportFormatCSV, "sets", []string{"reps"}).finish(nil, "failed")
+	if empty.Code != http.StatusOK || empty.Body.String() != "reps\n" {
+		t.Fatalf("empty export = %d %q", empty.Code, empty.Body.String())
+	}
+}
+
+func TestExportWriterErrorBeforeRows(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newExportWriter(rec, "user-1", models.ExportFormatCSV, "sets", []string{"reps"}).finish(errors.New("boom"), "failed to export sets")
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
+
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (f failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestExportWriterAbortsOnLateErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		w    http.ResponseWriter
+		err  error
+	}{
+		{name: "write error", w: failingResponseWriter{httptest.NewRecorder()}},
+		{name: "store error", w: httptest.NewRecorder(), err: errors.New("boom")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := newExportWriter(tc.w, "user-1", models.ExportFormatCSV, "sets", []string{"reps"})
+			if err := out.write(nil, []string{"5"}); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			defer func() {
+				if recovered := recover(); recovered != http.ErrAbortHandler {
+					t.Fatalf("expected the handler to abort, got %v", recovered)
+				}
+			}()
+			out.finish(tc.err, "failed")
+		})
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	for value, want := range map[string]string{
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+1":                "'+1",
+		"-cmd":              "'-cmd",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\tnote":            "'\tnote",
+		"\rnote":            "'\rnote",
+		"Bench Press":       "Bench Press",
+		"":                  "",
+	} {
+		if got := csvText(value); got != want {
+			t.Fatalf("csvText(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestConvertWeight(t *testing.T) {
+	if got := convertWeight(100, models.UnitLb); got != 220.46 {
+		t.Fatalf("100kg = %v lb, want 220.46", got)
+	}
+	if got := convertWeight(62.5, models.UnitKg); got != 62.5 {
+		t.Fatalf("kg passthrough = %v", got)
+	}
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/goals"
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	goalsstore "fitonex/backend/internal/store/goals"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxGoalTitle = 120
+
+// GoalRequest represents a new goal. Lift targets are in Unit, which
+// defaults to the user's weight unit; other targets are counts.
+type GoalRequest struct {
+	Type         string  `json:"type"`
+	Title        string  `json:"title,omitempty"`
+	Target       float64 `json:"target"`
+	Unit         string  `json:"unit,omitempty"`
+	MachineID    *string `json:"machine_id,omitempty"`
+	ExerciseName *string `json:"exercise_name,omitempty"`
+	Reps         *int    `json:"reps,omitempty"`
+	Weeks        *int    `json:"weeks,omitempty"`
+	Deadline     string  `json:"deadline,omitempty"`
+}
+
+// UpdateGoalRequest represents a goal edit. An empty deadline clears it.
+type UpdateGoalRequest struct {
+	Title    *string  `json:"title,omitempty"`
+	Target   *float64 `json:"target,omitempty"`
+	Unit     string   `json:"unit,omitempty"`
+	Deadline *string  `json:"deadline,omitempty"`
+}
+
+// CreateGoal starts a new goal for the caller
+func (h *Handlers) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req GoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	goal, apiErr := goalFromRequest(req, units, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	goal.UserID = userID
+
+	if err := h.store.Goals.Create(goal); err != nil {
+		writeGoalError(w, err, "failed to create goal")
+		return
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "goal_created", map[string]any{
+			"goal_id": goal.ID,
+			"type":    goal.Type,
+		})
+	}
+	if goal.Status == models.GoalStatusCompleted {
+		h.emitGoalCompleted(r, *goal)
+	}
+
+	presentGoal(goal, units)
+	httpx.WriteJSON(w, http.StatusCreated, goal)
+}
+
+// GetGoals evaluates the caller's goals and lists them by status
+func (h *Handlers) GetGoals(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+	if err := h.evaluateGoals(r, userID); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to evaluate goals"))
+		return
+	}
+
+	list, err := h.store.Goals.ListByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch goals"))
+		return
+	}
+	for _, group := range [][]models.Goal{list.Active, list.Completed, list.Expired} {
+		for i := range group {
+			presentGoal(&group[i], units)
+		}
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, list)
+}
+
+// GetGoal returns one of the caller's goals with up to date progress
+func (h *Handlers) GetGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+	if err := h.evaluateGoals(r, userID); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to evaluate goals"))
+		return
+	}
+
+	goal, err := h.store.Goals.GetByID(userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeGoalError(w, err, "failed to fetch goal")
+		return
+	}
+
+	presentGoal(goal, units)
+	httpx.WriteJSON(w, http.StatusOK, goal)
+}
+
+// UpdateGoal edits a goal's title, target or deadline. Completed goals are
+// final; moving an expired goal's deadline into the future reactivates it.
+func (h *Handlers) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	units, err := h.bodyUnits(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch unit preferences"))
+		return
+	}
+
+	goal, err := h.store.Goals.GetByID(userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeGoalError(w, err, "failed to fetch goal")
+		return
+	}
+	if goal.Status == models.GoalStatusCompleted {
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "completed goals cannot be changed")
+		return
+	}
+	if apiErr := applyGoalUpdate(goal, req, units, time.Now().UTC()); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	completed, err := h.store.Goals.Update(goal)
+	if err != nil {
+		writeGoalError(w, err, "failed to update goal")
+		return
+	}
+	if completed {
+		h.emitGoalCompleted(r, *goal)
+	}
+
+	presentGoal(goal, units)
+	httpx.WriteJSON(w, http.StatusOK, goal)
+}
+
+// DeleteGoal removes one of the caller's goals
+func (h *Handlers) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.store.Goals.Delete(userID, chi.URLParam(r, "id")); err != nil {
+		writeGoalError(w, err, "failed to delete goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// evaluateGoals refreshes the user's active goals and reports the ones that
+// were just completed.
+func (h *Handlers) evaluateGoals(r *http.Request, userID string) error {
+	completed, err := h.store.Goals.Evaluate(userID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	for _, goal := range completed {
+		h.emitGoalCompleted(r, goal)
+	}
+	return nil
+}
+
+func (h *Handlers) emitGoalCompleted(r *http.Request, goal models.Goal) {
+	if h.analytics == nil {
+		return
+	}
+	h.analytics.EmitEvent(r.Context(), goal.UserID, "goal_completed", map[string]any{
+		"goal_id": goal.ID,
+		"type":    goal.Type,
+		"target":  goal.Target,
+	})
+}
+
+// goalFromRequest validates a new goal, converting lift targets to kilograms.
+func goalFromRequest(req GoalRequest, units bodyUnits, now time.Time) (*models.Goal, *httpx.APIError) {
+	goal := &models.Goal{
+		Type:         strings.ToLower(strings.TrimSpace(req.Type)),
+		Title:        strings.TrimSpace(req.Title),
+		Target:       req.Target,
+		MachineID:    trimmedOptional(req.MachineID),
+		ExerciseName: trimmedOptional(req.ExerciseName),
+		Reps:         req.Reps,
+		Weeks:        req.Weeks,
+	}
+	if goal.Type == models.GoalLift {
+		target, apiErr := liftTargetKg(req.Target, req.Unit, units)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		goal.Target = target
+	} else {
+		goal.MachineID, goal.ExerciseName, goal.Reps = nil, nil, nil
+	}
+	if goal.Type != models.GoalWeeklyTrainingDays {
+		goal.Weeks = nil
+	}
+
+	if err := goals.Validate(goal); err != nil {
+		if errors.Is(err, goals.ErrUnknownType) {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "type must be one of "+strings.Join(goals.Types(), ", "))
+		}
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
+	}
+
+	deadline, apiErr := parseGoalDeadline(req.Deadline, now)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	goal.Deadline = deadline
+
+	if goal.Title == "" {
+		goal.Title = defaultGoalTitle(*goal, units)
+	}
+	if len(goal.Title) > maxGoalTitle {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "title must be at most 120 characters")
+	}
+	return goal, nil
+}
+
+// applyGoalUpdate validates an edit and applies it to goal.
+func applyGoalUpdate(goal *models.Goal, req UpdateGoalRequest, units bodyUnits, now time.Time) *httpx.APIError {
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" || len(title) > maxGoalTitle {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "title must be between 1 and 120 characters")
+		}
+		goal.Title = title
+	}
+	if req.Target != nil {
+		goal.Target = *req.Target
+		if goal.Type == models.GoalLift {
+			target, apiErr := liftTargetKg(*req.Target, req.Unit, units)
+			if apiErr != nil {
+				return apiErr
+			}
+			goal.Target = target
+		}
+		if err := goals.Validate(goal); err != nil {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
+		}
+	}
+	if req.Deadline != nil {
+		deadline, apiErr := parseGoalDeadline(*req.Deadline, now)
+		if apiErr != nil {
+			return apiErr
+		}
+		goal.Deadline = deadline
+	}
+	return nil
+}
+
+func liftTargetKg(target float64, unit string, units bodyUnits) (float64, *httpx.APIError) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		unit = units.weight
+	}
+	if unit != models.UnitKg && unit != models.UnitLb {
+		return 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit must be kg or lb")
+	}
+	return convertBodyValue(target, unit, models.UnitKg), nil
+}
+
+// parseGoalDeadline parses an optional YYYY-MM-DD deadline, which must not be
+// in the past.
+func parseGoalDeadline(value string, now time.Time) (*time.Time, *httpx.APIError) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	deadline, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "deadline must use YYYY-MM-DD format")
+	}
+	if deadline.Before(now.Truncate(24 * time.Hour)) {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "deadline cannot be in the past")
+	}
+	return &deadline, nil
+}
+
+func defaultGoalTitle(goal models.Goal, units bodyUnits) string {
+	switch goal.Type {
+	case models.GoalWeeklyTrainingDays:
+		if goal.Weeks != nil && *goal.Weeks > 1 {
+			return fmt.Sprintf("Train %gx a week for %d weeks", goal.Target, *goal.Weeks)
+		}
+		return fmt.Sprintf("Train %gx a week", goal.Target)
+	case models.GoalWorkoutCount:
+		return fmt.Sprintf("Log %g workouts", goal.Target)
+	case models.GoalCheckinStreak:
+		return fmt.Sprintf("%g-day check-in streak", goal.Target)
+	case models.GoalLift:
+		name := "Lift"
+		if goal.ExerciseName != nil {
+			name = *goal.ExerciseName
+		}
+		title := fmt.Sprintf("%s %g %s", name, convertBodyValue(goal.Target, models.UnitKg, units.weight), units.weight)
+		if goal.Reps != nil && *goal.Reps > 1 {
+			title += fmt.Sprintf(" for %d reps", *goal.Reps)
+		}
+		return title
+	}
+	return goal.Type
+}
+
+// presentGoal shows lift goals in the user's weight unit
+func presentGoal(goal *models.Goal, units bodyUnits) {
+	if goal.Type != models.GoalLift || goal.Unit == units.weight {
+		return
+	}
+	goal.Target = convertBodyValue(goal.Target, goal.Unit, units.weight)
+	goal.Current = convertBodyValue(goal.Current, goal.Unit, units.weight)
+	goal.Unit = units.weight
+}
+
+func writeGoalError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, goalsstore.ErrGoalNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "goal not found")
+	case errors.Is(err, goalsstore.ErrTooManyGoals):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, fmt.Sprintf("at most %d goals can be active", goalsstore.MaxActiveGoals))
+	case errors.Is(err, goalsstore.ErrMachineNotFound):
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "machine not found")
+	default:
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestGoalFromRequestConvertsLiftTargets(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	imperial := bodyUnits{weight: models.UnitLb, length: models.UnitIn}
+
+	name := " Bench Press "
+	weeks := 4
+	goal, apiErr := goalFromRequest(GoalRequest{Type: "LIFT", Target: 225, ExerciseName: &name, Weeks: &weeks, Deadline: "2024-08-01"}, imperial, now)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if goal.Target != 102.06 || goal.Unit != models.UnitKg || *goal.ExerciseName != "Bench Press" || goal.Weeks != nil || *goal.Reps != 1 {
+		t.Fatalf("unexpected lift goal %+v", goal)
+	}
+	if goal.Title != "Bench Press 225 lb" || goal.Deadline == nil || goal.Deadline.Format("2006-01-02") != "2024-08-01" {
+		t.Fatalf("unexpected title or deadline %q %v", goal.Title, goal.Deadline)
+	}
+
+	presentGoal(goal, imperial)
+	if goal.Target != 225 || goal.Unit != models.UnitLb {
+		t.Fatalf("expected the target in pounds, got %v %s", goal.Target, goal.Unit)
+	}
+
+	goal, apiErr = goalFromRequest(GoalRequest{Type: models.GoalWeeklyTrainingDays, Target: 4}, imperial, now)
+	if apiErr != nil || goal.Title != "Train 4x a week" || *goal.Weeks != 1 {
+		t.Fatalf("unexpected weekly goal %+v %v", goal, apiErr)
+	}
+}
+
+func TestGoalFromRequestRejectsInvalidGoals(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	metric := bodyUnits{weight: models.UnitKg, length: models.UnitCm}
+	name := "Squat"
+
+	for label, req := range map[string]GoalRequest{
+		"unknown type":  {Type: "marathon", Target: 1},
+		"past deadline": {Type: models.GoalWorkoutCount, Target: 10, Deadline: "2024-05-07"},
+		"bad deadline":  {Type: models.GoalWorkoutCount, Target: 10, Deadline: "soon"},
+		"bad unit":      {Type: models.GoalLift, Target: 100, Unit: "stone", ExerciseName: &name},
+		"no exercise":   {Type: models.GoalLift, Target: 100},
+	} {
+		if _, apiErr := goalFromRequest(req, metric, now); apiErr == nil {
+			t.Fatalf("expected %s to be rejected", label)
+		}
+	}
+}
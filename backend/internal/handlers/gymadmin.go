@@ -0,0 +1,714 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/gymhours"
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/maptiles"
+	"fitonex/backend/internal/models"
+	gymsstore "fitonex/backend/internal/store/gyms"
+	machinesstore "fitonex/backend/internal/store/machines"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxGymNameLength     = 120
+	maxGymAddressLength  = 300
+	maxPlanNameLength    = 60
+	maxMachineNameLength = 120
+	maxPriceCents        = 10000000
+	maxMachineQuantity   = 500
+	maxMachineMET        = 25
+	maxClaimMessageChars = 1000
+)
+
+var pricePeriods = map[string]bool{"day": true, "week": true, "month": true, "quarter": true, "year": true}
+
+// GymRequest represents a gym's editable details
+type GymRequest struct {
+	Name    string   `json:"name"`
+	Lat     *float64 `json:"lat"`
+	Lng     *float64 `json:"lng"`
+	Address string   `json:"address"`
+	Phone   *string  `json:"phone,omitempty"`
+	Website *string  `json:"website,omitempty"`
+}
+
+// GymPriceRequest represents a price plan. Saving a plan name that already
+// exists replaces it.
+type GymPriceRequest struct {
+	PlanName   string `json:"plan_name"`
+	PriceCents *int   `json:"price_cents"`
+	Period     string `json:"period"`
+}
+
+// GymMachineRequest sets how many of a machine a gym has
+type GymMachineRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+// MachineRequest represents a catalog machine
+type MachineRequest struct {
+	Name     string   `json:"name"`
+	BodyPart string   `json:"body_part"`
+	MET      *float64 `json:"met,omitempty"`
+}
+
+// GymAmenitiesRequest replaces the amenities a gym lists
+type GymAmenitiesRequest struct {
+	Amenities []string `json:"amenities"`
+}
+
+// GymClaimRequest represents an ownership claim, with an optional note for
+// the reviewing admin
+type GymClaimRequest struct {
+	Message *string `json:"message,omitempty"`
+}
+
+// ReviewGymClaimRequest carries an optional note back to the claimant
+type ReviewGymClaimRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+// CreateGym adds a gym (admin only)
+func (h *Handlers) CreateGym(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req GymRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	gym, apiErr := gymFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	if err := h.store.Gyms.Create(gym); err != nil {
+		writeGymError(w, err, "failed to create gym")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gym.ID)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "gym_created", map[string]any{
+			"gym_id": gym.ID,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, gym)
+}
+
+// UpdateGym replaces a gym's details (admin or owner)
+func (h *Handlers) UpdateGym(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	userID, ok := h.authorizeGymManager(w, r, gymID)
+	if !ok {
+		return
+	}
+
+	var req GymRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	gym, apiErr := gymFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	gym.ID = gymID
+
+	if err := h.store.Gyms.Update(gym); err != nil {
+		writeGymError(w, err, "failed to update gym")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "gym_updated", map[string]any{
+			"gym_id": gymID,
+		})
+	}
+
+	updated, err := h.store.Gyms.GetByID(gymID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch gym")
+		return
+	}
+	httpx.WriteJSON(w, http.StatusOK, updated)
+}
+
+// DeleteGym removes a gym and everything attached to it (admin only)
+func (h *Handlers) DeleteGym(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	gymID := chi.URLParam(r, "id")
+	if err := h.store.Gyms.Delete(gymID); err != nil {
+		writeGymError(w, err, "failed to delete gym")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "gym_deleted", map[string]any{
+			"gym_id": gymID,
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReleaseGymOwner removes a gym's owner so it can be claimed again (admin only)
+func (h *Handlers) ReleaseGymOwner(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if err := h.store.Gyms.ClearOwner(gymID); err != nil {
+		writeGymError(w, err, "failed to release gym")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetGymHours replaces a gym's opening hours (admin or owner)
+func (h *Handlers) SetGymHours(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	var hours models.OpeningHours
+	if err := json.NewDecoder(r.Body).Decode(&hours); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if err := gymhours.Validate(&hours); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	if err := h.store.Gyms.SetHours(gymID, hours); err != nil {
+		writeGymError(w, err, "failed to save gym hours")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	h.GetGymHours(w, r)
+}
+
+// SetGymAmenities replaces a gym's amenities (admin or owner)
+func (h *Handlers) SetGymAmenities(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	var req GymAmenitiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	amenities, apiErr := amenitiesFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	if err := h.store.Gyms.SetAmenities(gymID, amenities); err != nil {
+		writeGymError(w, err, "failed to save amenities")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"amenities": amenities})
+}
+
+// SetGymPrice creates or replaces a price plan (admin or owner)
+func (h *Handlers) SetGymPrice(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	var req GymPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	price, apiErr := priceFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	price.GymID = gymID
+
+	if err := h.store.Gyms.SetPrice(price); err != nil {
+		writeGymError(w, err, "failed to save price")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	h.writeGymPrices(w, gymID)
+}
+
+// DeleteGymPrice removes a price plan (admin or owner)
+func (h *Handlers) DeleteGymPrice(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	planName, err := url.PathUnescape(chi.URLParam(r, "plan"))
+	if err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid plan name")
+		return
+	}
+	if err := h.store.Gyms.DeletePrice(gymID, planName); err != nil {
+		writeGymError(w, err, "failed to delete price")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	h.writeGymPrices(w, gymID)
+}
+
+// SetGymMachine adds a machine to a gym or changes its quantity (admin or owner)
+func (h *Handlers) SetGymMachine(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	req := GymMachineRequest{Quantity: 1}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.Quantity < 1 || req.Quantity > maxMachineQuantity {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("quantity must be between 1 and %d", maxMachineQuantity))
+		return
+	}
+
+	if err := h.store.Gyms.SetMachine(gymID, chi.URLParam(r, "machineId"), req.Quantity); err != nil {
+		writeGymError(w, err, "failed to save gym machine")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	h.writeGymMachines(w, gymID)
+}
+
+// RemoveGymMachine takes a machine out of a gym's inventory (admin or owner)
+func (h *Handlers) RemoveGymMachine(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	if _, ok := h.authorizeGymManager(w, r, gymID); !ok {
+		return
+	}
+
+	if err := h.store.Gyms.RemoveMachine(gymID, chi.URLParam(r, "machineId")); err != nil {
+		writeGymError(w, err, "failed to remove gym machine")
+		return
+	}
+	h.invalidateGymCaches(r.Context(), gymID)
+
+	h.writeGymMachines(w, gymID)
+}
+
+// CreateMachine adds a machine to the catalog (admin only). A new machine
+// belongs to no gym yet, so no cached gym, nearby page or tile can include it.
+func (h *Handlers) CreateMachine(w http.ResponseWriter, r *http.Request) {
+	var req MachineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	machine, apiErr := machineFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	if err := h.store.Machines.Create(machine); err != nil {
+		writeGymError(w, err, "failed to create machine")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, machine)
+}
+
+// UpdateMachine replaces a catalog machine's details (admin only)
+func (h *Handlers) UpdateMachine(w http.ResponseWriter, r *http.Request) {
+	var req MachineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	machine, apiErr := machineFromRequest(req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	machine.ID = chi.URLParam(r, "id")
+
+	if err := h.store.Machines.Update(machine); err != nil {
+		writeGymError(w, err, "failed to update machine")
+		return
+	}
+	h.invalidateNearbyCaches(r.Context())
+
+	httpx.WriteJSON(w, http.StatusOK, machine)
+}
+
+// ClaimGym files an ownership claim for an unowned gym
+func (h *Handlers) ClaimGym(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req GymClaimRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+			return
+		}
+	}
+	message := trimmedOptional(req.Message)
+	if message != nil && len([]rune(*message)) > maxClaimMessageChars {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("message must be at most %d characters", maxClaimMessageChars))
+		return
+	}
+
+	gymID := chi.URLParam(r, "id")
+	claim, err := h.store.Gyms.CreateClaim(gymID, userID, message)
+	if err != nil {
+		writeGymError(w, err, "failed to create claim")
+		return
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "gym_claim_requested", map[string]any{
+			"gym_id":   gymID,
+			"claim_id": claim.ID,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, claim)
+}
+
+// GetMyGymClaims lists the caller's ownership claims
+func (h *Handlers) GetMyGymClaims(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	claims, err := h.store.Gyms.ListClaimsByUser(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch claims"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"claims": claims})
+}
+
+// GetGymClaims lists claims for review, pending ones by default (admin only)
+func (h *Handlers) GetGymClaims(w http.ResponseWriter, r *http.Request) {
+	status := strings.TrimSpace(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = models.GymClaimPending
+	case "all":
+		status = ""
+	case models.GymClaimPending, models.GymClaimApproved, models.GymClaimRejected:
+	default:
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "status must be pending, approved, rejected or all")
+		return
+	}
+	limit := 50
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 && v <= 200 {
+			limit = v
+		}
+	}
+
+	claims, err := h.store.Gyms.ListClaims(status, limit)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch claims"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"claims": claims})
+}
+
+// ApproveGymClaim makes the claimant the gym's owner (admin only)
+func (h *Handlers) ApproveGymClaim(w http.ResponseWriter, r *http.Request) {
+	h.reviewGymClaim(w, r, true)
+}
+
+// RejectGymClaim turns a claim down (admin only)
+func (h *Handlers) RejectGymClaim(w http.ResponseWriter, r *http.Request) {
+	h.reviewGymClaim(w, r, false)
+}
+
+func (h *Handlers) reviewGymClaim(w http.ResponseWriter, r *http.Request, approve bool) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ReviewGymClaimRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+			return
+		}
+	}
+	note := trimmedOptional(req.Note)
+	if note != nil && len([]rune(*note)) > maxClaimMessageChars {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("note must be at most %d characters", maxClaimMessageChars))
+		return
+	}
+
+	claim, err := h.store.Gyms.ReviewClaim(chi.URLParam(r, "id"), userID, approve, note)
+	if err != nil {
+		writeGymError(w, err, "failed to review claim")
+		return
+	}
+	if approve {
+		h.invalidateGymCaches(r.Context(), claim.GymID)
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "gym_claim_reviewed", map[string]any{
+			"gym_id":   claim.GymID,
+			"claim_id": claim.ID,
+			"status":   claim.Status,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, claim)
+}
+
+// authorizeGymManager lets admins and the gym's owner through, writing a
+// 404 or 403 otherwise.
+func (h *Handlers) authorizeGymManager(w http.ResponseWriter, r *http.Request, gymID string) (string, bool) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return "", false
+	}
+
+	owner, err := h.store.Gyms.OwnerID(gymID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch gym")
+		return "", false
+	}
+	if user, ok := currentUserFromContext(r); ok && user.IsAdmin() {
+		return userID, true
+	}
+	if owner == nil || *owner != userID {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the gym's owner or an admin can manage it")
+		return "", false
+	}
+	return userID, true
+}
+
+// invalidateGymCaches drops the cached gym and every cached nearby page and
+// map tile, since any of them may include the gym.
+func (h *Handlers) invalidateGymCaches(ctx context.Context, gymID string) {
+	if h.cache == nil {
+		return
+	}
+	_ = h.cache.Delete(ctx, fmt.Sprintf("GYM:%s", gymID))
+	h.invalidateNearbyCaches(ctx)
+}
+
+// invalidateGymRatingCaches drops the cached gym and the map tiles holding
+// it after its rating changes. Reviews are too frequent to scan the keyspace
+// for, so nearby pages are left to expire on their short TTL.
+func (h *Handlers) invalidateGymRatingCaches(ctx context.Context, gymID string) {
+	if h.cache == nil {
+		return
+	}
+	keys := []string{fmt.Sprintf("GYM:%s", gymID)}
+	if gym, err := h.store.Gyms.GetByID(gymID); err == nil {
+		for _, tile := range maptiles.Containing(gym.Lat, gym.Lng) {
+			keys = append(keys, "GYMTILE:"+tile.Key())
+		}
+	}
+	_ = h.cache.Delete(ctx, keys...)
+}
+
+// invalidateNearbyCaches drops every cached nearby page and map tile. A
+// catalog machine's name and body part feed the nearby filters of every gym
+// that lists it.
+func (h *Handlers) invalidateNearbyCaches(ctx context.Context) {
+	if h.cache == nil {
+		return
+	}
+	_ = h.cache.InvalidatePrefix(ctx, "NEARBY:", 200)
+	_ = h.cache.InvalidatePrefix(ctx, "GYMTILE:", 200)
+}
+
+func (h *Handlers) writeGymPrices(w http.ResponseWriter, gymID string) {
+	prices, err := h.store.Gyms.GetPrices(gymID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch gym prices"))
+		return
+	}
+	if prices == nil {
+		prices = []models.GymPrice{}
+	}
+	httpx.WriteJSON(w, http.StatusOK, prices)
+}
+
+func (h *Handlers) writeGymMachines(w http.ResponseWriter, gymID string) {
+	machines, err := h.store.Gyms.GetMachines(gymID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch gym machines"))
+		return
+	}
+	if machines == nil {
+		machines = []models.Machine{}
+	}
+	httpx.WriteJSON(w, http.StatusOK, machines)
+}
+
+func gymFromRequest(req GymRequest) (*models.Gym, *httpx.APIError) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxGymNameLength {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxGymNameLength))
+	}
+	address := strings.TrimSpace(req.Address)
+	if address == "" || len([]rune(address)) > maxGymAddressLength {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("address is required and must be at most %d characters", maxGymAddressLength))
+	}
+	if req.Lat == nil || math.IsNaN(*req.Lat) || *req.Lat < -90 || *req.Lat > 90 {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "lat must be a valid coordinate between -90 and 90")
+	}
+	if req.Lng == nil || math.IsNaN(*req.Lng) || *req.Lng < -180 || *req.Lng > 180 {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "lng must be a valid coordinate between -180 and 180")
+	}
+
+	website := trimmedOptional(req.Website)
+	if website != nil {
+		parsed, err := url.Parse(*website)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "website must be an http or https URL")
+		}
+	}
+
+	return &models.Gym{
+		Name:    name,
+		Lat:     *req.Lat,
+		Lng:     *req.Lng,
+		Address: address,
+		Phone:   trimmedOptional(req.Phone),
+		Website: website,
+	}, nil
+}
+
+func priceFromRequest(req GymPriceRequest) (models.GymPrice, *httpx.APIError) {
+	planName := strings.TrimSpace(req.PlanName)
+	if planName == "" || len([]rune(planName)) > maxPlanNameLength {
+		return models.GymPrice{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("plan_name is required and must be at most %d characters", maxPlanNameLength))
+	}
+	if req.PriceCents == nil || *req.PriceCents < 0 || *req.PriceCents > maxPriceCents {
+		return models.GymPrice{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "price_cents must be between 0 and 10000000")
+	}
+	period := strings.ToLower(strings.TrimSpace(req.Period))
+	if !pricePeriods[period] {
+		return models.GymPrice{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "period must be day, week, month, quarter or year")
+	}
+	return models.GymPrice{PlanName: planName, PriceCents: *req.PriceCents, Period: period}, nil
+}
+
+func machineFromRequest(req MachineRequest) (*models.Machine, *httpx.APIError) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxMachineNameLength {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxMachineNameLength))
+	}
+	bodyPart := strings.TrimSpace(req.BodyPart)
+	if bodyPart == "" {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "body_part is required")
+	}
+	if req.MET != nil && (math.IsNaN(*req.MET) || *req.MET <= 0 || *req.MET > maxMachineMET) {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("met must be greater than 0 and at most %d", maxMachineMET))
+	}
+	return &models.Machine{Name: name, BodyPart: bodyPart, MET: req.MET}, nil
+}
+
+func amenitiesFromRequest(req GymAmenitiesRequest) ([]string, *httpx.APIError) {
+	amenities := []string{}
+	for _, amenity := range req.Amenities {
+		amenity = strings.ToLower(strings.TrimSpace(amenity))
+		if !slices.Contains(models.Amenities, amenity) {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("unknown amenity %q; expected one of %s", amenity, strings.Join(models.Amenities, ", ")))
+		}
+		if !slices.Contains(amenities, amenity) {
+			amenities = append(amenities, amenity)
+		}
+	}
+	slices.Sort(amenities)
+	return amenities, nil
+}
+
+func writeGymError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, gymsstore.ErrGymNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "gym not found")
+	case errors.Is(err, gymsstore.ErrPriceNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "price plan not found")
+	case errors.Is(err, gymsstore.ErrMachineNotFound), errors.Is(err, machinesstore.ErrMachineNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "machine not found")
+	case errors.Is(err, machinesstore.ErrDuplicateName):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "a machine with this name already exists")
+	case errors.Is(err, gymsstore.ErrClaimNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "claim not found")
+	case errors.Is(err, gymsstore.ErrClaimPending):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "you already have a pending claim for this gym")
+	case errors.Is(err, gymsstore.ErrClaimResolved):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "claim has already been reviewed")
+	case errors.Is(err, gymsstore.ErrGymOwned):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "gym already has an owner")
+	case errors.Is(err, gymsstore.ErrReviewNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "review not found")
+	case errors.Is(err, gymsstore.ErrOwnReview):
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "you cannot vote on your own review")
+	case errors.Is(err, gymsstore.ErrPhotoNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "photo not found")
+	case errors.Is(err, gymsstore.ErrPhotoLimit):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "review photo limit reached")
+	case errors.Is(err, gymsstore.ErrPhotoAttached):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "photo is already attached")
+	default:
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestGymFromRequestNormalizesFields(t *testing.T) {
+	lat, lng := 47.61, -122.33
+	phone, website := "  ", " https://gym.example "
+	gym, apiErr := gymFromRequest(GymRequest{Name: " Downtown Gym ", Lat: &lat, Lng: &lng, Address: "123 Main St", Phone: &phone, Website: &website})
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if gym.Name != "Downtown Gym" || gym.Phone != nil || *gym.Website != "https://gym.example" || gym.OwnerID != nil {
+		t.Fatalf("unexpected gym %+v", gym)
+	}
+}
+
+func TestGymFromRequestRejectsInvalidGyms(t *testing.T) {
+	lat, lng, far := 47.61, -122.33, 91.0
+	website := "ftp://gym.example"
+	cases := map[string]GymRequest{
+		"missing name":     {Lat: &lat, Lng: &lng, Address: "123 Main St"},
+		"missing address":  {Name: "Gym", Lat: &lat, Lng: &lng},
+		"missing lat":      {Name: "Gym", Lng: &lng, Address: "123 Main St"},
+		"lat out of range": {Name: "Gym", Lat: &far, Lng: &lng, Address: "123 Main St"},
+		"bad website":      {Name: "Gym", Lat: &lat, Lng: &lng, Address: "123 Main St", Website: &website},
+	}
+	for name, req := range cases {
+		if _, apiErr := gymFromRequest(req); apiErr == nil || apiErr.Status != http.StatusBadRequest {
+			t.Fatalf("expected %s to be rejected, got %v", name, apiErr)
+		}
+	}
+}
+
+func TestPriceFromRequest(t *testing.T) {
+	cents, negative := 4900, -1
+	price, apiErr := priceFromRequest(GymPriceRequest{PlanName: " Monthly ", PriceCents: &cents, Period: "Month"})
+	if apiErr != nil || price.PlanName != "Monthly" || price.Period != "month" || price.PriceCents != 4900 {
+		t.Fatalf("unexpected price %+v %v", price, apiErr)
+	}
+
+	for name, req := range map[string]GymPriceRequest{
+		"missing price":  {PlanName: "Monthly", Period: "month"},
+		"negative price": {PlanName: "Monthly", PriceCents: &negative, Period: "month"},
+		"unknown period": {PlanName: "Monthly", PriceCents: &cents, Period: "fortnight"},
+		"missing name":   {PriceCents: &cents, Period: "month"},
+	} {
+		if _, apiErr := priceFromRequest(req); apiErr == nil {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	h := &Handlers{}
+	handler := h.AdminMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cases := []struct {
+		user   *models.User
+		status int
+	}{
+		{nil, http.StatusUnauthorized},
+		{&models.User{ID: "u1", Role: models.RoleUser}, http.StatusForbidden},
+		{&models.User{ID: "u2", Role: models.RoleAdmin}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/gyms", nil)
+		if tc.user != nil {
+			req = req.WithContext(context.WithValue(req.Context(), ctxUser, tc.user))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status {
+			t.Fatalf("user %+v: expected %d, got %d", tc.user, tc.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/maptiles"
+	"fitonex/backend/internal/models"
+)
+
+const (
+	// maxViewportTiles covers a tablet-sized viewport at its own zoom with a
+	// margin of tiles on every side.
+	maxViewportTiles = 100
+	// tileGrid splits a dense 256px tile into 64px cluster cells.
+	tileGrid = 4
+	// maxTileGyms is how many gyms a tile lists before it is clustered.
+	maxTileGyms = 24
+	// unclusteredZoom is street level, where every gym is shown.
+	unclusteredZoom = 16
+)
+
+// GetGymsInViewport returns the gyms in a map viewport at a zoom level.
+// Sparse tiles list their gyms; dense ones are clustered per grid cell.
+func (h *Handlers) GetGymsInViewport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	minLat, apiErr := coordinateParam(query, "min_lat", 90)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	maxLat, apiErr := coordinateParam(query, "max_lat", 90)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	minLng, apiErr := coordinateParam(query, "min_lng", 180)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	maxLng, apiErr := coordinateParam(query, "max_lng", 180)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	if minLat > maxLat {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "min_lat must not be greater than max_lat")
+		return
+	}
+
+	zoom, err := strconv.Atoi(strings.TrimSpace(query.Get("zoom")))
+	if err != nil || zoom < 0 || zoom > maptiles.MaxZoom {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("zoom must be an integer between 0 and %d", maptiles.MaxZoom))
+		return
+	}
+
+	tiles, err := maptiles.Covering(minLat, minLng, maxLat, maxLng, zoom, maxViewportTiles)
+	if err != nil {
+		if errors.Is(err, maptiles.ErrTooManyTiles) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "viewport is too large for the zoom level")
+			return
+		}
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
+		return
+	}
+
+	viewport := models.GymViewport{
+		Zoom:     zoom,
+		Tiles:    len(tiles),
+		Gyms:     []models.MapGym{},
+		Clusters: []models.GymCluster{},
+	}
+	for _, tile := range tiles {
+		markers, err := h.gymTile(r.Context(), tile)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch map gyms"))
+			return
+		}
+		viewport.Gyms = append(viewport.Gyms, markers.Gyms...)
+		viewport.Clusters = append(viewport.Clusters, markers.Clusters...)
+	}
+
+	// Tiles stay in Redis until a gym changes, but browsers and proxies
+	// cannot be told about changes, so responses use the short nearby TTL.
+	httpx.WriteJSONWithCache(w, http.StatusOK, viewport, h.config.CacheTTLNearby)
+}
+
+// gymTile returns a tile's markers from the cache, loading and caching them
+// on a miss.
+func (h *Handlers) gymTile(ctx context.Context, tile maptiles.Tile) (models.GymTile, error) {
+	cacheKey := "GYMTILE:" + tile.Key()
+	var markers models.GymTile
+	if h.cache != nil {
+		if ok, _ := h.cache.GetJSON(ctx, cacheKey, &markers); ok {
+			return markers, nil
+		}
+	}
+
+	maxGyms := maxTileGyms
+	if tile.Z >= unclusteredZoom {
+		maxGyms = math.MaxInt32
+	}
+	markers, err := h.store.Gyms.GetTile(tile, tileGrid, maxGyms)
+	if err != nil {
+		return markers, err
+	}
+
+	if h.cache != nil {
+		_ = h.cache.SetJSON(ctx, cacheKey, markers, h.config.CacheTTLTiles)
+	}
+	return markers, nil
+}
+
+func coordinateParam(query url.Values, name string, limit float64) (float64, *httpx.APIError) {
+	raw := strings.TrimSpace(query.Get(name))
+	value, err := strconv.ParseFloat(raw, 64)
+	if raw == "" || err != nil || math.IsNaN(value) || value < -limit || value > limit {
+		return 0, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("%s must be a coordinate between %v and %v", name, -limit, limit))
+	}
+	return value, nil
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fitonex/backend/internal/config"
+)
+
+func TestGetGymsInViewportValidation(t *testing.T) {
+	h := &Handlers{config: &config.Config{}}
+
+	for _, raw := range []string{
+		"min_lng=-122.4&max_lat=47.7&max_lng=-122.2&zoom=12",
+		"min_lat=47.5&min_lng=-122.4&max_lat=47.7&max_lng=-122.2",
+		"min_lat=47.5&min_lng=-122.4&max_lat=47.7&max_lng=-122.2&zoom=23",
+		"min_lat=47.5&min_lng=-122.4&max_lat=91&max_lng=-122.2&zoom=12",
+		"min_lat=47.7&min_lng=-122.4&max_lat=47.5&max_lng=-122.2&zoom=12",
+		"min_lat=-60&min_lng=-170&max_lat=70&max_lng=170&zoom=12",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/gyms/bbox?"+raw, nil)
+		res := httptest.NewRecorder()
+		h.GetGymsInViewport(res, req)
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", raw, res.Code)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/imaging"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const reviewPhotoURLTTL = 15 * time.Minute
+
+// reviewPhotoDecodes caps how many uploaded photos are decoded and resized at
+// once; each can hold tens of megabytes of pixels in memory.
+var reviewPhotoDecodes = make(chan struct{}, 2)
+
+// reviewPhotoVariants are the sizes served to clients; originals stay
+// private since they may carry EXIF location.
+var reviewPhotoVariants = []imaging.Variant{
+	{Name: "thumb", MaxSide: 320},
+	{Name: "large", MaxSide: 1600},
+}
+
+var reviewPhotoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// ReviewPhotoUploadRequest represents the review photo upload URL payload.
+type ReviewPhotoUploadRequest struct {
+	ContentType string `json:"content_type"`
+	Bytes       int64  `json:"bytes"`
+}
+
+// ReviewPhotoUploadResponse represents the presigned review photo upload.
+type ReviewPhotoUploadResponse struct {
+	UploadURL string `json:"upload_url"`
+	PhotoKey  string `json:"photo_key"`
+}
+
+// AttachReviewPhotoRequest represents the attach payload.
+type AttachReviewPhotoRequest struct {
+	PhotoKey string `json:"photo_key"`
+}
+
+// GetReviewPhotoUploadURL returns a pre-signed URL for uploading a photo to
+// the caller's review.
+func (h *Handlers) GetReviewPhotoUploadURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	var req ReviewPhotoUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := h.validateReviewPhotoUpload(&req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if review.UserID != userID {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author can add photos to a review")
+		return
+	}
+	if len(review.Photos) >= h.reviewPhotoLimit() {
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, fmt.Sprintf("a review can have at most %d photos", h.reviewPhotoLimit()))
+		return
+	}
+
+	if !h.allowReviewPhotoUpload(w, r, userID) {
+		return
+	}
+
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		service, err := storage.NewS3Service(h.config)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to initialize storage service"))
+			return
+		}
+		h.SetObjectStorage(service)
+		storageSvc = service
+	}
+
+	photoKey := reviewPhotoUploadPrefix(reviewID) + uuid.NewString() + reviewPhotoExtensions[req.ContentType]
+	uploadURL, err := storageSvc.PresignPut(r.Context(), photoKey, req.ContentType, req.Bytes, uploadURLTTL)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to generate upload url"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, ReviewPhotoUploadResponse{
+		UploadURL: uploadURL,
+		PhotoKey:  photoKey,
+	})
+}
+
+// AttachReviewPhoto verifies an uploaded photo, stores its resized variants
+// and attaches it to the caller's review.
+func (h *Handlers) AttachReviewPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	var req AttachReviewPhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	photoKey := strings.TrimSpace(req.PhotoKey)
+	if !strings.HasPrefix(photoKey, reviewPhotoUploadPrefix(reviewID)) || strings.Contains(photoKey, "..") {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "photo_key was not issued for this review")
+		return
+	}
+	if !h.allowReviewPhotoUpload(w, r, userID) {
+		return
+	}
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if review.UserID != userID {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author can add photos to a review")
+		return
+	}
+
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		service, err := storage.NewS3Service(h.config)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to initialize storage service"))
+			return
+		}
+		h.SetObjectStorage(service)
+		storageSvc = service
+	}
+
+	photo, apiErr := h.processReviewPhoto(r.Context(), storageSvc, reviewID, photoKey)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	if err := h.store.Gyms.AddReviewPhoto(photo, h.reviewPhotoLimit()); err != nil {
+		deleteReviewPhotoVariants(r.Context(), storageSvc, photo)
+		writeGymError(w, err, "failed to attach photo")
+		return
+	}
+
+	h.signReviewPhoto(r.Context(), photo)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "review_photo_added", map[string]any{
+			"gym_id":    gymID,
+			"review_id": reviewID,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, photo)
+}
+
+// DeleteReviewPhoto removes a photo from a review (author or admin).
+func (h *Handlers) DeleteReviewPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID, photoID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId"), chi.URLParam(r, "photoId")
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if !h.canManageReview(r, review, userID) {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author or an admin can remove a review photo")
+		return
+	}
+
+	photo, err := h.store.Gyms.DeleteReviewPhoto(reviewID, photoID)
+	if err != nil {
+		writeGymError(w, err, "failed to delete photo")
+		return
+	}
+
+	if storageSvc := h.storageService(); storageSvc != nil {
+		_ = storageSvc.DeleteObject(r.Context(), photo.ObjectKey)
+		deleteReviewPhotoVariants(r.Context(), storageSvc, photo)
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "review_photo_deleted", map[string]any{
+			"gym_id":    gymID,
+			"review_id": reviewID,
+			"by_author": review.UserID == userID,
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// processReviewPhoto checks that the uploaded object exists and is a JPEG or
+// PNG within the size limit, then uploads its resized variants.
+func (h *Handlers) processReviewPhoto(ctx context.Context, storageSvc objectStorage, reviewID, photoKey string) (*models.GymReviewPhoto, *httpx.APIError) {
+	maxBytes := h.reviewPhotoMaxBytes()
+
+	info, err := storageSvc.StatObject(ctx, photoKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "photo has not been uploaded")
+	}
+	if err != nil {
+		return nil, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to check photo")
+	}
+	if info.Size > maxBytes {
+		_ = storageSvc.DeleteObject(ctx, photoKey)
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("photo must be at most %d MB", maxBytes>>20))
+	}
+
+	data, err := storageSvc.GetObject(ctx, photoKey, maxBytes)
+	if errors.Is(err, storage.ErrObjectTooLarge) {
+		_ = storageSvc.DeleteObject(ctx, photoKey)
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("photo must be at most %d MB", maxBytes>>20))
+	}
+	if err != nil {
+		return nil, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to read photo")
+	}
+
+	select {
+	case reviewPhotoDecodes <- struct{}{}:
+		defer func() { <-reviewPhotoDecodes }()
+	case <-ctx.Done():
+		return nil, httpx.WrapError(ctx.Err(), http.StatusServiceUnavailable, httpx.ErrorCodeInternal, "photo processing is busy")
+	}
+
+	img, contentType, err := imaging.Decode(data)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		_ = storageSvc.DeleteObject(ctx, photoKey)
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "photo dimensions are too large")
+	}
+	if err != nil {
+		_ = storageSvc.DeleteObject(ctx, photoKey)
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "photo must be a JPEG or PNG image")
+	}
+
+	variants, err := imaging.Render(img, reviewPhotoVariants)
+	if err != nil {
+		return nil, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to resize photo")
+	}
+
+	photoID := uuid.NewString()
+	photo := &models.GymReviewPhoto{
+		ID:          photoID,
+		ReviewID:    reviewID,
+		ObjectKey:   photoKey,
+		ThumbKey:    fmt.Sprintf("reviews/photos/%s/thumb.jpg", photoID),
+		LargeKey:    fmt.Sprintf("reviews/photos/%s/large.jpg", photoID),
+		ContentType: contentType,
+		Bytes:       int64(len(data)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+	for key, variant := range map[string]string{photo.ThumbKey: "thumb", photo.LargeKey: "large"} {
+		if err := storageSvc.PutObject(ctx, key, "image/jpeg", variants[variant]); err != nil {
+			deleteReviewPhotoVariants(ctx, storageSvc, photo)
+			return nil, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to store photo")
+		}
+	}
+	return photo, nil
+}
+
+// allowReviewPhotoUpload spends a token from the upload limiter, writing a 429
+// when the caller is out of them. Both issuing an upload URL and attaching
+// the photo cost a token, since attaching decodes and resizes it.
+func (h *Handlers) allowReviewPhotoUpload(w http.ResponseWriter, r *http.Request, userID string) bool {
+	if h.uploadLimiter == nil {
+		return true
+	}
+	decision, err := h.uploadLimiter.Allow(r.Context(), userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "rate limit check failed"))
+		return false
+	}
+	if !decision.Allowed {
+		retrySeconds := int(math.Ceil(decision.RetryAfter.Seconds()))
+		if retrySeconds <= 0 {
+			retrySeconds = 1
+		}
+		message := fmt.Sprintf("Rate limit exceeded. Try again in %d seconds.", retrySeconds)
+		httpx.WriteError(w, http.StatusTooManyRequests, httpx.ErrorCodeTooManyRequests, message)
+		return false
+	}
+	return true
+}
+
+func (h *Handlers) validateReviewPhotoUpload(req *ReviewPhotoUploadRequest) *httpx.APIError {
+	req.ContentType = strings.ToLower(strings.TrimSpace(req.ContentType))
+	if _, ok := reviewPhotoExtensions[req.ContentType]; !ok {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "content_type must be image/jpeg or image/png")
+	}
+	if req.Bytes <= 0 {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "bytes must be greater than zero")
+	}
+	if maxBytes := h.reviewPhotoMaxBytes(); req.Bytes > maxBytes {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("photo must be at most %d MB", maxBytes>>20))
+	}
+	return nil
+}
+
+// signReviewPhotos fills in the variant URLs of each review's photos.
+func (h *Handlers) signReviewPhotos(ctx context.Context, reviews ...*models.GymReview) {
+	for _, review := range reviews {
+		for i := range review.Photos {
+			h.signReviewPhoto(ctx, &review.Photos[i])
+		}
+	}
+}
+
+func (h *Handlers) signReviewPhoto(ctx context.Context, photo *models.GymReviewPhoto) {
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		return
+	}
+	if thumbURL, err := storageSvc.SignedGet(ctx, photo.ThumbKey, h.cdnBaseURL, reviewPhotoURLTTL); err == nil {
+		photo.ThumbURL = thumbURL
+	}
+	if largeURL, err := storageSvc.SignedGet(ctx, photo.LargeKey, h.cdnBaseURL, reviewPhotoURLTTL); err == nil {
+		photo.URL = largeURL
+	}
+}
+
+func (h *Handlers) reviewPhotoLimit() int {
+	if h.config != nil && h.config.ReviewPhotoLimit > 0 {
+		return h.config.ReviewPhotoLimit
+	}
+	return 5
+}
+
+func (h *Handlers) reviewPhotoMaxBytes() int64 {
+	if h.config != nil && h.config.ReviewPhotoMaxMB > 0 {
+		return int64(h.config.ReviewPhotoMaxMB) << 20
+	}
+	return 10 << 20
+}
+
+func reviewPhotoUploadPrefix(reviewID string) string {
+	return fmt.Sprintf("reviews/photos/uploads/%s/", reviewID)
+}
+
+func deleteReviewPhotoVariants(ctx context.Context, storageSvc objectStorage, photo *models.GymReviewPhoto) {
+	_ = storageSvc.DeleteObject(ctx, photo.ThumbKey)
+	_ = storageSvc.DeleteObject(ctx, photo.LargeKey)
+}
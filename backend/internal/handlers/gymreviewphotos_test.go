@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/config"
+	"fitonex/backend/internal/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestValidateReviewPhotoUpload(t *testing.T) {
+	h := &Handlers{config: &config.Config{ReviewPhotoMaxMB: 2}}
+
+	req := ReviewPhotoUploadRequest{ContentType: " IMAGE/PNG ", Bytes: 1 << 20}
+	if err := h.validateReviewPhotoUpload(&req); err != nil {
+		t.Fatalf("expected a valid request, got %v", err)
+	}
+	if req.ContentType != "image/png" {
+		t.Fatalf("expected the content type to be normalized, got %q", req.ContentType)
+	}
+
+	for _, tc := range []ReviewPhotoUploadRequest{
+		{ContentType: "image/gif", Bytes: 1024},
+		{ContentType: "image/jpeg"},
+		{ContentType: "image/jpeg", Bytes: 3 << 20},
+	} {
+		tc := tc
+		if err := h.validateReviewPhotoUpload(&tc); err == nil || err.Status != http.StatusBadRequest {
+			t.Fatalf("%+v: expected a 400, got %v", tc, err)
+		}
+	}
+}
+
+func TestProcessReviewPhoto(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2000, 1000))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	prefix := reviewPhotoUploadPrefix("review-1")
+	storage := &fakeStorage{objects: map[string][]byte{
+		prefix + "ok.png":    buf.Bytes(),
+		prefix + "text.png":  []byte("not an image"),
+		prefix + "large.png": bytes.Repeat([]byte{0}, 1<<20+1),
+	}}
+	h := &Handlers{config: &config.Config{ReviewPhotoMaxMB: 1}}
+
+	photo, apiErr := h.processReviewPhoto(context.Background(), storage, "review-1", prefix+"ok.png")
+	if apiErr != nil {
+		t.Fatalf("processReviewPhoto: %v", apiErr)
+	}
+	if photo.ContentType != "image/png" || photo.Width != 2000 || photo.Height != 1000 {
+		t.Fatalf("unexpected photo %+v", photo)
+	}
+	if len(storage.calls) != 2 {
+		t.Fatalf("expected two variants to be stored, got %d", len(storage.calls))
+	}
+	for _, call := range storage.calls {
+		if call.ContentType != "image/jpeg" || !strings.HasPrefix(call.Key, "reviews/photos/"+photo.ID+"/") {
+			t.Fatalf("unexpected variant upload %+v", call)
+		}
+	}
+
+	for _, key := range []string{"missing.png", "text.png", "large.png"} {
+		if _, apiErr := h.processReviewPhoto(context.Background(), storage, "review-1", prefix+key); apiErr == nil || apiErr.Status != http.StatusBadRequest {
+			t.Fatalf("%s: expected a 400, got %v", key, apiErr)
+		}
+	}
+	if _, ok := storage.objects[prefix+"text.png"]; ok {
+		t.Fatal("expected a rejected upload to be deleted")
+	}
+}
+
+func TestAttachReviewPhotoRejectsForeignKey(t *testing.T) {
+	h := &Handlers{}
+
+	body := strings.NewReader(`{"photo_key":"reviews/photos/uploads/other-review/x.png"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/gyms/g1/reviews/r1/photos", body)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "g1")
+	routeCtx.URLParams.Add("reviewId", "r1")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	req = req.WithContext(context.WithValue(ctx, ctxUserID, "user-1"))
+	res := httptest.NewRecorder()
+	h.AttachReviewPhoto(res, req)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", res.Code)
+	}
+}
+
+func TestAttachReviewPhotoRateLimited(t *testing.T) {
+	limiter := &fakeLimiter{decision: ratelimit.Decision{Allowed: false, RetryAfter: 5 * time.Second}}
+	h := &Handlers{}
+	h.SetUploadLimiter(limiter)
+
+	body := strings.NewReader(`{"photo_key":"` + reviewPhotoUploadPrefix("r1") + `x.png"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/gyms/g1/reviews/r1/photos", body)
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "g1")
+	routeCtx.URLParams.Add("reviewId", "r1")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	req = req.WithContext(context.WithValue(ctx, ctxUserID, "user-1"))
+	res := httptest.NewRecorder()
+	h.AttachReviewPhoto(res, req)
+	if res.Code != http.StatusTooManyRequests || !limiter.called {
+		t.Fatalf("expected status 429 from the upload limiter, got %d", res.Code)
+	}
+}
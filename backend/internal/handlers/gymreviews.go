@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/moderation"
+	"fitonex/backend/internal/pagination"
+	gymsstore "fitonex/backend/internal/store/gyms"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateGymReviewRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// CreateGymReview handles moderated review creation. A user has one active
+// review per gym, so posting again edits it.
+func (h *Handlers) CreateGymReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID := chi.URLParam(r, "id")
+
+	var req CreateGymReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := h.validateReview(req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	review, created, err := h.store.Gyms.SaveReview(gymID, userID, req.Rating, req.Comment)
+	if err != nil {
+		writeGymError(w, err, "failed to save review")
+		return
+	}
+
+	h.invalidateGymRatingCaches(r.Context(), gymID)
+
+	h.signReviewPhotos(r.Context(), review)
+
+	event, status := "review_updated", http.StatusOK
+	if created {
+		event, status = "review_created", http.StatusCreated
+	}
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, event, map[string]any{
+			"gym_id": gymID,
+			"rating": req.Rating,
+		})
+	}
+
+	httpx.WriteJSON(w, status, review)
+}
+
+// UpdateGymReview edits the caller's review, keeping the previous version.
+func (h *Handlers) UpdateGymReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	var req CreateGymReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := h.validateReview(req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if review.UserID != userID {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author can edit a review")
+		return
+	}
+
+	review, err = h.store.Gyms.UpdateReview(gymID, reviewID, req.Rating, req.Comment)
+	if err != nil {
+		writeGymError(w, err, "failed to update review")
+		return
+	}
+	h.signReviewPhotos(r.Context(), review)
+
+	h.invalidateGymRatingCaches(r.Context(), gymID)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "review_updated", map[string]any{
+			"gym_id": gymID,
+			"rating": req.Rating,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, review)
+}
+
+// DeleteGymReview removes a review (author or admin).
+func (h *Handlers) DeleteGymReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if !h.canManageReview(r, review, userID) {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author or an admin can delete a review")
+		return
+	}
+
+	if err := h.store.Gyms.DeleteReview(gymID, reviewID); err != nil {
+		writeGymError(w, err, "failed to delete review")
+		return
+	}
+
+	h.invalidateGymRatingCaches(r.Context(), gymID)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "review_deleted", map[string]any{
+			"gym_id":    gymID,
+			"by_author": review.UserID == userID,
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetGymReviewHistory lists a review's earlier versions (author or admin).
+func (h *Handlers) GetGymReviewHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	review, err := h.store.Gyms.GetReview(gymID, reviewID)
+	if err != nil {
+		writeGymError(w, err, "failed to fetch review")
+		return
+	}
+	if !h.canManageReview(r, review, userID) {
+		httpx.WriteError(w, http.StatusForbidden, httpx.ErrorCodeForbidden, "only the author or an admin can see a review's history")
+		return
+	}
+
+	edits, err := h.store.Gyms.GetReviewEdits(reviewID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch review history"))
+		return
+	}
+	h.signReviewPhotos(r.Context(), review)
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{
+		"review": review,
+		"edits":  edits,
+	})
+}
+
+// VoteGymReviewHelpful marks a review as helpful.
+func (h *Handlers) VoteGymReviewHelpful(w http.ResponseWriter, r *http.Request) {
+	h.setHelpfulVote(w, r, true)
+}
+
+// RemoveGymReviewHelpfulVote withdraws a helpful vote.
+func (h *Handlers) RemoveGymReviewHelpfulVote(w http.ResponseWriter, r *http.Request) {
+	h.setHelpfulVote(w, r, false)
+}
+
+func (h *Handlers) setHelpfulVote(w http.ResponseWriter, r *http.Request, helpful bool) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	gymID, reviewID := chi.URLParam(r, "id"), chi.URLParam(r, "reviewId")
+
+	var (
+		count int
+		err   error
+	)
+	if helpful {
+		count, err = h.store.Gyms.VoteHelpful(gymID, reviewID, userID)
+	} else {
+		count, err = h.store.Gyms.RemoveHelpfulVote(gymID, reviewID, userID)
+	}
+	if err != nil {
+		writeGymError(w, err, "failed to save helpful vote")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{
+		"review_id":     reviewID,
+		"helpful":       helpful,
+		"helpful_count": count,
+	})
+}
+
+// GetGymReviews returns paginated reviews, newest first unless sort asks for
+// helpful, highest or lowest.
+func (h *Handlers) GetGymReviews(w http.ResponseWriter, r *http.Request) {
+	gymID := chi.URLParam(r, "id")
+	limit := 20
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		if v, err := strconv.Atoi(raw); err == nil && v > 0 && v <= 50 {
+			limit = v
+		}
+	}
+	cursor := r.URL.Query().Get("cursor")
+	sort := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sort")))
+	if sort == "" {
+		sort = models.ReviewSortNewest
+	}
+	if !gymsstore.ValidReviewSort(sort) {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "sort must be newest, helpful, highest or lowest")
+		return
+	}
+
+	reviews, nextCursor, err := h.store.Gyms.GetReviews(gymID, sort, cursor, limit)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch reviews"))
+		return
+	}
+	for i := range reviews {
+		h.signReviewPhotos(r.Context(), &reviews[i])
+	}
+
+	response := map[string]any{
+		"reviews": reviews,
+		"next":    nextCursor,
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, response)
+}
+
+// validateReview checks the rating and, when moderation is on, the comment.
+func (h *Handlers) validateReview(req CreateGymReviewRequest) *httpx.APIError {
+	if req.Rating < 1 || req.Rating > 5 {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rating must be between 1 and 5")
+	}
+	if h.moderationEnabled {
+		if err := moderation.ValidateReview(req.Comment); err != nil {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
+		}
+	}
+	return nil
+}
+
+func (h *Handlers) canManageReview(r *http.Request, review *models.GymReview, userID string) bool {
+	if review.UserID == userID {
+		return true
+	}
+	user, ok := currentUserFromContext(r)
+	return ok && user.IsAdmin()
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateReview(t *testing.T) {
+	h := &Handlers{moderationEnabled: true}
+
+	if err := h.validateReview(CreateGymReviewRequest{Rating: 4, Comment: "Clean and well equipped."}); err != nil {
+		t.Fatalf("expected a valid review, got %v", err)
+	}
+	for _, rating := range []int{0, 6} {
+		if err := h.validateReview(CreateGymReviewRequest{Rating: rating}); err == nil || err.Status != http.StatusBadRequest {
+			t.Fatalf("rating %d: expected a 400, got %v", rating, err)
+		}
+	}
+}
+
+func TestGymReviewMutationsRequireAuth(t *testing.T) {
+	h := &Handlers{}
+
+	for _, tc := range []struct {
+		method  string
+		handler http.HandlerFunc
+	}{
+		{http.MethodPost, h.CreateGymReview},
+		{http.MethodPut, h.UpdateGymReview},
+		{http.MethodDelete, h.DeleteGymReview},
+		{http.MethodPut, h.VoteGymReviewHelpful},
+	} {
+		req := httptest.NewRequest(tc.method, "/v1/gyms/g1/reviews/r1", strings.NewReader(`{"rating":5}`))
+		res := httptest.NewRecorder()
+		tc.handler(res, req)
+		if res.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected status 401, got %d", tc.method, res.Code)
+		}
+	}
+}
+
+func TestGetGymReviewsRejectsUnknownSort(t *testing.T) {
+	h := &Handlers{}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/gyms/g1/reviews?sort=oldest", nil)
+	res := httptest.NewRecorder()
+	h.GetGymReviews(res, req)
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", res.Code)
+	}
+}
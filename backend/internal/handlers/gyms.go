@@ -1,20 +1,23 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"fitonex/backend/internal/gymhours"
 	"fitonex/backend/internal/httpx"
 	"fitonex/backend/internal/models"
-	"fitonex/backend/internal/moderation"
 	"fitonex/backend/internal/pagination"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 // GetNearbyGyms returns gyms ordered by distance with optional caching.
@@ -68,6 +71,12 @@ func (h *Handlers) GetNearbyGyms(w http.ResponseWriter, r *http.Request) {
 		limit = value
 	}
 
+	filter, apiErr := nearbyFilterFromQuery(query)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
 	var cursor *pagination.DistanceAscCursor
 	if cursorStr != "" {
 		decoded, err := pagination.DecodeCursor[pagination.DistanceAscCursor](cursorStr)
@@ -84,7 +93,7 @@ func (h *Handlers) GetNearbyGyms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf("NEARBY:%.4f:%.4f:%.2f:%d:%s", lat, lng, radius, limit, cursorStr)
+	cacheKey := fmt.Sprintf("NEARBY:%.4f:%.4f:%.2f:%d:%s", lat, lng, radius, limit, cursorStr) + nearbyFilterKey(filter)
 	var page pagination.Paginated[models.NearbyGym]
 	if h.cache != nil {
 		if ok, _ := h.cache.GetJSON(r.Context(), cacheKey, &page); ok {
@@ -93,7 +102,7 @@ func (h *Handlers) GetNearbyGyms(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	page, err := service.GetNearby(lat, lng, radius, limit, cursor)
+	page, err = service.GetNearby(lat, lng, radius, limit, cursor, filter)
 	if err != nil {
 		if errors.Is(err, pagination.ErrInvalidLimit) {
 			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be greater than zero")
@@ -115,15 +124,155 @@ func (h *Handlers) GetNearbyGyms(w http.ResponseWriter, r *http.Request) {
 	if h.analytics != nil {
 		uid, _ := userIDFromContext(r)
 		h.analytics.EmitEvent(r.Context(), uid, "map_opened", map[string]any{
-			"lat":    lat,
-			"lng":    lng,
-			"radius": radius,
+			"lat":       lat,
+			"lng":       lng,
+			"radius":    radius,
+			"open_only": filter.OpenAt != nil,
+			"filtered":  nearbyFilterKey(filter) != "",
 		})
 	}
 
 	httpx.WriteJSONWithCache(w, http.StatusOK, page, h.config.CacheTTLNearby)
 }
 
+const maxNearbyFilterValues = 20
+
+// nearbyFilterFromQuery reads the nearby search filters. List parameters
+// accept comma-separated values, repeated parameters or both.
+func nearbyFilterFromQuery(query url.Values) (models.NearbyFilter, *httpx.APIError) {
+	var filter models.NearbyFilter
+
+	filter.MachineIDs = queryList(query, "machines")
+	if len(filter.MachineIDs) > maxNearbyFilterValues {
+		return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("at most %d machines can be required", maxNearbyFilterValues))
+	}
+	for i, id := range filter.MachineIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "machines must be machine ids")
+		}
+		filter.MachineIDs[i] = parsed.String()
+	}
+	filter.MachineIDs = uniqueStrings(filter.MachineIDs)
+	switch strings.ToLower(strings.TrimSpace(query.Get("machines_match"))) {
+	case "", "all":
+	case "any":
+		filter.AnyMachine = len(filter.MachineIDs) > 0
+	default:
+		return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "machines_match must be all or any")
+	}
+
+	bodyParts := queryList(query, "body_parts")
+	for i, part := range bodyParts {
+		bodyParts[i] = strings.ToLower(part)
+	}
+	filter.BodyParts = uniqueStrings(bodyParts)
+	if len(filter.BodyParts) > maxNearbyFilterValues {
+		return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("at most %d body parts can be required", maxNearbyFilterValues))
+	}
+
+	if raw := strings.TrimSpace(query.Get("max_price_cents")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "max_price_cents must be a non-negative integer")
+		}
+		filter.MaxPriceCents = &value
+	}
+
+	if raw := strings.TrimSpace(query.Get("min_rating")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || value < 1 || value > 5 {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "min_rating must be a number between 1 and 5")
+		}
+		filter.MinRating = &value
+	}
+
+	amenities := queryList(query, "amenities")
+	for i, amenity := range amenities {
+		amenities[i] = strings.ToLower(amenity)
+		if !slices.Contains(models.Amenities, amenities[i]) {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, fmt.Sprintf("unknown amenity %q", amenity))
+		}
+	}
+	filter.Amenities = uniqueStrings(amenities)
+
+	if raw := strings.TrimSpace(query.Get("open_at")); raw != "" {
+		openAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "open_at must be an RFC 3339 timestamp")
+		}
+		openAt = openAt.UTC().Truncate(time.Minute)
+		filter.OpenAt = &openAt
+	} else if raw := strings.TrimSpace(query.Get("open_now")); raw != "" {
+		openNow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "open_now must be true or false")
+		}
+		if openNow {
+			now := time.Now().UTC().Truncate(time.Minute)
+			filter.OpenAt = &now
+		}
+	}
+
+	return filter, nil
+}
+
+// nearbyFilterKey renders a filter as a cache key suffix. Lists are sorted
+// so the same filter always maps to the same key; an empty filter adds
+// nothing, leaving unfiltered keys as they were.
+func nearbyFilterKey(filter models.NearbyFilter) string {
+	var key strings.Builder
+	list := func(name string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+		fmt.Fprintf(&key, ":%s=%s", name, strings.Join(sorted, ","))
+	}
+
+	list("machines", filter.MachineIDs)
+	if filter.AnyMachine {
+		key.WriteString(":match=any")
+	}
+	list("parts", filter.BodyParts)
+	if filter.MaxPriceCents != nil {
+		fmt.Fprintf(&key, ":price=%d", *filter.MaxPriceCents)
+	}
+	if filter.MinRating != nil {
+		fmt.Fprintf(&key, ":rating=%.2f", *filter.MinRating)
+	}
+	list("amenities", filter.Amenities)
+	if filter.OpenAt != nil {
+		fmt.Fprintf(&key, ":open=%d", filter.OpenAt.Unix())
+	}
+	return key.String()
+}
+
+// queryList collects a parameter's values, splitting each on commas and
+// dropping blanks.
+func queryList(query url.Values, name string) []string {
+	var values []string
+	for _, raw := range query[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+	for _, value := range values {
+		if !slices.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
 // GetGym returns a gym with cache awareness.
 func (h *Handlers) GetGym(w http.ResponseWriter, r *http.Request) {
 	gymID := chi.URLParam(r, "id")
@@ -131,6 +280,7 @@ func (h *Handlers) GetGym(w http.ResponseWriter, r *http.Request) {
 	var gym models.Gym
 	if h.cache != nil {
 		if ok, _ := h.cache.GetJSON(r.Context(), cacheKey, &gym); ok {
+			setOpenNow(&gym, time.Now())
 			httpx.WriteJSONWithCache(w, http.StatusOK, gym, h.config.CacheTTLGym)
 			return
 		}
@@ -143,13 +293,32 @@ func (h *Handlers) GetGym(w http.ResponseWriter, r *http.Request) {
 	}
 	gym = *result
 
+	hours, err := h.store.Gyms.GetHours(gymID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch gym hours"))
+		return
+	}
+	gym.Hours = hours
+
 	if h.cache != nil {
 		_ = h.cache.SetJSON(r.Context(), cacheKey, gym, h.config.CacheTTLGym)
 	}
 
+	setOpenNow(&gym, time.Now())
 	httpx.WriteJSONWithCache(w, http.StatusOK, gym, h.config.CacheTTLGym)
 }
 
+// setOpenNow is applied after the cache so open_now is never stale. It
+// stays unset when the gym has not published hours.
+func setOpenNow(gym *models.Gym, now time.Time) {
+	gym.OpenNow = nil
+	if gym.Hours == nil || !gymhours.Published(*gym.Hours) {
+		return
+	}
+	open := gymhours.OpenAt(*gym.Hours, now)
+	gym.OpenNow = &open
+}
+
 // GetGymMachines returns machines for a gym.
 func (h *Handlers) GetGymMachines(w http.ResponseWriter, r *http.Request) {
 	gymID := chi.URLParam(r, "id")
@@ -163,89 +332,30 @@ func (h *Handlers) GetGymMachines(w http.ResponseWriter, r *http.Request) {
 	httpx.WriteJSON(w, http.StatusOK, machines)
 }
 
-// GetGymPrices returns price plans for a gym.
-func (h *Handlers) GetGymPrices(w http.ResponseWriter, r *http.Request) {
-	gymID := chi.URLParam(r, "id")
-
-	prices, err := h.store.Gyms.GetPrices(gymID)
+// GetGymHours returns a gym's opening hours and whether it is open now.
+func (h *Handlers) GetGymHours(w http.ResponseWriter, r *http.Request) {
+	hours, err := h.store.Gyms.GetHours(chi.URLParam(r, "id"))
 	if err != nil {
-		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch gym prices"))
-		return
-	}
-
-	httpx.WriteJSON(w, http.StatusOK, prices)
-}
-
-type CreateGymReviewRequest struct {
-	Rating  int    `json:"rating"`
-	Comment string `json:"comment"`
-}
-
-// CreateGymReview handles moderated review creation.
-func (h *Handlers) CreateGymReview(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
-	gymID := chi.URLParam(r, "id")
-
-	var req CreateGymReviewRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		writeGymError(w, err, "failed to fetch gym hours")
 		return
 	}
 
-	if req.Rating < 1 || req.Rating > 5 {
-		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rating must be between 1 and 5")
-		return
+	response := map[string]any{"hours": hours}
+	if gymhours.Published(*hours) {
+		response["open_now"] = gymhours.OpenAt(*hours, time.Now())
 	}
-
-	if h.moderationEnabled {
-		if err := moderation.ValidateReview(req.Comment); err != nil {
-			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, err.Error())
-			return
-		}
-	}
-
-	review, err := h.store.Gyms.CreateReview(gymID, userID, req.Rating, req.Comment)
-	if err != nil {
-		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to create review"))
-		return
-	}
-
-	if h.cache != nil {
-		_ = h.cache.Delete(r.Context(), fmt.Sprintf("GYM:%s", gymID))
-		_ = h.cache.InvalidatePrefix(r.Context(), "NEARBY:", 200)
-	}
-
-	if h.analytics != nil {
-		h.analytics.EmitEvent(r.Context(), userID, "review_created", map[string]any{
-			"gym_id": gymID,
-			"rating": req.Rating,
-		})
-	}
-
-	httpx.WriteJSON(w, http.StatusCreated, review)
+	httpx.WriteJSON(w, http.StatusOK, response)
 }
 
-// GetGymReviews returns paginated reviews.
-func (h *Handlers) GetGymReviews(w http.ResponseWriter, r *http.Request) {
+// GetGymPrices returns price plans for a gym.
+func (h *Handlers) GetGymPrices(w http.ResponseWriter, r *http.Request) {
 	gymID := chi.URLParam(r, "id")
-	limit := 20
-	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
-		if v, err := strconv.Atoi(raw); err == nil && v > 0 && v <= 50 {
-			limit = v
-		}
-	}
-	cursor := r.URL.Query().Get("cursor")
 
-	reviews, nextCursor, err := h.store.Gyms.GetReviews(gymID, cursor, limit)
+	prices, err := h.store.Gyms.GetPrices(gymID)
 	if err != nil {
-		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch reviews"))
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch gym prices"))
 		return
 	}
 
-	response := map[string]any{
-		"reviews": reviews,
-		"next":    nextCursor,
-	}
-
-	httpx.WriteJSON(w, http.StatusOK, response)
+	httpx.WriteJSON(w, http.StatusOK, prices)
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"fitonex/backend/internal/config"
 	"fitonex/backend/internal/httpx"
@@ -22,10 +24,12 @@ type fakeGymsService struct {
 	radiusKm    float64
 	limit       int
 	cursor      *pagination.DistanceAscCursor
+	filter      models.NearbyFilter
 }
 
-func (f *fakeGymsService) GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor) (pagination.Paginated[models.NearbyGym], error) {
+func (f *fakeGymsService) GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor, filter models.NearbyFilter) (pagination.Paginated[models.NearbyGym], error) {
 	f.called = true
+	f.filter = filter
 	f.lat = lat
 	f.lng = lng
 	f.radiusKm = radiusKm
@@ -293,6 +297,108 @@ func TestGetNearbyGymsLimitClamp(t *testing.T) {
 	}
 }
 
+func TestGetNearbyGymsOpenFilter(t *testing.T) {
+	service := &fakeGymsService{}
+	h := &Handlers{
+		config:      &config.Config{},
+		gymsService: service,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/gyms/nearby?lat=47.6&lng=-122.3&open_at=2024-03-10T02:30:45-08:00", nil)
+	res := httptest.NewRecorder()
+	h.GetNearbyGyms(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", res.Code)
+	}
+	want := time.Date(2024, 3, 10, 10, 30, 0, 0, time.UTC)
+	if service.filter.OpenAt == nil || !service.filter.OpenAt.Equal(want) {
+		t.Fatalf("expected open_at truncated to %v, got %v", want, service.filter.OpenAt)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/gyms/nearby?lat=47.6&lng=-122.3&open_now=false", nil)
+	h.GetNearbyGyms(httptest.NewRecorder(), req)
+	if service.filter.OpenAt != nil {
+		t.Fatalf("expected no open filter, got %v", service.filter.OpenAt)
+	}
+
+	for _, raw := range []string{"open_at=tomorrow", "open_now=maybe"} {
+		service.called = false
+		req = httptest.NewRequest(http.MethodGet, "/v1/gyms/nearby?lat=47.6&lng=-122.3&"+raw, nil)
+		res = httptest.NewRecorder()
+		h.GetNearbyGyms(res, req)
+		if res.Code != http.StatusBadRequest || service.called {
+			t.Fatalf("%s: expected status 400 without a query, got %d", raw, res.Code)
+		}
+	}
+}
+
+func TestGetNearbyGymsFilters(t *testing.T) {
+	service := &fakeGymsService{}
+	h := &Handlers{
+		config:      &config.Config{},
+		gymsService: service,
+	}
+
+	machineA := "6F9619FF-8B86-D011-B42D-00C04FC964FF"
+	machineB := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	req := httptest.NewRequest(http.MethodGet, "/v1/gyms/nearby?lat=47.6&lng=-122.3&machines="+machineA+","+machineB+
+		"&machines="+machineB+"&machines_match=any&body_parts=Legs,%20back&max_price_cents=5000&min_rating=4&amenities=Sauna&amenities=pool,sauna", nil)
+	res := httptest.NewRecorder()
+	h.GetNearbyGyms(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", res.Code, res.Body.String())
+	}
+	filter := service.filter
+	if len(filter.MachineIDs) != 2 || filter.MachineIDs[0] != strings.ToLower(machineA) || !filter.AnyMachine {
+		t.Fatalf("unexpected machine filter %+v", filter)
+	}
+	if strings.Join(filter.BodyParts, ",") != "legs,back" || strings.Join(filter.Amenities, ",") != "sauna,pool" {
+		t.Fatalf("unexpected body part or amenity filter %+v", filter)
+	}
+	if filter.MaxPriceCents == nil || *filter.MaxPriceCents != 5000 || filter.MinRating == nil || *filter.MinRating != 4 {
+		t.Fatalf("unexpected price or rating filter %+v", filter)
+	}
+
+	for _, raw := range []string{
+		"machines=not-a-uuid",
+		"machines_match=some",
+		"max_price_cents=-1",
+		"min_rating=6",
+		"amenities=helipad",
+	} {
+		service.called = false
+		req = httptest.NewRequest(http.MethodGet, "/v1/gyms/nearby?lat=47.6&lng=-122.3&"+raw, nil)
+		res = httptest.NewRecorder()
+		h.GetNearbyGyms(res, req)
+		if res.Code != http.StatusBadRequest || service.called {
+			t.Fatalf("%s: expected status 400 without a query, got %d", raw, res.Code)
+		}
+	}
+}
+
+func TestNearbyFilterKeyIsCanonical(t *testing.T) {
+	rating := 4.0
+	a := models.NearbyFilter{BodyParts: []string{"legs", "back"}, Amenities: []string{"sauna", "pool"}, MinRating: &rating}
+	b := models.NearbyFilter{BodyParts: []string{"back", "legs"}, Amenities: []string{"pool", "sauna"}, MinRating: &rating}
+	if nearbyFilterKey(a) != nearbyFilterKey(b) {
+		t.Fatalf("expected equal keys, got %q and %q", nearbyFilterKey(a), nearbyFilterKey(b))
+	}
+	if a.BodyParts[0] != "legs" {
+		t.Fatal("expected filter lists to be left unsorted")
+	}
+	if nearbyFilterKey(models.NearbyFilter{}) != "" {
+		t.Fatal("expected an empty filter to add nothing to the key")
+	}
+
+	all := models.NearbyFilter{MachineIDs: []string{"m1"}}
+	anyMachine := models.NearbyFilter{MachineIDs: []string{"m1"}, AnyMachine: true}
+	if nearbyFilterKey(all) == nearbyFilterKey(anyMachine) {
+		t.Fatal("expected all and any machine matches to use different keys")
+	}
+}
+
 func floatPtr(v float64) *float64 {
 	return &v
 }
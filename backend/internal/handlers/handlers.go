@@ -14,11 +14,12 @@ import (
 	"fitonex/backend/internal/payments"
 	"fitonex/backend/internal/pagination"
 	"fitonex/backend/internal/ratelimit"
+	"fitonex/backend/internal/storage"
 	"fitonex/backend/internal/store"
 )
 
 type nearbyGymsService interface {
-	GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor) (pagination.Paginated[models.NearbyGym], error)
+	GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor, filter models.NearbyFilter) (pagination.Paginated[models.NearbyGym], error)
 }
 
 type machineService interface {
@@ -38,7 +39,11 @@ type videoService interface {
 
 type objectStorage interface {
 	PresignPut(ctx context.Context, key, contentType string, sizeBytes int64, ttl time.Duration) (string, error)
+	PutObject(ctx context.Context, key, contentType string, data []byte) error
 	SignedGet(ctx context.Context, key string, cdnBase string, ttl time.Duration) (string, error)
+	StatObject(ctx context.Context, key string) (storage.ObjectInfo, error)
+	GetObject(ctx context.Context, key string, maxBytes int64) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
 	Ping(ctx context.Context) error
 }
 
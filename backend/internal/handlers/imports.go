@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/importer"
+	"fitonex/backend/internal/models"
+	importsstore "fitonex/backend/internal/store/imports"
+	"fitonex/backend/internal/strength"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxImportBytes = 10 << 20
+	// minImportMatchScore is the trigram similarity needed to link an
+	// exercise name to a machine; weaker matches stay custom exercises.
+	minImportMatchScore = 0.35
+)
+
+// ImportWorkouts imports a Strong or Hevy CSV export. The file is sent either
+// as the "file" field of a multipart form or as the raw request body.
+// Uploading the same file again returns the original import unchanged.
+func (h *Handlers) ImportWorkouts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	source := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("source")))
+	switch source {
+	case "", "auto":
+		source = ""
+	case models.ImportSourceStrong, models.ImportSourceHevy:
+	default:
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "source must be one of auto, strong, hevy")
+		return
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("unit")))
+	switch unit {
+	case "":
+		unit = "kg"
+	case "kg", "lb":
+	default:
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit must be kg or lb")
+		return
+	}
+
+	data, apiErr := readImportFile(w, r)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	parsed, err := importer.Parse(bytes.NewReader(data), source, unit)
+	if err != nil {
+		if errors.Is(err, importer.ErrUnknownFormat) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "file is not a Strong or Hevy CSV export")
+			return
+		}
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, strings.TrimPrefix(err.Error(), "importer: "))
+		return
+	}
+	if len(parsed.Sessions) == 0 {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "file contains no importable sets")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	imp := &models.WorkoutImport{
+		UserID:   userID,
+		Source:   parsed.Source,
+		FileHash: hex.EncodeToString(sum[:]),
+		Skipped:  parsed.Skipped,
+	}
+
+	sessions, err := h.mapImportSessions(imp, parsed.Sessions)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to match machines"))
+		return
+	}
+
+	result, err := h.store.Imports.Create(imp, sessions)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to import workouts"))
+		return
+	}
+	if result.Duplicate {
+		httpx.WriteJSON(w, http.StatusOK, result)
+		return
+	}
+
+	h.rebuildImportedRecords(userID, sessions)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "workouts_imported", map[string]any{
+			"import_id": result.ID,
+			"source":    result.Source,
+			"workouts":  result.Workouts,
+			"sets":      result.Sets,
+			"unmapped":  len(result.Unmapped),
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, result)
+}
+
+// GetImport returns one of the caller's import reports
+func (h *Handlers) GetImport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	imp, err := h.store.Imports.GetByID(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		if errors.Is(err, importsstore.ErrImportNotFound) {
+			httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "import not found")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch import"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, imp)
+}
+
+func readImportFile(w http.ResponseWriter, r *http.Request) ([]byte, *httpx.APIError) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	var reader io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid multipart form or file larger than 10MB")
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "file is required")
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "file must be 10MB or smaller")
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "file is required")
+	}
+	return data, nil
+}
+
+// mapImportSessions links exercise names to machines and converts parsed
+// sessions into workouts. Names without a good match are imported as custom
+// exercises and reported in Unmapped.
+func (h *Handlers) mapImportSessions(imp *models.WorkoutImport, parsed []importer.Session) ([]importsstore.Session, error) {
+	rowsByName := map[string][]int{}
+	var names []string
+	for _, session := range parsed {
+		for _, exercise := range session.Exercises {
+			if _, seen := rowsByName[exercise.Name]; !seen {
+				names = append(names, exercise.Name)
+			}
+			rowsByName[exercise.Name] = append(rowsByName[exercise.Name], exercise.Rows...)
+		}
+	}
+	sort.Strings(names)
+
+	matches, err := h.store.Machines.BestMatches(names, minImportMatchScore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		match, ok := matches[name]
+		if !ok {
+			rows := rowsByName[name]
+			sort.Ints(rows)
+			imp.Unmapped = append(imp.Unmapped, models.ImportIssue{Rows: rows, ExerciseName: name, Reason: "no matching machine"})
+			continue
+		}
+		imp.Mappings = append(imp.Mappings, models.ImportMapping{
+			ExerciseName: name,
+			MachineID:    match.ID,
+			MachineName:  match.Name,
+			Score:        match.Score,
+		})
+	}
+
+	sessions := make([]importsstore.Session, 0, len(parsed))
+	for _, session := range parsed {
+		items := make([]models.Exercise, 0, len(session.Exercises))
+		for _, exercise := range session.Exercises {
+			item := models.Exercise{Name: exercise.Name, Sets: exercise.Sets}
+			if match, ok := matches[exercise.Name]; ok {
+				machineID, machineName := match.ID, match.Name
+				item.MachineID = &machineID
+				item.MachineName = &machineName
+			}
+			items = append(items, item)
+		}
+
+		sessions = append(sessions, importsstore.Session{
+			Key: session.Key,
+			Workout: models.Workout{
+				Name:        session.Name,
+				Description: session.Notes,
+				Duration:    session.DurationMinutes,
+				Type:        "strength",
+				CreatedAt:   session.StartedAt,
+			},
+			Exercises: items,
+		})
+	}
+	return sessions, nil
+}
+
+// rebuildImportedRecords replays record history for every lift in the import.
+// Imported sessions are usually older than what is already logged, so the
+// records are rebuilt rather than detected, and no record events are sent.
+func (h *Handlers) rebuildImportedRecords(userID string, sessions []importsstore.Session) {
+	done := map[string]bool{}
+	for _, session := range sessions {
+		for _, exercise := range session.Exercises {
+			key := strength.RecordKey(exercise.MachineID, exercise.Name)
+			if done[key] {
+				continue
+			}
+			done[key] = true
+
+			history, err := h.store.Exercises.ListForRecordKey(userID, exercise.MachineID, exercise.Name)
+			if err != nil {
+				log.Printf("failed to load record history for user %s exercise %s: %v", userID, exercise.ID, err)
+				continue
+			}
+			if _, err := h.store.Records.ReplaceForKey(userID, key, strength.Replay(history)); err != nil {
+				log.Printf("failed to rebuild personal records for user %s exercise %s: %v", userID, exercise.ID, err)
+			}
+		}
+	}
+}
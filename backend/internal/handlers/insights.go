@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/training"
+)
+
+const (
+	defaultTrainingLoadWeeks = 12
+	maxTrainingLoadWeeks     = 52
+)
+
+// GetTrainingLoad returns weekly training load with the acute:chronic
+// workload ratio, weekly sets per body part, per-workout load and warnings
+// for weeks where load spiked.
+func (h *Handlers) GetTrainingLoad(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	query, apiErr := parseTrainingLoadQuery(r, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	insights, err := h.store.Training.Insights(userID, query)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch training load"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, insights)
+}
+
+// parseTrainingLoadQuery reads metric, weeks and to. The range covers whole
+// weeks and ends with the week containing to.
+func parseTrainingLoadQuery(r *http.Request, now time.Time) (models.TrainingLoadQuery, *httpx.APIError) {
+	values := r.URL.Query()
+
+	query := models.TrainingLoadQuery{Metric: strings.TrimSpace(values.Get("metric"))}
+	if query.Metric == "" {
+		query.Metric = models.TrainingMetricSessionLoad
+	}
+	if query.Metric != models.TrainingMetricSessionLoad && query.Metric != models.TrainingMetricSetEffort {
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "metric must be session_load or set_effort")
+	}
+
+	weeks := defaultTrainingLoadWeeks
+	if weeksParam := strings.TrimSpace(values.Get("weeks")); weeksParam != "" {
+		value, err := strconv.Atoi(weeksParam)
+		if err != nil || value <= 0 || value > maxTrainingLoadWeeks {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "weeks must be between 1 and 52")
+		}
+		weeks = value
+	}
+
+	to := now
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+
+	lastWeek := training.WeekStart(to)
+	query.From = lastWeek.AddDate(0, 0, -7*(weeks-1))
+	query.To = lastWeek.AddDate(0, 0, 7)
+	return query, nil
+}
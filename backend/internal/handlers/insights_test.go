@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestParseTrainingLoadQuery(t *testing.T) {
+	now := time.Date(2024, 5, 9, 15, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/insights/training-load", nil)
+	query, apiErr := parseTrainingLoadQuery(req, now)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if query.Metric != models.TrainingMetricSessionLoad {
+		t.Fatalf("unexpected metric %q", query.Metric)
+	}
+	if query.To.Format("2006-01-02") != "2024-05-13" || query.To.Sub(query.From) != 12*7*24*time.Hour {
+		t.Fatalf("expected twelve whole weeks ending this week, got %v - %v", query.From, query.To)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/insights/training-load?metric=set_effort&weeks=1&to=2024-03-03", nil)
+	query, apiErr = parseTrainingLoadQuery(req, now)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if query.From.Format("2006-01-02") != "2024-02-26" || query.To.Format("2006-01-02") != "2024-03-04" {
+		t.Fatalf("unexpected range %v - %v", query.From, query.To)
+	}
+
+	for _, raw := range []string{"metric=volume", "weeks=0", "weeks=53", "to=03-03-2024"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/insights/training-load?"+raw, nil)
+		if _, apiErr := parseTrainingLoadQuery(req, now); apiErr == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/planner"
+	"fitonex/backend/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultGenerateMinutes = 45
+	minGenerateMinutes     = 10
+	maxGenerateMinutes     = 180
+	maxGenerateBodyParts   = 10
+)
+
+// GenerateWorkoutRequest represents the workout generation payload
+type GenerateWorkoutRequest struct {
+	BodyParts []string `json:"body_parts"`
+	Minutes   int      `json:"minutes"`
+	// Seed makes the plan reproducible; a random one is picked and returned when omitted.
+	Seed *int64 `json:"seed,omitempty"`
+	// Save stores the plan as a planned workout, performed once its first
+	// exercise is logged.
+	Save bool   `json:"save"`
+	Name string `json:"name,omitempty"`
+}
+
+// GenerateWorkout plans a balanced workout from the machines available at a
+// gym, with the most-liked instruction video for each machine.
+func (h *Handlers) GenerateWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req GenerateWorkoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := normalizeGenerateWorkoutRequest(&req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	gymID := chi.URLParam(r, "id")
+	gym, err := h.store.Gyms.GetByID(gymID)
+	if err != nil {
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "gym not found")
+		return
+	}
+
+	machines, err := h.store.Gyms.GetMachines(gymID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch machines"))
+		return
+	}
+
+	plan, err := planner.Generate(machines, planner.Request{BodyParts: req.BodyParts, Minutes: req.Minutes, Seed: *req.Seed})
+	if err != nil {
+		if errors.Is(err, planner.ErrNoMachines) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "gym has no machines for the requested body parts")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to generate workout"))
+		return
+	}
+
+	generated := models.GeneratedWorkout{
+		GymID:            gymID,
+		Seed:             *req.Seed,
+		BodyParts:        plan.BodyParts,
+		MissingBodyParts: plan.Missing,
+		BudgetMinutes:    req.Minutes,
+		EstimatedMinutes: (plan.EstimatedSeconds + 59) / 60,
+		Exercises:        plan.Exercises,
+	}
+	if err := h.attachPlanVideos(r, userID, generated.Exercises); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch videos"))
+		return
+	}
+
+	if req.Save {
+		name := req.Name
+		if name == "" {
+			name = gym.Name + " workout"
+		}
+		workout, err := h.store.Workouts.CreatePlanned(userID, name, "", generated.EstimatedMinutes, "strength", plannedExercisesFromPlan(gymID, generated.Exercises))
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to save workout"))
+			return
+		}
+		generated.Workout = workout
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "workout_generated", map[string]any{
+			"gym_id":     gymID,
+			"body_parts": generated.BodyParts,
+			"minutes":    req.Minutes,
+			"exercises":  len(generated.Exercises),
+			"saved":      req.Save,
+		})
+	}
+
+	status := http.StatusOK
+	if req.Save {
+		status = http.StatusCreated
+	}
+	httpx.WriteJSON(w, status, generated)
+}
+
+// attachPlanVideos adds the most-liked video of each planned machine. Premium
+// videos keep their metadata but only get a play URL for premium users.
+func (h *Handlers) attachPlanVideos(r *http.Request, userID string, exercises []models.GeneratedExercise) error {
+	machineIDs := make([]string, 0, len(exercises))
+	for _, exercise := range exercises {
+		machineIDs = append(machineIDs, exercise.MachineID)
+	}
+	videos, err := h.store.Videos.MostLikedByMachines(machineIDs)
+	if err != nil {
+		return err
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		if service, err := storage.NewS3Service(h.config); err == nil {
+			h.SetObjectStorage(service)
+			storageSvc = service
+		}
+	}
+	userPremium := h.userIsPremium(userID)
+
+	for i := range exercises {
+		video, ok := videos[exercises[i].MachineID]
+		if !ok {
+			continue
+		}
+		if storageSvc != nil && (!video.PremiumOnly || userPremium) {
+			if playURL, err := storageSvc.SignedGet(r.Context(), video.VideoKey, h.cdnBaseURL, 5*time.Minute); err == nil {
+				video.PlayURL = playURL
+			}
+		}
+		exercises[i].Video = &video
+	}
+	return nil
+}
+
+// plannedExercisesFromPlan turns a generated plan into the planned exercises
+// of a workout at the gym; weights are chosen when the sets are logged.
+func plannedExercisesFromPlan(gymID string, planned []models.GeneratedExercise) []models.PlannedExercise {
+	exercises := make([]models.PlannedExercise, 0, len(planned))
+	for _, exercise := range planned {
+		machineID := exercise.MachineID
+		gym := gymID
+		exercises = append(exercises, models.PlannedExercise{
+			GymID:       &gym,
+			MachineID:   &machineID,
+			Name:        exercise.MachineName,
+			Sets:        exercise.Sets,
+			Reps:        exercise.Reps,
+			RestSeconds: exercise.RestSeconds,
+		})
+	}
+	return exercises
+}
+
+func normalizeGenerateWorkoutRequest(req *GenerateWorkoutRequest) *httpx.APIError {
+	if req.Minutes == 0 {
+		req.Minutes = defaultGenerateMinutes
+	}
+	if req.Minutes < minGenerateMinutes || req.Minutes > maxGenerateMinutes {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "minutes must be between 10 and 180")
+	}
+	if len(req.BodyParts) > maxGenerateBodyParts {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "at most 10 body parts can be requested")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Seed == nil {
+		seed := rand.Int63()
+		req.Seed = &seed
+	}
+	return nil
+}
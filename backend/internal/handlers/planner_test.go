@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestNormalizeGenerateWorkoutRequest(t *testing.T) {
+	req := GenerateWorkoutRequest{Name: "  Push day "}
+	if apiErr := normalizeGenerateWorkoutRequest(&req); apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if req.Minutes != defaultGenerateMinutes || req.Seed == nil || req.Name != "Push day" {
+		t.Fatalf("unexpected defaults %+v", req)
+	}
+
+	seed := int64(0)
+	req = GenerateWorkoutRequest{Minutes: 30, Seed: &seed}
+	if apiErr := normalizeGenerateWorkoutRequest(&req); apiErr != nil || *req.Seed != 0 {
+		t.Fatalf("a zero seed must be kept, got %+v %v", req, apiErr)
+	}
+
+	for _, minutes := range []int{5, 181} {
+		req := GenerateWorkoutRequest{Minutes: minutes}
+		if apiErr := normalizeGenerateWorkoutRequest(&req); apiErr == nil {
+			t.Fatalf("expected %d minutes to be rejected", minutes)
+		}
+	}
+}
+
+func TestPlannedExercisesFromPlan(t *testing.T) {
+	exercises := plannedExercisesFromPlan("g1", []models.GeneratedExercise{
+		{Position: 1, MachineID: "m1", MachineName: "Chest Press", Sets: 3, Reps: 10, RestSeconds: 90},
+		{Position: 2, MachineID: "m2", MachineName: "Leg Press", Sets: 4, Reps: 8, RestSeconds: 120},
+	})
+	if len(exercises) != 2 || *exercises[0].GymID != "g1" || *exercises[0].MachineID != "m1" || exercises[0].Name != "Chest Press" {
+		t.Fatalf("unexpected exercises %+v", exercises)
+	}
+	if second := exercises[1]; second.Sets != 4 || second.Reps != 8 || second.RestSeconds != 120 {
+		t.Fatalf("unexpected targets %+v", second)
+	}
+}
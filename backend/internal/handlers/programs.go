@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	programsstore "fitonex/backend/internal/store/programs"
+	templatesstore "fitonex/backend/internal/store/templates"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxProgramWeeks = 52
+
+// ProgramRequest represents the create and update program payload
+type ProgramRequest struct {
+	Name        string              `json:"name"`
+	Description *string             `json:"description,omitempty"`
+	Weeks       int                 `json:"weeks"`
+	StartDate   *string             `json:"start_date,omitempty"`
+	Days        []models.ProgramDay `json:"days"`
+}
+
+// ProgramScheduleResponse represents a program resolved onto the calendar
+type ProgramScheduleResponse struct {
+	ProgramID string                    `json:"program_id"`
+	StartDate time.Time                 `json:"start_date"`
+	Items     []models.ScheduledWorkout `json:"items"`
+}
+
+// CreateProgram handles creating a training program
+func (h *Handlers) CreateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	startDate, apiErr := h.normalizeProgramRequest(userID, &req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	program, err := h.store.Programs.Create(userID, req.Name, req.Description, req.Weeks, startDate, req.Days)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to create program"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, program)
+}
+
+// GetPrograms lists the caller's programs
+func (h *Handlers) GetPrograms(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	programs, err := h.store.Programs.ListByOwner(userID)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch programs"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": programs})
+}
+
+// GetProgram returns one of the caller's programs
+func (h *Handlers) GetProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	program, err := h.store.Programs.GetByID(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeProgramError(w, err, "failed to fetch program")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, program)
+}
+
+// UpdateProgram replaces one of the caller's programs
+func (h *Handlers) UpdateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	startDate, apiErr := h.normalizeProgramRequest(userID, &req)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	program, err := h.store.Programs.Update(chi.URLParam(r, "id"), userID, req.Name, req.Description, req.Weeks, startDate, req.Days)
+	if err != nil {
+		writeProgramError(w, err, "failed to update program")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, program)
+}
+
+// DeleteProgram removes one of the caller's programs
+func (h *Handlers) DeleteProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.store.Programs.Delete(chi.URLParam(r, "id"), userID); err != nil {
+		writeProgramError(w, err, "failed to delete program")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProgramSchedule resolves program days to dates, starting from ?start=
+// or the program's start date.
+func (h *Handlers) GetProgramSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	program, err := h.store.Programs.GetByID(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeProgramError(w, err, "failed to fetch program")
+		return
+	}
+
+	var start time.Time
+	if startStr := strings.TrimSpace(r.URL.Query().Get("start")); startStr != "" {
+		start, err = time.Parse("2006-01-02", startStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "start must use YYYY-MM-DD format")
+			return
+		}
+	} else if program.StartDate != nil {
+		start = *program.StartDate
+	} else {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "start is required when the program has no start_date")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, ProgramScheduleResponse{
+		ProgramID: program.ID,
+		StartDate: start,
+		Items:     programsstore.Schedule(*program, start),
+	})
+}
+
+func (h *Handlers) normalizeProgramRequest(userID string, req *ProgramRequest) (*time.Time, *httpx.APIError) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "name is required")
+	}
+	req.Description = trimmedOptional(req.Description)
+
+	if req.Weeks <= 0 || req.Weeks > maxProgramWeeks {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "weeks must be between 1 and 52")
+	}
+
+	var startDate *time.Time
+	if value := trimmedOptional(req.StartDate); value != nil {
+		parsed, err := time.Parse("2006-01-02", *value)
+		if err != nil {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "start_date must use YYYY-MM-DD format")
+		}
+		startDate = &parsed
+	}
+
+	if len(req.Days) == 0 {
+		return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "days are required")
+	}
+
+	seen := make(map[string]bool, len(req.Days))
+	resolved := map[string]string{}
+	for i := range req.Days {
+		day := &req.Days[i]
+		day.TemplateID = strings.TrimSpace(day.TemplateID)
+
+		if day.Week < 1 || day.Week > req.Weeks {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "day week must be within the program weeks")
+		}
+		if day.DayOfWeek < 1 || day.DayOfWeek > 7 {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "day_of_week must be between 1 (Monday) and 7 (Sunday)")
+		}
+		slot := fmt.Sprintf("%d-%d", day.Week, day.DayOfWeek)
+		if seen[slot] {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "only one template can be scheduled per day")
+		}
+		seen[slot] = true
+
+		if day.TemplateID == "" {
+			return nil, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "template_id is required")
+		}
+		if id, ok := resolved[day.TemplateID]; ok {
+			day.TemplateID = id
+			continue
+		}
+		id, apiErr := h.ownedProgramTemplate(userID, day.TemplateID)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		resolved[day.TemplateID] = id
+		day.TemplateID = id
+	}
+
+	return startDate, nil
+}
+
+// ownedProgramTemplate returns the ID of a template the caller owns for a
+// program day. Templates shared by other users are copied into the caller's
+// library first, so their owner editing or deleting them never changes the
+// caller's program.
+func (h *Handlers) ownedProgramTemplate(userID, templateID string) (string, *httpx.APIError) {
+	template, err := h.store.Templates.GetByID(templateID)
+	if err != nil {
+		if errors.Is(err, templatesstore.ErrTemplateNotFound) {
+			return "", httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "template not found: "+templateID)
+		}
+		return "", httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch template")
+	}
+	if template.OwnerID == userID {
+		return template.ID, nil
+	}
+
+	copied, err := h.store.Templates.GetCopy(userID, template.ID)
+	if err == nil {
+		return copied.ID, nil
+	}
+	if !errors.Is(err, templatesstore.ErrTemplateNotFound) {
+		return "", httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch template")
+	}
+	copied, err = h.store.Templates.Create(userID, template.Name, template.Description, &template.ID, template.Exercises)
+	if err != nil {
+		return "", httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to copy template")
+	}
+	return copied.ID, nil
+}
+
+func writeProgramError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, programsstore.ErrProgramNotFound) {
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "program not found")
+		return
+	}
+	httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+}
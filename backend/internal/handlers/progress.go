@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+)
+
+const (
+	defaultProgressWeeks  = 12
+	defaultProgressMonths = 12
+	maxProgressRange      = 10 * 366 * 24 * time.Hour
+)
+
+// GetProgress returns volume, set counts and best e1RM as time series grouped
+// per machine, per body part or in total.
+func (h *Handlers) GetProgress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	query, apiErr := parseProgressQuery(r, time.Now().UTC())
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	report, err := h.store.Exercises.Progress(userID, query)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch progress"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, report)
+}
+
+// parseProgressQuery reads group_by, interval, from, to, machine_id and body_part.
+// from and to are inclusive days; the returned range is [from, to+1day).
+func parseProgressQuery(r *http.Request, now time.Time) (models.ProgressQuery, *httpx.APIError) {
+	values := r.URL.Query()
+
+	query := models.ProgressQuery{
+		GroupBy:  strings.TrimSpace(values.Get("group_by")),
+		Interval: strings.TrimSpace(values.Get("interval")),
+	}
+	if query.GroupBy == "" {
+		query.GroupBy = models.ProgressGroupMachine
+	}
+	switch query.GroupBy {
+	case models.ProgressGroupMachine, models.ProgressGroupBodyPart, models.ProgressGroupTotal:
+	default:
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "group_by must be machine, body_part or total")
+	}
+
+	if query.Interval == "" {
+		query.Interval = models.ProgressIntervalWeek
+	}
+	if query.Interval != models.ProgressIntervalWeek && query.Interval != models.ProgressIntervalMonth {
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "interval must be week or month")
+	}
+
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr := strings.TrimSpace(values.Get("to")); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -7*defaultProgressWeeks+1)
+	if query.Interval == models.ProgressIntervalMonth {
+		from = to.AddDate(0, -defaultProgressMonths, 1)
+	}
+	if fromStr := strings.TrimSpace(values.Get("from")); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must not be after to")
+	}
+	if to.Sub(from) > maxProgressRange {
+		return query, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "date range must not exceed 10 years")
+	}
+
+	query.From = from
+	query.To = to.AddDate(0, 0, 1)
+	query.MachineID = optionalQueryParam(r, "machine_id")
+	query.BodyPart = optionalQueryParam(r, "body_part")
+
+	return query, nil
+}
+
+func optionalQueryParam(r *http.Request, key string) *string {
+	value := strings.TrimSpace(r.URL.Query().Get(key))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
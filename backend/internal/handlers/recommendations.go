@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/progression"
+)
+
+const (
+	maxRecommendationIncrement = 50
+	maxRecommendationReps      = 50
+	maxRecommendationSets      = 10
+)
+
+// GetRecommendation proposes target weight and reps per set for the next
+// session on a machine or exercise, based on the user's history for it.
+func (h *Handlers) GetRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	machineID := optionalQueryParam(r, "machine_id")
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if machineID == nil && name == "" {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "machine_id or name is required")
+		return
+	}
+
+	algorithm, params, apiErr := parseRecommendationQuery(r)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	history, err := h.store.Exercises.ListRecentForRecordKey(userID, machineID, name, progression.HistorySessions)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch exercise history"))
+		return
+	}
+
+	recommendation, err := progression.Recommend(algorithm, history, params)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to build recommendation"))
+		return
+	}
+	recommendation.MachineID = machineID
+	recommendation.Name = name
+
+	httpx.WriteJSON(w, http.StatusOK, recommendation)
+}
+
+// parseRecommendationQuery reads algorithm, unit, increment, rep_min,
+// rep_max, sets and target_rpe on top of the unit's defaults.
+func parseRecommendationQuery(r *http.Request) (string, progression.Params, *httpx.APIError) {
+	values := r.URL.Query()
+
+	algorithm := strings.TrimSpace(values.Get("algorithm"))
+	if algorithm == "" {
+		algorithm = progression.DefaultAlgorithm
+	}
+	known := false
+	for _, name := range progression.Names() {
+		known = known || name == algorithm
+	}
+	if !known {
+		return "", progression.Params{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "algorithm must be one of "+strings.Join(progression.Names(), ", "))
+	}
+
+	unit := strings.TrimSpace(values.Get("unit"))
+	if unit == "" {
+		unit = models.UnitKg
+	}
+	if unit != models.UnitKg && unit != models.UnitLb {
+		return "", progression.Params{}, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "unit must be kg or lb")
+	}
+	params := progression.DefaultParams(unit)
+
+	if raw := strings.TrimSpace(values.Get("increment")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value <= 0 || value > maxRecommendationIncrement {
+			return "", params, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "increment must be greater than 0 and at most 50")
+		}
+		params.Increment = value
+	}
+
+	for _, field := range []struct {
+		key   string
+		limit int
+		dest  *int
+	}{
+		{"rep_min", maxRecommendationReps, &params.RepMin},
+		{"rep_max", maxRecommendationReps, &params.RepMax},
+		{"sets", maxRecommendationSets, &params.Sets},
+	} {
+		raw := strings.TrimSpace(values.Get(field.key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 || value > field.limit {
+			return "", params, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, field.key+" must be between 1 and "+strconv.Itoa(field.limit))
+		}
+		*field.dest = value
+	}
+	if params.RepMin > params.RepMax {
+		return "", params, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "rep_min must not be above rep_max")
+	}
+
+	if raw := strings.TrimSpace(values.Get("target_rpe")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 6 || value > 10 {
+			return "", params, httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target_rpe must be between 6 and 10")
+		}
+		params.TargetRPE = value
+	}
+
+	return algorithm, params, nil
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/progression"
+)
+
+func TestParseRecommendationQuery(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/recommendations/next?machine_id=m1&unit=lb", nil)
+	algorithm, params, apiErr := parseRecommendationQuery(req)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if algorithm != progression.DefaultAlgorithm || params.Unit != models.UnitLb || params.Increment != 5 {
+		t.Fatalf("unexpected defaults %q %+v", algorithm, params)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/recommendations/next?algorithm=rpe_target&increment=1.25&rep_min=3&rep_max=5&sets=5&target_rpe=8.5", nil)
+	algorithm, params, apiErr = parseRecommendationQuery(req)
+	if apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if algorithm != "rpe_target" || params.Increment != 1.25 || params.RepMin != 3 || params.RepMax != 5 || params.Sets != 5 || params.TargetRPE != 8.5 {
+		t.Fatalf("unexpected params %q %+v", algorithm, params)
+	}
+
+	for _, raw := range []string{"algorithm=linear", "unit=stone", "increment=0", "rep_min=10&rep_max=6", "sets=11", "target_rpe=11"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/recommendations/next?"+raw, nil)
+		if _, _, apiErr := parseRecommendationQuery(req); apiErr == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	"fitonex/backend/internal/strength"
+)
+
+const (
+	defaultRecordLimit = 20
+	maxRecordLimit     = 100
+)
+
+// GetPersonalRecords returns the current bests per lift, optionally narrowed
+// to one machine or exercise name.
+func (h *Handlers) GetPersonalRecords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	recordKey := recordKeyFromQuery(r)
+
+	records, err := h.store.Records.ListCurrent(userID, recordKey)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch personal records"))
+		return
+	}
+	if records == nil {
+		records = []models.PersonalRecord{}
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, map[string]any{"items": records})
+}
+
+// GetPersonalRecordHistory returns every record set for a machine or exercise
+// name, newest first.
+func (h *Handlers) GetPersonalRecordHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	recordKey := recordKeyFromQuery(r)
+	if recordKey == "" {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "machine_id or name is required")
+		return
+	}
+
+	limit := defaultRecordLimit
+	if limitParam := strings.TrimSpace(r.URL.Query().Get("limit")); limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		if value > maxRecordLimit {
+			value = maxRecordLimit
+		}
+		limit = value
+	}
+
+	var cursorPtr *pagination.TimeDescCursor
+	if cursorStr := strings.TrimSpace(r.URL.Query().Get("cursor")); cursorStr != "" {
+		cursor, err := pagination.DecodeCursor[pagination.TimeDescCursor](cursorStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
+		}
+		cursorPtr = &cursor
+	}
+
+	page, err := h.store.Records.ListHistory(userID, recordKey, limit, cursorPtr)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidLimit) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be greater than zero")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch personal record history"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, page)
+}
+
+// detectPersonalRecords stores the records a newly logged exercise beats.
+// Record keeping never fails the request that logged the exercise.
+func (h *Handlers) detectPersonalRecords(r *http.Request, userID string, exercise models.Exercise) []models.PersonalRecord {
+	records, err := h.store.Records.Insert(userID, strength.RecordKey(exercise.MachineID, exercise.Name), func(current []models.PersonalRecord) []models.PersonalRecord {
+		return strength.Detect(exercise, current)
+	})
+	if err != nil {
+		log.Printf("failed to detect personal records for user %s exercise %s: %v", userID, exercise.ID, err)
+		return nil
+	}
+
+	h.emitPersonalRecords(r, userID, records)
+	return records
+}
+
+// rebuildPersonalRecords replays the history of the lifts touched by an edit,
+// since changing an older exercise can invalidate records set after it. Only
+// bests the edit newly gives the exercise are announced.
+func (h *Handlers) rebuildPersonalRecords(r *http.Request, userID string, previous, updated models.Exercise) []models.PersonalRecord {
+	var result, fresh []models.PersonalRecord
+
+	targets := []models.Exercise{updated}
+	if strength.RecordKey(previous.MachineID, previous.Name) != strength.RecordKey(updated.MachineID, updated.Name) {
+		targets = append(targets, previous)
+	}
+
+	for _, target := range targets {
+		before, records, after, err := h.replayPersonalRecords(userID, target)
+		if err != nil {
+			log.Printf("failed to rebuild personal records for user %s exercise %s: %v", userID, updated.ID, err)
+			continue
+		}
+
+		for _, record := range records {
+			if record.ExerciseID == updated.ID {
+				result = append(result, record)
+			}
+		}
+		fresh = append(fresh, newRecords(before, after, updated.ID)...)
+	}
+
+	h.emitPersonalRecords(r, userID, fresh)
+	return result
+}
+
+// replayPersonalRecords rewrites the record history of the lift an exercise
+// belongs to, returning the records current before and after alongside the
+// replayed history.
+func (h *Handlers) replayPersonalRecords(userID string, lift models.Exercise) (before, records, after []models.PersonalRecord, err error) {
+	recordKey := strength.RecordKey(lift.MachineID, lift.Name)
+	if before, err = h.store.Records.Current(userID, recordKey); err != nil {
+		return nil, nil, nil, err
+	}
+
+	history, err := h.store.Exercises.ListForRecordKey(userID, lift.MachineID, lift.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if records, err = h.store.Records.ReplaceForKey(userID, recordKey, strength.Replay(history)); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if after, err = h.store.Records.Current(userID, recordKey); err != nil {
+		return nil, nil, nil, err
+	}
+	return before, records, after, nil
+}
+
+// newRecords returns the records in after that belong to one of exerciseIDs
+// and were not already current before. Rebuilt records get new IDs and an
+// edit can replace the sets, so records are matched on what they measure.
+func newRecords(before, after []models.PersonalRecord, exerciseIDs ...string) []models.PersonalRecord {
+	known := make(map[string]bool, len(before))
+	for _, record := range before {
+		known[recordIdentity(record)] = true
+	}
+	touched := make(map[string]bool, len(exerciseIDs))
+	for _, id := range exerciseIDs {
+		touched[id] = true
+	}
+
+	var result []models.PersonalRecord
+	for _, record := range after {
+		if touched[record.ExerciseID] && !known[recordIdentity(record)] {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func recordIdentity(record models.PersonalRecord) string {
+	identity := fmt.Sprintf("%s|%s|%g", record.RecordType, record.ExerciseID, record.Value)
+	if record.WeightKg != nil {
+		identity += fmt.Sprintf("|%g", *record.WeightKg)
+	}
+	return identity
+}
+
+func (h *Handlers) emitPersonalRecords(r *http.Request, userID string, records []models.PersonalRecord) {
+	if h.analytics == nil {
+		return
+	}
+	for _, record := range records {
+		props := map[string]any{
+			"record_type":   record.RecordType,
+			"record_key":    record.RecordKey,
+			"exercise_id":   record.ExerciseID,
+			"exercise_name": record.ExerciseName,
+			"value":         record.Value,
+		}
+		if record.PreviousValue != nil {
+			props["previous_value"] = *record.PreviousValue
+		}
+		h.analytics.EmitEvent(r.Context(), userID, "personal_record", props)
+	}
+}
+
+func recordKeyFromQuery(r *http.Request) string {
+	machineID := strings.TrimSpace(r.URL.Query().Get("machine_id"))
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if machineID == "" && name == "" {
+		return ""
+	}
+	return strength.RecordKey(&machineID, name)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestNewRecordsSkipsRecordsAlreadyHeld(t *testing.T) {
+	weight := 100.0
+	before := []models.PersonalRecord{
+		{ID: "old-1", RecordType: models.RecordTypeMaxWeight, ExerciseID: "ex-1", Value: 100, WeightKg: &weight},
+		{ID: "old-2", RecordType: models.RecordTypeE1RM, ExerciseID: "ex-2", Value: 120},
+	}
+	after := []models.PersonalRecord{
+		{ID: "new-1", RecordType: models.RecordTypeMaxWeight, ExerciseID: "ex-1", Value: 100, WeightKg: &weight},
+		{ID: "new-2", RecordType: models.RecordTypeE1RM, ExerciseID: "ex-1", Value: 125},
+		{ID: "new-3", RecordType: models.RecordTypeMaxVolume, ExerciseID: "ex-3", Value: 4000},
+	}
+
+	records := newRecords(before, after, "ex-1")
+	if len(records) != 1 || records[0].ID != "new-2" {
+		t.Fatalf("expected only the new e1rm record, got %+v", records)
+	}
+}
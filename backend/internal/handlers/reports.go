@@ -8,6 +8,12 @@ import (
 	"fitonex/backend/internal/httpx"
 )
 
+var reportObjectTypes = map[string]bool{
+	"review":       true,
+	"review_photo": true,
+	"video":        true,
+}
+
 type createReportRequest struct {
 	ObjectType string `json:"object_type"`
 	ObjectID   string `json:"object_id"`
@@ -40,8 +46,8 @@ func (h *Handlers) CreateReport(w http.ResponseWriter, r *http.Request) {
 	req.ObjectID = strings.TrimSpace(req.ObjectID)
 	req.Reason = strings.TrimSpace(req.Reason)
 
-	if req.ObjectType != "review" && req.ObjectType != "video" {
-		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "object_type must be review or video")
+	if !reportObjectTypes[req.ObjectType] {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "object_type must be review, review_photo or video")
 		return
 	}
 	if req.ObjectID == "" || req.Reason == "" {
@@ -60,8 +60,16 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 		}
 		h.emitSearchEvent(r, searchType, query, prefix)
 		httpx.WriteJSON(w, http.StatusOK, page)
+	case "exercise":
+		page, err := h.store.Catalog.Search(query, "", "", limit, cursor)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to search exercises"))
+			return
+		}
+		h.emitSearchEvent(r, searchType, query, prefix)
+		httpx.WriteJSON(w, http.StatusOK, page)
 	default:
-		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "type must be gym or machine")
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "type must be gym, machine or exercise")
 	}
 }
 
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	sessionsstore "fitonex/backend/internal/store/sessions"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const defaultSessionName = "Workout"
+
+// StartSessionRequest represents the request to start a live workout session
+type StartSessionRequest struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	GymID *string `json:"gym_id,omitempty"`
+}
+
+// UpdateSessionRequest represents the session metadata update request
+type UpdateSessionRequest struct {
+	Name  string  `json:"name"`
+	Type  string  `json:"type"`
+	GymID *string `json:"gym_id,omitempty"`
+}
+
+// SessionSetRequest logs one set in a live session. ExerciseID targets an
+// exercise already in the session; otherwise the exercise is described by
+// name, machine or catalog entry.
+type SessionSetRequest struct {
+	ExerciseID *string               `json:"exercise_id,omitempty"`
+	GymID      *string               `json:"gym_id,omitempty"`
+	MachineID  *string               `json:"machine_id,omitempty"`
+	CatalogID  *string               `json:"catalog_id,omitempty"`
+	Name       string                `json:"name"`
+	Group      *models.ExerciseGroup `json:"group,omitempty"`
+	Set        models.Set            `json:"set"`
+}
+
+// FinishSessionResponse is the finished session and the workout written from it
+type FinishSessionResponse struct {
+	Session *models.WorkoutSession `json:"session"`
+	Workout *models.Workout        `json:"workout"`
+}
+
+// StartSession starts a live workout session. A user can only have one
+// session in progress at a time.
+func (h *Handlers) StartSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req StartSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		req.Name = defaultSessionName
+	}
+
+	session, err := h.store.Sessions.Start(userID, req.Name, strings.TrimSpace(req.Type), trimmedOptional(req.GymID))
+	if err != nil {
+		writeSessionError(w, err, "failed to start session")
+		return
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "workout_session_started", map[string]any{
+			"session_id": session.ID,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, session)
+}
+
+// GetActiveSession returns the user's session in progress
+func (h *Handlers) GetActiveSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.store.Sessions.GetActive(userID)
+	if err != nil {
+		writeSessionError(w, err, "failed to fetch session")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// GetSession returns a session, live or closed
+func (h *Handlers) GetSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.store.Sessions.GetByID(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeSessionError(w, err, "failed to fetch session")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// UpdateSession changes a live session's name, type and gym
+func (h *Handlers) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "session name is required")
+		return
+	}
+
+	session, err := h.store.Sessions.Update(chi.URLParam(r, "id"), userID, req.Name, strings.TrimSpace(req.Type), trimmedOptional(req.GymID))
+	if err != nil {
+		writeSessionError(w, err, "failed to update session")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// AddSessionSet logs a set in a live session
+func (h *Handlers) AddSessionSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req SessionSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	sets := []models.Set{req.Set}
+	if apiErr := validateExerciseSets(sets); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	var exercise models.Exercise
+	exerciseID := ""
+	if id := trimmedOptional(req.ExerciseID); id != nil {
+		exerciseID = *id
+	} else {
+		catalogID, name, apiErr := h.resolveCatalogExercise(req.CatalogID, strings.TrimSpace(req.Name))
+		if apiErr != nil {
+			httpx.WriteAPIError(w, apiErr)
+			return
+		}
+		if name == "" {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "exercise_id or name is required")
+			return
+		}
+		group, apiErr := normalizeExerciseGroup(req.Group)
+		if apiErr != nil {
+			httpx.WriteAPIError(w, apiErr)
+			return
+		}
+		exercise = models.Exercise{
+			GymID:     trimmedOptional(req.GymID),
+			MachineID: trimmedOptional(req.MachineID),
+			CatalogID: catalogID,
+			Name:      name,
+			Group:     group,
+		}
+	}
+
+	session, err := h.store.Sessions.AppendSet(chi.URLParam(r, "id"), userID, exerciseID, exercise, sets[0])
+	if err != nil {
+		writeSessionError(w, err, "failed to add set")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, session)
+}
+
+// UpdateSessionSet replaces a set logged in a live session
+func (h *Handlers) UpdateSessionSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var set models.Set
+	if err := json.NewDecoder(r.Body).Decode(&set); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	sets := []models.Set{set}
+	if apiErr := validateExerciseSets(sets); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	session, err := h.store.Sessions.UpdateSet(chi.URLParam(r, "id"), userID, chi.URLParam(r, "setId"), sets[0])
+	if err != nil {
+		writeSessionError(w, err, "failed to update set")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// DeleteSessionSet removes a set from a live session
+func (h *Handlers) DeleteSessionSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.store.Sessions.DeleteSet(chi.URLParam(r, "id"), userID, chi.URLParam(r, "setId"))
+	if err != nil {
+		writeSessionError(w, err, "failed to delete set")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// PauseSession stops the session clock
+func (h *Handlers) PauseSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.store.Sessions.Pause(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeSessionError(w, err, "failed to pause session")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// ResumeSession restarts the session clock
+func (h *Handlers) ResumeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, err := h.store.Sessions.Resume(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeSessionError(w, err, "failed to resume session")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, session)
+}
+
+// FinishSession closes a live session and saves it as a workout
+func (h *Handlers) FinishSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	session, workout, err := h.store.Sessions.Finish(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		writeSessionError(w, err, "failed to finish session")
+		return
+	}
+
+	for i := range workout.Exercises {
+		workout.Exercises[i].PersonalRecords = h.detectPersonalRecords(r, userID, workout.Exercises[i])
+	}
+	// The workout is saved; an estimate that cannot be made is left out.
+	finished := []models.Workout{*workout}
+	h.attachCalories(userID, finished)
+	workout = &finished[0]
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "workout_session_finished", map[string]any{
+			"session_id":      session.ID,
+			"workout_id":      workout.ID,
+			"exercises":       len(workout.Exercises),
+			"elapsed_seconds": session.ElapsedSeconds,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, FinishSessionResponse{Session: session, Workout: workout})
+}
+
+// DiscardSession closes a live session without saving a workout
+func (h *Handlers) DiscardSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if _, err := h.store.Sessions.Discard(chi.URLParam(r, "id"), userID); err != nil {
+		writeSessionError(w, err, "failed to discard session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSessionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, sessionsstore.ErrSessionNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "session not found")
+	case errors.Is(err, sessionsstore.ErrExerciseNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "exercise not found in session")
+	case errors.Is(err, sessionsstore.ErrSetNotFound):
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "set not found")
+	case errors.Is(err, sessionsstore.ErrSessionInProgress):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "a workout session is already in progress")
+	case errors.Is(err, sessionsstore.ErrSessionClosed):
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "session is no longer active")
+	case errors.Is(err, sessionsstore.ErrSessionEmpty):
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "log at least one set before finishing")
+	case errors.Is(err, sessionsstore.ErrSessionFull):
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "a session can contain at most 50 exercises with 50 sets each")
+	case errors.Is(err, sessionsstore.ErrGymNotFound):
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "gym not found")
+	default:
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+	}
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	checkinsstore "fitonex/backend/internal/store/checkins"
+	exercisesstore "fitonex/backend/internal/store/exercises"
+	"fitonex/backend/internal/store/offline"
+	workoutsstore "fitonex/backend/internal/store/workouts"
+	"fitonex/backend/internal/strength"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxSyncMutations        = 100
+	maxSyncMutationIDLength = 128
+	defaultSyncChangeLimit  = 200
+	maxSyncChangeLimit      = 500
+	// maxSyncClockSkew bounds how far ahead of the server a client clock may
+	// be. A far-future updated_at would otherwise win every later conflict.
+	maxSyncClockSkew = 10 * time.Minute
+)
+
+// SyncPushRequest represents a batch of offline mutations
+type SyncPushRequest struct {
+	Mutations []models.SyncMutation `json:"mutations"`
+}
+
+// SyncPushResponse reports the outcome of every mutation in request order
+type SyncPushResponse struct {
+	Results []models.SyncResult `json:"results"`
+}
+
+type syncWorkoutData struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Duration    int        `json:"duration"`
+	Type        string     `json:"type"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+type syncExerciseData struct {
+	WorkoutID   *string               `json:"workout_id,omitempty"`
+	GymID       *string               `json:"gym_id,omitempty"`
+	MachineID   *string               `json:"machine_id,omitempty"`
+	CatalogID   *string               `json:"catalog_id,omitempty"`
+	Name        string                `json:"name"`
+	PerformedAt *time.Time            `json:"performed_at,omitempty"`
+	Group       *models.ExerciseGroup `json:"group,omitempty"`
+}
+
+type syncCheckinData struct {
+	Day string `json:"day"`
+}
+
+// PushSync applies a batch of client-generated mutations. Each mutation runs
+// on its own, so one rejected write does not block the rest, and mutations
+// that were already processed return their stored result.
+func (h *Handlers) PushSync(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req SyncPushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Mutations) == 0 || len(req.Mutations) > maxSyncMutations {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "mutations must contain between 1 and 100 items")
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := SyncPushResponse{Results: make([]models.SyncResult, 0, len(req.Mutations))}
+	counts := make(map[string]int)
+	var lifts []models.Exercise
+	for _, mutation := range req.Mutations {
+		apiErr := decodeSyncMutation(&mutation, now)
+		if apiErr == nil && mutation.Exercise != nil {
+			mutation.Exercise.CatalogID, mutation.Exercise.Name, apiErr = h.resolveCatalogExercise(mutation.Exercise.CatalogID, mutation.Exercise.Name)
+			if apiErr == nil && mutation.Exercise.Name == "" {
+				apiErr = httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "exercise name is required")
+			}
+		}
+		if apiErr != nil {
+			if apiErr.Status >= http.StatusInternalServerError {
+				httpx.WriteAPIError(w, apiErr)
+				return
+			}
+			resp.Results = append(resp.Results, models.SyncResult{
+				MutationID: mutation.ID,
+				EntityID:   mutation.EntityID,
+				Status:     models.SyncStatusRejected,
+				Error:      apiErr.Message,
+			})
+			counts[models.SyncStatusRejected]++
+			continue
+		}
+
+		previous := h.syncedLifts(userID, mutation, false)
+		result, err := h.store.Sync.Apply(userID, mutation)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to apply sync mutation"))
+			return
+		}
+		if result.Status == models.SyncStatusApplied && !result.Replayed {
+			lifts = append(lifts, previous...)
+			lifts = append(lifts, h.syncedLifts(userID, mutation, true)...)
+		}
+		resp.Results = append(resp.Results, result)
+		counts[result.Status]++
+	}
+
+	h.rebuildSyncedRecords(r, userID, lifts)
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "sync_pushed", map[string]any{
+			"mutations": len(req.Mutations),
+			"applied":   counts[models.SyncStatusApplied],
+			"conflicts": counts[models.SyncStatusConflict],
+			"rejected":  counts[models.SyncStatusRejected],
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
+
+// PullSync returns the latest state of every entity changed since token.
+// Entities changed several times appear once; deletions come back as
+// tombstones. An empty token starts from the beginning.
+func (h *Handlers) PullSync(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var position offline.Position
+	if token := strings.TrimSpace(r.URL.Query().Get("token")); token != "" {
+		decoded, err := pagination.DecodeCursor[offline.Position](token)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid token")
+			return
+		}
+		position = decoded
+	}
+
+	limit := defaultSyncChangeLimit
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(value, maxSyncChangeLimit)
+	}
+
+	entries, err := h.store.Sync.Changes(userID, position, limit)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch changes"))
+		return
+	}
+
+	resp := models.SyncChanges{Changes: make([]models.SyncChange, 0, len(entries))}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		resp.HasMore = true
+	}
+	for _, entry := range entries {
+		change, err := h.loadSyncChange(userID, entry)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch changes"))
+			return
+		}
+		resp.Changes = append(resp.Changes, change)
+		position = entry.Position
+	}
+
+	token, err := pagination.EncodeCursor(position)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to encode token"))
+		return
+	}
+	resp.NextToken = token
+
+	httpx.WriteJSON(w, http.StatusOK, resp)
+}
+
+// syncedLifts returns the exercises whose lifts a mutation changes, read
+// before the mutation is applied or, when applied is true, after it.
+func (h *Handlers) syncedLifts(userID string, mutation models.SyncMutation, applied bool) []models.Exercise {
+	var exerciseIDs []string
+	switch mutation.Entity {
+	case models.SyncEntityExercise:
+		if applied && mutation.Exercise != nil {
+			exercise := *mutation.Exercise
+			exercise.ID = mutation.EntityID
+			return []models.Exercise{exercise}
+		}
+		if !applied {
+			exerciseIDs = append(exerciseIDs, mutation.EntityID)
+		}
+	case models.SyncEntitySet:
+		if applied && mutation.Set != nil {
+			exerciseIDs = append(exerciseIDs, mutation.Set.ExerciseID)
+		}
+		if !applied {
+			if set, err := h.store.Exercises.GetSet(mutation.EntityID, userID); err == nil {
+				exerciseIDs = append(exerciseIDs, set.ExerciseID)
+			}
+		}
+	}
+
+	var lifts []models.Exercise
+	for _, id := range exerciseIDs {
+		if exercise, err := h.store.Exercises.GetByID(id, userID); err == nil {
+			lifts = append(lifts, *exercise)
+		}
+	}
+	return lifts
+}
+
+// rebuildSyncedRecords replays the records of every lift a push changed.
+// Synced exercises are often logged offline before newer ones, so records are
+// rebuilt the way an edit rebuilds them, and only new bests are announced.
+func (h *Handlers) rebuildSyncedRecords(r *http.Request, userID string, lifts []models.Exercise) {
+	exerciseIDs := map[string][]string{}
+	var order []models.Exercise
+	for _, lift := range lifts {
+		key := strength.RecordKey(lift.MachineID, lift.Name)
+		if _, ok := exerciseIDs[key]; !ok {
+			order = append(order, lift)
+		}
+		exerciseIDs[key] = append(exerciseIDs[key], lift.ID)
+	}
+
+	for _, lift := range order {
+		before, _, after, err := h.replayPersonalRecords(userID, lift)
+		if err != nil {
+			log.Printf("failed to rebuild personal records for user %s exercise %s: %v", userID, lift.ID, err)
+			continue
+		}
+		h.emitPersonalRecords(r, userID, newRecords(before, after, exerciseIDs[strength.RecordKey(lift.MachineID, lift.Name)]...))
+	}
+}
+
+// loadSyncChange reads the current row for a change. A row that disappeared
+// after the change was recorded is reported as deleted.
+func (h *Handlers) loadSyncChange(userID string, entry offline.EntityChange) (models.SyncChange, error) {
+	change := models.SyncChange{Entity: entry.Entity, EntityID: entry.EntityID, Op: models.SyncOpDelete}
+	if entry.Deleted {
+		return change, nil
+	}
+
+	var (
+		data any
+		err  error
+	)
+	switch entry.Entity {
+	case models.SyncEntityWorkout:
+		data, err = h.store.Workouts.GetByID(entry.EntityID, userID)
+		if errors.Is(err, workoutsstore.ErrWorkoutNotFound) {
+			return change, nil
+		}
+	case models.SyncEntityExercise:
+		data, err = h.store.Exercises.GetByID(entry.EntityID, userID)
+		if errors.Is(err, exercisesstore.ErrExerciseNotFound) {
+			return change, nil
+		}
+	case models.SyncEntitySet:
+		data, err = h.store.Exercises.GetSet(entry.EntityID, userID)
+		if errors.Is(err, exercisesstore.ErrSetNotFound) {
+			return change, nil
+		}
+	case models.SyncEntityCheckin:
+		data, err = h.store.Checkins.GetByID(entry.EntityID, userID)
+		if errors.Is(err, checkinsstore.ErrCheckinNotFound) {
+			return change, nil
+		}
+	}
+	if err != nil {
+		return change, err
+	}
+
+	change.Op = models.SyncOpUpsert
+	change.Data = data
+	return change, nil
+}
+
+// decodeSyncMutation validates the envelope of a mutation and decodes its
+// payload into the matching model.
+func decodeSyncMutation(mutation *models.SyncMutation, now time.Time) *httpx.APIError {
+	badRequest := func(message string) *httpx.APIError {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, message)
+	}
+
+	mutation.ID = strings.TrimSpace(mutation.ID)
+	if mutation.ID == "" || len(mutation.ID) > maxSyncMutationIDLength {
+		return badRequest("mutation id must be between 1 and 128 characters")
+	}
+	if _, err := uuid.Parse(mutation.EntityID); err != nil {
+		return badRequest("entity_id must be a UUID")
+	}
+	mutation.EntityID = strings.ToLower(mutation.EntityID)
+	if mutation.UpdatedAt.IsZero() {
+		return badRequest("updated_at is required")
+	}
+	if mutation.UpdatedAt.After(now.Add(maxSyncClockSkew)) {
+		return badRequest("updated_at is in the future")
+	}
+
+	switch mutation.Entity {
+	case models.SyncEntityWorkout, models.SyncEntityExercise, models.SyncEntitySet, models.SyncEntityCheckin:
+	default:
+		return badRequest("entity must be workout, exercise, set or checkin")
+	}
+	switch mutation.Op {
+	case models.SyncOpDelete:
+		return nil
+	case models.SyncOpUpsert:
+	default:
+		return badRequest("op must be upsert or delete")
+	}
+	if len(mutation.Data) == 0 {
+		return badRequest("data is required for upserts")
+	}
+
+	switch mutation.Entity {
+	case models.SyncEntityWorkout:
+		var data syncWorkoutData
+		if err := json.Unmarshal(mutation.Data, &data); err != nil {
+			return badRequest("invalid workout data")
+		}
+		data.Name = strings.TrimSpace(data.Name)
+		if data.Name == "" {
+			return badRequest("workout name is required")
+		}
+		if data.Duration < 0 {
+			return badRequest("duration must be non-negative")
+		}
+		mutation.Workout = &models.Workout{
+			ID:          mutation.EntityID,
+			Name:        data.Name,
+			Description: data.Description,
+			Duration:    data.Duration,
+			Type:        data.Type,
+		}
+		if data.CreatedAt != nil {
+			mutation.Workout.CreatedAt = data.CreatedAt.UTC()
+		}
+
+	case models.SyncEntityExercise:
+		var data syncExerciseData
+		if err := json.Unmarshal(mutation.Data, &data); err != nil {
+			return badRequest("invalid exercise data")
+		}
+		exercise := &models.Exercise{
+			ID:        mutation.EntityID,
+			WorkoutID: trimmedOptional(data.WorkoutID),
+			GymID:     trimmedOptional(data.GymID),
+			MachineID: trimmedOptional(data.MachineID),
+			CatalogID: trimmedOptional(data.CatalogID),
+			Name:      strings.TrimSpace(data.Name),
+		}
+		for _, ref := range []*string{exercise.WorkoutID, exercise.GymID, exercise.MachineID, exercise.CatalogID} {
+			if ref == nil {
+				continue
+			}
+			if _, err := uuid.Parse(*ref); err != nil {
+				return badRequest("workout_id, gym_id, machine_id and catalog_id must be UUIDs")
+			}
+		}
+		group, apiErr := normalizeExerciseGroup(data.Group)
+		if apiErr != nil {
+			return apiErr
+		}
+		exercise.Group = group
+		if data.PerformedAt != nil {
+			exercise.CreatedAt = data.PerformedAt.UTC()
+		}
+		mutation.Exercise = exercise
+
+	case models.SyncEntitySet:
+		var set models.Set
+		if err := json.Unmarshal(mutation.Data, &set); err != nil {
+			return badRequest("invalid set data")
+		}
+		if _, err := uuid.Parse(set.ExerciseID); err != nil {
+			return badRequest("exercise_id must be a UUID")
+		}
+		if set.SetIndex < 0 {
+			return badRequest("set_index must be non-negative")
+		}
+		sets := []models.Set{set}
+		if apiErr := validateExerciseSets(sets); apiErr != nil {
+			return apiErr
+		}
+		sets[0].ID = mutation.EntityID
+		mutation.Set = &sets[0]
+
+	case models.SyncEntityCheckin:
+		var data syncCheckinData
+		if err := json.Unmarshal(mutation.Data, &data); err != nil {
+			return badRequest("invalid checkin data")
+		}
+		day, err := time.Parse("2006-01-02", strings.TrimSpace(data.Day))
+		if err != nil {
+			return badRequest("day must use YYYY-MM-DD format")
+		}
+		mutation.Checkin = &models.Checkin{ID: mutation.EntityID, Day: day}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestDecodeSyncMutation(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	entityID := "9B1DEB4D-3B7D-4BAD-9BDD-2B0D7B3DCB6D"
+
+	set := models.SyncMutation{
+		ID:        "m-1",
+		Entity:    models.SyncEntitySet,
+		Op:        models.SyncOpUpsert,
+		EntityID:  entityID,
+		UpdatedAt: now,
+		Data:      json.RawMessage(`{"exercise_id":"1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed","reps":8,"weight_kg":60}`),
+	}
+	if apiErr := decodeSyncMutation(&set, now); apiErr != nil {
+		t.Fatalf("unexpected error %v", apiErr)
+	}
+	if set.Set == nil || set.Set.Type != models.SetTypeWorking || set.Set.ID != "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d" {
+		t.Fatalf("unexpected decoded set %+v", set.Set)
+	}
+
+	checkin := models.SyncMutation{
+		ID:        "m-2",
+		Entity:    models.SyncEntityCheckin,
+		Op:        models.SyncOpUpsert,
+		EntityID:  entityID,
+		UpdatedAt: now,
+		Data:      json.RawMessage(`{"day":"2024-05-01"}`),
+	}
+	if apiErr := decodeSyncMutation(&checkin, now); apiErr != nil || checkin.Checkin.Day.Format("2006-01-02") != "2024-05-01" {
+		t.Fatalf("unexpected checkin decode %v %+v", apiErr, checkin.Checkin)
+	}
+
+	deletion := models.SyncMutation{ID: "m-3", Entity: models.SyncEntityWorkout, Op: models.SyncOpDelete, EntityID: entityID, UpdatedAt: now}
+	if apiErr := decodeSyncMutation(&deletion, now); apiErr != nil {
+		t.Fatalf("deletes need no data: %v", apiErr)
+	}
+}
+
+func TestDecodeSyncMutationRejects(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	valid := func() models.SyncMutation {
+		return models.SyncMutation{
+			ID:        "m-1",
+			Entity:    models.SyncEntityWorkout,
+			Op:        models.SyncOpUpsert,
+			EntityID:  "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d",
+			UpdatedAt: now,
+			Data:      json.RawMessage(`{"name":"Push","duration":45}`),
+		}
+	}
+
+	cases := map[string]func(m *models.SyncMutation){
+		"missing id":       func(m *models.SyncMutation) { m.ID = " " },
+		"bad entity id":    func(m *models.SyncMutation) { m.EntityID = "local-1" },
+		"missing time":     func(m *models.SyncMutation) { m.UpdatedAt = time.Time{} },
+		"future time":      func(m *models.SyncMutation) { m.UpdatedAt = now.Add(time.Hour) },
+		"unknown entity":   func(m *models.SyncMutation) { m.Entity = "program" },
+		"unknown op":       func(m *models.SyncMutation) { m.Op = "patch" },
+		"missing data":     func(m *models.SyncMutation) { m.Data = nil },
+		"empty name":       func(m *models.SyncMutation) { m.Data = json.RawMessage(`{"name":" "}`) },
+		"negative minutes": func(m *models.SyncMutation) { m.Data = json.RawMessage(`{"name":"Push","duration":-1}`) },
+	}
+	for name, mutate := range cases {
+		mutation := valid()
+		mutate(&mutation)
+		if apiErr := decodeSyncMutation(&mutation, now); apiErr == nil {
+			t.Fatalf("%s: expected rejection", name)
+		}
+	}
+
+	if mutation := valid(); decodeSyncMutation(&mutation, now) != nil {
+		t.Fatalf("baseline mutation should be valid")
+	}
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	templatesstore "fitonex/backend/internal/store/templates"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultTemplateLimit  = 20
+	maxTemplateLimit      = 50
+	maxTemplateExercises  = 30
+	maxTemplateTargetSets = 20
+	maxTemplateTargetReps = 100
+)
+
+// TemplateRequest represents the create and update template payload
+type TemplateRequest struct {
+	Name        string                    `json:"name"`
+	Description *string                   `json:"description,omitempty"`
+	Exercises   []models.TemplateExercise `json:"exercises"`
+}
+
+// StartTemplateRequest represents the start session payload
+type StartTemplateRequest struct {
+	Day   string  `json:"day,omitempty"`
+	GymID *string `json:"gym_id,omitempty"`
+}
+
+// StartTemplateResponse represents the draft exercise log. Each exercise is
+// posted to /exercises with Day once performed.
+type StartTemplateResponse struct {
+	TemplateID string            `json:"template_id"`
+	Day        string            `json:"day"`
+	Exercises  []models.Exercise `json:"exercises"`
+}
+
+// CreateTemplate handles creating a workout template
+func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := normalizeTemplateRequest(&req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	template, err := h.store.Templates.Create(userID, req.Name, req.Description, nil, req.Exercises)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to create template"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, template)
+}
+
+// GetTemplates lists the caller's templates
+func (h *Handlers) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	limit := defaultTemplateLimit
+	if limitParam := strings.TrimSpace(r.URL.Query().Get("limit")); limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		if value > maxTemplateLimit {
+			value = maxTemplateLimit
+		}
+		limit = value
+	}
+
+	var cursorPtr *pagination.TimeDescCursor
+	if cursorStr := strings.TrimSpace(r.URL.Query().Get("cursor")); cursorStr != "" {
+		cursor, err := pagination.DecodeCursor[pagination.TimeDescCursor](cursorStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
+		}
+		cursorPtr = &cursor
+	}
+
+	page, err := h.store.Templates.ListByOwner(userID, limit, cursorPtr)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidLimit) {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be greater than zero")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch templates"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, page)
+}
+
+// GetTemplate returns a template by ID. Any signed-in user with the ID can view it.
+func (h *Handlers) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := userIDFromContext(r); !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	template, err := h.store.Templates.GetByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeTemplateError(w, err, "failed to fetch template")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, template)
+}
+
+// UpdateTemplate replaces a template owned by the caller
+func (h *Handlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+	if apiErr := normalizeTemplateRequest(&req); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	template, err := h.store.Templates.Update(chi.URLParam(r, "id"), userID, req.Name, req.Description, req.Exercises)
+	if err != nil {
+		writeTemplateError(w, err, "failed to update template")
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate removes a template owned by the caller
+func (h *Handlers) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.store.Templates.Delete(chi.URLParam(r, "id"), userID); err != nil {
+		writeTemplateError(w, err, "failed to delete template")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CopyTemplate saves a shared template into the caller's library
+func (h *Handlers) CopyTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	source, err := h.store.Templates.GetByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeTemplateError(w, err, "failed to fetch template")
+		return
+	}
+
+	template, err := h.store.Templates.Create(userID, source.Name, source.Description, &source.ID, source.Exercises)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to copy template"))
+		return
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, template)
+}
+
+// StartTemplate returns a draft exercise log for a day with sets pre-filled
+// from the targets. Nothing is logged until the client posts the exercises
+// as they are performed, so targets never count as lifted work.
+func (h *Handlers) StartTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	// The body is optional; an empty one starts the session today.
+	var req StartTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	day := time.Now().UTC().Format("2006-01-02")
+	if value := strings.TrimSpace(req.Day); value != "" {
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "day must use YYYY-MM-DD format")
+			return
+		}
+		day = value
+	}
+
+	template, err := h.store.Templates.GetByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeTemplateError(w, err, "failed to fetch template")
+		return
+	}
+
+	exercises := exercisesFromTemplate(*template)
+	gymID := trimmedOptional(req.GymID)
+	for i := range exercises {
+		exercises[i].GymID = gymID
+	}
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "template_started", map[string]any{
+			"template_id": template.ID,
+			"exercises":   len(exercises),
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusOK, StartTemplateResponse{TemplateID: template.ID, Day: day, Exercises: exercises})
+}
+
+// exercisesFromTemplate expands template targets into exercises with one set per target set.
+func exercisesFromTemplate(template models.WorkoutTemplate) []models.Exercise {
+	exercises := make([]models.Exercise, 0, len(template.Exercises))
+	for _, planned := range template.Exercises {
+		sets := make([]models.Set, planned.TargetSets)
+		for i := range sets {
+			sets[i].Reps = planned.TargetReps
+			if planned.TargetWeightKg != nil {
+				weight := *planned.TargetWeightKg
+				sets[i].WeightKg = &weight
+			}
+		}
+		exercises = append(exercises, models.Exercise{
+			MachineID:   planned.MachineID,
+			MachineName: planned.MachineName,
+			Name:        planned.Name,
+			Sets:        sets,
+		})
+	}
+	return exercises
+}
+
+func normalizeTemplateRequest(req *TemplateRequest) *httpx.APIError {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "name is required")
+	}
+	req.Description = trimmedOptional(req.Description)
+
+	if len(req.Exercises) == 0 || len(req.Exercises) > maxTemplateExercises {
+		return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "templates need between 1 and 30 exercises")
+	}
+
+	for i := range req.Exercises {
+		exercise := &req.Exercises[i]
+		exercise.Name = strings.TrimSpace(exercise.Name)
+		exercise.MachineID = trimmedOptional(exercise.MachineID)
+		exercise.Notes = trimmedOptional(exercise.Notes)
+
+		if exercise.Name == "" {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "exercise name is required")
+		}
+		if exercise.TargetSets <= 0 || exercise.TargetSets > maxTemplateTargetSets {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target_sets must be between 1 and 20")
+		}
+		if exercise.TargetReps <= 0 || exercise.TargetReps > maxTemplateTargetReps {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target_reps must be between 1 and 100")
+		}
+		if exercise.TargetWeightKg != nil && *exercise.TargetWeightKg < 0 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "target_weight_kg must be non-negative")
+		}
+	}
+	return nil
+}
+
+func writeTemplateError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, templatesstore.ErrTemplateNotFound) {
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "template not found")
+		return
+	}
+	if errors.Is(err, templatesstore.ErrTemplateInUse) {
+		httpx.WriteError(w, http.StatusConflict, httpx.ErrorCodeConflict, "template is scheduled in a program; remove it from the program first")
+		return
+	}
+	httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+}
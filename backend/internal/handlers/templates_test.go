@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestExercisesFromTemplatePrefillsTargets(t *testing.T) {
+	weight := 60.0
+	machineID := "machine-1"
+	template := models.WorkoutTemplate{
+		ID: "template-1",
+		Exercises: []models.TemplateExercise{
+			{Position: 1, MachineID: &machineID, Name: "Chest Press", TargetSets: 3, TargetReps: 10, TargetWeightKg: &weight},
+			{Position: 2, Name: "Plank", TargetSets: 2, TargetReps: 1},
+		},
+	}
+
+	exercises := exercisesFromTemplate(template)
+
+	if len(exercises) != 2 {
+		t.Fatalf("expected 2 exercises, got %d", len(exercises))
+	}
+	first := exercises[0]
+	if first.Name != "Chest Press" || first.MachineID == nil || *first.MachineID != machineID {
+		t.Fatalf("unexpected first exercise %+v", first)
+	}
+	if len(first.Sets) != 3 {
+		t.Fatalf("expected 3 sets, got %d", len(first.Sets))
+	}
+	for _, set := range first.Sets {
+		if set.Reps != 10 || set.WeightKg == nil || *set.WeightKg != 60 {
+			t.Fatalf("unexpected prefilled set %+v", set)
+		}
+	}
+	first.Sets[0].WeightKg = nil
+	if first.Sets[1].WeightKg == nil {
+		t.Fatal("expected sets to have independent weights")
+	}
+	if len(exercises[1].Sets) != 2 || exercises[1].Sets[0].WeightKg != nil {
+		t.Fatalf("unexpected bodyweight sets %+v", exercises[1].Sets)
+	}
+}
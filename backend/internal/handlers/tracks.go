@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/httpx"
+	"fitonex/backend/internal/importer"
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/storage"
+	tracksstore "fitonex/backend/internal/store/tracks"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const trackFileURLTTL = 15 * time.Minute
+
+var trackContentTypes = map[string]string{
+	models.TrackFormatGPX: "application/gpx+xml",
+	models.TrackFormatTCX: "application/vnd.garmin.tcx+xml",
+}
+
+// ImportTrack creates a cardio workout from a GPX or TCX file, sent either as
+// the "file" field of a multipart form or as the raw request body. The raw
+// file is kept in object storage. Uploading the same file again returns the
+// original track unchanged.
+func (h *Handlers) ImportTrack(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	data, apiErr := readImportFile(w, r)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	parsed, err := importer.ParseTrack(data)
+	if err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, strings.TrimPrefix(err.Error(), "importer: "))
+		return
+	}
+	summary := parsed.Summarize()
+	if summary.DurationSeconds <= 0 {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "track has no duration")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(sum[:])
+
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		service, err := storage.NewS3Service(h.config)
+		if err != nil {
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to initialize storage service"))
+			return
+		}
+		h.SetObjectStorage(service)
+		storageSvc = service
+	}
+
+	existing, err := h.store.Tracks.GetByHash(userID, fileHash)
+	if err == nil {
+		existing.Duplicate = true
+		h.attachTrackFileURL(r, existing)
+		httpx.WriteJSON(w, http.StatusOK, existing)
+		return
+	}
+	if !errors.Is(err, tracksstore.ErrTrackNotFound) {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to check track"))
+		return
+	}
+
+	track := &models.CardioTrack{
+		UserID:   userID,
+		Format:   parsed.Format,
+		Sport:    parsed.Sport,
+		Name:     parsed.Name,
+		FileKey:  fmt.Sprintf("tracks/%s/%s.%s", userID, fileHash, parsed.Format),
+		FileHash: fileHash,
+		Summary:  summary,
+	}
+	if track.Sport == "" {
+		track.Sport = "cardio"
+	}
+	if track.Name == "" {
+		track.Name = cardioWorkoutName(track.Sport)
+	}
+
+	if err := storageSvc.PutObject(r.Context(), track.FileKey, trackContentTypes[track.Format], data); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to store track file"))
+		return
+	}
+
+	workout, exercises := cardioWorkoutFromTrack(track)
+	result, err := h.store.Tracks.Create(track, workout, exercises)
+	if err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to import track"))
+		return
+	}
+	h.attachTrackFileURL(r, result)
+	if result.Duplicate {
+		httpx.WriteJSON(w, http.StatusOK, result)
+		return
+	}
+	// The workout is saved; an estimate that cannot be made is left out.
+	created := []models.Workout{*workout}
+	h.attachCalories(userID, created)
+	result.Workout = &created[0]
+
+	if h.analytics != nil {
+		h.analytics.EmitEvent(r.Context(), userID, "track_imported", map[string]any{
+			"track_id":        result.ID,
+			"workout_id":      result.WorkoutID,
+			"format":          result.Format,
+			"sport":           result.Sport,
+			"distance_meters": result.Summary.DistanceMeters,
+		})
+	}
+
+	httpx.WriteJSON(w, http.StatusCreated, result)
+}
+
+// GetTrack returns one of the caller's tracks with a link to the raw file
+func (h *Handlers) GetTrack(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	track, err := h.store.Tracks.GetByID(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		if errors.Is(err, tracksstore.ErrTrackNotFound) {
+			httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "track not found")
+			return
+		}
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch track"))
+		return
+	}
+
+	if h.storageService() == nil {
+		if service, err := storage.NewS3Service(h.config); err == nil {
+			h.SetObjectStorage(service)
+		}
+	}
+	h.attachTrackFileURL(r, track)
+	httpx.WriteJSON(w, http.StatusOK, track)
+}
+
+// attachTrackFileURL links the raw file when storage is available
+func (h *Handlers) attachTrackFileURL(r *http.Request, track *models.CardioTrack) {
+	storageSvc := h.storageService()
+	if storageSvc == nil {
+		return
+	}
+	if fileURL, err := storageSvc.SignedGet(r.Context(), track.FileKey, "", trackFileURLTTL); err == nil {
+		track.FileURL = fileURL
+	}
+}
+
+// cardioWorkoutFromTrack builds the workout for a track: one exercise with a
+// single set carrying the duration and distance.
+func cardioWorkoutFromTrack(track *models.CardioTrack) (*models.Workout, []models.Exercise) {
+	seconds := track.Summary.DurationSeconds
+	set := models.Set{Type: models.SetTypeWorking, DurationSeconds: &seconds}
+	if track.Summary.DistanceMeters > 0 {
+		distance := track.Summary.DistanceMeters
+		set.DistanceMeters = &distance
+	}
+
+	var description []string
+	if track.Summary.ElevationGainMeters > 0 {
+		description = append(description, fmt.Sprintf("Elevation gain %.0f m", track.Summary.ElevationGainMeters))
+	}
+	if track.Summary.AvgHeartRate != nil {
+		description = append(description, fmt.Sprintf("avg HR %d bpm", *track.Summary.AvgHeartRate))
+	}
+
+	workout := &models.Workout{
+		Name:        track.Name,
+		Description: strings.Join(description, ", "),
+		Duration:    int(math.Ceil(float64(seconds) / 60)),
+		Type:        track.Sport,
+		CreatedAt:   track.Summary.StartedAt,
+	}
+	exercises := []models.Exercise{{
+		Name:      cardioWorkoutName(track.Sport),
+		CreatedAt: track.Summary.StartedAt,
+		Sets:      []models.Set{set},
+	}}
+	return workout, exercises
+}
+
+func cardioWorkoutName(sport string) string {
+	if sport == "" {
+		return "Cardio"
+	}
+	return strings.ToUpper(sport[:1]) + sport[1:]
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestCardioWorkoutFromTrack(t *testing.T) {
+	heartRate := 151
+	started := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	track := &models.CardioTrack{
+		Name:  "Morning Run",
+		Sport: "running",
+		Summary: models.TrackSummary{
+			StartedAt:           started,
+			DurationSeconds:     1530,
+			DistanceMeters:      5012.3,
+			ElevationGainMeters: 42,
+			AvgHeartRate:        &heartRate,
+		},
+	}
+
+	workout, exercises := cardioWorkoutFromTrack(track)
+	if workout.Name != "Morning Run" || workout.Type != "running" || workout.Duration != 26 || !workout.CreatedAt.Equal(started) {
+		t.Fatalf("unexpected workout %+v", workout)
+	}
+	if workout.Description != "Elevation gain 42 m, avg HR 151 bpm" {
+		t.Fatalf("unexpected description %q", workout.Description)
+	}
+	if len(exercises) != 1 || exercises[0].Name != "Running" || len(exercises[0].Sets) != 1 {
+		t.Fatalf("unexpected exercises %+v", exercises)
+	}
+	set := exercises[0].Sets[0]
+	if *set.DurationSeconds != 1530 || *set.DistanceMeters != 5012.3 || set.Reps != 0 {
+		t.Fatalf("unexpected set %+v", set)
+	}
+}
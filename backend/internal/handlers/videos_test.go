@@ -14,6 +14,7 @@ import (
     "fitonex/backend/internal/models"
     "fitonex/backend/internal/pagination"
     "fitonex/backend/internal/ratelimit"
+    "fitonex/backend/internal/storage"
 )
 
 type fakeStorage struct {
@@ -25,6 +26,7 @@ type fakeStorage struct {
 	videoURL string
 	thumbURL string
 	err      error
+	objects  map[string][]byte
 }
 
 func (f *fakeStorage) PresignPut(_ context.Context, key, contentType string, sizeBytes int64, _ time.Duration) (string, error) {
@@ -42,6 +44,15 @@ func (f *fakeStorage) PresignPut(_ context.Context, key, contentType string, siz
 	return f.thumbURL, nil
 }
 
+func (f *fakeStorage) PutObject(_ context.Context, key, contentType string, data []byte) error {
+	f.calls = append(f.calls, struct {
+		Key         string
+		ContentType string
+		Size        int64
+	}{key, contentType, int64(len(data))})
+	return f.err
+}
+
 func (f *fakeStorage) SignedGet(_ context.Context, key string, _ string, _ time.Duration) (string, error) {
 	if f.err != nil {
 		return "", f.err
@@ -49,6 +60,30 @@ func (f *fakeStorage) SignedGet(_ context.Context, key string, _ string, _ time.
 	return f.videoURL, nil
 }
 
+func (f *fakeStorage) StatObject(_ context.Context, key string) (storage.ObjectInfo, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return storage.ObjectInfo{}, storage.ErrObjectNotFound
+	}
+	return storage.ObjectInfo{Size: int64(len(data))}, nil
+}
+
+func (f *fakeStorage) GetObject(_ context.Context, key string, maxBytes int64) ([]byte, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, storage.ErrObjectTooLarge
+	}
+	return data, nil
+}
+
+func (f *fakeStorage) DeleteObject(_ context.Context, key string) error {
+	delete(f.objects, key)
+	return f.err
+}
+
 func (f *fakeStorage) Ping(ctx context.Context) error {
 	return nil
 }
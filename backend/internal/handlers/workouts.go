@@ -1,22 +1,46 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"fitonex/backend/internal/httpx"
 	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	exercisesstore "fitonex/backend/internal/store/exercises"
+	workoutsstore "fitonex/backend/internal/store/workouts"
 
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	defaultWorkoutLimit = 20
+	maxWorkoutLimit     = 100
+	maxWorkoutExercises = 50
+)
+
+// WorkoutExerciseRequest represents an exercise logged as part of a workout
+type WorkoutExerciseRequest struct {
+	GymID     *string               `json:"gym_id,omitempty"`
+	MachineID *string               `json:"machine_id,omitempty"`
+	CatalogID *string               `json:"catalog_id,omitempty"`
+	Name      string                `json:"name"`
+	Group     *models.ExerciseGroup `json:"group,omitempty"`
+	Sets      []models.Set          `json:"sets"`
+}
+
 // CreateWorkoutRequest represents the workout creation request
 type CreateWorkoutRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Duration    int    `json:"duration"` // in minutes
-	Type        string `json:"type"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Duration    int                      `json:"duration"` // in minutes
+	Type        string                   `json:"type"`
+	GymID       *string                  `json:"gym_id,omitempty"`
+	Exercises   []WorkoutExerciseRequest `json:"exercises,omitempty"`
 }
 
 // UpdateWorkoutRequest represents the workout update request
@@ -27,120 +51,367 @@ type UpdateWorkoutRequest struct {
 	Type        string `json:"type"`
 }
 
-// WorkoutsResponse represents the workouts list response
-type WorkoutsResponse struct {
-	Workouts []models.Workout `json:"workouts"`
-	Next     string           `json:"next,omitempty"`
-}
-
-// GetWorkouts handles getting user workouts with cursor-based pagination
+// GetWorkouts handles listing user workouts with cursor-based pagination,
+// filtering by type and date range, and sorting by newest, oldest or longest.
+// planned=true lists the workouts planned but not yet performed instead.
 func (h *Handlers) GetWorkouts(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
-	
-	// Get cursor from query parameter
-	cursor := r.URL.Query().Get("cursor")
-	limitStr := r.URL.Query().Get("limit")
-	
-	limit := 20 // default limit
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := defaultWorkoutLimit
+	if limitParam := strings.TrimSpace(query.Get("limit")); limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value <= 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		if value > maxWorkoutLimit {
+			value = maxWorkoutLimit
+		}
+		limit = value
+	}
+
+	filter := workoutsstore.ListFilter{
+		Type: optionalQueryParam(r, "type"),
+		Sort: strings.TrimSpace(query.Get("sort")),
+	}
+	if plannedParam := strings.TrimSpace(query.Get("planned")); plannedParam != "" {
+		planned, err := strconv.ParseBool(plannedParam)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "planned must be true or false")
+			return
+		}
+		filter.Planned = planned
+	}
+	switch filter.Sort {
+	case "", workoutsstore.SortNewest, workoutsstore.SortOldest, workoutsstore.SortLongest:
+	default:
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "sort must be newest, oldest or longest")
+		return
+	}
+
+	if fromStr := strings.TrimSpace(query.Get("from")); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must use YYYY-MM-DD format")
+			return
+		}
+		filter.From = &from
+	}
+	if toStr := strings.TrimSpace(query.Get("to")); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "to must use YYYY-MM-DD format")
+			return
+		}
+		end := to.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "from must not be after to")
+		return
+	}
+
+	cursor := strings.TrimSpace(query.Get("cursor"))
+	if _, legacy := workoutsstore.ParseLegacyCursor(cursor); legacy {
+		if filter.Sort != "" && filter.Sort != workoutsstore.SortNewest {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+			return
 		}
+		// Raw timestamp cursors are deprecated in favour of next_cursor.
+		w.Header().Set("Deprecation", "true")
 	}
 
-	workouts, nextCursor, err := h.store.Workouts.GetByUserID(userID, cursor, limit)
+	page, err := h.store.Workouts.List(userID, filter, limit, cursor)
 	if err != nil {
-		http.Error(w, "Failed to fetch workouts", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid cursor")
+		case errors.Is(err, pagination.ErrInvalidLimit):
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "limit must be greater than zero")
+		default:
+			httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workouts"))
+		}
 		return
 	}
 
-	response := WorkoutsResponse{
-		Workouts: workouts,
-		Next:     nextCursor,
+	if err := h.attachWorkoutExercises(userID, page.Items); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workout exercises"))
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	httpx.WriteJSON(w, http.StatusOK, page)
 }
 
-// CreateWorkout handles creating a new workout
+// CreateWorkout handles creating a new workout, optionally with its exercises
 func (h *Handlers) CreateWorkout(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
 
 	var req CreateWorkoutRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
 		return
 	}
 
 	// Validate input
+	req.Name = strings.TrimSpace(req.Name)
 	if req.Name == "" {
-		http.Error(w, "Workout name is required", http.StatusBadRequest)
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "workout name is required")
+		return
+	}
+	if req.Duration < 0 {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "duration must be non-negative")
+		return
+	}
+	if len(req.Exercises) > maxWorkoutExercises {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "a workout can contain at most 50 exercises")
 		return
 	}
 
-	workout, err := h.store.Workouts.Create(userID, req.Name, req.Description, req.Duration, req.Type)
+	exercises := make([]models.Exercise, 0, len(req.Exercises))
+	for _, item := range req.Exercises {
+		catalogID, name, apiErr := h.resolveCatalogExercise(item.CatalogID, strings.TrimSpace(item.Name))
+		if apiErr != nil {
+			httpx.WriteAPIError(w, apiErr)
+			return
+		}
+		item.Name = name
+		if item.Name == "" || len(item.Sets) == 0 {
+			httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "exercise name and sets are required")
+			return
+		}
+		if apiErr := validateExerciseSets(item.Sets); apiErr != nil {
+			httpx.WriteAPIError(w, apiErr)
+			return
+		}
+		group, apiErr := normalizeExerciseGroup(item.Group)
+		if apiErr != nil {
+			httpx.WriteAPIError(w, apiErr)
+			return
+		}
+
+		gymID := trimmedOptional(item.GymID)
+		if gymID == nil {
+			gymID = trimmedOptional(req.GymID)
+		}
+		exercises = append(exercises, models.Exercise{
+			GymID:     gymID,
+			MachineID: trimmedOptional(item.MachineID),
+			CatalogID: catalogID,
+			Name:      item.Name,
+			Group:     group,
+			Sets:      item.Sets,
+		})
+	}
+	if apiErr := validateWorkoutGroups(exercises); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	workout, err := h.store.Workouts.Create(userID, req.Name, req.Description, req.Duration, req.Type, exercises)
 	if err != nil {
-		http.Error(w, "Failed to create workout", http.StatusInternalServerError)
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to create workout"))
 		return
 	}
 
+	for i := range workout.Exercises {
+		workout.Exercises[i].PersonalRecords = h.detectPersonalRecords(r, userID, workout.Exercises[i])
+	}
+	// The workout is saved; an estimate that cannot be made is left out.
+	created := []models.Workout{*workout}
+	h.attachCalories(userID, created)
+	workout = &created[0]
+
 	if h.analytics != nil {
 		h.analytics.EmitEvent(r.Context(), userID, "workout_created", map[string]any{
 			"workout_id": workout.ID,
+			"exercises":  len(workout.Exercises),
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(workout)
+	httpx.WriteJSON(w, http.StatusCreated, workout)
 }
 
-// GetWorkout handles getting a specific workout
+// GetWorkout handles getting a specific workout with its exercises and totals
 func (h *Handlers) GetWorkout(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
 	workoutID := chi.URLParam(r, "id")
 
 	workout, err := h.store.Workouts.GetByID(workoutID, userID)
 	if err != nil {
-		http.Error(w, "Workout not found", http.StatusNotFound)
+		writeWorkoutError(w, err, "failed to fetch workout")
+		return
+	}
+
+	workouts := []models.Workout{*workout}
+	if err := h.attachWorkoutExercises(userID, workouts); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workout exercises"))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(workout)
+	httpx.WriteJSON(w, http.StatusOK, workouts[0])
 }
 
 // UpdateWorkout handles updating a workout
 func (h *Handlers) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
 	workoutID := chi.URLParam(r, "id")
 
 	var req UpdateWorkoutRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
 		return
 	}
 
 	workout, err := h.store.Workouts.Update(workoutID, userID, req.Name, req.Description, req.Duration, req.Type)
 	if err != nil {
-		http.Error(w, "Failed to update workout", http.StatusInternalServerError)
+		writeWorkoutError(w, err, "failed to update workout")
+		return
+	}
+
+	workouts := []models.Workout{*workout}
+	if err := h.attachWorkoutExercises(userID, workouts); err != nil {
+		httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, "failed to fetch workout exercises"))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(workout)
+	httpx.WriteJSON(w, http.StatusOK, workouts[0])
 }
 
-// DeleteWorkout handles deleting a workout
+// DeleteWorkout handles deleting a workout. Its exercises stay logged but are detached.
 func (h *Handlers) DeleteWorkout(w http.ResponseWriter, r *http.Request) {
-	userID := r.Context().Value("userID").(string)
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
 	workoutID := chi.URLParam(r, "id")
 
 	if err := h.store.Workouts.Delete(workoutID, userID); err != nil {
-		http.Error(w, "Failed to delete workout", http.StatusInternalServerError)
+		writeWorkoutError(w, err, "failed to delete workout")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// AddWorkoutExercise logs an exercise inside an existing workout. Logging
+// into a planned workout marks it performed from now on.
+func (h *Handlers) AddWorkoutExercise(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		httpx.WriteError(w, http.StatusUnauthorized, httpx.ErrorCodeUnauthorized, "unauthorized")
+		return
+	}
+	workoutID := chi.URLParam(r, "id")
+
+	var req WorkoutExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	catalogID, name, apiErr := h.resolveCatalogExercise(req.CatalogID, req.Name)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	req.Name = name
+	if req.Name == "" || len(req.Sets) == 0 {
+		httpx.WriteError(w, http.StatusBadRequest, httpx.ErrorCodeBadRequest, "name and sets are required")
+		return
+	}
+	if apiErr := validateExerciseSets(req.Sets); apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+	group, apiErr := normalizeExerciseGroup(req.Group)
+	if apiErr != nil {
+		httpx.WriteAPIError(w, apiErr)
+		return
+	}
+
+	exercise, err := h.store.Exercises.Create(userID, &workoutID, time.Now().UTC(), trimmedOptional(req.GymID), trimmedOptional(req.MachineID), catalogID, req.Name, group, req.Sets)
+	if err != nil {
+		writeWorkoutError(w, err, "failed to create exercise")
+		return
+	}
+
+	exercise.PersonalRecords = h.detectPersonalRecords(r, userID, *exercise)
+
+	httpx.WriteJSON(w, http.StatusCreated, exercise)
+}
+
+// validateWorkoutGroups checks that exercises sharing a group ID agree on the
+// group type and that every group links at least two exercises.
+func validateWorkoutGroups(exercises []models.Exercise) *httpx.APIError {
+	types := map[string]string{}
+	counts := map[string]int{}
+	for _, exercise := range exercises {
+		if exercise.Group == nil {
+			continue
+		}
+		if existing, ok := types[exercise.Group.ID]; ok && existing != exercise.Group.Type {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "exercises in group "+exercise.Group.ID+" must share one group type")
+		}
+		types[exercise.Group.ID] = exercise.Group.Type
+		counts[exercise.Group.ID]++
+	}
+	for id, count := range counts {
+		if count < 2 {
+			return httpx.NewError(http.StatusBadRequest, httpx.ErrorCodeBadRequest, "group "+id+" needs at least two exercises")
+		}
+	}
+	return nil
+}
+
+// attachWorkoutExercises embeds exercises, planned exercises, totals and
+// calorie estimates into each workout in place.
+func (h *Handlers) attachWorkoutExercises(userID string, workouts []models.Workout) error {
+	ids := make([]string, 0, len(workouts))
+	for _, workout := range workouts {
+		ids = append(ids, workout.ID)
+	}
+
+	byWorkout, err := h.store.Exercises.ListByWorkouts(userID, ids)
+	if err != nil {
+		return err
+	}
+	plannedByWorkout, err := h.store.Workouts.ListPlannedExercises(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range workouts {
+		workoutsstore.AttachExercises(&workouts[i], byWorkout[workouts[i].ID])
+		workouts[i].PlannedExercises = plannedByWorkout[workouts[i].ID]
+	}
+	h.attachCalories(userID, workouts)
+	return nil
+}
+
+func writeWorkoutError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, workoutsstore.ErrWorkoutNotFound) || errors.Is(err, exercisesstore.ErrWorkoutNotFound) {
+		httpx.WriteError(w, http.StatusNotFound, httpx.ErrorCodeNotFound, "workout not found")
+		return
+	}
+	httpx.WriteAPIError(w, httpx.WrapError(err, http.StatusInternalServerError, httpx.ErrorCodeInternal, message))
+}
@@ -0,0 +1,168 @@
+// Package imaging validates uploaded photos and renders resized JPEG
+// variants using only the standard library image packages.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// MaxPixels bounds the decoded size of an image so a small, highly
+// compressed upload cannot exhaust memory. Rendering holds the decoded image
+// plus one RGBA copy, about 4 bytes per pixel each.
+const MaxPixels = 24_000_000
+
+// JPEGQuality is used for every variant.
+const JPEGQuality = 85
+
+var (
+	// ErrUnsupportedFormat is returned for data that is not a JPEG or PNG image.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooManyPixels is returned when an image exceeds MaxPixels.
+	ErrTooManyPixels = errors.New("image dimensions too large")
+)
+
+// ContentTypes maps the accepted upload content types to their decoder
+// format names.
+var ContentTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+}
+
+// Variant is a resized copy of an image.
+type Variant struct {
+	Name    string
+	MaxSide int
+}
+
+// Decode checks the header of data before decoding it, rejecting anything
+// other than a JPEG or PNG within MaxPixels. It returns the image and its
+// content type.
+func Decode(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	contentType := ""
+	for ct, name := range ContentTypes {
+		if name == format {
+			contentType = ct
+		}
+	}
+	if contentType == "" {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", format, err)
+	}
+	return img, contentType, nil
+}
+
+// Fit scales src down so its longer side is at most maxSide, averaging the
+// source pixels that fall into each destination pixel. Images that already
+// fit are returned unscaled and may share pixels with src.
+func Fit(src image.Image, maxSide int) *image.RGBA {
+	return fit(toRGBA(src), maxSide)
+}
+
+func fit(rgba *image.RGBA, maxSide int) *image.RGBA {
+	bounds := rgba.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			height = max(1, height*maxSide/width)
+			width = maxSide
+		} else {
+			width = max(1, width*maxSide/height)
+			height = maxSide
+		}
+	}
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*bounds.Dy()/height, (y+1)*bounds.Dy()/height
+		y1 = max(y1, y0+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*bounds.Dx()/width, (x+1)*bounds.Dx()/width
+			x1 = max(x1, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// toRGBA returns src as an RGBA image anchored at the origin, copying it
+// only when it is not one already.
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	return rgba
+}
+
+// EncodeJPEG renders img as a JPEG. Transparent areas come out black, so
+// callers flatten PNGs with Flatten first.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Flatten draws img over a white background.
+func Flatten(img *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// Render produces a JPEG for each variant. Re-encoding also drops the
+// original's metadata, such as EXIF location. The source is converted to
+// RGBA once and shared by every variant.
+func Render(src image.Image, variants []Variant) (map[string][]byte, error) {
+	rgba := toRGBA(src)
+	out := make(map[string][]byte, len(variants))
+	for _, variant := range variants {
+		data, err := EncodeJPEG(Flatten(fit(rgba, variant.MaxSide)))
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", variant.Name, err)
+		}
+		out[variant.Name] = data
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+func solid(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solid(40, 20, color.RGBA{R: 255, A: 255})); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	img, contentType, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if contentType != "image/png" || img.Bounds().Dx() != 40 {
+		t.Fatalf("unexpected result %s %v", contentType, img.Bounds())
+	}
+
+	buf.Reset()
+	if err := gif.Encode(&buf, solid(4, 4, color.Black), nil); err != nil {
+		t.Fatalf("gif.Encode: %v", err)
+	}
+	if _, _, err := Decode(buf.Bytes()); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected gif to be rejected, got %v", err)
+	}
+	if _, _, err := Decode([]byte("not an image")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected garbage to be rejected, got %v", err)
+	}
+}
+
+func TestFit(t *testing.T) {
+	src := solid(400, 100, color.RGBA{B: 200, A: 255})
+	dst := Fit(src, 100)
+	if dst.Bounds().Dx() != 100 || dst.Bounds().Dy() != 25 {
+		t.Fatalf("expected 100x25, got %v", dst.Bounds())
+	}
+	if got := dst.RGBAAt(50, 10); got != (color.RGBA{B: 200, A: 255}) {
+		t.Fatalf("expected the colour to survive averaging, got %v", got)
+	}
+
+	if small := Fit(solid(30, 60, color.White), 100); small.Bounds().Dx() != 30 || small.Bounds().Dy() != 60 {
+		t.Fatalf("expected a small image to keep its size, got %v", small.Bounds())
+	}
+}
+
+func TestRender(t *testing.T) {
+	out, err := Render(solid(800, 600, color.RGBA{G: 128, A: 255}), []Variant{{Name: "thumb", MaxSide: 200}})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	img, contentType, err := Decode(out["thumb"])
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if contentType != "image/jpeg" || img.Bounds().Dx() != 200 || img.Bounds().Dy() != 150 {
+		t.Fatalf("unexpected variant %s %v", contentType, img.Bounds())
+	}
+}
+
+func TestToRGBA(t *testing.T) {
+	src := solid(10, 10, color.White)
+	if toRGBA(src) != src {
+		t.Fatal("expected an RGBA image to be reused")
+	}
+
+	gray := image.NewGray(image.Rect(5, 5, 15, 25))
+	rgba := toRGBA(gray)
+	if rgba.Bounds() != image.Rect(0, 0, 10, 20) {
+		t.Fatalf("expected the copy to start at the origin, got %v", rgba.Bounds())
+	}
+}
@@ -0,0 +1,445 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	// kgPerLb converts pounds to kilograms.
+	kgPerLb = 0.45359237
+	// metersPerMile converts miles to meters.
+	metersPerMile = 1609.344
+)
+
+// ErrUnknownFormat is returned when the CSV header matches no supported tracker.
+var ErrUnknownFormat = errors.New("importer: unrecognized export format")
+
+// Session is one workout reconstructed from export rows.
+type Session struct {
+	// Key identifies the session within its source so overlapping exports
+	// do not import the same workout twice.
+	Key             string
+	Name            string
+	StartedAt       time.Time
+	DurationMinutes int
+	Notes           string
+	Exercises       []Exercise
+}
+
+// Exercise groups the sets of one exercise within a session.
+type Exercise struct {
+	Name string
+	Rows []int
+	Sets []models.Set
+}
+
+// Result is a parsed export.
+type Result struct {
+	Source   string
+	Sessions []Session
+	Skipped  []models.ImportIssue
+}
+
+// row is a normalized export line.
+type row struct {
+	line         int
+	sessionName  string
+	startedAt    time.Time
+	duration     int
+	sessionNotes string
+	exercise     string
+	setType      string
+	reps         int
+	weightKg     *float64
+	rpe          *float64
+	seconds      *int
+	meters       *float64
+	notes        string
+}
+
+// Parse reads a Strong or Hevy CSV export. source may be empty to detect the
+// format from the header. defaultUnit ("kg" or "lb") applies when the export
+// does not state the weight unit; with "lb", unlabeled distances are miles.
+func Parse(r io.Reader, source, defaultUnit string) (*Result, error) {
+	reader, err := newCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("importer: read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	detected := detect(columns)
+	if detected == "" {
+		return nil, ErrUnknownFormat
+	}
+	if source != "" && source != detected {
+		return nil, fmt.Errorf("importer: file looks like a %s export, not %s", detected, source)
+	}
+
+	result := &Result{Source: detected}
+	var rows []row
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("importer: line %d: %w", line, err)
+		}
+
+		var parsed row
+		var reason string
+		switch detected {
+		case models.ImportSourceStrong:
+			parsed, reason = parseStrongRow(record, columns, defaultUnit)
+		case models.ImportSourceHevy:
+			parsed, reason = parseHevyRow(record, columns, defaultUnit)
+		}
+		parsed.line = line
+		if reason != "" {
+			result.Skipped = appendIssue(result.Skipped, line, parsed.exercise, reason)
+			continue
+		}
+		rows = append(rows, parsed)
+	}
+
+	result.Sessions = groupSessions(detected, rows)
+	return result, nil
+}
+
+func newCSVReader(r io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(r)
+	first, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("importer: read: %w", err)
+	}
+	// Spreadsheet tools often prepend a UTF-8 byte order mark.
+	bom := []byte("\xef\xbb\xbf")
+	if bytes.HasPrefix(first, bom) {
+		first = first[len(bom):]
+		if _, err := buffered.Discard(len(bom)); err != nil {
+			return nil, fmt.Errorf("importer: read: %w", err)
+		}
+	}
+
+	headerLine := first
+	if idx := bytes.IndexByte(headerLine, '\n'); idx >= 0 {
+		headerLine = headerLine[:idx]
+	}
+
+	reader := csv.NewReader(buffered)
+	// Some Strong locales export with semicolons.
+	if bytes.Count(headerLine, []byte(";")) > bytes.Count(headerLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	return reader, nil
+}
+
+func detect(columns map[string]int) string {
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := columns[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("exercise_title", "start_time", "reps"):
+		return models.ImportSourceHevy
+	case has("exercise name", "date", "reps"):
+		return models.ImportSourceStrong
+	}
+	return ""
+}
+
+func parseStrongRow(record []string, columns map[string]int, defaultUnit string) (row, string) {
+	get := fieldGetter(record, columns)
+
+	parsed := row{
+		sessionName:  get("workout name"),
+		sessionNotes: get("workout notes"),
+		exercise:     get("exercise name"),
+		notes:        get("notes"),
+	}
+	if parsed.exercise == "" {
+		return parsed, "missing exercise name"
+	}
+	// Strong writes rest timer entries as their own rows and marks set types
+	// in the set order column.
+	switch order := strings.ToUpper(get("set order")); order {
+	case "REST TIMER":
+		return parsed, "rest timer row"
+	case "W":
+		parsed.setType = models.SetTypeWarmup
+	case "D":
+		parsed.setType = models.SetTypeDropset
+	case "F":
+		parsed.setType = models.SetTypeFailure
+	}
+	parsed.seconds = positiveInt(get("seconds"))
+	distanceScale := 1000.0
+	if defaultUnit == "lb" {
+		distanceScale = metersPerMile
+	}
+	switch strings.ToLower(get("distance unit")) {
+	case "km":
+		distanceScale = 1000
+	case "mi":
+		distanceScale = metersPerMile
+	case "m":
+		distanceScale = 1
+	}
+	parsed.meters = positiveNumber(get("distance"), distanceScale)
+
+	startedAt, err := parseTime(get("date"), "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05Z07:00")
+	if err != nil {
+		return parsed, "invalid date"
+	}
+	parsed.startedAt = startedAt
+	parsed.duration = parseStrongDuration(get("duration"))
+
+	unit := defaultUnit
+	if value := strings.ToLower(get("weight unit")); value != "" {
+		unit = value
+	}
+	return fillSet(parsed, get("reps"), get("weight"), unit, get("rpe"))
+}
+
+func parseHevyRow(record []string, columns map[string]int, defaultUnit string) (row, string) {
+	get := fieldGetter(record, columns)
+
+	parsed := row{
+		sessionName:  get("title"),
+		sessionNotes: get("description"),
+		exercise:     get("exercise_title"),
+		notes:        get("exercise_notes"),
+	}
+	if parsed.exercise == "" {
+		return parsed, "missing exercise name"
+	}
+
+	layouts := []string{"2 Jan 2006, 15:04", "Jan 2, 2006, 3:04 PM", "2006-01-02 15:04:05", time.RFC3339}
+	startedAt, err := parseTime(get("start_time"), layouts...)
+	if err != nil {
+		return parsed, "invalid start_time"
+	}
+	parsed.startedAt = startedAt
+	switch strings.ToLower(get("set_type")) {
+	case "warmup":
+		parsed.setType = models.SetTypeWarmup
+	case "dropset":
+		parsed.setType = models.SetTypeDropset
+	case "failure":
+		parsed.setType = models.SetTypeFailure
+	}
+	parsed.seconds = positiveInt(get("duration_seconds"))
+	if meters := positiveNumber(get("distance_km"), 1000); meters != nil {
+		parsed.meters = meters
+	} else {
+		parsed.meters = positiveNumber(get("distance_miles"), metersPerMile)
+	}
+	if endedAt, err := parseTime(get("end_time"), layouts...); err == nil && endedAt.After(startedAt) {
+		parsed.duration = int(endedAt.Sub(startedAt).Minutes())
+	}
+
+	weight, unit := get("weight_kg"), "kg"
+	if _, ok := columns["weight_kg"]; !ok {
+		if _, ok := columns["weight_lbs"]; ok {
+			weight, unit = get("weight_lbs"), "lb"
+		} else {
+			weight, unit = get("weight"), defaultUnit
+		}
+	}
+	return fillSet(parsed, get("reps"), weight, unit, get("rpe"))
+}
+
+func fillSet(parsed row, repsValue, weightValue, unit, rpeValue string) (row, string) {
+	reps, _ := strconv.Atoi(strings.TrimSuffix(repsValue, ".0"))
+	if reps <= 0 {
+		// Cardio rows carry a duration or distance instead of reps.
+		if parsed.seconds == nil && parsed.meters == nil {
+			return parsed, "set has no reps"
+		}
+		reps = 0
+	}
+	parsed.reps = reps
+	if parsed.setType == "" {
+		parsed.setType = models.SetTypeWorking
+	}
+
+	if weightValue != "" {
+		weight, err := parseNumber(weightValue)
+		if err != nil || weight < 0 {
+			return parsed, "invalid weight"
+		}
+		if unit == "lb" || unit == "lbs" {
+			weight *= kgPerLb
+		}
+		if weight > 0 {
+			rounded := math.Round(weight*100) / 100
+			parsed.weightKg = &rounded
+		}
+	}
+
+	if rpeValue != "" {
+		if rpe, err := parseNumber(rpeValue); err == nil && rpe >= 1 && rpe <= 10 {
+			parsed.rpe = &rpe
+		}
+	}
+	return parsed, ""
+}
+
+func groupSessions(source string, rows []row) []Session {
+	var sessions []Session
+	index := map[string]int{}
+
+	for _, item := range rows {
+		key := fmt.Sprintf("%s:%s:%s", source, item.startedAt.UTC().Format(time.RFC3339), strings.ToLower(item.sessionName))
+		position, ok := index[key]
+		if !ok {
+			name := item.sessionName
+			if name == "" {
+				name = "Imported workout"
+			}
+			sessions = append(sessions, Session{
+				Key:             key,
+				Name:            name,
+				StartedAt:       item.startedAt.UTC(),
+				DurationMinutes: item.duration,
+				Notes:           item.sessionNotes,
+			})
+			position = len(sessions) - 1
+			index[key] = position
+		}
+
+		session := &sessions[position]
+		exercisePosition := -1
+		for i := range session.Exercises {
+			if strings.EqualFold(session.Exercises[i].Name, item.exercise) {
+				exercisePosition = i
+				break
+			}
+		}
+		if exercisePosition < 0 {
+			session.Exercises = append(session.Exercises, Exercise{Name: item.exercise})
+			exercisePosition = len(session.Exercises) - 1
+		}
+
+		exercise := &session.Exercises[exercisePosition]
+		set := models.Set{
+			Type:            item.setType,
+			Reps:            item.reps,
+			WeightKg:        item.weightKg,
+			RPE:             item.rpe,
+			DurationSeconds: item.seconds,
+			DistanceMeters:  item.meters,
+		}
+		if item.notes != "" {
+			notes := item.notes
+			set.Notes = &notes
+		}
+		exercise.Rows = append(exercise.Rows, item.line)
+		exercise.Sets = append(exercise.Sets, set)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+	return sessions
+}
+
+// parseStrongDuration reads values such as "1h 5m", "45m" or "30s".
+func parseStrongDuration(value string) int {
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	if value == "" {
+		return 0
+	}
+	if minutes, err := strconv.Atoi(value); err == nil {
+		return minutes
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return int(duration.Minutes())
+}
+
+func parseTime(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("importer: unsupported time %q", value)
+}
+
+// positiveInt parses a whole number, ignoring empty, invalid and zero values.
+func positiveInt(value string) *int {
+	number, err := parseNumber(value)
+	if err != nil || number < 1 {
+		return nil
+	}
+	result := int(math.Round(number))
+	return &result
+}
+
+// positiveNumber parses a value and scales it, ignoring empty, invalid and zero values.
+func positiveNumber(value string, scale float64) *float64 {
+	number, err := parseNumber(value)
+	if err != nil || number <= 0 {
+		return nil
+	}
+	result := math.Round(number*scale*100) / 100
+	return &result
+}
+
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", "."), 64)
+}
+
+func fieldGetter(record []string, columns map[string]int) func(name string) string {
+	return func(name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+}
+
+// appendIssue merges rows that share an exercise name and reason.
+func appendIssue(issues []models.ImportIssue, line int, exercise, reason string) []models.ImportIssue {
+	for i := range issues {
+		if issues[i].ExerciseName == exercise && issues[i].Reason == reason {
+			issues[i].Rows = append(issues[i].Rows, line)
+			return issues
+		}
+	}
+	return append(issues, models.ImportIssue{Rows: []int{line}, ExerciseName: exercise, Reason: reason})
+}
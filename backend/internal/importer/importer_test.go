@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestParseStrong(t *testing.T) {
+	data := "\xef\xbb\xbfDate,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE\n" +
+		"2024-03-04 18:00:00,Push Day,1h 5m,Bench Press (Barbell),1,60,8,0,0,,Felt good,8\n" +
+		"2024-03-04 18:00:00,Push Day,1h 5m,Bench Press (Barbell),2,62.5,6,0,0,,Felt good,\n" +
+		"2024-03-04 18:00:00,Push Day,1h 5m,Bench Press (Barbell),Rest Timer,0,0,0,90,,Felt good,\n" +
+		"2024-03-04 18:00:00,Push Day,1h 5m,Chest Fly,1,20,0,0,0,,Felt good,\n" +
+		"2024-03-02 09:30:00,Legs,45m,Leg Press,1,100,10,0,0,Slow,,\n"
+
+	result, err := Parse(strings.NewReader(data), "", "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if result.Source != models.ImportSourceStrong {
+		t.Fatalf("source = %q, want strong", result.Source)
+	}
+	if len(result.Sessions) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(result.Sessions))
+	}
+
+	legs, push := result.Sessions[0], result.Sessions[1]
+	if legs.Name != "Legs" || legs.DurationMinutes != 45 {
+		t.Fatalf("first session = %q/%d, want Legs/45", legs.Name, legs.DurationMinutes)
+	}
+	if push.DurationMinutes != 65 || len(push.Exercises) != 1 {
+		t.Fatalf("push session = %d min, %d exercises", push.DurationMinutes, len(push.Exercises))
+	}
+	bench := push.Exercises[0]
+	if len(bench.Sets) != 2 || *bench.Sets[1].WeightKg != 62.5 || bench.Sets[0].RPE == nil {
+		t.Fatalf("unexpected bench sets: %+v", bench.Sets)
+	}
+	if legs.Exercises[0].Sets[0].Notes == nil || *legs.Exercises[0].Sets[0].Notes != "Slow" {
+		t.Fatalf("expected set notes to be kept")
+	}
+
+	if len(result.Skipped) != 2 {
+		t.Fatalf("skipped = %+v, want rest timer and zero-rep rows", result.Skipped)
+	}
+}
+
+func TestParseHevyPounds(t *testing.T) {
+	data := "title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_lbs,reps,distance_miles,duration_seconds,rpe\n" +
+		"Upper,\"5 Mar 2024, 07:00\",\"5 Mar 2024, 08:10\",,Lat Pulldown (Cable),,,0,normal,100,10,,,\n" +
+		"Upper,\"5 Mar 2024, 07:00\",\"5 Mar 2024, 08:10\",,lat pulldown (cable),,,1,normal,110,8,,,\n"
+
+	result, err := Parse(strings.NewReader(data), models.ImportSourceHevy, "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Sessions) != 1 {
+		t.Fatalf("sessions = %d, want 1", len(result.Sessions))
+	}
+	session := result.Sessions[0]
+	if session.DurationMinutes != 70 {
+		t.Fatalf("duration = %d, want 70", session.DurationMinutes)
+	}
+	if !session.StartedAt.Equal(time.Date(2024, 3, 5, 7, 0, 0, 0, time.UTC)) {
+		t.Fatalf("started at = %v", session.StartedAt)
+	}
+	if len(session.Exercises) != 1 || len(session.Exercises[0].Sets) != 2 {
+		t.Fatalf("expected sets grouped case-insensitively, got %+v", session.Exercises)
+	}
+	if got := *session.Exercises[0].Sets[0].WeightKg; got != 45.36 {
+		t.Fatalf("weight = %v, want 45.36", got)
+	}
+}
+
+func TestParseSetTypesAndCardio(t *testing.T) {
+	data := "Date,Workout Name,Exercise Name,Set Order,Weight,Reps,Distance,Seconds\n" +
+		"2024-03-04 18:00:00,Mixed,Squat,W,40,10,0,0\n" +
+		"2024-03-04 18:00:00,Mixed,Squat,1,100,5,0,0\n" +
+		"2024-03-04 18:00:00,Mixed,Squat,D,80,8,0,0\n" +
+		"2024-03-04 18:00:00,Mixed,Rowing Machine,1,0,0,2.5,600\n"
+
+	result, err := Parse(strings.NewReader(data), "", "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Skipped) != 0 {
+		t.Fatalf("unexpected skipped rows %+v", result.Skipped)
+	}
+
+	squat := result.Sessions[0].Exercises[0].Sets
+	if squat[0].Type != models.SetTypeWarmup || squat[1].Type != models.SetTypeWorking || squat[2].Type != models.SetTypeDropset {
+		t.Fatalf("unexpected set types %q %q %q", squat[0].Type, squat[1].Type, squat[2].Type)
+	}
+
+	row := result.Sessions[0].Exercises[1].Sets[0]
+	if row.Reps != 0 || row.DurationSeconds == nil || *row.DurationSeconds != 600 {
+		t.Fatalf("expected a 600s timed set, got %+v", row)
+	}
+	if row.DistanceMeters == nil || *row.DistanceMeters != 2500 {
+		t.Fatalf("expected 2500m, got %v", row.DistanceMeters)
+	}
+}
+
+func TestParseSemicolonDecimalComma(t *testing.T) {
+	data := "Date;Workout Name;Exercise Name;Set Order;Weight;Reps\n" +
+		"2024-03-04 18:00:00;Push;Bench Press;1;62,5;5\n"
+
+	result, err := Parse(strings.NewReader(data), "", "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := *result.Sessions[0].Exercises[0].Sets[0].WeightKg; got != 62.5 {
+		t.Fatalf("weight = %v, want 62.5", got)
+	}
+}
+
+func TestParseRejectsUnknownOrMismatchedFormat(t *testing.T) {
+	if _, err := Parse(strings.NewReader("a,b,c\n1,2,3\n"), "", "kg"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("err = %v, want ErrUnknownFormat", err)
+	}
+
+	strong := "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n"
+	if _, err := Parse(strings.NewReader(strong), models.ImportSourceHevy, "kg"); err == nil {
+		t.Fatalf("expected an error when the source does not match the file")
+	}
+}
+
+func TestSessionKeysAreStable(t *testing.T) {
+	data := "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n" +
+		"2024-03-04 18:00:00,Push,Bench Press,1,60,5\n"
+
+	first, err := Parse(strings.NewReader(data), "", "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	second, err := Parse(strings.NewReader(data+"2024-03-06 18:00:00,Pull,Row,1,50,8\n"), "", "kg")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if first.Sessions[0].Key != second.Sessions[0].Key {
+		t.Fatalf("keys differ across exports: %q vs %q", first.Sessions[0].Key, second.Sessions[0].Key)
+	}
+}
@@ -0,0 +1,319 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	earthRadiusMeters = 6371000
+	// elevationThreshold filters GPS altitude noise: a climb only counts once
+	// the track has risen this far above its last low point.
+	elevationThreshold = 3.0
+)
+
+var (
+	// ErrUnknownTrackFormat is returned when a file is neither GPX nor TCX.
+	ErrUnknownTrackFormat = errors.New("importer: file is not a GPX or TCX track")
+	// ErrEmptyTrack is returned when a track has no timed points.
+	ErrEmptyTrack = errors.New("importer: track has no timed points")
+)
+
+// TrackPoint is one recorded position. Distance is the cumulative distance
+// some TCX devices record; fields the file leaves out are nil.
+type TrackPoint struct {
+	Time      time.Time
+	Lat, Lng  *float64
+	Elevation *float64
+	Distance  *float64
+	HeartRate *int
+}
+
+// Track is a parsed GPX or TCX file.
+type Track struct {
+	Format string
+	Name   string
+	// Sport is lowercase and mapped to a workout type where one fits.
+	Sport  string
+	Points []TrackPoint
+	// LapDistance and LapSeconds are TCX lap totals, used when points carry
+	// no distance or span no time.
+	LapDistance float64
+	LapSeconds  float64
+}
+
+// ParseTrack reads a GPX or TCX file, detected from its root element.
+func ParseTrack(data []byte) (*Track, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, ErrUnknownTrackFormat
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: invalid XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var track *Track
+		var format string
+		switch start.Name.Local {
+		case "gpx":
+			format = models.TrackFormatGPX
+			track, err = parseGPX(decoder, start)
+		case "TrainingCenterDatabase":
+			format = models.TrackFormatTCX
+			track, err = parseTCX(decoder, start)
+		default:
+			return nil, ErrUnknownTrackFormat
+		}
+		if err != nil {
+			return nil, fmt.Errorf("importer: invalid %s file: %w", strings.ToUpper(format), err)
+		}
+		sort.SliceStable(track.Points, func(i, j int) bool { return track.Points[i].Time.Before(track.Points[j].Time) })
+		if len(track.Points) == 0 {
+			return nil, ErrEmptyTrack
+		}
+		return track, nil
+	}
+}
+
+type gpxFile struct {
+	Metadata struct {
+		Name string `xml:"name"`
+	} `xml:"metadata"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Lat        float64  `xml:"lat,attr"`
+				Lon        float64  `xml:"lon,attr"`
+				Elevation  *float64 `xml:"ele"`
+				Time       string   `xml:"time"`
+				Extensions struct {
+					HeartRate    *int `xml:"TrackPointExtension>hr"`
+					RawHeartRate *int `xml:"hr"`
+				} `xml:"extensions"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+func parseGPX(decoder *xml.Decoder, start xml.StartElement) (*Track, error) {
+	var file gpxFile
+	if err := decoder.DecodeElement(&file, &start); err != nil {
+		return nil, err
+	}
+
+	track := &Track{Format: models.TrackFormatGPX, Name: strings.TrimSpace(file.Metadata.Name)}
+	for _, trk := range file.Tracks {
+		if track.Name == "" {
+			track.Name = strings.TrimSpace(trk.Name)
+		}
+		if track.Sport == "" {
+			track.Sport = normalizeSport(trk.Type)
+		}
+		for _, segment := range trk.Segments {
+			for _, point := range segment.Points {
+				timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(point.Time))
+				if err != nil {
+					continue
+				}
+				lat, lng := point.Lat, point.Lon
+				heartRate := point.Extensions.HeartRate
+				if heartRate == nil {
+					heartRate = point.Extensions.RawHeartRate
+				}
+				track.Points = append(track.Points, TrackPoint{
+					Time:      timestamp.UTC(),
+					Lat:       &lat,
+					Lng:       &lng,
+					Elevation: point.Elevation,
+					HeartRate: heartRate,
+				})
+			}
+		}
+	}
+	return track, nil
+}
+
+type tcxFile struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Notes string `xml:"Notes"`
+		Laps  []struct {
+			TotalTimeSeconds float64 `xml:"TotalTimeSeconds"`
+			DistanceMeters   float64 `xml:"DistanceMeters"`
+			Tracks           []struct {
+				Points []struct {
+					Time      string   `xml:"Time"`
+					Lat       *float64 `xml:"Position>LatitudeDegrees"`
+					Lng       *float64 `xml:"Position>LongitudeDegrees"`
+					Altitude  *float64 `xml:"AltitudeMeters"`
+					Distance  *float64 `xml:"DistanceMeters"`
+					HeartRate *int     `xml:"HeartRateBpm>Value"`
+				} `xml:"Trackpoint"`
+			} `xml:"Track"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+func parseTCX(decoder *xml.Decoder, start xml.StartElement) (*Track, error) {
+	var file tcxFile
+	if err := decoder.DecodeElement(&file, &start); err != nil {
+		return nil, err
+	}
+
+	track := &Track{Format: models.TrackFormatTCX}
+	for _, activity := range file.Activities {
+		if track.Sport == "" {
+			track.Sport = normalizeSport(activity.Sport)
+		}
+		if track.Name == "" {
+			track.Name = strings.TrimSpace(activity.Notes)
+		}
+		for _, lap := range activity.Laps {
+			track.LapDistance += lap.DistanceMeters
+			track.LapSeconds += lap.TotalTimeSeconds
+			for _, trk := range lap.Tracks {
+				for _, point := range trk.Points {
+					timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(point.Time))
+					if err != nil {
+						continue
+					}
+					track.Points = append(track.Points, TrackPoint{
+						Time:      timestamp.UTC(),
+						Lat:       point.Lat,
+						Lng:       point.Lng,
+						Elevation: point.Altitude,
+						Distance:  point.Distance,
+						HeartRate: point.HeartRate,
+					})
+				}
+			}
+		}
+	}
+	return track, nil
+}
+
+// normalizeSport maps GPX track types and TCX sports to workout types.
+func normalizeSport(value string) string {
+	sport := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case sport == "":
+		return ""
+	case strings.Contains(sport, "run"):
+		return "running"
+	case strings.Contains(sport, "bik"), strings.Contains(sport, "cycl"):
+		return "cycling"
+	case strings.Contains(sport, "walk"):
+		return "walking"
+	case strings.Contains(sport, "hik"):
+		return "hiking"
+	case strings.Contains(sport, "swim"):
+		return "swimming"
+	case strings.Contains(sport, "row"):
+		return "rowing"
+	}
+	return "cardio"
+}
+
+// Summarize computes distance, duration, elevation gain, pace and heart rate
+// for a track. Recorded cumulative distance wins over the distance between
+// positions; lap totals fill in what the points lack.
+func (t *Track) Summarize() models.TrackSummary {
+	summary := models.TrackSummary{Points: len(t.Points)}
+	if len(t.Points) > 0 {
+		first, last := t.Points[0], t.Points[len(t.Points)-1]
+		summary.StartedAt = first.Time
+		summary.DurationSeconds = int(math.Round(last.Time.Sub(first.Time).Seconds()))
+	}
+	if summary.DurationSeconds == 0 {
+		summary.DurationSeconds = int(math.Round(t.LapSeconds))
+	}
+
+	var (
+		recorded, measured  float64
+		prev                *TrackPoint
+		anchor              *float64
+		heartRateSum, beats int
+	)
+	for i := range t.Points {
+		point := &t.Points[i]
+		if point.Distance != nil {
+			recorded = math.Max(recorded, *point.Distance)
+		}
+		if point.Lat != nil && point.Lng != nil {
+			if prev != nil {
+				measured += haversine(*prev.Lat, *prev.Lng, *point.Lat, *point.Lng)
+			}
+			prev = point
+		}
+		if point.Elevation != nil {
+			switch {
+			case anchor == nil:
+				anchor = point.Elevation
+			case *point.Elevation-*anchor >= elevationThreshold:
+				summary.ElevationGainMeters += *point.Elevation - *anchor
+				anchor = point.Elevation
+			case *point.Elevation < *anchor:
+				anchor = point.Elevation
+			}
+		}
+		if point.HeartRate != nil && *point.HeartRate > 0 {
+			heartRateSum += *point.HeartRate
+			beats++
+			if summary.MaxHeartRate == nil || *point.HeartRate > *summary.MaxHeartRate {
+				value := *point.HeartRate
+				summary.MaxHeartRate = &value
+			}
+		}
+	}
+
+	switch {
+	case recorded > 0:
+		if first := t.Points[0].Distance; first != nil && *first < recorded {
+			recorded -= *first
+		}
+		summary.DistanceMeters = recorded
+	case measured > 0:
+		summary.DistanceMeters = measured
+	default:
+		summary.DistanceMeters = t.LapDistance
+	}
+	summary.DistanceMeters = math.Round(summary.DistanceMeters*10) / 10
+	summary.ElevationGainMeters = math.Round(summary.ElevationGainMeters*10) / 10
+
+	if summary.DistanceMeters > 0 && summary.DurationSeconds > 0 {
+		pace := math.Round(float64(summary.DurationSeconds) / (summary.DistanceMeters / 1000))
+		speed := math.Round(summary.DistanceMeters/float64(summary.DurationSeconds)*3.6*100) / 100
+		summary.AvgPaceSecondsPerKm = &pace
+		summary.AvgSpeedKmh = &speed
+	}
+	if beats > 0 {
+		average := int(math.Round(float64(heartRateSum) / float64(beats)))
+		summary.AvgHeartRate = &average
+	}
+	return summary
+}
+
+func haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
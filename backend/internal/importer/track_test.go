@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+const sampleGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1"
+     xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <metadata><name>Morning Run</name></metadata>
+  <trk>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="0.001" lon="0"><ele>102</ele><time>2024-05-06T07:01:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>130</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="0" lon="0"><ele>100</ele><time>2024-05-06T07:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="0.002" lon="0"><ele>106</ele><time>2024-05-06T07:02:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const sampleTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+  <Activities>
+    <Activity Sport="Biking">
+      <Id>2024-05-06T17:00:00Z</Id>
+      <Lap StartTime="2024-05-06T17:00:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>5000</DistanceMeters>
+        <Track>
+          <Trackpoint><Time>2024-05-06T17:00:00Z</Time><AltitudeMeters>50</AltitudeMeters><DistanceMeters>10</DistanceMeters></Trackpoint>
+          <Trackpoint><Time>2024-05-06T17:05:00Z</Time><AltitudeMeters>48</AltitudeMeters><DistanceMeters>2510</DistanceMeters></Trackpoint>
+          <Trackpoint><Time>2024-05-06T17:10:00Z</Time><AltitudeMeters>53</AltitudeMeters><DistanceMeters>5010</DistanceMeters></Trackpoint>
+        </Track>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+func TestParseTrackGPX(t *testing.T) {
+	track, err := ParseTrack([]byte(sampleGPX))
+	if err != nil {
+		t.Fatalf("ParseTrack: %v", err)
+	}
+	if track.Format != models.TrackFormatGPX || track.Name != "Morning Run" || track.Sport != "running" || len(track.Points) != 3 {
+		t.Fatalf("unexpected track %+v", track)
+	}
+
+	summary := track.Summarize()
+	if summary.DurationSeconds != 120 || summary.DistanceMeters != 222.4 || summary.ElevationGainMeters != 6 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if *summary.AvgPaceSecondsPerKm != 540 || *summary.AvgSpeedKmh != 6.67 || *summary.AvgHeartRate != 130 || *summary.MaxHeartRate != 140 {
+		t.Fatalf("unexpected pace or heart rate %+v", summary)
+	}
+	if !summary.StartedAt.Equal(track.Points[0].Time) || track.Points[0].Time.Minute() != 0 {
+		t.Fatalf("points must be ordered by time, started at %v", summary.StartedAt)
+	}
+}
+
+func TestParseTrackTCX(t *testing.T) {
+	track, err := ParseTrack([]byte(sampleTCX))
+	if err != nil {
+		t.Fatalf("ParseTrack: %v", err)
+	}
+	if track.Format != models.TrackFormatTCX || track.Sport != "cycling" {
+		t.Fatalf("unexpected track %+v", track)
+	}
+
+	summary := track.Summarize()
+	// Recorded distance is used, relative to the first point.
+	if summary.DurationSeconds != 600 || summary.DistanceMeters != 5000 || summary.ElevationGainMeters != 5 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if *summary.AvgPaceSecondsPerKm != 120 || summary.AvgHeartRate != nil {
+		t.Fatalf("unexpected pace or heart rate %+v", summary)
+	}
+}
+
+func TestParseTrackRejectsOtherFiles(t *testing.T) {
+	if _, err := ParseTrack([]byte(`<kml><Document/></kml>`)); !errors.Is(err, ErrUnknownTrackFormat) {
+		t.Fatalf("expected ErrUnknownTrackFormat, got %v", err)
+	}
+	if _, err := ParseTrack([]byte(`<gpx><trk><trkseg/></trk></gpx>`)); !errors.Is(err, ErrEmptyTrack) {
+		t.Fatalf("expected ErrEmptyTrack, got %v", err)
+	}
+	if _, err := ParseTrack([]byte(`Date,Exercise Name`)); err == nil {
+		t.Fatalf("expected a CSV file to be rejected")
+	}
+}
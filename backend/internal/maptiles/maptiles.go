@@ -0,0 +1,120 @@
+// Package maptiles maps viewports onto Web Mercator (slippy map) tiles, the
+// grid the mobile map renders on. Tiles are the unit the gym viewport
+// endpoint caches and clusters by.
+package maptiles
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MaxZoom is the deepest zoom level the map requests.
+	MaxZoom = 22
+	// MaxLat is the latitude where Web Mercator tiles end.
+	MaxLat = 85.05112878
+)
+
+// ErrTooManyTiles is returned when a viewport covers more tiles than allowed
+// at its zoom level, usually because the zoom does not match the bounds.
+var ErrTooManyTiles = errors.New("viewport covers too many tiles for its zoom level")
+
+// Tile identifies a tile by zoom, column and row. Row 0 is the northernmost.
+type Tile struct {
+	Z int `json:"z"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Key renders the tile as z/x/y.
+func (t Tile) Key() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// Bounds returns the tile's south-west and north-east corners.
+func (t Tile) Bounds() (minLat, minLng, maxLat, maxLng float64) {
+	n := float64(int(1) << t.Z)
+	minLng = float64(t.X)/n*360 - 180
+	maxLng = float64(t.X+1)/n*360 - 180
+	maxLat = rowLat(float64(t.Y), n)
+	minLat = rowLat(float64(t.Y+1), n)
+	return minLat, minLng, maxLat, maxLng
+}
+
+// Containing returns the tile holding the point at every zoom level, from
+// the world tile down to MaxZoom.
+func Containing(lat, lng float64) []Tile {
+	tiles := make([]Tile, 0, MaxZoom+1)
+	for zoom := 0; zoom <= MaxZoom; zoom++ {
+		tiles = append(tiles, Tile{Z: zoom, X: Column(lng, zoom), Y: Row(lat, zoom)})
+	}
+	return tiles
+}
+
+// Covering returns the tiles at zoom that cover the viewport, west to east
+// and north to south. A viewport whose minLng is east of its maxLng crosses
+// the antimeridian. Latitudes are clamped to the Mercator range.
+func Covering(minLat, minLng, maxLat, maxLng float64, zoom, limit int) ([]Tile, error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return nil, fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+	n := 1 << zoom
+	top, bottom := Row(maxLat, zoom), Row(minLat, zoom)
+	west, east := Column(minLng, zoom), Column(maxLng, zoom)
+
+	var columns []int
+	if minLng > maxLng {
+		for x := west; x < n; x++ {
+			columns = append(columns, x)
+		}
+		for x := 0; x <= east; x++ {
+			columns = append(columns, x)
+		}
+	} else {
+		for x := west; x <= east; x++ {
+			columns = append(columns, x)
+		}
+	}
+
+	if count := len(columns) * (bottom - top + 1); count > limit {
+		return nil, ErrTooManyTiles
+	}
+	tiles := make([]Tile, 0, len(columns)*(bottom-top+1))
+	for y := top; y <= bottom; y++ {
+		for _, x := range columns {
+			tiles = append(tiles, Tile{Z: zoom, X: x, Y: y})
+		}
+	}
+	return tiles, nil
+}
+
+// Column returns the tile column holding the longitude at zoom.
+func Column(lng float64, zoom int) int {
+	n := 1 << zoom
+	x := int(math.Floor((lng + 180) / 360 * float64(n)))
+	return clamp(x, n)
+}
+
+// Row returns the tile row holding the latitude at zoom.
+func Row(lat float64, zoom int) int {
+	n := 1 << zoom
+	lat = math.Max(-MaxLat, math.Min(MaxLat, lat))
+	rad := lat * math.Pi / 180
+	y := int(math.Floor((1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * float64(n)))
+	return clamp(y, n)
+}
+
+func rowLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+func clamp(index, n int) int {
+	if index < 0 {
+		return 0
+	}
+	if index >= n {
+		return n - 1
+	}
+	return index
+}
@@ -0,0 +1,76 @@
+package maptiles
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestColumnAndRow(t *testing.T) {
+	// Seattle at zoom 10 is tile 10/164/357 on OpenStreetMap.
+	if x, y := Column(-122.33, 10), Row(47.61, 10); x != 164 || y != 357 {
+		t.Fatalf("expected 164/357, got %d/%d", x, y)
+	}
+	if Column(180, 3) != 7 || Column(-180, 3) != 0 {
+		t.Fatal("expected longitudes on the edges to stay in range")
+	}
+	if Row(89, 2) != 0 || Row(-89, 2) != 3 {
+		t.Fatal("expected polar latitudes to clamp to the first and last rows")
+	}
+}
+
+func TestBoundsRoundTrip(t *testing.T) {
+	tile := Tile{Z: 12, X: 656, Y: 1430}
+	minLat, minLng, maxLat, maxLng := tile.Bounds()
+	if minLat >= maxLat || minLng >= maxLng {
+		t.Fatalf("unexpected bounds %v %v %v %v", minLat, minLng, maxLat, maxLng)
+	}
+	centerLat, centerLng := (minLat+maxLat)/2, (minLng+maxLng)/2
+	if Column(centerLng, 12) != tile.X || Row(centerLat, 12) != tile.Y {
+		t.Fatalf("expected the tile centre to map back to %s", tile.Key())
+	}
+
+	_, _, top, _ := Tile{Z: 0}.Bounds()
+	if math.Abs(top-MaxLat) > 1e-6 {
+		t.Fatalf("expected the world tile to end at %v, got %v", MaxLat, top)
+	}
+}
+
+func TestContaining(t *testing.T) {
+	tiles := Containing(47.61, -122.33)
+	if len(tiles) != MaxZoom+1 {
+		t.Fatalf("expected a tile per zoom level, got %d", len(tiles))
+	}
+	if tiles[0] != (Tile{}) {
+		t.Fatalf("expected the world tile first, got %s", tiles[0].Key())
+	}
+	if tiles[10] != (Tile{Z: 10, X: 164, Y: 357}) {
+		t.Fatalf("expected 10/164/357, got %s", tiles[10].Key())
+	}
+}
+
+func TestCovering(t *testing.T) {
+	tiles, err := Covering(47.57, -122.36, 47.63, -122.30, 12, 100)
+	if err != nil {
+		t.Fatalf("Covering: %v", err)
+	}
+	if len(tiles) != 4 || tiles[0] != (Tile{Z: 12, X: 655, Y: 1430}) || tiles[3] != (Tile{Z: 12, X: 656, Y: 1431}) {
+		t.Fatalf("unexpected tiles %+v", tiles)
+	}
+
+	// Fiji's viewport wraps from the east edge of the map to the west.
+	tiles, err = Covering(-18.5, 177, -16, -179, 4, 100)
+	if err != nil {
+		t.Fatalf("Covering: %v", err)
+	}
+	if len(tiles) != 2 || tiles[0].X != 15 || tiles[1].X != 0 {
+		t.Fatalf("expected the antimeridian to be crossed, got %+v", tiles)
+	}
+
+	if _, err := Covering(-60, -170, 70, 170, 10, 100); !errors.Is(err, ErrTooManyTiles) {
+		t.Fatalf("expected ErrTooManyTiles, got %v", err)
+	}
+	if _, err := Covering(0, 0, 1, 1, 23, 100); err == nil {
+		t.Fatal("expected an out-of-range zoom to be rejected")
+	}
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+)
+
+// Body metric types
+const (
+	BodyMetricWeight  = "weight"
+	BodyMetricBodyFat = "body_fat"
+	BodyMetricWaist   = "waist"
+	BodyMetricChest   = "chest"
+	BodyMetricHips    = "hips"
+	BodyMetricNeck    = "neck"
+	BodyMetricArm     = "arm"
+	BodyMetricThigh   = "thigh"
+	BodyMetricCalf    = "calf"
+)
+
+// Length and ratio units
+const (
+	UnitCm      = "cm"
+	UnitIn      = "in"
+	UnitPercent = "percent"
+)
+
+// BodyMetricBaseUnit returns the unit a metric type is stored in: kilograms
+// for weight, percent for body fat and centimetres for circumferences. It
+// returns an empty string for an unknown type.
+func BodyMetricBaseUnit(metricType string) string {
+	switch metricType {
+	case BodyMetricWeight:
+		return UnitKg
+	case BodyMetricBodyFat:
+		return UnitPercent
+	case BodyMetricWaist, BodyMetricChest, BodyMetricHips, BodyMetricNeck, BodyMetricArm, BodyMetricThigh, BodyMetricCalf:
+		return UnitCm
+	}
+	return ""
+}
+
+// BodyMetric is one timestamped measurement of the user's body
+type BodyMetric struct {
+	ID         string    `json:"id" db:"id"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	Type       string    `json:"type" db:"metric_type"`
+	Value      float64   `json:"value" db:"value"`
+	Unit       string    `json:"unit"`
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+	Notes      *string   `json:"notes,omitempty" db:"notes"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BodyMetricTrendPoint is the mean of one day's measurements with the moving
+// average of the days in the window ending on it
+type BodyMetricTrendPoint struct {
+	Day           time.Time `json:"day"`
+	Value         float64   `json:"value"`
+	MovingAverage float64   `json:"moving_average"`
+	Count         int       `json:"count"`
+}
+
+// BodyMetricTrend is a metric's daily values between From and To, the day
+// after the last one. Change is the difference between the first and last
+// moving averages.
+type BodyMetricTrend struct {
+	Type       string                 `json:"type"`
+	Unit       string                 `json:"unit"`
+	WindowDays int                    `json:"window_days"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Points     []BodyMetricTrendPoint `json:"points"`
+	Latest     *float64               `json:"latest,omitempty"`
+	Change     *float64               `json:"change,omitempty"`
+	Goal       *BodyWeightGoal        `json:"goal,omitempty"`
+}
+
+// BodyWeightGoal is the weight a user is working towards. Target and Start
+// are in Unit; the Kg fields hold the same weights in kilograms.
+type BodyWeightGoal struct {
+	Target     float64    `json:"target"`
+	TargetKg   float64    `json:"target_kg" db:"target_kg"`
+	Start      *float64   `json:"start,omitempty"`
+	StartKg    *float64   `json:"start_kg,omitempty" db:"start_kg"`
+	Unit       string     `json:"unit"`
+	TargetDate *time.Time `json:"target_date,omitempty" db:"target_date"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Progress from the latest weigh-in
+	Current         *float64 `json:"current,omitempty"`
+	Remaining       *float64 `json:"remaining,omitempty"`
+	ProgressPercent *float64 `json:"progress_percent,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// CatalogExercise represents a curated exercise that logged exercises can reference
+type CatalogExercise struct {
+	ID               string    `json:"id" db:"id"`
+	Slug             string    `json:"slug" db:"slug"`
+	Name             string    `json:"name" db:"name"`
+	Aliases          []string  `json:"aliases" db:"aliases"`
+	PrimaryMuscles   []string  `json:"primary_muscles" db:"primary_muscles"`
+	SecondaryMuscles []string  `json:"secondary_muscles" db:"secondary_muscles"`
+	Equipment        string    `json:"equipment" db:"equipment"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+
+	// Linked machines and instruction videos, filled in on detail reads
+	MachineIDs []string `json:"machine_ids,omitempty"`
+	VideoIDs   []string `json:"video_ids,omitempty"`
+}
+
+// CatalogSearchResult is a catalog exercise ranked against a search term
+type CatalogSearchResult struct {
+	ID             string   `json:"id"`
+	Slug           string   `json:"slug"`
+	Name           string   `json:"name"`
+	PrimaryMuscles []string `json:"primary_muscles"`
+	Equipment      string   `json:"equipment"`
+	Score          float64  `json:"score"`
+}
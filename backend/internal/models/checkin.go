@@ -29,10 +29,15 @@ type LeaderboardEntry struct {
 type Exercise struct {
 	ID        string    `json:"id" db:"id"`
 	UserID    string    `json:"user_id" db:"user_id"`
+	WorkoutID *string   `json:"workout_id,omitempty" db:"workout_id"`
 	GymID     *string   `json:"gym_id,omitempty" db:"gym_id"`
 	MachineID *string   `json:"machine_id,omitempty" db:"machine_id"`
+	CatalogID *string   `json:"catalog_id,omitempty" db:"catalog_id"`
 	Name      string    `json:"name" db:"name"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Superset or circuit this exercise is performed in, if any
+	Group *ExerciseGroup `json:"group,omitempty"`
 	
 	// Related data
 	Sets []Set `json:"sets,omitempty"`
@@ -40,6 +45,13 @@ type Exercise struct {
 	// Display info
 	GymName     *string `json:"gym_name,omitempty"`
 	MachineName *string `json:"machine_name,omitempty"`
+	CatalogName *string `json:"catalog_name,omitempty"`
+
+	// Records set by this exercise when it was logged or edited
+	PersonalRecords []PersonalRecord `json:"personal_records,omitempty"`
+
+	// Estimated energy burned, set on exercises returned within a workout
+	EstimatedKcal *float64 `json:"estimated_kcal,omitempty"`
 }
 
 // Set represents a set within an exercise
@@ -51,4 +63,37 @@ type Set struct {
 	WeightKg  *float64  `json:"weight_kg,omitempty" db:"weight_kg"`
 	RPE       *float64  `json:"rpe,omitempty" db:"rpe"`
 	Notes     *string   `json:"notes,omitempty" db:"notes"`
+
+	Type            string   `json:"type" db:"set_type"`
+	RestSeconds     *int     `json:"rest_seconds,omitempty" db:"rest_seconds"`
+	DurationSeconds *int     `json:"duration_seconds,omitempty" db:"duration_seconds"`
+	DistanceMeters  *float64 `json:"distance_meters,omitempty" db:"distance_meters"`
+}
+
+// Set types
+const (
+	SetTypeWarmup  = "warmup"
+	SetTypeWorking = "working"
+	SetTypeDropset = "dropset"
+	SetTypeFailure = "failure"
+	SetTypeAMRAP   = "amrap"
+)
+
+// IsWarmup reports whether the set is a warm-up. Warm-ups are kept in the
+// log but left out of records, volume and other training statistics.
+func (s Set) IsWarmup() bool {
+	return s.Type == SetTypeWarmup
+}
+
+// Exercise group types
+const (
+	GroupTypeSuperset = "superset"
+	GroupTypeCircuit  = "circuit"
+)
+
+// ExerciseGroup links exercises performed back to back. ID is chosen by the
+// client and shared by every exercise in the group.
+type ExerciseGroup struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
 }
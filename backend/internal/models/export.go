@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Export formats and weight units
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+
+	UnitKg = "kg"
+	UnitLb = "lb"
+)
+
+// SetExportRow represents one logged set in a training log export
+type SetExportRow struct {
+	PerformedAt     time.Time `json:"performed_at"`
+	WorkoutID       *string   `json:"workout_id,omitempty"`
+	ExerciseID      string    `json:"exercise_id"`
+	ExerciseName    string    `json:"exercise_name"`
+	MachineID       *string   `json:"machine_id,omitempty"`
+	MachineName     *string   `json:"machine_name,omitempty"`
+	GymID           *string   `json:"gym_id,omitempty"`
+	GymName         *string   `json:"gym_name,omitempty"`
+	GroupID         *string   `json:"group_id,omitempty"`
+	GroupType       *string   `json:"group_type,omitempty"`
+	SetIndex        int       `json:"set_index"`
+	SetType         string    `json:"set_type"`
+	Reps            int       `json:"reps"`
+	Weight          *float64  `json:"weight,omitempty"`
+	Unit            string    `json:"unit"`
+	RPE             *float64  `json:"rpe,omitempty"`
+	RestSeconds     *int      `json:"rest_seconds,omitempty"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
+	Notes           *string   `json:"notes,omitempty"`
+}
+
+// WorkoutExportRow represents one workout with its totals in a training log export
+type WorkoutExportRow struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description,omitempty"`
+	Type            string    `json:"type"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Exercises       int       `json:"exercises"`
+	Sets            int       `json:"sets"`
+	WarmupSets      int       `json:"warmup_sets"`
+	Reps            int       `json:"reps"`
+	Volume          float64   `json:"volume"`
+	DistanceMeters  float64   `json:"distance_meters"`
+	Unit            string    `json:"unit"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Goal types
+const (
+	GoalWeeklyTrainingDays = "weekly_training_days"
+	GoalWorkoutCount       = "workout_count"
+	GoalCheckinStreak      = "checkin_streak"
+	GoalLift               = "lift"
+)
+
+// Goal statuses
+const (
+	GoalStatusActive    = "active"
+	GoalStatusCompleted = "completed"
+	GoalStatusExpired   = "expired"
+)
+
+// Goal is a target the user works towards, evaluated against activity logged
+// from StartedAt on. Current and Target are in Unit; a goal with a deadline
+// expires when the deadline's day ends before it is met.
+type Goal struct {
+	ID              string     `json:"id" db:"id"`
+	UserID          string     `json:"user_id" db:"user_id"`
+	Type            string     `json:"type" db:"goal_type"`
+	Title           string     `json:"title" db:"title"`
+	Target          float64    `json:"target" db:"target"`
+	Unit            string     `json:"unit"`
+	MachineID       *string    `json:"machine_id,omitempty" db:"machine_id"`
+	ExerciseName    *string    `json:"exercise_name,omitempty" db:"exercise_name"`
+	Reps            *int       `json:"reps,omitempty" db:"reps"`
+	Weeks           *int       `json:"weeks,omitempty" db:"weeks"`
+	Deadline        *time.Time `json:"deadline,omitempty" db:"deadline"`
+	Status          string     `json:"status" db:"status"`
+	Current         float64    `json:"current" db:"current_value"`
+	ProgressPercent float64    `json:"progress_percent" db:"progress_percent"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	EvaluatedAt     *time.Time `json:"evaluated_at,omitempty" db:"evaluated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// GoalList groups a user's goals by status
+type GoalList struct {
+	Active    []Goal `json:"active"`
+	Completed []Goal `json:"completed"`
+	Expired   []Goal `json:"expired"`
+}
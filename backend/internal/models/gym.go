@@ -13,7 +13,10 @@ type Gym struct {
 	Address   string    `json:"address" db:"address"`
 	Phone     *string   `json:"phone,omitempty" db:"phone"`
 	Website   *string   `json:"website,omitempty" db:"website"`
+	OwnerID   *string   `json:"owner_id,omitempty" db:"owner_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Hours     *OpeningHours `json:"hours,omitempty"`
+	Amenities []string      `json:"amenities"`
 	
 	// Computed fields
 	DistanceKm *float64 `json:"distance_km,omitempty"`
@@ -21,6 +24,53 @@ type Gym struct {
 	ReviewCount int      `json:"review_count"`
 	MachineCount int    `json:"machine_count"`
 	PriceFromCents *int  `json:"price_from_cents,omitempty"`
+	OpenNow        *bool `json:"open_now,omitempty"`
+}
+
+// OpeningHours is a gym's weekly schedule in its own timezone. Weekly is
+// keyed by lowercase weekday name; an interval whose closing time is before
+// its opening time runs past midnight into the next day. Holidays replace
+// the weekly schedule (or the 24/7 flag) on their date, and a holiday with
+// no intervals is closed all day.
+type OpeningHours struct {
+	Timezone string                     `json:"timezone"`
+	Open24x7 bool                       `json:"open_24_7"`
+	Weekly   map[string][]HoursInterval `json:"weekly"`
+	Holidays []GymHoliday               `json:"holidays"`
+}
+
+// HoursInterval is an opening interval in local "HH:MM" wall-clock time.
+// Closes may be "24:00".
+type HoursInterval struct {
+	Opens  string `json:"opens"`
+	Closes string `json:"closes"`
+}
+
+// GymHoliday overrides the schedule on a local date (YYYY-MM-DD)
+type GymHoliday struct {
+	Date      string          `json:"date"`
+	Name      string          `json:"name,omitempty"`
+	Intervals []HoursInterval `json:"intervals"`
+}
+
+// NearbyFilter narrows the nearby gyms feed. MachineIDs must all be in a
+// gym's inventory unless AnyMachine is set; every body part and amenity
+// listed must be covered. OpenAt keeps gyms open at that instant; gyms
+// without published hours, prices or ratings never match a filter on them.
+type NearbyFilter struct {
+	MachineIDs    []string
+	AnyMachine    bool
+	BodyParts     []string
+	MaxPriceCents *int
+	MinRating     *float64
+	Amenities     []string
+	OpenAt        *time.Time
+}
+
+// Amenities a gym can list
+var Amenities = []string{
+	"accessible", "childcare", "classes", "lockers", "parking", "personal_training",
+	"pool", "sauna", "showers", "steam_room", "towels", "wifi",
 }
 
 // NearbyGym represents the minimal payload for the nearby gyms feed.
@@ -36,6 +86,39 @@ type NearbyGym struct {
 	PriceFromCents  *int     `json:"price_from_cents,omitempty"`
 }
 
+// MapGym is a single gym marker in a map viewport.
+type MapGym struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Lat            float64  `json:"lat"`
+	Lng            float64  `json:"lng"`
+	AvgRating      *float64 `json:"avg_rating,omitempty"`
+	PriceFromCents *int     `json:"price_from_cents,omitempty"`
+}
+
+// GymCluster stands in for the gyms in one grid cell of a dense map tile,
+// placed at their centroid.
+type GymCluster struct {
+	ID    string  `json:"id"`
+	Count int     `json:"count"`
+	Lat   float64 `json:"lat"`
+	Lng   float64 `json:"lng"`
+}
+
+// GymTile holds the markers for one map tile.
+type GymTile struct {
+	Gyms     []MapGym     `json:"gyms"`
+	Clusters []GymCluster `json:"clusters"`
+}
+
+// GymViewport is the map payload for every tile covering a viewport.
+type GymViewport struct {
+	Zoom     int          `json:"zoom"`
+	Tiles    int          `json:"tiles"`
+	Gyms     []MapGym     `json:"gyms"`
+	Clusters []GymCluster `json:"clusters"`
+}
+
 type GymSearchResult struct {
 	ID      string  `json:"id"`
 	Name    string  `json:"name"`
@@ -53,23 +136,66 @@ type GymPrice struct {
 
 // GymReview represents a user review for a gym
 type GymReview struct {
-	ID        string    `json:"id" db:"id"`
-	GymID     string    `json:"gym_id" db:"gym_id"`
-	UserID    string    `json:"user_id" db:"user_id"`
-	Rating    int       `json:"rating" db:"rating"`
-	Comment   *string   `json:"comment,omitempty" db:"comment"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	
+	ID           string     `json:"id" db:"id"`
+	GymID        string     `json:"gym_id" db:"gym_id"`
+	UserID       string     `json:"user_id" db:"user_id"`
+	Rating       int        `json:"rating" db:"rating"`
+	Comment      *string    `json:"comment,omitempty" db:"comment"`
+	HelpfulCount int        `json:"helpful_count" db:"helpful_count"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
 	// User info for display
 	UserName string `json:"user_name,omitempty"`
+
+	Photos []GymReviewPhoto `json:"photos"`
+}
+
+// GymReviewEdit is an earlier version of a review, kept when it is edited.
+type GymReviewEdit struct {
+	ID       string    `json:"id"`
+	ReviewID string    `json:"review_id"`
+	Rating   int       `json:"rating"`
+	Comment  *string   `json:"comment,omitempty"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// GymReviewPhoto is an image attached to a review. Only the resized
+// variants are served, through signed URLs filled in by the handlers.
+type GymReviewPhoto struct {
+	ID          string    `json:"id"`
+	ReviewID    string    `json:"review_id"`
+	ObjectKey   string    `json:"-"`
+	ThumbKey    string    `json:"-"`
+	LargeKey    string    `json:"-"`
+	ContentType string    `json:"content_type"`
+	Bytes       int64     `json:"bytes"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	ThumbURL    string    `json:"thumb_url,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
+// Review sort orders
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortHelpful = "helpful"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
 // Machine represents a gym machine/equipment
 type Machine struct {
 	ID       string    `json:"id" db:"id"`
 	Name     string    `json:"name" db:"name"`
 	BodyPart string    `json:"body_part" db:"body_part"`
+	MET      *float64  `json:"met,omitempty" db:"met"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Quantity is set when listing a gym's machines
+	Quantity *int `json:"quantity,omitempty"`
 }
 
 type MachineSearchResult struct {
@@ -85,3 +211,28 @@ type GymMachine struct {
 	MachineID string `json:"machine_id" db:"machine_id"`
 	Quantity  int    `json:"quantity" db:"quantity"`
 }
+
+// Gym claim statuses
+const (
+	GymClaimPending  = "pending"
+	GymClaimApproved = "approved"
+	GymClaimRejected = "rejected"
+)
+
+// GymClaim is a user's request to be recorded as a gym's owner. Once an
+// admin approves it the user can manage the gym's details, prices and machines.
+type GymClaim struct {
+	ID         string     `json:"id" db:"id"`
+	GymID      string     `json:"gym_id" db:"gym_id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	Status     string     `json:"status" db:"status"`
+	Message    *string    `json:"message,omitempty" db:"message"`
+	ReviewerID *string    `json:"reviewer_id,omitempty" db:"reviewer_id"`
+	ReviewNote *string    `json:"review_note,omitempty" db:"review_note"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+
+	// Display fields
+	GymName  string `json:"gym_name,omitempty"`
+	UserName string `json:"user_name,omitempty"`
+}
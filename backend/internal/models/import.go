@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// Import sources
+const (
+	ImportSourceStrong = "strong"
+	ImportSourceHevy   = "hevy"
+)
+
+// WorkoutImport represents the outcome of importing a tracker export
+type WorkoutImport struct {
+	ID              string          `json:"id" db:"id"`
+	UserID          string          `json:"user_id" db:"user_id"`
+	Source          string          `json:"source" db:"source"`
+	FileHash        string          `json:"file_hash" db:"file_hash"`
+	Workouts        int             `json:"workouts" db:"workouts"`
+	Exercises       int             `json:"exercises" db:"exercises"`
+	Sets            int             `json:"sets" db:"sets"`
+	SkippedSessions int             `json:"skipped_sessions" db:"skipped_sessions"`
+	Mappings        []ImportMapping `json:"mappings"`
+	Unmapped        []ImportIssue   `json:"unmapped"`
+	Skipped         []ImportIssue   `json:"skipped"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+
+	// Duplicate is set when the same file was already imported and nothing was written
+	Duplicate bool `json:"duplicate"`
+}
+
+// ImportMapping represents an exercise name matched to a machine
+type ImportMapping struct {
+	ExerciseName string  `json:"exercise_name"`
+	MachineID    string  `json:"machine_id"`
+	MachineName  string  `json:"machine_name"`
+	Score        float64 `json:"score"`
+}
+
+// ImportIssue represents CSV rows that were not mapped or not imported
+type ImportIssue struct {
+	Rows         []int  `json:"rows"`
+	ExerciseName string `json:"exercise_name,omitempty"`
+	Reason       string `json:"reason"`
+}
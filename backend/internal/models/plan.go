@@ -0,0 +1,31 @@
+package models
+
+// GeneratedWorkout is a workout planned from the machines available at a gym.
+// The same seed, machines and request always produce the same plan.
+type GeneratedWorkout struct {
+	GymID            string              `json:"gym_id"`
+	Seed             int64               `json:"seed"`
+	BodyParts        []string            `json:"body_parts"`
+	MissingBodyParts []string            `json:"missing_body_parts,omitempty"`
+	BudgetMinutes    int                 `json:"budget_minutes"`
+	EstimatedMinutes int                 `json:"estimated_minutes"`
+	Exercises        []GeneratedExercise `json:"exercises"`
+
+	// Set when the plan was saved as a planned workout
+	Workout *Workout `json:"workout,omitempty"`
+}
+
+// GeneratedExercise is one planned machine exercise of a generated workout
+type GeneratedExercise struct {
+	Position         int    `json:"position"`
+	MachineID        string `json:"machine_id"`
+	MachineName      string `json:"machine_name"`
+	BodyPart         string `json:"body_part"`
+	Sets             int    `json:"sets"`
+	Reps             int    `json:"reps"`
+	RestSeconds      int    `json:"rest_seconds"`
+	EstimatedSeconds int    `json:"estimated_seconds"`
+
+	// Most-liked instruction video for the machine, if any
+	Video *InstructionVideo `json:"video,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Progress grouping options
+const (
+	ProgressGroupTotal    = "total"
+	ProgressGroupMachine  = "machine"
+	ProgressGroupBodyPart = "body_part"
+)
+
+// Progress bucket sizes
+const (
+	ProgressIntervalWeek  = "week"
+	ProgressIntervalMonth = "month"
+)
+
+// ProgressQuery describes which training data to aggregate
+type ProgressQuery struct {
+	GroupBy   string
+	Interval  string
+	From      time.Time
+	To        time.Time
+	MachineID *string
+	BodyPart  *string
+}
+
+// ProgressPoint represents aggregated training for one period
+type ProgressPoint struct {
+	Period   time.Time `json:"period"`
+	VolumeKg float64   `json:"volume_kg"`
+	Sets     int       `json:"sets"`
+	BestE1RM *float64  `json:"best_e1rm_kg"`
+}
+
+// ProgressSeries represents one chart line, e.g. a machine or a body part
+type ProgressSeries struct {
+	Key      string          `json:"key"`
+	Label    string          `json:"label"`
+	VolumeKg float64         `json:"total_volume_kg"`
+	Sets     int             `json:"total_sets"`
+	BestE1RM *float64        `json:"best_e1rm_kg"`
+	Points   []ProgressPoint `json:"points"`
+}
+
+// ProgressReport represents the progress response
+type ProgressReport struct {
+	GroupBy  string           `json:"group_by"`
+	Interval string           `json:"interval"`
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Periods  []time.Time      `json:"periods"`
+	Series   []ProgressSeries `json:"series"`
+}
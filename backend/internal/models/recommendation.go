@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Recommendation actions
+const (
+	RecommendationStart    = "start"
+	RecommendationIncrease = "increase_weight"
+	RecommendationAddReps  = "add_reps"
+	RecommendationRepeat   = "repeat"
+	RecommendationDeload   = "deload"
+)
+
+// TargetSet is one set proposed for the next session. Weight is in the
+// recommendation's unit; WeightKg is the same load in kilograms.
+type TargetSet struct {
+	SetIndex  int      `json:"set_index"`
+	Weight    float64  `json:"weight"`
+	WeightKg  float64  `json:"weight_kg"`
+	Reps      int      `json:"reps"`
+	TargetRPE *float64 `json:"target_rpe,omitempty"`
+}
+
+// Recommendation proposes what to lift next time on a machine or exercise,
+// with the reasoning behind it.
+type Recommendation struct {
+	Algorithm        string      `json:"algorithm"`
+	Action           string      `json:"action"`
+	Unit             string      `json:"unit"`
+	Increment        float64     `json:"increment"`
+	MachineID        *string     `json:"machine_id,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	LastPerformedAt  *time.Time  `json:"last_performed_at,omitempty"`
+	SessionsAnalyzed int         `json:"sessions_analyzed"`
+	Sets             []TargetSet `json:"sets"`
+	Reasoning        []string    `json:"reasoning"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Personal record types tracked per machine or exercise name.
+const (
+	RecordTypeMaxWeight = "max_weight"
+	RecordTypeE1RM      = "e1rm"
+	RecordTypeMaxReps   = "max_reps"
+	RecordTypeMaxVolume = "max_volume"
+)
+
+// PersonalRecord represents a personal best achieved on a machine or named exercise.
+type PersonalRecord struct {
+	ID            string    `json:"id" db:"id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	RecordKey     string    `json:"record_key" db:"record_key"`
+	MachineID     *string   `json:"machine_id,omitempty" db:"machine_id"`
+	ExerciseName  string    `json:"exercise_name" db:"exercise_name"`
+	RecordType    string    `json:"record_type" db:"record_type"`
+	Value         float64   `json:"value" db:"value"`
+	WeightKg      *float64  `json:"weight_kg,omitempty" db:"weight_kg"`
+	Reps          *int      `json:"reps,omitempty" db:"reps"`
+	PreviousValue *float64  `json:"previous_value,omitempty" db:"previous_value"`
+	ExerciseID    string    `json:"exercise_id" db:"exercise_id"`
+	SetID         *string   `json:"set_id,omitempty" db:"set_id"`
+	AchievedAt    time.Time `json:"achieved_at" db:"achieved_at"`
+}
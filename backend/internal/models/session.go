@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// Workout session statuses. Active and paused sessions are live; a user has
+// at most one live session at a time.
+const (
+	SessionStatusActive    = "active"
+	SessionStatusPaused    = "paused"
+	SessionStatusFinished  = "finished"
+	SessionStatusDiscarded = "discarded"
+)
+
+// WorkoutSession is a workout being logged while it happens. Exercises and
+// sets are kept on the session until it is finished, when they are written
+// out as a regular workout.
+type WorkoutSession struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	GymID          *string    `json:"gym_id,omitempty"`
+	Status         string     `json:"status"`
+	StartedAt      time.Time  `json:"started_at"`
+	PausedAt       *time.Time `json:"paused_at,omitempty"`
+	PausedSeconds  int        `json:"paused_seconds"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	AutoFinished   bool       `json:"auto_finished"`
+	WorkoutID      *string    `json:"workout_id,omitempty"`
+	Exercises      []Exercise `json:"exercises"`
+
+	// Computed on read from the server clock
+	ElapsedSeconds int `json:"elapsed_seconds"`
+}
+
+// IsLive reports whether the session can still be changed.
+func (s WorkoutSession) IsLive() bool {
+	return s.Status == SessionStatusActive || s.Status == SessionStatusPaused
+}
+
+// Elapsed returns the training time of the session at now: wall time since
+// the start, less completed pauses and any pause still running. Finished
+// sessions stop counting at their finish time.
+func (s WorkoutSession) Elapsed(now time.Time) time.Duration {
+	end := now
+	if s.FinishedAt != nil {
+		end = *s.FinishedAt
+	}
+
+	elapsed := end.Sub(s.StartedAt) - time.Duration(s.PausedSeconds)*time.Second
+	if s.PausedAt != nil && end.After(*s.PausedAt) {
+		elapsed -= end.Sub(*s.PausedAt)
+	}
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Sync entity types
+const (
+	SyncEntityWorkout  = "workout"
+	SyncEntityExercise = "exercise"
+	SyncEntitySet      = "set"
+	SyncEntityCheckin  = "checkin"
+)
+
+// Sync operations
+const (
+	SyncOpUpsert = "upsert"
+	SyncOpDelete = "delete"
+)
+
+// Sync mutation outcomes
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"
+	SyncStatusRejected = "rejected"
+)
+
+// SyncMutation is one offline write pushed by a client. ID is generated by the
+// client and makes replays harmless; EntityID is the client-generated ID of
+// the row being written.
+type SyncMutation struct {
+	ID        string          `json:"id"`
+	Entity    string          `json:"entity"`
+	Op        string          `json:"op"`
+	EntityID  string          `json:"entity_id"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data,omitempty"`
+
+	// Decoded payload for upserts, set by the handler after validation
+	Workout  *Workout  `json:"-"`
+	Exercise *Exercise `json:"-"`
+	Set      *Set      `json:"-"`
+	Checkin  *Checkin  `json:"-"`
+}
+
+// SyncResult reports what happened to a pushed mutation. Replayed is true
+// when the mutation had already been processed and the stored result is returned.
+type SyncResult struct {
+	MutationID string `json:"mutation_id"`
+	EntityID   string `json:"entity_id"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	Replayed   bool   `json:"replayed,omitempty"`
+}
+
+// SyncChange is the latest state of one entity since a change token. Deleted
+// entities are returned as tombstones without data.
+type SyncChange struct {
+	Entity   string `json:"entity"`
+	EntityID string `json:"entity_id"`
+	Op       string `json:"op"`
+	Data     any    `json:"data,omitempty"`
+}
+
+// SyncChanges is a page of changes with the token to pull the next one
+type SyncChanges struct {
+	Changes   []SyncChange `json:"changes"`
+	NextToken string       `json:"next_token"`
+	HasMore   bool         `json:"has_more"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkoutTemplate represents a reusable workout made of ordered exercises
+type WorkoutTemplate struct {
+	ID          string             `json:"id" db:"id"`
+	OwnerID     string             `json:"owner_id" db:"owner_id"`
+	Name        string             `json:"name" db:"name"`
+	Description *string            `json:"description,omitempty" db:"description"`
+	CopiedFrom  *string            `json:"copied_from,omitempty" db:"copied_from"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+	Exercises   []TemplateExercise `json:"exercises"`
+}
+
+// TemplateExercise represents one planned exercise within a template
+type TemplateExercise struct {
+	ID             string   `json:"id" db:"id"`
+	TemplateID     string   `json:"template_id" db:"template_id"`
+	Position       int      `json:"position" db:"position"`
+	MachineID      *string  `json:"machine_id,omitempty" db:"machine_id"`
+	Name           string   `json:"name" db:"name"`
+	TargetSets     int      `json:"target_sets" db:"target_sets"`
+	TargetReps     int      `json:"target_reps" db:"target_reps"`
+	TargetWeightKg *float64 `json:"target_weight_kg,omitempty" db:"target_weight_kg"`
+	Notes          *string  `json:"notes,omitempty" db:"notes"`
+
+	// Display info
+	MachineName *string `json:"machine_name,omitempty"`
+}
+
+// TrainingProgram represents a multi-week plan that schedules templates onto days
+type TrainingProgram struct {
+	ID          string       `json:"id" db:"id"`
+	OwnerID     string       `json:"owner_id" db:"owner_id"`
+	Name        string       `json:"name" db:"name"`
+	Description *string      `json:"description,omitempty" db:"description"`
+	Weeks       int          `json:"weeks" db:"weeks"`
+	StartDate   *time.Time   `json:"start_date,omitempty" db:"start_date"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	Days        []ProgramDay `json:"days"`
+}
+
+// ProgramDay assigns a template to a day of a program week.
+// Week starts at 1 and DayOfWeek runs from 1 (Monday) to 7 (Sunday).
+type ProgramDay struct {
+	ID           string `json:"id" db:"id"`
+	ProgramID    string `json:"program_id" db:"program_id"`
+	Week         int    `json:"week" db:"week"`
+	DayOfWeek    int    `json:"day_of_week" db:"day_of_week"`
+	TemplateID   string `json:"template_id" db:"template_id"`
+	TemplateName string `json:"template_name,omitempty"`
+}
+
+// ScheduledWorkout represents a program day resolved to a calendar date
+type ScheduledWorkout struct {
+	Date         time.Time `json:"date"`
+	Week         int       `json:"week"`
+	DayOfWeek    int       `json:"day_of_week"`
+	TemplateID   string    `json:"template_id"`
+	TemplateName string    `json:"template_name,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Track file formats
+const (
+	TrackFormatGPX = "gpx"
+	TrackFormatTCX = "tcx"
+)
+
+// CardioTrack is an imported GPS track and the cardio workout created from
+// it. The raw file is kept in object storage under FileKey.
+type CardioTrack struct {
+	ID        string       `json:"id" db:"id"`
+	UserID    string       `json:"user_id" db:"user_id"`
+	WorkoutID string       `json:"workout_id" db:"workout_id"`
+	Format    string       `json:"format" db:"format"`
+	Sport     string       `json:"sport" db:"sport"`
+	Name      string       `json:"name" db:"name"`
+	FileKey   string       `json:"-" db:"file_key"`
+	FileHash  string       `json:"file_hash" db:"file_hash"`
+	FileURL   string       `json:"file_url,omitempty"`
+	Summary   TrackSummary `json:"summary"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+
+	// Workout is the cardio workout created by the import, returned once
+	Workout *Workout `json:"workout,omitempty"`
+
+	// Duplicate is set when the same file was already imported and nothing was written
+	Duplicate bool `json:"duplicate"`
+}
+
+// TrackSummary is computed from a track's points. Pace is in seconds per
+// kilometre and is omitted for tracks without distance.
+type TrackSummary struct {
+	StartedAt           time.Time `json:"started_at" db:"started_at"`
+	DurationSeconds     int       `json:"duration_seconds" db:"duration_seconds"`
+	DistanceMeters      float64   `json:"distance_meters" db:"distance_meters"`
+	ElevationGainMeters float64   `json:"elevation_gain_meters" db:"elevation_gain_meters"`
+	AvgPaceSecondsPerKm *float64  `json:"avg_pace_seconds_per_km,omitempty" db:"avg_pace_seconds_per_km"`
+	AvgSpeedKmh         *float64  `json:"avg_speed_kmh,omitempty" db:"avg_speed_kmh"`
+	AvgHeartRate        *int      `json:"avg_heart_rate,omitempty" db:"avg_heart_rate"`
+	MaxHeartRate        *int      `json:"max_heart_rate,omitempty" db:"max_heart_rate"`
+	Points              int       `json:"points" db:"points"`
+}
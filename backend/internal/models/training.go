@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// Training load metrics used for the acute:chronic workload ratio
+const (
+	TrainingMetricSessionLoad = "session_load"
+	TrainingMetricSetEffort   = "set_effort"
+)
+
+// Training warning types
+const (
+	TrainingWarningLoadSpike   = "load_spike"
+	TrainingWarningMuscleSpike = "muscle_volume_spike"
+)
+
+// TrainingLoadQuery describes the weeks an insights report covers. From is
+// the Monday of the first week and To the day after the last one.
+type TrainingLoadQuery struct {
+	Metric string
+	From   time.Time
+	To     time.Time
+}
+
+// SessionLoad is the training load of one workout: session RPE (the mean RPE
+// of its working sets) times its duration in minutes, plus the summed RPE of
+// its working sets.
+type SessionLoad struct {
+	WorkoutID       string    `json:"workout_id"`
+	Name            string    `json:"name"`
+	PerformedAt     time.Time `json:"performed_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	SessionRPE      *float64  `json:"session_rpe"`
+	Load            float64   `json:"load"`
+	SetEffort       float64   `json:"set_effort"`
+	Sets            int       `json:"sets"`
+}
+
+// TrainingLoadWeek is one week of load with its acute:chronic workload ratio.
+// Acute is the week's load for the chosen metric; chronic is the mean of the
+// four weeks before it.
+type TrainingLoadWeek struct {
+	WeekStart   time.Time `json:"week_start"`
+	SessionLoad float64   `json:"session_load"`
+	SetEffort   float64   `json:"set_effort"`
+	Sets        int       `json:"sets"`
+	HardSets    int       `json:"hard_sets"`
+	Acute       float64   `json:"acute_load"`
+	Chronic     float64   `json:"chronic_load"`
+	ACWR        *float64  `json:"acwr"`
+}
+
+// MuscleWeekSets is the number of working sets a body part got in one week
+type MuscleWeekSets struct {
+	WeekStart time.Time `json:"week_start"`
+	Sets      int       `json:"sets"`
+}
+
+// MuscleLoad is the weekly set count series for one body part
+type MuscleLoad struct {
+	BodyPart  string           `json:"body_part"`
+	TotalSets int              `json:"total_sets"`
+	Weeks     []MuscleWeekSets `json:"weeks"`
+}
+
+// TrainingWarning flags a week whose load jumped well above what the user
+// is used to.
+type TrainingWarning struct {
+	Type      string    `json:"type"`
+	WeekStart time.Time `json:"week_start"`
+	BodyPart  *string   `json:"body_part,omitempty"`
+	Value     float64   `json:"value"`
+	Message   string    `json:"message"`
+}
+
+// TrainingInsights represents the training load insights response
+type TrainingInsights struct {
+	Metric   string             `json:"metric"`
+	From     time.Time          `json:"from"`
+	To       time.Time          `json:"to"`
+	Weeks    []TrainingLoadWeek `json:"weeks"`
+	Muscles  []MuscleLoad       `json:"muscles"`
+	Sessions []SessionLoad      `json:"sessions"`
+	Warnings []TrainingWarning  `json:"warnings"`
+}
@@ -16,11 +16,24 @@ type User struct {
 	OAuthProvider *string    `json:"oauth_provider,omitempty" db:"oauth_provider"`
 	OAuthID       *string    `json:"oauth_id,omitempty" db:"oauth_id"`
 	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	WeightUnit    string     `json:"weight_unit" db:"weight_unit"`
+	LengthUnit    string     `json:"length_unit" db:"length_unit"`
+	Role          string     `json:"role" db:"role"`
 }
 
+// User roles. Admins manage gyms and review ownership claims.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 func (u *User) IsPremium() bool {
 	if u == nil || u.PremiumUntil == nil {
 		return false
 	}
 	return u.PremiumUntil.After(time.Now())
 }
+
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == RoleAdmin
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 )
 
-// Workout represents a workout in the system
+// Workout represents a workout in the system. A planned workout has not been
+// performed yet: it stays out of history, records, totals, training load and
+// goals until its first exercise is logged.
 type Workout struct {
 	ID          string    `json:"id" db:"id"`
 	UserID      string    `json:"user_id" db:"user_id"`
@@ -12,6 +14,74 @@ type Workout struct {
 	Description string    `json:"description" db:"description"`
 	Duration    int       `json:"duration" db:"duration"` // in minutes
 	Type        string    `json:"type" db:"type"`
+	Planned     bool      `json:"planned" db:"planned"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// Related data
+	Exercises        []Exercise        `json:"exercises"`
+	PlannedExercises []PlannedExercise `json:"planned_exercises,omitempty"`
+	Totals           *WorkoutTotals    `json:"totals,omitempty"`
+}
+
+// PlannedExercise is an exercise a workout plans to do, with its target sets
+// and reps. It is not a logged exercise and carries no weights.
+type PlannedExercise struct {
+	ID          string  `json:"id" db:"id"`
+	WorkoutID   string  `json:"workout_id" db:"workout_id"`
+	Position    int     `json:"position" db:"position"`
+	GymID       *string `json:"gym_id,omitempty" db:"gym_id"`
+	MachineID   *string `json:"machine_id,omitempty" db:"machine_id"`
+	Name        string  `json:"name" db:"name"`
+	Sets        int     `json:"sets" db:"sets"`
+	Reps        int     `json:"reps" db:"reps"`
+	RestSeconds int     `json:"rest_seconds" db:"rest_seconds"`
+}
+
+// WorkoutTotals represents totals computed from a workout's exercises
+type WorkoutTotals struct {
+	DurationMinutes int     `json:"duration_minutes"`
+	VolumeKg        float64 `json:"volume_kg"`
+	Sets            int     `json:"sets"`
+	WarmupSets      int     `json:"warmup_sets"`
+	Reps            int     `json:"reps"`
+	DistanceMeters  float64 `json:"distance_meters,omitempty"`
+	Exercises       int     `json:"exercises"`
+
+	Calories *CalorieEstimate `json:"calories,omitempty"`
+}
+
+// Body weight sources for calorie estimates
+const (
+	BodyWeightSourceMetrics = "body_metrics"
+	BodyWeightSourceDefault = "default"
+)
+
+// CalorieEstimate is the energy a workout is estimated to burn from MET
+// values and the user's body weight
+type CalorieEstimate struct {
+	Kcal             float64 `json:"kcal"`
+	MET              float64 `json:"met"`
+	BodyWeightKg     float64 `json:"body_weight_kg"`
+	BodyWeightSource string  `json:"body_weight_source"`
+}
+
+// DailySummary totals a day's workouts
+type DailySummary struct {
+	Date            string  `json:"date"`
+	Workouts        int     `json:"workouts"`
+	DurationMinutes int     `json:"duration_minutes"`
+	Sets            int     `json:"sets"`
+	VolumeKg        float64 `json:"volume_kg"`
+	CaloriesKcal    float64 `json:"calories_kcal"`
+}
+
+// DailySummaryResponse lists daily summaries over a date range
+type DailySummaryResponse struct {
+	From             string         `json:"from"`
+	To               string         `json:"to"`
+	Days             []DailySummary `json:"days"`
+	CaloriesKcal     float64        `json:"calories_kcal"`
+	BodyWeightKg     float64        `json:"body_weight_kg"`
+	BodyWeightSource string         `json:"body_weight_source"`
 }
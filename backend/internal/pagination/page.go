@@ -27,6 +27,12 @@ type TimeDescCursor struct {
 	ID        string    `json:"id"`
 }
 
+// TimeAscCursor models a cursor for created_at ASC pagination.
+type TimeAscCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
 // DistanceAscCursor models a cursor for distance ASC pagination.
 type DistanceAscCursor struct {
 	DistanceM float64 `json:"distance_m"`
@@ -51,6 +57,18 @@ func TimeDescPage[T any](items []T, limit int, extractor func(item T) TimeDescCu
 	})
 }
 
+// TimeAscPage builds a paginated response for time ASC ordered lists.
+func TimeAscPage[T any](items []T, limit int, extractor func(item T) TimeAscCursor) (Paginated[T], error) {
+	return buildPage(items, limit, func(item T) (any, error) {
+		cursor := extractor(item)
+		if cursor.ID == "" || cursor.CreatedAt.IsZero() {
+			return nil, ErrInvalidCursorValue
+		}
+		cursor.CreatedAt = cursor.CreatedAt.UTC()
+		return cursor, nil
+	})
+}
+
 // DistanceAscPage builds a paginated response for distance ASC ordered lists.
 func DistanceAscPage[T any](items []T, limit int, extractor func(item T) DistanceAscCursor) (Paginated[T], error) {
 	return buildPage(items, limit, func(item T) (any, error) {
@@ -94,6 +94,32 @@ func TestTimeDescPageInvalidCursor(t *testing.T) {
 	}
 }
 
+func TestTimeAscPageHasMore(t *testing.T) {
+	items := []timeItem{
+		{ID: "1", CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "2", CreatedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)},
+		{ID: "3", CreatedAt: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)},
+	}
+
+	page, err := TimeAscPage(items, 2, func(item timeItem) TimeAscCursor {
+		return TimeAscCursor{ID: item.ID, CreatedAt: item.CreatedAt}
+	})
+	if err != nil {
+		t.Fatalf("TimeAscPage error: %v", err)
+	}
+	if len(page.Items) != 2 || !page.HasMore {
+		t.Fatalf("expected 2 items and more, got %d items, has_more=%v", len(page.Items), page.HasMore)
+	}
+
+	cursor, err := DecodeCursor[TimeAscCursor](page.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor error: %v", err)
+	}
+	if cursor.ID != "2" || !cursor.CreatedAt.Equal(items[1].CreatedAt) {
+		t.Fatalf("unexpected cursor %+v", cursor)
+	}
+}
+
 func TestDistanceAscPageHasMore(t *testing.T) {
 	items := []distanceItem{
 		{ID: "a", DistanceM: 10},
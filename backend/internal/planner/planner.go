@@ -0,0 +1,177 @@
+package planner
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"fitonex/backend/internal/models"
+)
+
+// Timing used to fit a plan into a time budget, in seconds.
+const (
+	SetSeconds   = 45
+	RestSeconds  = 90
+	SetupSeconds = 120
+)
+
+// Volume bounds for each planned exercise.
+const (
+	DefaultSets  = 3
+	MinSets      = 2
+	MaxSets      = 5
+	DefaultReps  = 10
+	MaxExercises = 12
+)
+
+// ErrNoMachines is returned when none of the requested body parts has a machine.
+var ErrNoMachines = errors.New("planner: no machines for the requested body parts")
+
+// Request describes the workout to generate. An empty BodyParts targets
+// every body part the machines cover.
+type Request struct {
+	BodyParts []string
+	Minutes   int
+	Seed      int64
+}
+
+// Plan is a generated workout. BodyParts are the targets that could be
+// trained and Missing the requested ones without a machine.
+type Plan struct {
+	BodyParts        []string
+	Missing          []string
+	Exercises        []models.GeneratedExercise
+	EstimatedSeconds int
+}
+
+// Generate picks machines for the requested body parts and fits sets into
+// the time budget. Body parts take turns so the plan stays balanced, and
+// the machine order within each body part is shuffled by the seed.
+func Generate(machines []models.Machine, req Request) (Plan, error) {
+	groups := map[string][]models.Machine{}
+	for _, machine := range machines {
+		part := normalize(machine.BodyPart)
+		if part == "" {
+			continue
+		}
+		groups[part] = append(groups[part], machine)
+	}
+
+	targets := normalizeAll(req.BodyParts)
+	if len(targets) == 0 {
+		for part := range groups {
+			targets = append(targets, part)
+		}
+		sort.Strings(targets)
+	}
+
+	var plan Plan
+	for _, part := range targets {
+		if len(groups[part]) == 0 {
+			plan.Missing = append(plan.Missing, part)
+			continue
+		}
+		plan.BodyParts = append(plan.BodyParts, part)
+	}
+	if len(plan.BodyParts) == 0 {
+		return plan, ErrNoMachines
+	}
+
+	// Map iteration and query order must not leak into the plan, so each
+	// group is sorted before the seeded shuffle.
+	rng := rand.New(rand.NewSource(req.Seed))
+	available := 0
+	for _, part := range plan.BodyParts {
+		group := groups[part]
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+		rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		available += len(group)
+	}
+
+	budget := req.Minutes * 60
+	count := max(budget/exerciseSeconds(DefaultSets), 1)
+	count = min(count, MaxExercises, available)
+
+	next := make(map[string]int, len(plan.BodyParts))
+	for len(plan.Exercises) < count {
+		for _, part := range plan.BodyParts {
+			if len(plan.Exercises) == count || next[part] >= len(groups[part]) {
+				continue
+			}
+			machine := groups[part][next[part]]
+			next[part]++
+			plan.Exercises = append(plan.Exercises, models.GeneratedExercise{
+				Position:    len(plan.Exercises) + 1,
+				MachineID:   machine.ID,
+				MachineName: machine.Name,
+				BodyPart:    part,
+				Sets:        DefaultSets,
+				Reps:        DefaultReps,
+				RestSeconds: RestSeconds,
+			})
+		}
+	}
+
+	fitSets(plan.Exercises, budget)
+	for i := range plan.Exercises {
+		plan.Exercises[i].EstimatedSeconds = exerciseSeconds(plan.Exercises[i].Sets)
+		plan.EstimatedSeconds += plan.Exercises[i].EstimatedSeconds
+	}
+	return plan, nil
+}
+
+// fitSets trims sets from the end of the plan while it runs over budget
+// and hands spare time out as extra sets from the start.
+func fitSets(exercises []models.GeneratedExercise, budget int) {
+	used := 0
+	for _, exercise := range exercises {
+		used += exerciseSeconds(exercise.Sets)
+	}
+
+	step := SetSeconds + RestSeconds
+	for changed := true; changed && used > budget; {
+		changed = false
+		for i := len(exercises) - 1; i >= 0 && used > budget; i-- {
+			if exercises[i].Sets > MinSets {
+				exercises[i].Sets--
+				used -= step
+				changed = true
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range exercises {
+			if exercises[i].Sets < MaxSets && used+step <= budget {
+				exercises[i].Sets++
+				used += step
+				changed = true
+			}
+		}
+	}
+}
+
+// exerciseSeconds is the time to set up a machine and perform the sets,
+// resting between them.
+func exerciseSeconds(sets int) int {
+	return SetupSeconds + sets*SetSeconds + (sets-1)*RestSeconds
+}
+
+func normalize(part string) string {
+	return strings.ToLower(strings.TrimSpace(part))
+}
+
+func normalizeAll(parts []string) []string {
+	var normalized []string
+	seen := map[string]bool{}
+	for _, part := range parts {
+		part = normalize(part)
+		if part == "" || seen[part] {
+			continue
+		}
+		seen[part] = true
+		normalized = append(normalized, part)
+	}
+	return normalized
+}
@@ -0,0 +1,111 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestGenerateIsDeterministicForSeed(t *testing.T) {
+	machines := gymMachines()
+	reversed := make([]models.Machine, len(machines))
+	for i, machine := range machines {
+		reversed[len(machines)-1-i] = machine
+	}
+
+	req := Request{BodyParts: []string{"Chest", "back"}, Minutes: 45, Seed: 42}
+	first, err := Generate(machines, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	second, err := Generate(reversed, req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("same seed produced different plans:\n%+v\n%+v", first, second)
+	}
+
+	differs := false
+	for seed := int64(1); seed < 20 && !differs; seed++ {
+		other, _ := Generate(machines, Request{BodyParts: req.BodyParts, Minutes: req.Minutes, Seed: seed})
+		differs = !reflect.DeepEqual(first.Exercises, other.Exercises)
+	}
+	if !differs {
+		t.Fatalf("expected other seeds to pick other machines")
+	}
+}
+
+func TestGenerateBalancesBodyPartsWithinBudget(t *testing.T) {
+	plan, err := Generate(gymMachines(), Request{BodyParts: []string{"chest", "back", "legs"}, Minutes: 45, Seed: 7})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(plan.Exercises) != 6 {
+		t.Fatalf("expected 6 exercises in 45 minutes, got %d", len(plan.Exercises))
+	}
+	counts := map[string]int{}
+	for i, exercise := range plan.Exercises {
+		counts[exercise.BodyPart]++
+		if exercise.Position != i+1 || exercise.Sets < MinSets || exercise.Sets > MaxSets {
+			t.Fatalf("unexpected exercise %+v", exercise)
+		}
+		if i > 0 && plan.Exercises[i-1].BodyPart == exercise.BodyPart {
+			t.Fatalf("body parts should alternate, got %+v", plan.Exercises)
+		}
+	}
+	if counts["chest"] != 2 || counts["back"] != 2 || counts["legs"] != 2 {
+		t.Fatalf("unbalanced plan %v", counts)
+	}
+	if plan.EstimatedSeconds > 45*60 {
+		t.Fatalf("plan runs over budget: %ds", plan.EstimatedSeconds)
+	}
+}
+
+func TestGenerateAddsSetsWhenMachinesRunOut(t *testing.T) {
+	machines := []models.Machine{{ID: "m1", Name: "Leg Press", BodyPart: "legs"}}
+
+	plan, err := Generate(machines, Request{Minutes: 30, Seed: 1})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(plan.Exercises) != 1 || plan.Exercises[0].Sets != MaxSets {
+		t.Fatalf("expected one exercise with %d sets, got %+v", MaxSets, plan.Exercises)
+	}
+
+	short, _ := Generate(machines, Request{Minutes: 5, Seed: 1})
+	if short.Exercises[0].Sets != MinSets {
+		t.Fatalf("expected a short budget to trim to %d sets, got %d", MinSets, short.Exercises[0].Sets)
+	}
+}
+
+func TestGenerateReportsMissingBodyParts(t *testing.T) {
+	plan, err := Generate(gymMachines(), Request{BodyParts: []string{"chest", "calves"}, Minutes: 20, Seed: 3})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !reflect.DeepEqual(plan.Missing, []string{"calves"}) || !reflect.DeepEqual(plan.BodyParts, []string{"chest"}) {
+		t.Fatalf("unexpected targets %v missing %v", plan.BodyParts, plan.Missing)
+	}
+
+	if _, err := Generate(gymMachines(), Request{BodyParts: []string{"calves"}, Minutes: 20}); !errors.Is(err, ErrNoMachines) {
+		t.Fatalf("expected ErrNoMachines, got %v", err)
+	}
+}
+
+func gymMachines() []models.Machine {
+	var machines []models.Machine
+	for _, part := range []string{"chest", "back", "legs"} {
+		for i := 1; i <= 4; i++ {
+			machines = append(machines, models.Machine{
+				ID:       fmt.Sprintf("%s-%d", part, i),
+				Name:     fmt.Sprintf("%s machine %d", part, i),
+				BodyPart: part,
+			})
+		}
+	}
+	return machines
+}
@@ -0,0 +1,182 @@
+package progression
+
+import (
+	"fmt"
+
+	"fitonex/backend/internal/models"
+)
+
+// hardRPE is the RPE at which a set is treated as a near-maximal effort that
+// should be repeated rather than progressed.
+const hardRPE = 9.5
+
+// DoubleProgression works within a rep range: reps are added at a fixed
+// weight until every set reaches the top of the range, then the weight goes
+// up by one increment and reps restart at the bottom.
+type DoubleProgression struct{}
+
+// Name implements Algorithm.
+func (DoubleProgression) Name() string {
+	return "double_progression"
+}
+
+// Recommend implements Algorithm.
+func (DoubleProgression) Recommend(history []Session, params Params) models.Recommendation {
+	if len(history) == 0 {
+		return startRecommendation(params)
+	}
+	if recommendation, ok := deloadRecommendation(history, params); ok {
+		return recommendation
+	}
+
+	last := history[len(history)-1]
+	top := last.TopSets()
+	weight := roundTo(toUnit(last.TopWeight(), params.Unit), params.Increment)
+	count := setCount(len(top), params)
+
+	reachedTop := true
+	hardest := 0.0
+	for _, set := range top {
+		if set.Reps < params.RepMax {
+			reachedTop = false
+		}
+		if set.RPE != nil && *set.RPE > hardest {
+			hardest = *set.RPE
+		}
+	}
+
+	var recommendation models.Recommendation
+	switch {
+	case reachedTop && hardest < hardRPE:
+		next := roundTo(weight+params.Increment, params.Increment)
+		recommendation.Action = models.RecommendationIncrease
+		for i := 0; i < count; i++ {
+			recommendation.Sets = append(recommendation.Sets, target(i, next, params.RepMin, params.Unit, nil))
+		}
+		recommendation.Reasoning = []string{
+			fmt.Sprintf("Every set at %s reached %d reps last time.", formatWeight(weight, params.Unit), params.RepMax),
+			fmt.Sprintf("Add %s and restart at %d reps.", formatWeight(params.Increment, params.Unit), params.RepMin),
+		}
+	case reachedTop:
+		recommendation.Action = models.RecommendationRepeat
+		for i := 0; i < count; i++ {
+			recommendation.Sets = append(recommendation.Sets, target(i, weight, params.RepMax, params.Unit, nil))
+		}
+		recommendation.Reasoning = []string{
+			fmt.Sprintf("Every set at %s reached %d reps, but at RPE %g.", formatWeight(weight, params.Unit), params.RepMax, hardest),
+			"Repeat the session until it feels easier before adding weight.",
+		}
+	default:
+		recommendation.Action = models.RecommendationAddReps
+		for i := 0; i < count; i++ {
+			previous := top[min(i, len(top)-1)]
+			reps := previous.Reps
+			if previous.RPE == nil || *previous.RPE < hardRPE {
+				reps++
+			}
+			recommendation.Sets = append(recommendation.Sets, target(i, weight, min(reps, params.RepMax), params.Unit, nil))
+		}
+		recommendation.Reasoning = []string{
+			fmt.Sprintf("Not every set at %s reached %d reps yet.", formatWeight(weight, params.Unit), params.RepMax),
+			"Keep the weight and add a rep to each set that was not near failure.",
+		}
+	}
+	return recommendation
+}
+
+// RPETarget estimates a one-rep max from the best set of the last session,
+// counting reps left in reserve from its RPE, and picks the weight that
+// should land the bottom of the rep range at the target RPE.
+type RPETarget struct{}
+
+// Name implements Algorithm.
+func (RPETarget) Name() string {
+	return "rpe_target"
+}
+
+// Recommend implements Algorithm.
+func (RPETarget) Recommend(history []Session, params Params) models.Recommendation {
+	if len(history) == 0 {
+		return startRecommendation(params)
+	}
+	if recommendation, ok := deloadRecommendation(history, params); ok {
+		return recommendation
+	}
+
+	last := history[len(history)-1]
+
+	var (
+		best      float64
+		bestSet   models.Set
+		bestRPE   float64
+		assumeRPE bool
+	)
+	for _, set := range last.Sets {
+		rpe := params.TargetRPE
+		if set.RPE != nil {
+			rpe = *set.RPE
+		}
+		if e1rm := *set.WeightKg * (1 + (float64(set.Reps)+10-rpe)/30); e1rm > best {
+			best, bestSet, bestRPE, assumeRPE = e1rm, set, rpe, set.RPE == nil
+		}
+	}
+
+	share := 1 / (1 + (float64(params.RepMin)+10-params.TargetRPE)/30)
+	weight := roundDown(toUnit(best*share, params.Unit), params.Increment)
+	current := roundTo(toUnit(last.TopWeight(), params.Unit), params.Increment)
+
+	recommendation := models.Recommendation{Action: models.RecommendationRepeat}
+	if weight > current {
+		recommendation.Action = models.RecommendationIncrease
+	}
+
+	targetRPE := params.TargetRPE
+	count := setCount(len(last.TopSets()), params)
+	for i := 0; i < count; i++ {
+		recommendation.Sets = append(recommendation.Sets, target(i, weight, params.RepMin, params.Unit, &targetRPE))
+	}
+
+	rpeNote := fmt.Sprintf("RPE %g", bestRPE)
+	if assumeRPE {
+		rpeNote = fmt.Sprintf("an assumed RPE %g since none was logged", bestRPE)
+	}
+	recommendation.Reasoning = []string{
+		fmt.Sprintf("Best set last time was %s x %d at %s, an estimated 1RM of %s.",
+			formatWeight(roundTo(toUnit(*bestSet.WeightKg, params.Unit), params.Increment), params.Unit), bestSet.Reps, rpeNote,
+			formatWeight(round2(toUnit(best, params.Unit)), params.Unit)),
+		fmt.Sprintf("%d reps at RPE %g is about %.0f%% of that, rounded down to %s.",
+			params.RepMin, params.TargetRPE, share*100, formatWeight(weight, params.Unit)),
+	}
+	return recommendation
+}
+
+func startRecommendation(params Params) models.Recommendation {
+	return models.Recommendation{
+		Action: models.RecommendationStart,
+		Reasoning: []string{
+			"No weighted working sets are logged for this lift yet.",
+			fmt.Sprintf("Pick a weight you can lift for %d-%d reps at about RPE %g and log it.", params.RepMin, params.RepMax, params.TargetRPE),
+		},
+	}
+}
+
+func deloadRecommendation(history []Session, params Params) (models.Recommendation, bool) {
+	if !stalled(history, params.StallSessions) {
+		return models.Recommendation{}, false
+	}
+
+	last := history[len(history)-1]
+	weight := roundTo(toUnit(last.TopWeight(), params.Unit), params.Increment)
+	return models.Recommendation{
+		Action: models.RecommendationDeload,
+		Sets:   deload(last, params),
+		Reasoning: []string{
+			fmt.Sprintf("No progress at %s over the last %d sessions.", formatWeight(weight, params.Unit), params.StallSessions),
+			fmt.Sprintf("Take %.0f%% off and build back up from %d reps.", params.DeloadPercent*100, params.RepMin),
+		},
+	}, true
+}
+
+func formatWeight(weight float64, unit string) string {
+	return fmt.Sprintf("%g %s", weight, unit)
+}
@@ -0,0 +1,262 @@
+package progression
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// kgPerLb converts pounds to kilograms.
+const kgPerLb = 0.45359237
+
+// HistorySessions is how many recent sessions an algorithm looks at, so
+// callers need not load more history than that.
+const HistorySessions = 10
+
+// ErrUnknownAlgorithm is returned for an algorithm name that is not registered.
+var ErrUnknownAlgorithm = errors.New("progression: unknown algorithm")
+
+// Params tunes a recommendation. Increment is the smallest weight step
+// available on the machine, in Unit.
+type Params struct {
+	Unit      string
+	Increment float64
+	RepMin    int
+	RepMax    int
+	// Sets is the number of working sets to plan; zero repeats the last session.
+	Sets      int
+	TargetRPE float64
+	// StallSessions is how many sessions without progress trigger a deload,
+	// which takes DeloadPercent off the working weight.
+	StallSessions int
+	DeloadPercent float64
+}
+
+// DefaultParams returns the parameters used when a request leaves them out.
+func DefaultParams(unit string) Params {
+	params := Params{
+		Unit:          models.UnitKg,
+		Increment:     2.5,
+		RepMin:        8,
+		RepMax:        12,
+		TargetRPE:     8,
+		StallSessions: 3,
+		DeloadPercent: 0.1,
+	}
+	if unit == models.UnitLb {
+		params.Unit = models.UnitLb
+		params.Increment = 5
+	}
+	return params
+}
+
+// Algorithm proposes the next session for one lift from its history.
+type Algorithm interface {
+	Name() string
+	Recommend(history []Session, params Params) models.Recommendation
+}
+
+// DefaultAlgorithm is used when a request does not name one.
+const DefaultAlgorithm = "double_progression"
+
+var algorithms = map[string]Algorithm{}
+
+// Register makes an algorithm available by name.
+func Register(algorithm Algorithm) {
+	algorithms[algorithm.Name()] = algorithm
+}
+
+// Names lists the registered algorithms in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(DoubleProgression{})
+	Register(RPETarget{})
+}
+
+// Session is the working sets of one logged exercise, weights in kilograms.
+type Session struct {
+	PerformedAt time.Time
+	Sets        []models.Set
+}
+
+// TopWeight returns the heaviest working weight of the session.
+func (s Session) TopWeight() float64 {
+	top := 0.0
+	for _, set := range s.Sets {
+		if *set.WeightKg > top {
+			top = *set.WeightKg
+		}
+	}
+	return top
+}
+
+// TopSets returns the sets performed at the top weight, in order.
+func (s Session) TopSets() []models.Set {
+	top := s.TopWeight()
+	var sets []models.Set
+	for _, set := range s.Sets {
+		if sameWeight(*set.WeightKg, top) {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// Recommend runs the named algorithm over a lift's exercise history. Only
+// weighted working sets are considered and only the most recent sessions.
+func Recommend(name string, exercises []models.Exercise, params Params) (models.Recommendation, error) {
+	if name == "" {
+		name = DefaultAlgorithm
+	}
+	algorithm, ok := algorithms[name]
+	if !ok {
+		return models.Recommendation{}, ErrUnknownAlgorithm
+	}
+
+	history := Sessions(exercises)
+	if len(history) > HistorySessions {
+		history = history[len(history)-HistorySessions:]
+	}
+
+	recommendation := algorithm.Recommend(history, params)
+	recommendation.Algorithm = algorithm.Name()
+	recommendation.Unit = params.Unit
+	recommendation.Increment = params.Increment
+	recommendation.SessionsAnalyzed = len(history)
+	if len(history) > 0 {
+		last := history[len(history)-1].PerformedAt
+		recommendation.LastPerformedAt = &last
+	}
+	if recommendation.Sets == nil {
+		recommendation.Sets = []models.TargetSet{}
+	}
+	return recommendation, nil
+}
+
+// Sessions extracts weighted working sets per exercise, oldest first.
+// Exercises without any are skipped.
+func Sessions(exercises []models.Exercise) []Session {
+	ordered := make([]models.Exercise, len(exercises))
+	copy(ordered, exercises)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	var sessions []Session
+	for _, exercise := range ordered {
+		session := Session{PerformedAt: exercise.CreatedAt}
+		for _, set := range exercise.Sets {
+			if set.IsWarmup() || set.Reps <= 0 || set.WeightKg == nil || *set.WeightKg <= 0 {
+				continue
+			}
+			session.Sets = append(session.Sets, set)
+		}
+		if len(session.Sets) > 0 {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// stalled reports whether the last n sessions stayed at one top weight
+// without beating the reps of the first of them.
+func stalled(history []Session, n int) bool {
+	if n <= 1 || len(history) < n {
+		return false
+	}
+	window := history[len(history)-n:]
+	weight := window[0].TopWeight()
+	first := topReps(window[0])
+	for _, session := range window[1:] {
+		if !sameWeight(session.TopWeight(), weight) || topReps(session) > first {
+			return false
+		}
+	}
+	return true
+}
+
+func topReps(session Session) int {
+	total := 0
+	for _, set := range session.TopSets() {
+		total += set.Reps
+	}
+	return total
+}
+
+// deload plans the last top sets at a reduced weight for RepMin reps.
+func deload(last Session, params Params) []models.TargetSet {
+	weight := roundDown(toUnit(last.TopWeight(), params.Unit)*(1-params.DeloadPercent), params.Increment)
+	count := setCount(len(last.TopSets()), params)
+	sets := make([]models.TargetSet, 0, count)
+	for i := 0; i < count; i++ {
+		sets = append(sets, target(i, weight, params.RepMin, params.Unit, nil))
+	}
+	return sets
+}
+
+func setCount(last int, params Params) int {
+	if params.Sets > 0 {
+		return params.Sets
+	}
+	return max(last, 1)
+}
+
+func target(index int, weight float64, reps int, unit string, rpe *float64) models.TargetSet {
+	return models.TargetSet{
+		SetIndex:  index + 1,
+		Weight:    weight,
+		WeightKg:  round2(toKg(weight, unit)),
+		Reps:      reps,
+		TargetRPE: rpe,
+	}
+}
+
+func toUnit(kg float64, unit string) float64 {
+	if unit == models.UnitLb {
+		return kg / kgPerLb
+	}
+	return kg
+}
+
+func toKg(value float64, unit string) float64 {
+	if unit == models.UnitLb {
+		return value * kgPerLb
+	}
+	return value
+}
+
+// roundTo snaps a weight to the nearest multiple of the increment.
+func roundTo(weight, increment float64) float64 {
+	if increment <= 0 {
+		return round2(weight)
+	}
+	return round2(math.Round(weight/increment) * increment)
+}
+
+// roundDown snaps a weight to the multiple of the increment at or below it,
+// never below one increment.
+func roundDown(weight, increment float64) float64 {
+	if increment <= 0 {
+		return round2(weight)
+	}
+	return round2(math.Max(math.Floor(weight/increment+1e-9), 1) * increment)
+}
+
+func sameWeight(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,128 @@
+package progression
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestDoubleProgressionAddsWeightAtTopOfRange(t *testing.T) {
+	history := []models.Exercise{
+		session(0, working(12, 50, nil), working(12, 50, nil), working(12, 50, nil)),
+	}
+
+	got, err := Recommend("", history, DefaultParams(models.UnitKg))
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if got.Algorithm != DefaultAlgorithm || got.Action != models.RecommendationIncrease || len(got.Sets) != 3 {
+		t.Fatalf("unexpected recommendation %+v", got)
+	}
+	if got.Sets[0].Weight != 52.5 || got.Sets[0].Reps != 8 {
+		t.Fatalf("unexpected target %+v", got.Sets[0])
+	}
+	if len(got.Reasoning) == 0 {
+		t.Fatalf("expected reasoning")
+	}
+}
+
+func TestDoubleProgressionAddsRepsBelowTop(t *testing.T) {
+	hard := 10.0
+	history := []models.Exercise{
+		session(0, warmup(10, 20), working(10, 50, nil), working(9, 50, nil), working(8, 50, &hard)),
+	}
+
+	got, err := Recommend(DefaultAlgorithm, history, DefaultParams(models.UnitKg))
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if got.Action != models.RecommendationAddReps {
+		t.Fatalf("unexpected action %q", got.Action)
+	}
+	// The set taken to failure keeps its reps; the others get one more.
+	if got.Sets[0].Reps != 11 || got.Sets[1].Reps != 10 || got.Sets[2].Reps != 8 {
+		t.Fatalf("unexpected targets %+v", got.Sets)
+	}
+}
+
+func TestDoubleProgressionDeloadsAfterStall(t *testing.T) {
+	history := []models.Exercise{
+		session(0, working(10, 60, nil), working(9, 60, nil)),
+		session(1, working(10, 60, nil), working(8, 60, nil)),
+		session(2, working(9, 60, nil), working(9, 60, nil)),
+	}
+
+	got, err := Recommend(DefaultAlgorithm, history, DefaultParams(models.UnitKg))
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if got.Action != models.RecommendationDeload || got.Sets[0].Weight != 52.5 || got.Sets[0].Reps != 8 {
+		t.Fatalf("unexpected deload %+v", got)
+	}
+
+	// Once the lighter weight is used the stall is over.
+	history = append(history, session(3, working(8, 52.5, nil), working(8, 52.5, nil)))
+	got, _ = Recommend(DefaultAlgorithm, history, DefaultParams(models.UnitKg))
+	if got.Action != models.RecommendationAddReps {
+		t.Fatalf("expected progression after a deload, got %q", got.Action)
+	}
+}
+
+func TestRecommendationsUsePounds(t *testing.T) {
+	params := DefaultParams(models.UnitLb)
+	history := []models.Exercise{
+		session(0, working(12, 45.36, nil), working(12, 45.36, nil)),
+	}
+
+	got, err := Recommend(DefaultAlgorithm, history, params)
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if got.Unit != models.UnitLb || got.Sets[0].Weight != 105 || got.Sets[0].WeightKg != 47.63 {
+		t.Fatalf("unexpected pound target %+v", got.Sets[0])
+	}
+}
+
+func TestRPETargetUsesEstimatedMax(t *testing.T) {
+	rpe := 8.0
+	history := []models.Exercise{
+		session(0, working(8, 100, &rpe)),
+	}
+
+	got, err := Recommend("rpe_target", history, DefaultParams(models.UnitKg))
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	// Same reps at the same RPE lands on the same weight.
+	if got.Action != models.RecommendationRepeat || got.Sets[0].Weight != 100 || got.Sets[0].TargetRPE == nil {
+		t.Fatalf("unexpected recommendation %+v", got)
+	}
+}
+
+func TestRecommendWithoutHistoryAndUnknownAlgorithm(t *testing.T) {
+	got, err := Recommend(DefaultAlgorithm, []models.Exercise{session(0, models.Set{Reps: 20})}, DefaultParams(models.UnitKg))
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if got.Action != models.RecommendationStart || got.Sets == nil || len(got.Sets) != 0 {
+		t.Fatalf("unexpected start recommendation %+v", got)
+	}
+
+	if _, err := Recommend("linear", nil, DefaultParams(models.UnitKg)); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Fatalf("expected ErrUnknownAlgorithm, got %v", err)
+	}
+}
+
+func session(day int, sets ...models.Set) models.Exercise {
+	return models.Exercise{CreatedAt: time.Date(2024, 5, 1+day, 18, 0, 0, 0, time.UTC), Sets: sets}
+}
+
+func working(reps int, weight float64, rpe *float64) models.Set {
+	return models.Set{Reps: reps, WeightKg: &weight, RPE: rpe, Type: models.SetTypeWorking}
+}
+
+func warmup(reps int, weight float64) models.Set {
+	return models.Set{Reps: reps, WeightKg: &weight, Type: models.SetTypeWarmup}
+}
@@ -183,15 +183,20 @@ func setupRoutes(r *chi.Mux, h *handlers.Handlers) {
 		r.Get("/streaks/top", h.GetTopStreaks)
 
 		r.Get("/gyms/nearby", h.GetNearbyGyms)
+		r.Get("/gyms/bbox", h.GetGymsInViewport)
 		r.Get("/gyms/{id}", h.GetGym)
 		r.Get("/gyms/{id}/machines", h.GetGymMachines)
 		r.Get("/gyms/{id}/prices", h.GetGymPrices)
+		r.Get("/gyms/{id}/hours", h.GetGymHours)
 		r.Get("/gyms/{id}/reviews", h.GetGymReviews)
 
 		r.Get("/machines", h.SearchMachines)
 		r.Get("/machines/body-parts", h.GetBodyParts)
 		r.Get("/machines/{id}", h.GetMachine)
 
+		r.Get("/catalog", h.SearchCatalog)
+		r.Get("/catalog/{id}", h.GetCatalogExercise)
+
 		r.Get("/videos", h.GetVideos)
 		r.Get("/videos/{id}", h.GetVideo)
 
@@ -210,14 +215,34 @@ func setupRoutes(r *chi.Mux, h *handlers.Handlers) {
 
 			r.Get("/profile", h.GetProfile)
 			r.Put("/profile", h.UpdateProfile)
+			r.Put("/profile/units", h.UpdateUnits)
 
 			r.Get("/workouts", h.GetWorkouts)
 			r.Post("/workouts", h.CreateWorkout)
 			r.Get("/workouts/{id}", h.GetWorkout)
 			r.Put("/workouts/{id}", h.UpdateWorkout)
 			r.Delete("/workouts/{id}", h.DeleteWorkout)
+			r.Post("/workouts/{id}/exercises", h.AddWorkoutExercise)
 
 			r.Post("/gyms/{id}/reviews", h.CreateGymReview)
+			r.Put("/gyms/{id}/reviews/{reviewId}", h.UpdateGymReview)
+			r.Delete("/gyms/{id}/reviews/{reviewId}", h.DeleteGymReview)
+			r.Get("/gyms/{id}/reviews/{reviewId}/history", h.GetGymReviewHistory)
+			r.Put("/gyms/{id}/reviews/{reviewId}/helpful", h.VoteGymReviewHelpful)
+			r.Delete("/gyms/{id}/reviews/{reviewId}/helpful", h.RemoveGymReviewHelpfulVote)
+			r.Post("/gyms/{id}/reviews/{reviewId}/photos/upload-url", h.GetReviewPhotoUploadURL)
+			r.Post("/gyms/{id}/reviews/{reviewId}/photos", h.AttachReviewPhoto)
+			r.Delete("/gyms/{id}/reviews/{reviewId}/photos/{photoId}", h.DeleteReviewPhoto)
+			r.Put("/gyms/{id}", h.UpdateGym)
+			r.Put("/gyms/{id}/hours", h.SetGymHours)
+			r.Put("/gyms/{id}/amenities", h.SetGymAmenities)
+			r.Put("/gyms/{id}/prices", h.SetGymPrice)
+			r.Delete("/gyms/{id}/prices/{plan}", h.DeleteGymPrice)
+			r.Put("/gyms/{id}/machines/{machineId}", h.SetGymMachine)
+			r.Delete("/gyms/{id}/machines/{machineId}", h.RemoveGymMachine)
+			r.Post("/gyms/{id}/claims", h.ClaimGym)
+			r.Get("/gym-claims", h.GetMyGymClaims)
+			r.Post("/gyms/{id}/generate-workout", h.GenerateWorkout)
 			r.Post("/payments/session", h.CreateCheckoutSession)
 
 			r.Post("/videos/upload-url", h.GetUploadURL)
@@ -232,9 +257,84 @@ func setupRoutes(r *chi.Mux, h *handlers.Handlers) {
 			r.Post("/exercises", h.CreateExercise)
 			r.Get("/exercises", h.GetExercises)
 			r.Get("/exercises/{id}", h.GetExercise)
+			r.Put("/exercises/{id}", h.UpdateExercise)
+
+			r.Get("/records", h.GetPersonalRecords)
+			r.Get("/records/history", h.GetPersonalRecordHistory)
+			r.Get("/progress", h.GetProgress)
+			r.Get("/insights/training-load", h.GetTrainingLoad)
+			r.Get("/recommendations/next", h.GetRecommendation)
+
+			r.Get("/templates", h.GetTemplates)
+			r.Post("/templates", h.CreateTemplate)
+			r.Get("/templates/{id}", h.GetTemplate)
+			r.Put("/templates/{id}", h.UpdateTemplate)
+			r.Delete("/templates/{id}", h.DeleteTemplate)
+			r.Post("/templates/{id}/copy", h.CopyTemplate)
+			r.Post("/templates/{id}/start", h.StartTemplate)
+
+			r.Get("/programs", h.GetPrograms)
+			r.Post("/programs", h.CreateProgram)
+			r.Get("/programs/{id}", h.GetProgram)
+			r.Put("/programs/{id}", h.UpdateProgram)
+			r.Delete("/programs/{id}", h.DeleteProgram)
+			r.Get("/programs/{id}/schedule", h.GetProgramSchedule)
+
+			r.Get("/export/sets", h.ExportSets)
+			r.Get("/export/workouts", h.ExportWorkouts)
+
+			r.Post("/imports", h.ImportWorkouts)
+			r.Get("/imports/{id}", h.GetImport)
+			r.Post("/imports/tracks", h.ImportTrack)
+			r.Get("/tracks/{id}", h.GetTrack)
+
+			r.Post("/sync/push", h.PushSync)
+			r.Get("/sync/changes", h.PullSync)
+
+			r.Post("/sessions", h.StartSession)
+			r.Get("/sessions/active", h.GetActiveSession)
+			r.Get("/sessions/{id}", h.GetSession)
+			r.Put("/sessions/{id}", h.UpdateSession)
+			r.Delete("/sessions/{id}", h.DiscardSession)
+			r.Post("/sessions/{id}/sets", h.AddSessionSet)
+			r.Put("/sessions/{id}/sets/{setId}", h.UpdateSessionSet)
+			r.Delete("/sessions/{id}/sets/{setId}", h.DeleteSessionSet)
+			r.Post("/sessions/{id}/pause", h.PauseSession)
+			r.Post("/sessions/{id}/resume", h.ResumeSession)
+			r.Post("/sessions/{id}/finish", h.FinishSession)
+
+			r.Post("/body-metrics", h.CreateBodyMetric)
+			r.Get("/body-metrics", h.GetBodyMetrics)
+			r.Get("/body-metrics/trends", h.GetBodyMetricTrend)
+			r.Get("/body-metrics/goal", h.GetBodyWeightGoal)
+			r.Put("/body-metrics/goal", h.SetBodyWeightGoal)
+			r.Delete("/body-metrics/goal", h.DeleteBodyWeightGoal)
+			r.Put("/body-metrics/{id}", h.UpdateBodyMetric)
+			r.Delete("/body-metrics/{id}", h.DeleteBodyMetric)
+
+			r.Post("/goals", h.CreateGoal)
+			r.Get("/goals", h.GetGoals)
+			r.Get("/goals/{id}", h.GetGoal)
+			r.Put("/goals/{id}", h.UpdateGoal)
+			r.Delete("/goals/{id}", h.DeleteGoal)
+
+			r.Get("/summary/daily", h.GetDailySummary)
 
 			r.Post("/account/export", h.ExportAccount)
 			r.Post("/account/delete", h.DeleteAccount)
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.AdminMiddleware)
+
+				r.Post("/gyms", h.CreateGym)
+				r.Delete("/gyms/{id}", h.DeleteGym)
+				r.Delete("/gyms/{id}/owner", h.ReleaseGymOwner)
+				r.Get("/gym-claims", h.GetGymClaims)
+				r.Post("/gym-claims/{id}/approve", h.ApproveGymClaim)
+				r.Post("/gym-claims/{id}/reject", h.RejectGymClaim)
+				r.Post("/machines", h.CreateMachine)
+				r.Put("/machines/{id}", h.UpdateMachine)
+			})
 		})
 	})
 
@@ -1,19 +1,36 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"fitonex/backend/internal/config"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+var (
+	// ErrObjectNotFound is returned when an object does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrObjectTooLarge is returned when an object exceeds the size a caller accepts.
+	ErrObjectTooLarge = errors.New("object too large")
+)
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
 // S3Service handles S3-compatible storage operations
 type S3Service struct {
 	client *s3.S3
@@ -64,6 +81,21 @@ func (s *S3Service) PresignPut(ctx context.Context, key, contentType string, siz
 	return url, nil
 }
 
+// PutObject uploads a file
+func (s *S3Service) PutObject(ctx context.Context, key, contentType string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+
+	return nil
+}
+
 // PresignGet generates a presigned URL for downloading a file
 func (s *S3Service) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
 	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
@@ -123,3 +155,54 @@ func (s *S3Service) ObjectExists(ctx context.Context, key string) (bool, error)
 
 	return true, nil
 }
+
+// StatObject returns an object's size and content type.
+func (s *S3Service) StatObject(ctx context.Context, key string) (ObjectInfo, error) {
+	out, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return ObjectInfo{
+		Size:        aws.Int64Value(out.ContentLength),
+		ContentType: aws.StringValue(out.ContentType),
+	}, nil
+}
+
+// GetObject downloads an object, failing with ErrObjectTooLarge rather than
+// reading more than maxBytes.
+func (s *S3Service) GetObject(ctx context.Context, key string, maxBytes int64) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(out.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrObjectTooLarge
+	}
+	return data, nil
+}
+
+func isNotFound(err error) bool {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound"
+	}
+	return false
+}
@@ -0,0 +1,366 @@
+package bodymetrics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+// ErrMetricNotFound indicates the measurement does not exist or belongs to another user
+var ErrMetricNotFound = errors.New("body metric not found")
+
+// ErrGoalNotFound indicates the user has not set a goal weight
+var ErrGoalNotFound = errors.New("goal weight not found")
+
+// MetricFilter narrows a measurement listing. From is inclusive and To exclusive.
+type MetricFilter struct {
+	Type *string
+	From *time.Time
+	To   *time.Time
+}
+
+// Store handles body metric database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new body metrics store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create records a measurement. The value must already be in the type's base unit.
+func (s *Store) Create(userID, metricType string, value float64, recordedAt time.Time, notes *string) (*models.BodyMetric, error) {
+	now := time.Now().UTC()
+	metric := &models.BodyMetric{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Type:       metricType,
+		Value:      value,
+		Unit:       models.BodyMetricBaseUnit(metricType),
+		RecordedAt: recordedAt.UTC(),
+		Notes:      notes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	query := `
+		INSERT INTO body_metrics (id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := s.db.Exec(query, metric.ID, userID, metricType, value, metric.RecordedAt, notes, now, now); err != nil {
+		return nil, fmt.Errorf("failed to create body metric: %w", err)
+	}
+	return metric, nil
+}
+
+// GetByID returns one of the user's measurements or ErrMetricNotFound
+func (s *Store) GetByID(userID, id string) (*models.BodyMetric, error) {
+	query := `
+		SELECT id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at
+		FROM body_metrics
+		WHERE id = $1 AND user_id = $2
+	`
+	metric, err := scanMetric(s.db.QueryRow(query, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMetricNotFound
+		}
+		return nil, fmt.Errorf("failed to get body metric: %w", err)
+	}
+	return metric, nil
+}
+
+// Update replaces a measurement's value, time and notes. The value must be in
+// the type's base unit.
+func (s *Store) Update(userID, id string, value float64, recordedAt time.Time, notes *string) (*models.BodyMetric, error) {
+	query := `
+		UPDATE body_metrics
+		SET value = $1, recorded_at = $2, notes = $3, updated_at = $4
+		WHERE id = $5 AND user_id = $6
+		RETURNING id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at
+	`
+	metric, err := scanMetric(s.db.QueryRow(query, value, recordedAt.UTC(), notes, time.Now().UTC(), id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMetricNotFound
+		}
+		return nil, fmt.Errorf("failed to update body metric: %w", err)
+	}
+	return metric, nil
+}
+
+// Delete removes one of the user's measurements
+func (s *Store) Delete(userID, id string) error {
+	result, err := s.db.Exec(`DELETE FROM body_metrics WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete body metric: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete body metric: %w", err)
+	}
+	if affected == 0 {
+		return ErrMetricNotFound
+	}
+	return nil
+}
+
+// List returns the user's measurements ordered by recorded_at DESC, id DESC.
+// The cursor's CreatedAt holds the recorded_at of the last item.
+func (s *Store) List(userID string, filter MetricFilter, limit int, cursor *pagination.TimeDescCursor) (pagination.Paginated[models.BodyMetric], error) {
+	if limit <= 0 {
+		return pagination.Paginated[models.BodyMetric]{}, pagination.ErrInvalidLimit
+	}
+
+	query := `
+		SELECT id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at
+		FROM body_metrics
+		WHERE user_id = $1
+	`
+	args := []interface{}{userID}
+	if filter.Type != nil {
+		args = append(args, *filter.Type)
+		query += fmt.Sprintf(" AND metric_type = $%d", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(" AND recorded_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(" AND recorded_at < $%d", len(args))
+	}
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (recorded_at < $%d OR (recorded_at = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args))
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY recorded_at DESC, id DESC LIMIT $%d", len(args))
+
+	items, err := s.queryMetrics(query, args...)
+	if err != nil {
+		return pagination.Paginated[models.BodyMetric]{}, err
+	}
+
+	page, err := pagination.TimeDescPage(items, limit, func(item models.BodyMetric) pagination.TimeDescCursor {
+		return pagination.TimeDescCursor{CreatedAt: item.RecordedAt, ID: item.ID}
+	})
+	if err != nil {
+		return pagination.Paginated[models.BodyMetric]{}, err
+	}
+	return page, nil
+}
+
+// Latest returns the user's most recent measurement of a type or ErrMetricNotFound
+func (s *Store) Latest(userID, metricType string) (*models.BodyMetric, error) {
+	query := `
+		SELECT id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at
+		FROM body_metrics
+		WHERE user_id = $1 AND metric_type = $2
+		ORDER BY recorded_at DESC, id DESC
+		LIMIT 1
+	`
+	metric, err := scanMetric(s.db.QueryRow(query, userID, metricType))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMetricNotFound
+		}
+		return nil, fmt.Errorf("failed to get latest body metric: %w", err)
+	}
+	return metric, nil
+}
+
+// Trend returns daily means of a metric in [from, to) with a moving average
+// over the trailing window days. Days before from are read so the first
+// points already average a full window. For weight the goal is attached.
+func (s *Store) Trend(userID, metricType string, from, to time.Time, window int) (models.BodyMetricTrend, error) {
+	query := `
+		SELECT (recorded_at AT TIME ZONE 'UTC')::date AS day, AVG(value)::float8, COUNT(*)
+		FROM body_metrics
+		WHERE user_id = $1 AND metric_type = $2 AND recorded_at >= $3 AND recorded_at < $4
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := s.db.Query(query, userID, metricType, from.AddDate(0, 0, -(window-1)), to)
+	if err != nil {
+		return models.BodyMetricTrend{}, fmt.Errorf("failed to query body metric trend: %w", err)
+	}
+	defer rows.Close()
+
+	var days []dailyValue
+	for rows.Next() {
+		var day dailyValue
+		if err := rows.Scan(&day.day, &day.value, &day.count); err != nil {
+			return models.BodyMetricTrend{}, fmt.Errorf("failed to scan body metric day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return models.BodyMetricTrend{}, fmt.Errorf("body metric trend rows error: %w", err)
+	}
+
+	trend := buildTrend(metricType, from, to, window, days)
+	if metricType != models.BodyMetricWeight {
+		return trend, nil
+	}
+
+	goal, err := s.GetGoal(userID)
+	if err != nil && !errors.Is(err, ErrGoalNotFound) {
+		return models.BodyMetricTrend{}, err
+	}
+	trend.Goal = goal
+	return trend, nil
+}
+
+// GetGoal returns the user's goal weight with progress from the latest
+// weigh-in, or ErrGoalNotFound
+func (s *Store) GetGoal(userID string) (*models.BodyWeightGoal, error) {
+	var (
+		goal       models.BodyWeightGoal
+		startKg    sql.NullFloat64
+		targetDate sql.NullTime
+	)
+	query := `
+		SELECT target_kg::float8, start_kg::float8, target_date, created_at, updated_at
+		FROM body_weight_goals
+		WHERE user_id = $1
+	`
+	err := s.db.QueryRow(query, userID).Scan(&goal.TargetKg, &startKg, &targetDate, &goal.CreatedAt, &goal.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("failed to get goal weight: %w", err)
+	}
+	if startKg.Valid {
+		value := startKg.Float64
+		goal.StartKg = &value
+	}
+	if targetDate.Valid {
+		value := targetDate.Time
+		goal.TargetDate = &value
+	}
+
+	var currentKg *float64
+	latest, err := s.Latest(userID, models.BodyMetricWeight)
+	if err != nil && !errors.Is(err, ErrMetricNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		currentKg = &latest.Value
+	}
+	goalProgress(&goal, currentKg)
+	return &goal, nil
+}
+
+// SetGoal creates or replaces the user's goal weight. Without a start weight
+// the latest weigh-in is used.
+func (s *Store) SetGoal(userID string, targetKg float64, startKg *float64, targetDate *time.Time) (*models.BodyWeightGoal, error) {
+	if startKg == nil {
+		latest, err := s.Latest(userID, models.BodyMetricWeight)
+		if err != nil && !errors.Is(err, ErrMetricNotFound) {
+			return nil, err
+		}
+		if latest != nil {
+			startKg = &latest.Value
+		}
+	}
+
+	query := `
+		INSERT INTO body_weight_goals (user_id, target_kg, start_kg, target_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			target_kg = EXCLUDED.target_kg,
+			start_kg = EXCLUDED.start_kg,
+			target_date = EXCLUDED.target_date,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.Exec(query, userID, targetKg, startKg, targetDate, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("failed to set goal weight: %w", err)
+	}
+	return s.GetGoal(userID)
+}
+
+// DeleteGoal clears the user's goal weight
+func (s *Store) DeleteGoal(userID string) error {
+	result, err := s.db.Exec(`DELETE FROM body_weight_goals WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal weight: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete goal weight: %w", err)
+	}
+	if affected == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+func (s *Store) ExportByUser(userID string) ([]models.BodyMetric, error) {
+	query := `
+		SELECT id, user_id, metric_type, value, recorded_at, notes, created_at, updated_at
+		FROM body_metrics
+		WHERE user_id = $1
+		ORDER BY recorded_at, id
+	`
+	return s.queryMetrics(query, userID)
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM body_weight_goals WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete goal weight: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM body_metrics WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete body metrics: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryMetrics(query string, args ...interface{}) ([]models.BodyMetric, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query body metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []models.BodyMetric
+	for rows.Next() {
+		metric, err := scanMetric(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan body metric: %w", err)
+		}
+		metrics = append(metrics, *metric)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("body metric rows error: %w", err)
+	}
+	return metrics, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMetric(row rowScanner) (*models.BodyMetric, error) {
+	var (
+		metric models.BodyMetric
+		notes  sql.NullString
+	)
+	if err := row.Scan(&metric.ID, &metric.UserID, &metric.Type, &metric.Value, &metric.RecordedAt, &notes, &metric.CreatedAt, &metric.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if notes.Valid {
+		value := notes.String
+		metric.Notes = &value
+	}
+	metric.Unit = models.BodyMetricBaseUnit(metric.Type)
+	return &metric, nil
+}
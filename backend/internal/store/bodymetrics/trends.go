@@ -0,0 +1,87 @@
+package bodymetrics
+
+import (
+	"math"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// dailyValue is the mean of one day's measurements
+type dailyValue struct {
+	day   time.Time
+	value float64
+	count int
+}
+
+// buildTrend turns daily means into trend points for the days in [from, to).
+// Each point's moving average is the mean of the days with measurements in
+// the window days ending on it, so gaps shrink the average rather than
+// counting as zero. Days before from only feed the averages.
+func buildTrend(metricType string, from, to time.Time, window int, days []dailyValue) models.BodyMetricTrend {
+	trend := models.BodyMetricTrend{
+		Type:       metricType,
+		Unit:       models.BodyMetricBaseUnit(metricType),
+		WindowDays: window,
+		From:       from,
+		To:         to,
+		Points:     []models.BodyMetricTrendPoint{},
+	}
+
+	start := 0
+	sum := 0.0
+	for i, day := range days {
+		sum += day.value
+		windowStart := day.day.AddDate(0, 0, -(window - 1))
+		for days[start].day.Before(windowStart) {
+			sum -= days[start].value
+			start++
+		}
+		if day.day.Before(from) || !day.day.Before(to) {
+			continue
+		}
+		trend.Points = append(trend.Points, models.BodyMetricTrendPoint{
+			Day:           day.day,
+			Value:         round2(day.value),
+			MovingAverage: round2(sum / float64(i-start+1)),
+			Count:         day.count,
+		})
+	}
+
+	if len(trend.Points) > 0 {
+		first := trend.Points[0]
+		last := trend.Points[len(trend.Points)-1]
+		latest := last.Value
+		change := round2(last.MovingAverage - first.MovingAverage)
+		trend.Latest = &latest
+		trend.Change = &change
+	}
+	return trend
+}
+
+// goalProgress fills the goal's display fields in kilograms and its progress
+// from the start weight towards the target, capped to 0-100%.
+func goalProgress(goal *models.BodyWeightGoal, currentKg *float64) {
+	goal.Unit = models.UnitKg
+	goal.Target = goal.TargetKg
+	goal.Start = goal.StartKg
+	if currentKg == nil {
+		return
+	}
+
+	current := round2(*currentKg)
+	remaining := round2(math.Abs(goal.TargetKg - current))
+	goal.Current = &current
+	goal.Remaining = &remaining
+
+	if goal.StartKg == nil || math.Abs(*goal.StartKg-goal.TargetKg) < 0.01 {
+		return
+	}
+	progress := (*goal.StartKg - current) / (*goal.StartKg - goal.TargetKg) * 100
+	progress = math.Round(math.Min(math.Max(progress, 0), 100)*10) / 10
+	goal.ProgressPercent = &progress
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
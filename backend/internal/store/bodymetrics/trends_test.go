@@ -0,0 +1,68 @@
+package bodymetrics
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestBuildTrendMovingAverage(t *testing.T) {
+	from := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	days := []dailyValue{
+		{day: from.AddDate(0, 0, -2), value: 82, count: 1},
+		{day: from, value: 81, count: 2},
+		{day: from.AddDate(0, 0, 1), value: 80, count: 1},
+		// Two days without weigh-ins, then one after the range.
+		{day: from.AddDate(0, 0, 4), value: 79, count: 1},
+		{day: to, value: 70, count: 1},
+	}
+
+	trend := buildTrend(models.BodyMetricWeight, from, to, 3, days)
+	if trend.Unit != models.UnitKg || len(trend.Points) != 3 {
+		t.Fatalf("unexpected trend %+v", trend)
+	}
+
+	want := []float64{81.5, 80.5, 79}
+	for i, point := range trend.Points {
+		if point.MovingAverage != want[i] {
+			t.Fatalf("point %d moving average = %v, want %v", i, point.MovingAverage, want[i])
+		}
+	}
+	if trend.Points[0].Count != 2 || *trend.Latest != 79 || *trend.Change != -2.5 {
+		t.Fatalf("unexpected summary %+v latest %v change %v", trend.Points[0], *trend.Latest, *trend.Change)
+	}
+}
+
+func TestBuildTrendWithoutData(t *testing.T) {
+	from := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	trend := buildTrend(models.BodyMetricWaist, from, from.AddDate(0, 0, 7), 7, nil)
+	if trend.Unit != models.UnitCm || trend.Points == nil || len(trend.Points) != 0 || trend.Latest != nil || trend.Change != nil {
+		t.Fatalf("unexpected empty trend %+v", trend)
+	}
+}
+
+func TestGoalProgress(t *testing.T) {
+	start := 90.0
+	goal := models.BodyWeightGoal{TargetKg: 80, StartKg: &start}
+	current := 87.5
+
+	goalProgress(&goal, &current)
+	if *goal.Current != 87.5 || *goal.Remaining != 7.5 || *goal.ProgressPercent != 25 {
+		t.Fatalf("unexpected progress %+v", goal)
+	}
+
+	// Moving away from the target never reports negative progress.
+	current = 92
+	goalProgress(&goal, &current)
+	if *goal.ProgressPercent != 0 {
+		t.Fatalf("expected 0%% progress, got %v", *goal.ProgressPercent)
+	}
+
+	bare := models.BodyWeightGoal{TargetKg: 80}
+	goalProgress(&bare, nil)
+	if bare.Target != 80 || bare.Unit != models.UnitKg || bare.Current != nil || bare.ProgressPercent != nil {
+		t.Fatalf("unexpected goal without weigh-ins %+v", bare)
+	}
+}
@@ -0,0 +1,175 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrCatalogExerciseNotFound is returned when a catalog entry does not exist.
+var ErrCatalogExerciseNotFound = errors.New("catalog exercise not found")
+
+// Store handles exercise catalog database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new catalog store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Search ranks catalog entries by trigram similarity of the term to their name
+// or any alias. An empty term lists every entry. muscle matches primary or
+// secondary muscles and equipment matches exactly; both are optional.
+func (s *Store) Search(term, muscle, equipment string, limit int, cursor *pagination.ScoreDescCursor) (pagination.Paginated[models.CatalogSearchResult], error) {
+	if limit <= 0 {
+		return pagination.Paginated[models.CatalogSearchResult]{}, pagination.ErrInvalidLimit
+	}
+
+	args := []any{strings.TrimSpace(term)}
+	query := `
+		WITH ranked AS (
+			SELECT
+				c.id, c.slug, c.name, c.primary_muscles, c.equipment,
+				CASE WHEN $1 = '' THEN 1.0 ELSE GREATEST(
+					similarity(c.name, $1),
+					COALESCE((SELECT MAX(similarity(a, $1)) FROM unnest(c.aliases) a), 0)
+				) END AS score
+			FROM exercise_catalog c
+			WHERE TRUE
+	`
+	if muscle = strings.ToLower(strings.TrimSpace(muscle)); muscle != "" {
+		args = append(args, muscle)
+		query += fmt.Sprintf(" AND ($%d = ANY(c.primary_muscles) OR $%d = ANY(c.secondary_muscles))", len(args), len(args))
+	}
+	if equipment = strings.ToLower(strings.TrimSpace(equipment)); equipment != "" {
+		args = append(args, equipment)
+		query += fmt.Sprintf(" AND c.equipment = $%d", len(args))
+	}
+	query += `
+		)
+		SELECT id, slug, name, primary_muscles, equipment, score
+		FROM ranked
+		WHERE score > 0.1
+	`
+	if cursor != nil {
+		args = append(args, cursor.Score, cursor.ID)
+		query += fmt.Sprintf(" AND (score < $%d OR (score = $%d AND id > $%d))", len(args)-1, len(args)-1, len(args))
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY score DESC, id ASC LIMIT $%d", len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return pagination.Paginated[models.CatalogSearchResult]{}, fmt.Errorf("failed to search catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.CatalogSearchResult
+	for rows.Next() {
+		var item models.CatalogSearchResult
+		if err := rows.Scan(&item.ID, &item.Slug, &item.Name, pq.Array(&item.PrimaryMuscles), &item.Equipment, &item.Score); err != nil {
+			return pagination.Paginated[models.CatalogSearchResult]{}, fmt.Errorf("failed to scan catalog exercise: %w", err)
+		}
+		results = append(results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Paginated[models.CatalogSearchResult]{}, fmt.Errorf("catalog rows error: %w", err)
+	}
+
+	return pagination.ScoreDescPage(results, limit, func(item models.CatalogSearchResult) pagination.ScoreDescCursor {
+		return pagination.ScoreDescCursor{Score: item.Score, ID: item.ID}
+	})
+}
+
+// GetByID returns a catalog entry with its linked machines and instruction videos
+func (s *Store) GetByID(id string) (*models.CatalogExercise, error) {
+	var item models.CatalogExercise
+	err := s.db.QueryRow(`
+		SELECT
+			c.id, c.slug, c.name, c.aliases, c.primary_muscles, c.secondary_muscles, c.equipment, c.created_at,
+			COALESCE((SELECT array_agg(cm.machine_id::text ORDER BY cm.machine_id) FROM exercise_catalog_machines cm WHERE cm.catalog_id = c.id), '{}'),
+			COALESCE((SELECT array_agg(cv.video_id::text ORDER BY cv.video_id) FROM exercise_catalog_videos cv WHERE cv.catalog_id = c.id), '{}')
+		FROM exercise_catalog c
+		WHERE c.id = $1
+	`, id).Scan(
+		&item.ID, &item.Slug, &item.Name,
+		pq.Array(&item.Aliases), pq.Array(&item.PrimaryMuscles), pq.Array(&item.SecondaryMuscles),
+		&item.Equipment, &item.CreatedAt,
+		pq.Array(&item.MachineIDs), pq.Array(&item.VideoIDs),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCatalogExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to get catalog exercise: %w", err)
+	}
+	item.CreatedAt = item.CreatedAt.UTC()
+
+	return &item, nil
+}
+
+// SyncCurated upserts the built-in catalog by slug and links each entry to
+// the machines with a matching name. Entries added by hand are left alone.
+func (s *Store) SyncCurated() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, entry := range Curated {
+		var id string
+		err := tx.QueryRow(`
+			INSERT INTO exercise_catalog (id, slug, name, aliases, primary_muscles, secondary_muscles, equipment)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (slug) DO UPDATE
+			SET name = EXCLUDED.name,
+			    aliases = EXCLUDED.aliases,
+			    primary_muscles = EXCLUDED.primary_muscles,
+			    secondary_muscles = EXCLUDED.secondary_muscles,
+			    equipment = EXCLUDED.equipment
+			RETURNING id
+		`, uuid.New().String(), entry.Slug, entry.Name, pq.Array(nonNil(entry.Aliases)),
+			pq.Array(nonNil(entry.PrimaryMuscles)), pq.Array(nonNil(entry.SecondaryMuscles)), entry.Equipment,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to upsert catalog exercise %s: %w", entry.Slug, err)
+		}
+
+		if len(entry.Machines) == 0 {
+			continue
+		}
+		names := make([]string, len(entry.Machines))
+		for i, name := range entry.Machines {
+			names[i] = strings.ToLower(name)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO exercise_catalog_machines (catalog_id, machine_id)
+			SELECT $1, id FROM machines WHERE LOWER(name) = ANY($2)
+			ON CONFLICT DO NOTHING
+		`, id, pq.Array(names)); err != nil {
+			return fmt.Errorf("failed to link catalog machines for %s: %w", entry.Slug, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,250 @@
+package catalog
+
+// CuratedExercise is a built-in catalog entry. Machines are matched by name so
+// the list does not depend on machine IDs of a particular database.
+type CuratedExercise struct {
+	Slug             string
+	Name             string
+	Aliases          []string
+	PrimaryMuscles   []string
+	SecondaryMuscles []string
+	Equipment        string
+	Machines         []string
+}
+
+// Curated is the built-in exercise catalog kept in sync by SyncCurated.
+var Curated = []CuratedExercise{
+	{
+		Slug: "barbell-bench-press", Name: "Barbell Bench Press",
+		Aliases:          []string{"Bench Press", "Flat Bench Press", "Bench Press (Barbell)", "BB Bench"},
+		PrimaryMuscles:   []string{"chest"},
+		SecondaryMuscles: []string{"triceps", "shoulders"},
+		Equipment:        "barbell",
+		Machines:         []string{"Bench Press"},
+	},
+	{
+		Slug: "incline-barbell-bench-press", Name: "Incline Barbell Bench Press",
+		Aliases:          []string{"Incline Bench Press", "Incline Bench Press (Barbell)"},
+		PrimaryMuscles:   []string{"chest"},
+		SecondaryMuscles: []string{"shoulders", "triceps"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "dumbbell-bench-press", Name: "Dumbbell Bench Press",
+		Aliases:          []string{"Bench Press (Dumbbell)", "DB Bench Press"},
+		PrimaryMuscles:   []string{"chest"},
+		SecondaryMuscles: []string{"triceps", "shoulders"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "dumbbell-fly", Name: "Dumbbell Fly",
+		Aliases:          []string{"Chest Fly", "Dumbbell Flyes", "Chest Fly (Dumbbell)"},
+		PrimaryMuscles:   []string{"chest"},
+		SecondaryMuscles: []string{"shoulders"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "push-up", Name: "Push-Up",
+		Aliases:          []string{"Push Up", "Pushups", "Press-Up"},
+		PrimaryMuscles:   []string{"chest"},
+		SecondaryMuscles: []string{"triceps", "shoulders", "core"},
+		Equipment:        "bodyweight",
+	},
+	{
+		Slug: "overhead-press", Name: "Overhead Press",
+		Aliases:          []string{"Military Press", "OHP", "Standing Shoulder Press", "Overhead Press (Barbell)"},
+		PrimaryMuscles:   []string{"shoulders"},
+		SecondaryMuscles: []string{"triceps", "core"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "dumbbell-shoulder-press", Name: "Dumbbell Shoulder Press",
+		Aliases:          []string{"Seated Dumbbell Press", "Shoulder Press (Dumbbell)"},
+		PrimaryMuscles:   []string{"shoulders"},
+		SecondaryMuscles: []string{"triceps"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "lateral-raise", Name: "Lateral Raise",
+		Aliases:        []string{"Side Raise", "Lateral Raise (Dumbbell)", "Side Lateral Raise"},
+		PrimaryMuscles: []string{"shoulders"},
+		Equipment:      "dumbbell",
+	},
+	{
+		Slug: "lat-pulldown", Name: "Lat Pulldown",
+		Aliases:          []string{"Lat Pull Down", "Lat Pulldown (Cable)", "Wide Grip Pulldown"},
+		PrimaryMuscles:   []string{"lats"},
+		SecondaryMuscles: []string{"biceps", "upper back"},
+		Equipment:        "cable",
+		Machines:         []string{"Lat Pulldown"},
+	},
+	{
+		Slug: "pull-up", Name: "Pull-Up",
+		Aliases:          []string{"Pull Up", "Pullups", "Chin-Up", "Chin Up"},
+		PrimaryMuscles:   []string{"lats"},
+		SecondaryMuscles: []string{"biceps", "upper back"},
+		Equipment:        "bodyweight",
+	},
+	{
+		Slug: "seated-cable-row", Name: "Seated Cable Row",
+		Aliases:          []string{"Cable Row", "Seated Row", "Seated Row (Cable)"},
+		PrimaryMuscles:   []string{"upper back", "lats"},
+		SecondaryMuscles: []string{"biceps"},
+		Equipment:        "cable",
+		Machines:         []string{"Cable Row"},
+	},
+	{
+		Slug: "barbell-row", Name: "Barbell Row",
+		Aliases:          []string{"Bent Over Row", "Bent Over Row (Barbell)", "Pendlay Row"},
+		PrimaryMuscles:   []string{"upper back", "lats"},
+		SecondaryMuscles: []string{"biceps", "lower back"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "dumbbell-row", Name: "Dumbbell Row",
+		Aliases:          []string{"One Arm Dumbbell Row", "Single Arm Row", "Bent Over One Arm Row (Dumbbell)"},
+		PrimaryMuscles:   []string{"lats", "upper back"},
+		SecondaryMuscles: []string{"biceps"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "deadlift", Name: "Deadlift",
+		Aliases:          []string{"Conventional Deadlift", "Deadlift (Barbell)"},
+		PrimaryMuscles:   []string{"hamstrings", "glutes", "lower back"},
+		SecondaryMuscles: []string{"quads", "forearms", "upper back"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "romanian-deadlift", Name: "Romanian Deadlift",
+		Aliases:          []string{"RDL", "Romanian Deadlift (Barbell)", "Stiff Leg Deadlift"},
+		PrimaryMuscles:   []string{"hamstrings", "glutes"},
+		SecondaryMuscles: []string{"lower back"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "back-squat", Name: "Back Squat",
+		Aliases:          []string{"Squat", "Barbell Squat", "Squat (Barbell)", "High Bar Squat"},
+		PrimaryMuscles:   []string{"quads", "glutes"},
+		SecondaryMuscles: []string{"hamstrings", "lower back", "core"},
+		Equipment:        "barbell",
+		Machines:         []string{"Squat Rack"},
+	},
+	{
+		Slug: "front-squat", Name: "Front Squat",
+		Aliases:          []string{"Front Squat (Barbell)"},
+		PrimaryMuscles:   []string{"quads"},
+		SecondaryMuscles: []string{"glutes", "core"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "smith-machine-squat", Name: "Smith Machine Squat",
+		Aliases:          []string{"Squat (Smith Machine)", "Smith Squat"},
+		PrimaryMuscles:   []string{"quads", "glutes"},
+		SecondaryMuscles: []string{"hamstrings"},
+		Equipment:        "machine",
+		Machines:         []string{"Smith Machine"},
+	},
+	{
+		Slug: "leg-press", Name: "Leg Press",
+		Aliases:          []string{"Leg Press (Machine)", "Seated Leg Press", "Sled Leg Press"},
+		PrimaryMuscles:   []string{"quads", "glutes"},
+		SecondaryMuscles: []string{"hamstrings"},
+		Equipment:        "machine",
+		Machines:         []string{"Leg Press"},
+	},
+	{
+		Slug: "walking-lunge", Name: "Walking Lunge",
+		Aliases:          []string{"Lunge", "Lunges", "Walking Lunge (Dumbbell)"},
+		PrimaryMuscles:   []string{"quads", "glutes"},
+		SecondaryMuscles: []string{"hamstrings"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "leg-extension", Name: "Leg Extension",
+		Aliases:        []string{"Leg Extension (Machine)", "Quad Extension"},
+		PrimaryMuscles: []string{"quads"},
+		Equipment:      "machine",
+	},
+	{
+		Slug: "lying-leg-curl", Name: "Lying Leg Curl",
+		Aliases:        []string{"Leg Curl", "Hamstring Curl", "Lying Leg Curl (Machine)"},
+		PrimaryMuscles: []string{"hamstrings"},
+		Equipment:      "machine",
+	},
+	{
+		Slug: "hip-thrust", Name: "Hip Thrust",
+		Aliases:          []string{"Barbell Hip Thrust", "Hip Thrust (Barbell)", "Glute Bridge"},
+		PrimaryMuscles:   []string{"glutes"},
+		SecondaryMuscles: []string{"hamstrings"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "standing-calf-raise", Name: "Standing Calf Raise",
+		Aliases:        []string{"Calf Raise", "Calf Raises", "Standing Calf Raise (Machine)"},
+		PrimaryMuscles: []string{"calves"},
+		Equipment:      "machine",
+	},
+	{
+		Slug: "dumbbell-curl", Name: "Dumbbell Curl",
+		Aliases:          []string{"Bicep Curl", "Biceps Curl", "Bicep Curl (Dumbbell)", "Hammer Curl"},
+		PrimaryMuscles:   []string{"biceps"},
+		SecondaryMuscles: []string{"forearms"},
+		Equipment:        "dumbbell",
+	},
+	{
+		Slug: "barbell-curl", Name: "Barbell Curl",
+		Aliases:          []string{"Bicep Curl (Barbell)", "EZ Bar Curl"},
+		PrimaryMuscles:   []string{"biceps"},
+		SecondaryMuscles: []string{"forearms"},
+		Equipment:        "barbell",
+	},
+	{
+		Slug: "triceps-pushdown", Name: "Triceps Pushdown",
+		Aliases:        []string{"Tricep Pushdown", "Cable Pushdown", "Triceps Pushdown (Cable)", "Rope Pushdown"},
+		PrimaryMuscles: []string{"triceps"},
+		Equipment:      "cable",
+	},
+	{
+		Slug: "skull-crusher", Name: "Skull Crusher",
+		Aliases:        []string{"Lying Triceps Extension", "Skullcrusher (Barbell)"},
+		PrimaryMuscles: []string{"triceps"},
+		Equipment:      "barbell",
+	},
+	{
+		Slug: "plank", Name: "Plank",
+		Aliases:          []string{"Front Plank", "Forearm Plank"},
+		PrimaryMuscles:   []string{"core"},
+		SecondaryMuscles: []string{"shoulders"},
+		Equipment:        "bodyweight",
+	},
+	{
+		Slug: "hanging-leg-raise", Name: "Hanging Leg Raise",
+		Aliases:          []string{"Leg Raise", "Hanging Knee Raise"},
+		PrimaryMuscles:   []string{"core"},
+		SecondaryMuscles: []string{"forearms"},
+		Equipment:        "bodyweight",
+	},
+	{
+		Slug: "treadmill-run", Name: "Treadmill Run",
+		Aliases:          []string{"Treadmill", "Running (Treadmill)", "Treadmill Walk"},
+		PrimaryMuscles:   []string{"cardio"},
+		SecondaryMuscles: []string{"quads", "calves"},
+		Equipment:        "cardio machine",
+		Machines:         []string{"Treadmill"},
+	},
+	{
+		Slug: "elliptical", Name: "Elliptical Trainer",
+		Aliases:        []string{"Elliptical", "Cross Trainer"},
+		PrimaryMuscles: []string{"cardio"},
+		Equipment:      "cardio machine",
+		Machines:       []string{"Elliptical"},
+	},
+	{
+		Slug: "indoor-row", Name: "Indoor Row",
+		Aliases:          []string{"Rowing Machine", "Rowing", "Erg", "Rower"},
+		PrimaryMuscles:   []string{"cardio"},
+		SecondaryMuscles: []string{"upper back", "quads"},
+		Equipment:        "cardio machine",
+		Machines:         []string{"Rowing Machine"},
+	},
+}
@@ -0,0 +1,29 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCuratedEntriesAreUnambiguous(t *testing.T) {
+	slugs := make(map[string]bool)
+	terms := make(map[string]string)
+
+	for _, entry := range Curated {
+		if entry.Slug == "" || entry.Name == "" || entry.Equipment == "" || len(entry.PrimaryMuscles) == 0 {
+			t.Fatalf("incomplete entry %+v", entry)
+		}
+		if slugs[entry.Slug] {
+			t.Fatalf("duplicate slug %q", entry.Slug)
+		}
+		slugs[entry.Slug] = true
+
+		for _, term := range append([]string{entry.Name}, entry.Aliases...) {
+			key := strings.ToLower(term)
+			if owner, ok := terms[key]; ok && owner != entry.Slug {
+				t.Fatalf("%q is used by both %s and %s", term, owner, entry.Slug)
+			}
+			terms[key] = entry.Slug
+		}
+	}
+}
@@ -11,6 +11,9 @@ import (
     "github.com/google/uuid"
 )
 
+// ErrCheckinNotFound is returned when a check-in does not exist or belongs to another user.
+var ErrCheckinNotFound = errors.New("check-in not found")
+
 // Store handles check-in related database operations
 type Store struct {
 	db *sql.DB
@@ -54,6 +57,23 @@ func (s *Store) CreateToday(userID string) (*models.Checkin, bool, error) {
     return checkin, inserted, nil
 }
 
+// GetByID retrieves one of the user's check-ins
+func (s *Store) GetByID(id, userID string) (*models.Checkin, error) {
+	var checkin models.Checkin
+	err := s.db.QueryRow(`
+		SELECT id, user_id, day, created_at
+		FROM checkins
+		WHERE id = $1 AND user_id = $2
+	`, id, userID).Scan(&checkin.ID, &checkin.UserID, &checkin.Day, &checkin.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCheckinNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check-in: %w", err)
+	}
+	return &checkin, nil
+}
+
 // GetStats calculates streak statistics for a user
 func (s *Store) GetStats(userID string) (*models.CheckinStats, error) {
     rows, err := s.db.Query(`
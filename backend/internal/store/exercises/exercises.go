@@ -2,6 +2,7 @@ package exercises
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,8 +10,18 @@ import (
 	"fitonex/backend/internal/pagination"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrWorkoutNotFound is returned when an exercise is logged into a workout the user does not own.
+var ErrWorkoutNotFound = errors.New("workout not found")
+
+// ErrSetNotFound is returned when a set does not exist or belongs to another user.
+var ErrSetNotFound = errors.New("set not found")
+
+// ErrExerciseNotFound is returned when an exercise does not exist or belongs to another user.
+var ErrExerciseNotFound = errors.New("exercise not found")
+
 // Store handles exercise-related database operations
 type Store struct {
 	db *sql.DB
@@ -21,12 +32,13 @@ func New(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
-// Create creates a new exercise with sets.
-func (s *Store) Create(userID string, performedAt time.Time, gymID, machineID *string, name string, sets []models.Set) (*models.Exercise, error) {
+// Create creates a new exercise with sets, optionally inside one of the user's workouts.
+func (s *Store) Create(userID string, workoutID *string, performedAt time.Time, gymID, machineID, catalogID *string, name string, group *models.ExerciseGroup, sets []models.Set) (*models.Exercise, error) {
 	exercise := &models.Exercise{
 		ID:        uuid.New().String(),
 		UserID:    userID,
 		Name:      name,
+		Group:     group,
 		CreatedAt: performedAt.UTC(),
 	}
 
@@ -40,6 +52,16 @@ func (s *Store) Create(userID string, performedAt time.Time, gymID, machineID *s
 		exercise.MachineID = &copy
 	}
 
+	if catalogID != nil && *catalogID != "" {
+		copy := *catalogID
+		exercise.CatalogID = &copy
+	}
+
+	if workoutID != nil && *workoutID != "" {
+		copy := *workoutID
+		exercise.WorkoutID = &copy
+	}
+
 	// Start transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -47,51 +69,285 @@ func (s *Store) Create(userID string, performedAt time.Time, gymID, machineID *s
 	}
 	defer tx.Rollback()
 
-    // Insert exercise
-    exerciseQuery := `
-        INSERT INTO exercises (id, user_id, gym_id, machine_id, name, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6)
-    `
+	if exercise.WorkoutID != nil {
+		// Lock the workout so it cannot be deleted while the exercise is attached.
+		var exists int
+		err = tx.QueryRow(`SELECT 1 FROM workouts WHERE id = $1 AND user_id = $2 FOR UPDATE`, *exercise.WorkoutID, userID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWorkoutNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock workout: %w", err)
+		}
+		if err = TouchWorkoutTx(tx, *exercise.WorkoutID, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
 
-    var gymRef interface{}
-    if exercise.GymID != nil {
-        gymRef = *exercise.GymID
+    exercise.Sets = sets
+    if err = InsertTx(tx, exercise); err != nil {
+        return nil, err
     }
 
-    var machineRef interface{}
-    if exercise.MachineID != nil {
-        machineRef = *exercise.MachineID
-    }
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-    if _, err = tx.Exec(exerciseQuery, exercise.ID, exercise.UserID, gymRef, machineRef, exercise.Name, exercise.CreatedAt); err != nil {
-        return nil, fmt.Errorf("failed to create exercise: %w", err)
-    }
+    return exercise, nil
+}
+
+// TouchWorkoutTx marks a workout as changed when an exercise is logged in it.
+// A planned workout becomes performed at that moment, so it starts counting
+// towards history, records, training load and goals.
+func TouchWorkoutTx(tx *sql.Tx, workoutID string, at time.Time) error {
+	_, err := tx.Exec(`
+		UPDATE workouts
+		SET updated_at = $1,
+			created_at = CASE WHEN planned THEN $1 ELSE created_at END,
+			planned = FALSE
+		WHERE id = $2
+	`, at, workoutID)
+	if err != nil {
+		return fmt.Errorf("failed to touch workout: %w", err)
+	}
+	return nil
+}
+
+// Update replaces an exercise's details and sets. The catalog link is taken as
+// given, so the mapping job will not relink an exercise the user has edited.
+func (s *Store) Update(id, userID string, gymID, machineID, catalogID *string, name string, group *models.ExerciseGroup, sets []models.Set) (*models.Exercise, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-    // Insert sets
-    for i := range sets {
-        sets[i].ID = uuid.New().String()
-        sets[i].ExerciseID = exercise.ID
-        sets[i].SetIndex = i + 1
+	var gymRef interface{}
+	if gymID != nil && *gymID != "" {
+		gymRef = *gymID
+	}
 
-        set := sets[i]
+	var machineRef interface{}
+	if machineID != nil && *machineID != "" {
+		machineRef = *machineID
+	}
 
-        setQuery := `
-            INSERT INTO sets (id, exercise_id, set_index, reps, weight_kg, rpe, notes)
-            VALUES ($1, $2, $3, $4, $5, $6, $7)
-        `
+	var catalogRef interface{}
+	if catalogID != nil && *catalogID != "" {
+		catalogRef = *catalogID
+	}
 
-        if _, err = tx.Exec(setQuery, set.ID, set.ExerciseID, set.SetIndex, set.Reps, set.WeightKg, set.RPE, set.Notes); err != nil {
-            return nil, fmt.Errorf("failed to create set: %w", err)
-        }
-    }
+	groupID, groupType := groupColumns(group)
+
+	result, err := tx.Exec(`
+		UPDATE exercises
+		SET gym_id = $1, machine_id = $2, catalog_id = $3, name = $4, group_id = $5, group_type = $6, catalog_checked_at = NOW()
+		WHERE id = $7 AND user_id = $8
+	`, gymRef, machineRef, catalogRef, name, groupID, groupType, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update exercise: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrExerciseNotFound
+	}
+
+	if _, err = tx.Exec(`DELETE FROM sets WHERE exercise_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear sets: %w", err)
+	}
+
+	if err = insertSets(tx, id, sets); err != nil {
+		return nil, err
+	}
 
-	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-    exercise.Sets = sets
-    return exercise, nil
+	return s.GetByID(id, userID)
+}
+
+// ListForRecordKey returns every exercise of a user that counts toward the same
+// machine or exercise name, oldest first, with their sets.
+func (s *Store) ListForRecordKey(userID string, machineID *string, name string) ([]models.Exercise, error) {
+	keyFilter, args := recordKeyFilter(userID, machineID, name)
+	return s.listForRecordKey(`
+		SELECT id, user_id, gym_id, machine_id, name, created_at
+		FROM exercises
+		WHERE user_id = $1 AND `+keyFilter+`
+		ORDER BY created_at ASC, id ASC
+	`, args...)
+}
+
+// ListRecentForRecordKey returns the latest limit exercises of a lift that
+// have a weighted working set, oldest first, with their sets.
+func (s *Store) ListRecentForRecordKey(userID string, machineID *string, name string, limit int) ([]models.Exercise, error) {
+	keyFilter, args := recordKeyFilter(userID, machineID, name)
+	args = append(args, limit)
+	return s.listForRecordKey(`
+		SELECT id, user_id, gym_id, machine_id, name, created_at
+		FROM (
+			SELECT id, user_id, gym_id, machine_id, name, created_at
+			FROM exercises
+			WHERE user_id = $1 AND `+keyFilter+`
+				AND EXISTS (
+					SELECT 1 FROM sets s
+					WHERE s.exercise_id = exercises.id AND s.set_type <> 'warmup' AND s.reps > 0 AND s.weight_kg > 0
+				)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3
+		) recent
+		ORDER BY created_at ASC, id ASC
+	`, args...)
+}
+
+// recordKeyFilter matches the exercises of one lift: by machine, or by
+// normalized name for exercises without a machine. It uses $1 and $2.
+func recordKeyFilter(userID string, machineID *string, name string) (string, []interface{}) {
+	if machineID != nil && *machineID != "" {
+		return "machine_id = $2", []interface{}{userID, *machineID}
+	}
+	return `machine_id IS NULL
+			AND LOWER(regexp_replace(BTRIM(name), '\s+', ' ', 'g')) = LOWER(regexp_replace(BTRIM($2), '\s+', ' ', 'g'))`, []interface{}{userID, name}
+}
+
+func (s *Store) listForRecordKey(query string, args ...interface{}) ([]models.Exercise, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.Exercise
+	for rows.Next() {
+		var (
+			exercise  models.Exercise
+			gymID     sql.NullString
+			machineID sql.NullString
+		)
+		if err := rows.Scan(&exercise.ID, &exercise.UserID, &gymID, &machineID, &exercise.Name, &exercise.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exercise: %w", err)
+		}
+		exercise.CreatedAt = exercise.CreatedAt.UTC()
+		if gymID.Valid {
+			value := gymID.String
+			exercise.GymID = &value
+		}
+		if machineID.Valid {
+			value := machineID.String
+			exercise.MachineID = &value
+		}
+		items = append(items, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exercise rows error: %w", err)
+	}
+
+	if err := s.attachSets(items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// ListByWorkouts returns the exercises attached to the given workouts, keyed by
+// workout ID and ordered by created_at within each workout.
+func (s *Store) ListByWorkouts(userID string, workoutIDs []string) (map[string][]models.Exercise, error) {
+	result := make(map[string][]models.Exercise, len(workoutIDs))
+	if len(workoutIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT
+			e.id,
+			e.user_id,
+			e.workout_id,
+			e.gym_id,
+			e.machine_id,
+			e.name,
+			e.created_at,
+			e.group_id,
+			e.group_type,
+			e.catalog_id,
+			g.name AS gym_name,
+			m.name AS machine_name,
+			c.name AS catalog_name
+		FROM exercises e
+		LEFT JOIN gyms g ON e.gym_id = g.id
+		LEFT JOIN machines m ON e.machine_id = m.id
+		LEFT JOIN exercise_catalog c ON e.catalog_id = c.id
+		WHERE e.user_id = $1 AND e.workout_id = ANY($2)
+		ORDER BY e.created_at ASC, e.id ASC
+	`, userID, pq.Array(workoutIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workout exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.Exercise
+	for rows.Next() {
+		var (
+			exercise    models.Exercise
+			workoutID   string
+			gymID       sql.NullString
+			machineID   sql.NullString
+			groupID     sql.NullString
+			groupType   sql.NullString
+			catalogID   sql.NullString
+			gymName     sql.NullString
+			machineName sql.NullString
+			catalogName sql.NullString
+		)
+		if err := rows.Scan(&exercise.ID, &exercise.UserID, &workoutID, &gymID, &machineID, &exercise.Name, &exercise.CreatedAt, &groupID, &groupType, &catalogID, &gymName, &machineName, &catalogName); err != nil {
+			return nil, fmt.Errorf("failed to scan exercise: %w", err)
+		}
+		exercise.CreatedAt = exercise.CreatedAt.UTC()
+		exercise.Group = groupFromColumns(groupID, groupType)
+		exercise.WorkoutID = &workoutID
+		if gymID.Valid {
+			value := gymID.String
+			exercise.GymID = &value
+		}
+		if machineID.Valid {
+			value := machineID.String
+			exercise.MachineID = &value
+		}
+		if gymName.Valid {
+			value := gymName.String
+			exercise.GymName = &value
+		}
+		if machineName.Valid {
+			value := machineName.String
+			exercise.MachineName = &value
+		}
+		if catalogID.Valid {
+			value := catalogID.String
+			exercise.CatalogID = &value
+		}
+		if catalogName.Valid {
+			value := catalogName.String
+			exercise.CatalogName = &value
+		}
+		items = append(items, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exercise rows error: %w", err)
+	}
+
+	if err := s.attachSets(items); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		result[*items[i].WorkoutID] = append(result[*items[i].WorkoutID], items[i])
+	}
+
+	return result, nil
 }
 
 // ListByDay retrieves exercises for a specific day ordered by created_at desc.
@@ -107,15 +363,21 @@ func (s *Store) ListByDay(userID string, day time.Time, limit int, cursor *pagin
 		SELECT 
 			e.id,
 			e.user_id,
+			e.workout_id,
 			e.gym_id,
 			e.machine_id,
 			e.name,
 			e.created_at,
+			e.group_id,
+			e.group_type,
+			e.catalog_id,
 			g.name AS gym_name,
-			m.name AS machine_name
+			m.name AS machine_name,
+			c.name AS catalog_name
 		FROM exercises e
 		LEFT JOIN gyms g ON e.gym_id = g.id
 		LEFT JOIN machines m ON e.machine_id = m.id
+		LEFT JOIN exercise_catalog c ON e.catalog_id = c.id
 		WHERE e.user_id = $1 AND e.created_at >= $2 AND e.created_at < $3
 	`
 
@@ -150,27 +412,42 @@ func (s *Store) ListByDay(userID string, day time.Time, limit int, cursor *pagin
 	for rows.Next() {
 		var (
 			exercise    models.Exercise
+			workoutID   sql.NullString
 			gymID       sql.NullString
 			machineID   sql.NullString
+			groupID     sql.NullString
+			groupType   sql.NullString
+			catalogID   sql.NullString
 			gymName     sql.NullString
 			machineName sql.NullString
+			catalogName sql.NullString
 		)
 
 		if err := rows.Scan(
 			&exercise.ID,
 			&exercise.UserID,
+			&workoutID,
 			&gymID,
 			&machineID,
 			&exercise.Name,
 			&exercise.CreatedAt,
+			&groupID,
+			&groupType,
+			&catalogID,
 			&gymName,
 			&machineName,
+			&catalogName,
 		); err != nil {
 			return pagination.Paginated[models.Exercise]{}, fmt.Errorf("failed to scan exercise: %w", err)
 		}
 
 		exercise.CreatedAt = exercise.CreatedAt.UTC()
+		exercise.Group = groupFromColumns(groupID, groupType)
 
+		if workoutID.Valid {
+			value := workoutID.String
+			exercise.WorkoutID = &value
+		}
 		if gymID.Valid {
 			value := gymID.String
 			exercise.GymID = &value
@@ -187,6 +464,14 @@ func (s *Store) ListByDay(userID string, day time.Time, limit int, cursor *pagin
 			value := machineName.String
 			exercise.MachineName = &value
 		}
+		if catalogID.Valid {
+			value := catalogID.String
+			exercise.CatalogID = &value
+		}
+		if catalogName.Valid {
+			value := catalogName.String
+			exercise.CatalogName = &value
+		}
 
 		sets, err := s.getSetsForExercise(exercise.ID)
 		if err != nil {
@@ -211,10 +496,69 @@ func (s *Store) ListByDay(userID string, day time.Time, limit int, cursor *pagin
 	return page, nil
 }
 
+// InsertTx writes an exercise and its sets inside an existing transaction so
+// other stores can log exercises atomically with their own rows. The caller
+// assigns ID, UserID and CreatedAt.
+func InsertTx(tx *sql.Tx, exercise *models.Exercise) error {
+	var gymRef interface{}
+	if exercise.GymID != nil {
+		gymRef = *exercise.GymID
+	}
+
+	var machineRef interface{}
+	if exercise.MachineID != nil {
+		machineRef = *exercise.MachineID
+	}
+
+	var workoutRef interface{}
+	if exercise.WorkoutID != nil {
+		workoutRef = *exercise.WorkoutID
+	}
+
+	var catalogRef interface{}
+	if exercise.CatalogID != nil {
+		catalogRef = *exercise.CatalogID
+	}
+
+	groupID, groupType := groupColumns(exercise.Group)
+
+	if _, err := tx.Exec(`
+		INSERT INTO exercises (id, user_id, workout_id, gym_id, machine_id, catalog_id, name, group_id, group_type, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, exercise.ID, exercise.UserID, workoutRef, gymRef, machineRef, catalogRef, exercise.Name, groupID, groupType, exercise.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create exercise: %w", err)
+	}
+
+	return insertSets(tx, exercise.ID, exercise.Sets)
+}
+
+func insertSets(tx *sql.Tx, exerciseID string, sets []models.Set) error {
+	for i := range sets {
+		sets[i].ID = uuid.New().String()
+		sets[i].ExerciseID = exerciseID
+		sets[i].SetIndex = i + 1
+		if sets[i].Type == "" {
+			sets[i].Type = models.SetTypeWorking
+		}
+
+		set := sets[i]
+
+		setQuery := `
+			INSERT INTO sets (id, exercise_id, set_index, reps, weight_kg, rpe, notes, set_type, rest_seconds, duration_seconds, distance_meters)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`
+
+		if _, err := tx.Exec(setQuery, set.ID, set.ExerciseID, set.SetIndex, set.Reps, set.WeightKg, set.RPE, set.Notes, set.Type, set.RestSeconds, set.DurationSeconds, set.DistanceMeters); err != nil {
+			return fmt.Errorf("failed to create set: %w", err)
+		}
+	}
+	return nil
+}
+
 // getSetsForExercise retrieves all sets for a specific exercise
 func (s *Store) getSetsForExercise(exerciseID string) ([]models.Set, error) {
 	query := `
-		SELECT id, exercise_id, set_index, reps, weight_kg, rpe, notes
+		SELECT id, exercise_id, set_index, reps, weight_kg, rpe, notes, set_type, rest_seconds, duration_seconds, distance_meters
 		FROM sets
 		WHERE exercise_id = $1
 		ORDER BY set_index
@@ -233,6 +577,7 @@ func (s *Store) getSetsForExercise(exerciseID string) ([]models.Set, error) {
 		err := rows.Scan(
 			&set.ID, &set.ExerciseID, &set.SetIndex, &set.Reps, 
 			&set.WeightKg, &set.RPE, &set.Notes,
+			&set.Type, &set.RestSeconds, &set.DurationSeconds, &set.DistanceMeters,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan set: %w", err)
@@ -244,51 +589,138 @@ func (s *Store) getSetsForExercise(exerciseID string) ([]models.Set, error) {
 	return sets, nil
 }
 
+// attachSets loads the sets of every exercise in one query and fills them in,
+// ordered by set_index.
+func (s *Store) attachSets(items []models.Exercise) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, exercise_id, set_index, reps, weight_kg, rpe, notes, set_type, rest_seconds, duration_seconds, distance_meters
+		FROM sets
+		WHERE exercise_id = ANY($1::uuid[])
+		ORDER BY exercise_id, set_index
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query sets: %w", err)
+	}
+	defer rows.Close()
+
+	byExercise := make(map[string][]models.Set, len(items))
+	for rows.Next() {
+		var set models.Set
+		if err := rows.Scan(
+			&set.ID, &set.ExerciseID, &set.SetIndex, &set.Reps,
+			&set.WeightKg, &set.RPE, &set.Notes,
+			&set.Type, &set.RestSeconds, &set.DurationSeconds, &set.DistanceMeters,
+		); err != nil {
+			return fmt.Errorf("failed to scan set: %w", err)
+		}
+		byExercise[set.ExerciseID] = append(byExercise[set.ExerciseID], set)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("set rows error: %w", err)
+	}
+
+	for i := range items {
+		items[i].Sets = byExercise[items[i].ID]
+	}
+	return nil
+}
+
+// GetSet retrieves a single set owned by the user
+func (s *Store) GetSet(id, userID string) (*models.Set, error) {
+	var set models.Set
+	err := s.db.QueryRow(`
+		SELECT s.id, s.exercise_id, s.set_index, s.reps, s.weight_kg, s.rpe, s.notes, s.set_type, s.rest_seconds, s.duration_seconds, s.distance_meters
+		FROM sets s
+		JOIN exercises e ON e.id = s.exercise_id
+		WHERE s.id = $1 AND e.user_id = $2
+	`, id, userID).Scan(
+		&set.ID, &set.ExerciseID, &set.SetIndex, &set.Reps,
+		&set.WeightKg, &set.RPE, &set.Notes,
+		&set.Type, &set.RestSeconds, &set.DurationSeconds, &set.DistanceMeters,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get set: %w", err)
+	}
+	return &set, nil
+}
+
 // GetByID retrieves an exercise by ID with its sets
 func (s *Store) GetByID(id, userID string) (*models.Exercise, error) {
 	query := `
 		SELECT 
 			e.id,
 			e.user_id,
+			e.workout_id,
 			e.gym_id,
 			e.machine_id,
 			e.name,
 			e.created_at,
+			e.group_id,
+			e.group_type,
+			e.catalog_id,
 			g.name AS gym_name,
-			m.name AS machine_name
+			m.name AS machine_name,
+			c.name AS catalog_name
 		FROM exercises e
 		LEFT JOIN gyms g ON e.gym_id = g.id
 		LEFT JOIN machines m ON e.machine_id = m.id
+		LEFT JOIN exercise_catalog c ON e.catalog_id = c.id
 		WHERE e.id = $1 AND e.user_id = $2
 	`
 
 	var (
 		exercise    models.Exercise
+		workoutID   sql.NullString
 		gymID       sql.NullString
 		machineID   sql.NullString
+		groupID     sql.NullString
+		groupType   sql.NullString
+		catalogID   sql.NullString
 		gymName     sql.NullString
 		machineName sql.NullString
+		catalogName sql.NullString
 	)
 
 	err := s.db.QueryRow(query, id, userID).Scan(
 		&exercise.ID,
 		&exercise.UserID,
+		&workoutID,
 		&gymID,
 		&machineID,
 		&exercise.Name,
 		&exercise.CreatedAt,
+		&groupID,
+		&groupType,
+		&catalogID,
 		&gymName,
 		&machineName,
+		&catalogName,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("exercise not found")
+			return nil, ErrExerciseNotFound
 		}
 		return nil, fmt.Errorf("failed to get exercise: %w", err)
 	}
 
 	exercise.CreatedAt = exercise.CreatedAt.UTC()
+	exercise.Group = groupFromColumns(groupID, groupType)
 
+	if workoutID.Valid {
+		value := workoutID.String
+		exercise.WorkoutID = &value
+	}
 	if gymID.Valid {
 		value := gymID.String
 		exercise.GymID = &value
@@ -305,6 +737,14 @@ func (s *Store) GetByID(id, userID string) (*models.Exercise, error) {
 		value := machineName.String
 		exercise.MachineName = &value
 	}
+	if catalogID.Valid {
+		value := catalogID.String
+		exercise.CatalogID = &value
+	}
+	if catalogName.Valid {
+		value := catalogName.String
+		exercise.CatalogName = &value
+	}
 
 	sets, err := s.getSetsForExercise(exercise.ID)
 	if err != nil {
@@ -314,3 +754,17 @@ func (s *Store) GetByID(id, userID string) (*models.Exercise, error) {
 
 	return &exercise, nil
 }
+
+func groupColumns(group *models.ExerciseGroup) (interface{}, interface{}) {
+	if group == nil {
+		return nil, nil
+	}
+	return group.ID, group.Type
+}
+
+func groupFromColumns(groupID, groupType sql.NullString) *models.ExerciseGroup {
+	if !groupID.Valid || !groupType.Valid {
+		return nil
+	}
+	return &models.ExerciseGroup{ID: groupID.String, Type: groupType.String}
+}
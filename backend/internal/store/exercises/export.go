@@ -0,0 +1,118 @@
+package exercises
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// StreamSets calls fn for every set the user logged in [from, to), oldest
+// first. Rows are read one at a time so large logs never sit in memory.
+// Weights are in kilograms. Returning an error from fn stops the stream.
+func (s *Store) StreamSets(userID string, from, to *time.Time, fn func(models.SetExportRow) error) error {
+	query := `
+		SELECT
+			e.created_at,
+			e.workout_id,
+			e.id,
+			e.name,
+			e.machine_id,
+			m.name,
+			e.gym_id,
+			g.name,
+			e.group_id,
+			e.group_type,
+			s.set_index,
+			s.set_type,
+			s.reps,
+			s.weight_kg,
+			s.rpe,
+			s.rest_seconds,
+			s.duration_seconds,
+			s.distance_meters,
+			s.notes
+		FROM exercises e
+		JOIN sets s ON s.exercise_id = e.id
+		LEFT JOIN machines m ON e.machine_id = m.id
+		LEFT JOIN gyms g ON e.gym_id = g.id
+		WHERE e.user_id = $1
+	`
+	args := []interface{}{userID}
+	if from != nil {
+		args = append(args, *from)
+		query += fmt.Sprintf(" AND e.created_at >= $%d", len(args))
+	}
+	if to != nil {
+		args = append(args, *to)
+		query += fmt.Sprintf(" AND e.created_at < $%d", len(args))
+	}
+	query += " ORDER BY e.created_at ASC, e.id ASC, s.set_index ASC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query sets for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row         models.SetExportRow
+			workoutID   sql.NullString
+			machineID   sql.NullString
+			machineName sql.NullString
+			gymID       sql.NullString
+			gymName     sql.NullString
+			groupID     sql.NullString
+			groupType   sql.NullString
+		)
+		if err := rows.Scan(
+			&row.PerformedAt, &workoutID, &row.ExerciseID, &row.ExerciseName,
+			&machineID, &machineName, &gymID, &gymName, &groupID, &groupType,
+			&row.SetIndex, &row.SetType, &row.Reps, &row.Weight, &row.RPE,
+			&row.RestSeconds, &row.DurationSeconds, &row.DistanceMeters, &row.Notes,
+		); err != nil {
+			return fmt.Errorf("failed to scan set for export: %w", err)
+		}
+		row.PerformedAt = row.PerformedAt.UTC()
+		row.Unit = models.UnitKg
+		if workoutID.Valid {
+			value := workoutID.String
+			row.WorkoutID = &value
+		}
+		if machineID.Valid {
+			value := machineID.String
+			row.MachineID = &value
+		}
+		if machineName.Valid {
+			value := machineName.String
+			row.MachineName = &value
+		}
+		if gymID.Valid {
+			value := gymID.String
+			row.GymID = &value
+		}
+		if gymName.Valid {
+			value := gymName.String
+			row.GymName = &value
+		}
+		if groupID.Valid {
+			value := groupID.String
+			row.GroupID = &value
+		}
+		if groupType.Valid {
+			value := groupType.String
+			row.GroupType = &value
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export rows error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package exercises
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// e1rmExpression mirrors strength.EstimatedOneRepMax: Brzycki up to ten reps,
+// Epley above that.
+const e1rmExpression = `
+	CASE
+		WHEN s.weight_kg IS NULL OR s.weight_kg <= 0 OR s.reps <= 0 THEN NULL
+		WHEN s.reps = 1 THEN s.weight_kg
+		WHEN s.reps <= 10 THEN s.weight_kg * 36 / (37 - s.reps)
+		ELSE s.weight_kg * (1 + s.reps / 30.0)
+	END
+`
+
+// progressRow is one aggregated (series, period) bucket returned by the database.
+type progressRow struct {
+	key      string
+	label    string
+	period   time.Time
+	volume   float64
+	sets     int
+	bestE1RM *float64
+}
+
+// Progress aggregates volume, set counts and best e1RM per series and period
+// for exercises performed in [query.From, query.To).
+func (s *Store) Progress(userID string, query models.ProgressQuery) (*models.ProgressReport, error) {
+	var keyExpr, labelExpr string
+	switch query.GroupBy {
+	case models.ProgressGroupMachine:
+		keyExpr = `COALESCE('machine:' || e.machine_id::text, 'name:' || LOWER(regexp_replace(BTRIM(e.name), '\s+', ' ', 'g')))`
+		labelExpr = `MIN(COALESCE(m.name, e.name))`
+	case models.ProgressGroupBodyPart:
+		keyExpr = `COALESCE(LOWER(m.body_part), 'other')`
+		labelExpr = `MIN(COALESCE(m.body_part, 'Other'))`
+	case models.ProgressGroupTotal:
+		keyExpr = `'total'`
+		labelExpr = `'Total'`
+	default:
+		return nil, fmt.Errorf("unsupported progress grouping %q", query.GroupBy)
+	}
+
+	if query.Interval != models.ProgressIntervalWeek && query.Interval != models.ProgressIntervalMonth {
+		return nil, fmt.Errorf("unsupported progress interval %q", query.Interval)
+	}
+
+	filters := ""
+	args := []interface{}{userID, query.From.UTC(), query.To.UTC(), query.Interval}
+	if query.MachineID != nil {
+		args = append(args, *query.MachineID)
+		filters += fmt.Sprintf(" AND e.machine_id = $%d", len(args))
+	}
+	if query.BodyPart != nil {
+		args = append(args, *query.BodyPart)
+		filters += fmt.Sprintf(" AND LOWER(m.body_part) = LOWER($%d)", len(args))
+	}
+
+	// Range scans use idx_exercises_user_created; working sets are read from the
+	// covering idx_sets_exercise_working. Warm-ups never count toward progress.
+	sqlQuery := `
+		SELECT
+			` + keyExpr + ` AS series_key,
+			` + labelExpr + ` AS label,
+			date_trunc($4, e.created_at AT TIME ZONE 'UTC') AS period,
+			COUNT(s.id) AS set_count,
+			COALESCE(SUM(s.reps * COALESCE(s.weight_kg, 0)), 0) AS volume,
+			MAX(` + e1rmExpression + `) AS best_e1rm
+		FROM exercises e
+		JOIN sets s ON s.exercise_id = e.id
+		LEFT JOIN machines m ON m.id = e.machine_id
+		WHERE e.user_id = $1 AND e.created_at >= $2 AND e.created_at < $3 AND s.set_type <> 'warmup'` + filters + `
+		GROUP BY series_key, period
+		ORDER BY series_key, period
+	`
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query progress: %w", err)
+	}
+	defer rows.Close()
+
+	var items []progressRow
+	for rows.Next() {
+		var (
+			item     progressRow
+			bestE1RM sql.NullFloat64
+		)
+		if err := rows.Scan(&item.key, &item.label, &item.period, &item.sets, &item.volume, &bestE1RM); err != nil {
+			return nil, fmt.Errorf("failed to scan progress: %w", err)
+		}
+		item.period = time.Date(item.period.Year(), item.period.Month(), item.period.Day(), 0, 0, 0, 0, time.UTC)
+		if bestE1RM.Valid {
+			value := round2(bestE1RM.Float64)
+			item.bestE1RM = &value
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("progress rows error: %w", err)
+	}
+
+	report := buildProgressReport(items, query)
+	return &report, nil
+}
+
+// buildProgressReport turns sparse database buckets into chart-ready series
+// where every series has one point per period in the range.
+func buildProgressReport(items []progressRow, query models.ProgressQuery) models.ProgressReport {
+	periods := progressPeriods(query.From, query.To, query.Interval)
+	report := models.ProgressReport{
+		GroupBy:  query.GroupBy,
+		Interval: query.Interval,
+		From:     query.From.UTC(),
+		To:       query.To.UTC(),
+		Periods:  periods,
+		Series:   []models.ProgressSeries{},
+	}
+
+	index := make(map[time.Time]int, len(periods))
+	for i, period := range periods {
+		index[period] = i
+	}
+
+	seriesByKey := map[string]*models.ProgressSeries{}
+	var keys []string
+	for _, item := range items {
+		position, ok := index[item.period]
+		if !ok {
+			continue
+		}
+
+		series, ok := seriesByKey[item.key]
+		if !ok {
+			series = &models.ProgressSeries{
+				Key:    item.key,
+				Label:  item.label,
+				Points: make([]models.ProgressPoint, len(periods)),
+			}
+			for i, period := range periods {
+				series.Points[i].Period = period
+			}
+			seriesByKey[item.key] = series
+			keys = append(keys, item.key)
+		}
+
+		point := &series.Points[position]
+		point.VolumeKg = round2(point.VolumeKg + item.volume)
+		point.Sets += item.sets
+		point.BestE1RM = maxE1RM(point.BestE1RM, item.bestE1RM)
+
+		series.VolumeKg = round2(series.VolumeKg + item.volume)
+		series.Sets += item.sets
+		series.BestE1RM = maxE1RM(series.BestE1RM, item.bestE1RM)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		left, right := seriesByKey[keys[i]], seriesByKey[keys[j]]
+		if left.VolumeKg != right.VolumeKg {
+			return left.VolumeKg > right.VolumeKg
+		}
+		return left.Key < right.Key
+	})
+	for _, key := range keys {
+		report.Series = append(report.Series, *seriesByKey[key])
+	}
+
+	return report
+}
+
+// progressPeriods lists the start of every period overlapping [from, to).
+// Weeks start on Monday to match Postgres date_trunc('week').
+func progressPeriods(from, to time.Time, interval string) []time.Time {
+	var periods []time.Time
+	for period := periodStart(from, interval); period.Before(to); period = nextPeriod(period, interval) {
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == models.ProgressIntervalMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func nextPeriod(period time.Time, interval string) time.Time {
+	if interval == models.ProgressIntervalMonth {
+		return period.AddDate(0, 1, 0)
+	}
+	return period.AddDate(0, 0, 7)
+}
+
+func maxE1RM(current, candidate *float64) *float64 {
+	if candidate == nil {
+		return current
+	}
+	if current == nil || *candidate > *current {
+		value := *candidate
+		return &value
+	}
+	return current
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,84 @@
+package exercises
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestProgressPeriodsWeeksStartOnMonday(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) // Wednesday
+	to := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	periods := progressPeriods(from, to, models.ProgressIntervalWeek)
+
+	want := []time.Time{
+		time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+	}
+	if len(periods) != len(want) {
+		t.Fatalf("expected %d periods, got %v", len(want), periods)
+	}
+	for i := range want {
+		if !periods[i].Equal(want[i]) {
+			t.Fatalf("period %d: expected %v, got %v", i, want[i], periods[i])
+		}
+	}
+}
+
+func TestProgressPeriodsMonths(t *testing.T) {
+	from := time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	periods := progressPeriods(from, to, models.ProgressIntervalMonth)
+
+	if len(periods) != 3 || periods[0].Month() != time.December || periods[2].Month() != time.February {
+		t.Fatalf("unexpected monthly periods %v", periods)
+	}
+}
+
+func TestBuildProgressReportFillsGaps(t *testing.T) {
+	query := models.ProgressQuery{
+		GroupBy:  models.ProgressGroupMachine,
+		Interval: models.ProgressIntervalWeek,
+		From:     time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+	}
+	e1rm := 112.5
+	better := 120.0
+	items := []progressRow{
+		{key: "machine:a", label: "Leg Press", period: query.From, volume: 1000, sets: 3, bestE1RM: &e1rm},
+		{key: "machine:a", label: "Leg Press", period: query.From.AddDate(0, 0, 14), volume: 1200, sets: 4, bestE1RM: &better},
+		{key: "machine:b", label: "Row", period: query.From.AddDate(0, 0, 7), volume: 500, sets: 2},
+	}
+
+	report := buildProgressReport(items, query)
+
+	if len(report.Periods) != 3 {
+		t.Fatalf("expected 3 periods, got %d", len(report.Periods))
+	}
+	if len(report.Series) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(report.Series))
+	}
+
+	legPress := report.Series[0]
+	if legPress.Key != "machine:a" {
+		t.Fatalf("expected series ordered by volume, got %q first", legPress.Key)
+	}
+	if len(legPress.Points) != 3 || legPress.Points[1].Sets != 0 || legPress.Points[1].BestE1RM != nil {
+		t.Fatalf("expected an empty middle week, got %+v", legPress.Points)
+	}
+	if legPress.VolumeKg != 2200 || legPress.Sets != 7 {
+		t.Fatalf("unexpected totals %+v", legPress)
+	}
+	if legPress.BestE1RM == nil || *legPress.BestE1RM != 120 {
+		t.Fatalf("expected best e1rm 120, got %v", legPress.BestE1RM)
+	}
+
+	row := report.Series[1]
+	if row.Points[0].Sets != 0 || row.Points[1].Sets != 2 || row.BestE1RM != nil {
+		t.Fatalf("unexpected row series %+v", row)
+	}
+}
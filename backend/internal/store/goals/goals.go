@@ -0,0 +1,415 @@
+package goals
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	definitions "fitonex/backend/internal/goals"
+	"fitonex/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	foreignKeyViolation = "23503"
+
+	// MaxActiveGoals caps how many goals a user can work towards at once.
+	MaxActiveGoals = 20
+)
+
+var (
+	// ErrGoalNotFound indicates the goal does not exist or belongs to another user
+	ErrGoalNotFound = errors.New("goal not found")
+	// ErrTooManyGoals indicates the user already has MaxActiveGoals active goals
+	ErrTooManyGoals = errors.New("too many active goals")
+	// ErrMachineNotFound indicates a lift goal references an unknown machine
+	ErrMachineNotFound = errors.New("machine not found")
+)
+
+const goalColumns = `id, user_id, goal_type, title, target::float8, machine_id, exercise_name, reps, weeks, deadline,
+	status, current_value::float8, progress_percent::float8, started_at, evaluated_at, completed_at, created_at, updated_at`
+
+// Store handles goal database operations. It is also the activity source goals
+// are evaluated against.
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new goals store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create saves a validated goal as active, starting now, and evaluates it
+// against activity logged since the start of today.
+func (s *Store) Create(goal *models.Goal) error {
+	now := time.Now().UTC()
+	goal.ID = uuid.New().String()
+	goal.Status = models.GoalStatusActive
+	goal.StartedAt = now
+	goal.CreatedAt = now
+	goal.UpdatedAt = now
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize goal creation per user so the active goal cap holds.
+	if _, err := tx.Exec(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, goal.UserID); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	var active int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM goals WHERE user_id = $1 AND status = $2`, goal.UserID, models.GoalStatusActive).Scan(&active); err != nil {
+		return fmt.Errorf("failed to count goals: %w", err)
+	}
+	if active >= MaxActiveGoals {
+		return ErrTooManyGoals
+	}
+
+	if _, err := definitions.Evaluate(goal, s, now); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO goals (
+			id, user_id, goal_type, title, target, machine_id, exercise_name, reps, weeks, deadline,
+			status, current_value, progress_percent, started_at, evaluated_at, completed_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $17)
+	`
+	if _, err := tx.Exec(query, goal.ID, goal.UserID, goal.Type, goal.Title, goal.Target, goal.MachineID, goal.ExerciseName,
+		goal.Reps, goal.Weeks, goal.Deadline, goal.Status, goal.Current, goal.ProgressPercent, goal.StartedAt,
+		goal.EvaluatedAt, goal.CompletedAt, now); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrMachineNotFound
+		}
+		return fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns one of the user's goals or ErrGoalNotFound
+func (s *Store) GetByID(userID, id string) (*models.Goal, error) {
+	goal, err := scanGoal(s.db.QueryRow(`SELECT `+goalColumns+` FROM goals WHERE id = $1 AND user_id = $2`, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+	return goal, nil
+}
+
+// ListByUser returns the user's goals grouped by status. Active goals are
+// ordered by deadline, then newest first; finished goals most recent first.
+func (s *Store) ListByUser(userID string) (models.GoalList, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM goals
+		WHERE user_id = $1
+		ORDER BY deadline ASC NULLS LAST, COALESCE(completed_at, created_at) DESC, id DESC
+	`
+	goals, err := s.queryGoals(query, userID)
+	if err != nil {
+		return models.GoalList{}, err
+	}
+
+	list := models.GoalList{Active: []models.Goal{}, Completed: []models.Goal{}, Expired: []models.Goal{}}
+	for _, goal := range goals {
+		switch goal.Status {
+		case models.GoalStatusCompleted:
+			list.Completed = append(list.Completed, goal)
+		case models.GoalStatusExpired:
+			list.Expired = append(list.Expired, goal)
+		default:
+			list.Active = append(list.Active, goal)
+		}
+	}
+	return list, nil
+}
+
+// Update saves a goal's editable fields. An expired goal whose deadline moved
+// into the future becomes active again, and its progress is re-evaluated.
+// It reports whether the goal was completed by the update.
+func (s *Store) Update(goal *models.Goal) (bool, error) {
+	now := time.Now().UTC()
+	if goal.Status == models.GoalStatusExpired && (goal.Deadline == nil || now.Before(goal.Deadline.AddDate(0, 0, 1))) {
+		goal.Status = models.GoalStatusActive
+	}
+	completed, err := definitions.Evaluate(goal, s, now)
+	if err != nil {
+		return false, err
+	}
+	goal.UpdatedAt = now
+
+	query := `
+		UPDATE goals
+		SET title = $1, target = $2, reps = $3, weeks = $4, deadline = $5, status = $6,
+			current_value = $7, progress_percent = $8, evaluated_at = $9, completed_at = $10, updated_at = $11
+		WHERE id = $12 AND user_id = $13 AND status <> $14
+	`
+	result, err := s.db.Exec(query, goal.Title, goal.Target, goal.Reps, goal.Weeks, goal.Deadline, goal.Status,
+		goal.Current, goal.ProgressPercent, goal.EvaluatedAt, goal.CompletedAt, now, goal.ID, goal.UserID, models.GoalStatusCompleted)
+	if err != nil {
+		return false, fmt.Errorf("failed to update goal: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update goal: %w", err)
+	}
+	if affected == 0 {
+		return false, ErrGoalNotFound
+	}
+	return completed, nil
+}
+
+// Delete removes one of the user's goals
+func (s *Store) Delete(userID, id string) error {
+	result, err := s.db.Exec(`DELETE FROM goals WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	if affected == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// Evaluate recomputes the progress of the user's active goals and returns the
+// ones completed by this call. A goal completed concurrently elsewhere is only
+// returned once, so completion events are not duplicated.
+func (s *Store) Evaluate(userID string, now time.Time) ([]models.Goal, error) {
+	active, err := s.queryGoals(`SELECT `+goalColumns+` FROM goals WHERE user_id = $1 AND status = $2`, userID, models.GoalStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []models.Goal
+	for i := range active {
+		goal := &active[i]
+		done, err := definitions.Evaluate(goal, s, now)
+		if err != nil {
+			return nil, err
+		}
+
+		query := `
+			UPDATE goals
+			SET status = $1, current_value = $2, progress_percent = $3, evaluated_at = $4, completed_at = $5
+			WHERE id = $6 AND status = $7
+		`
+		result, err := s.db.Exec(query, goal.Status, goal.Current, goal.ProgressPercent, goal.EvaluatedAt, goal.CompletedAt, goal.ID, models.GoalStatusActive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save goal progress: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to save goal progress: %w", err)
+		}
+		if done && affected > 0 {
+			completed = append(completed, *goal)
+		}
+	}
+	return completed, nil
+}
+
+// UsersDue returns users with active goals, least recently evaluated first.
+func (s *Store) UsersDue(limit int) ([]string, error) {
+	query := `
+		SELECT user_id
+		FROM goals
+		WHERE status = $1
+		GROUP BY user_id
+		ORDER BY MIN(COALESCE(evaluated_at, 'epoch'::timestamptz)) ASC
+		LIMIT $2
+	`
+	rows, err := s.db.Query(query, models.GoalStatusActive, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goal users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan goal user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("goal user rows error: %w", err)
+	}
+	return userIDs, nil
+}
+
+// TrainingDays implements goals.Source. A day counts when a workout was
+// performed or an exercise was logged on it.
+func (s *Store) TrainingDays(userID string, since time.Time) ([]time.Time, error) {
+	query := `
+		SELECT (created_at AT TIME ZONE 'UTC')::date AS day FROM workouts WHERE user_id = $1 AND created_at >= $2 AND NOT planned
+		UNION
+		SELECT (created_at AT TIME ZONE 'UTC')::date FROM exercises WHERE user_id = $1 AND created_at >= $2
+		ORDER BY day
+	`
+	return s.queryDays(query, userID, since)
+}
+
+// WorkoutCount implements goals.Source. Planned workouts are not counted.
+func (s *Store) WorkoutCount(userID string, since time.Time) (int, error) {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM workouts WHERE user_id = $1 AND created_at >= $2 AND NOT planned`, userID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count workouts: %w", err)
+	}
+	return count, nil
+}
+
+// CheckinDays implements goals.Source.
+func (s *Store) CheckinDays(userID string, since time.Time) ([]time.Time, error) {
+	return s.queryDays(`SELECT day FROM checkins WHERE user_id = $1 AND day >= $2 ORDER BY day`, userID, since)
+}
+
+// BestLift implements goals.Source. It returns the heaviest working set of at
+// least minReps reps on the machine, or on the named exercise when no machine
+// is given, or zero.
+func (s *Store) BestLift(userID string, machineID *string, name string, minReps int, since time.Time) (float64, error) {
+	keyFilter := "e.machine_id = $2"
+	args := []interface{}{userID}
+	if machineID != nil && *machineID != "" {
+		args = append(args, *machineID)
+	} else {
+		keyFilter = `e.machine_id IS NULL
+			AND LOWER(regexp_replace(BTRIM(e.name), '\s+', ' ', 'g')) = LOWER(regexp_replace(BTRIM($2), '\s+', ' ', 'g'))`
+		args = append(args, name)
+	}
+	args = append(args, since, minReps, models.SetTypeWarmup)
+
+	query := `
+		SELECT COALESCE(MAX(s.weight_kg), 0)::float8
+		FROM sets s
+		JOIN exercises e ON e.id = s.exercise_id
+		WHERE e.user_id = $1 AND ` + keyFilter + `
+			AND e.created_at >= $3 AND s.reps >= $4 AND s.weight_kg IS NOT NULL AND s.set_type <> $5
+	`
+	var best float64
+	if err := s.db.QueryRow(query, args...).Scan(&best); err != nil {
+		return 0, fmt.Errorf("failed to query best lift: %w", err)
+	}
+	return best, nil
+}
+
+func (s *Store) ExportByUser(userID string) ([]models.Goal, error) {
+	return s.queryGoals(`SELECT `+goalColumns+` FROM goals WHERE user_id = $1 ORDER BY created_at, id`, userID)
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM goals WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete goals: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryDays(query string, args ...interface{}) ([]time.Time, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan activity day: %w", err)
+		}
+		days = append(days, day.UTC())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("activity day rows error: %w", err)
+	}
+	return days, nil
+}
+
+func (s *Store) queryGoals(query string, args ...interface{}) ([]models.Goal, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []models.Goal
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, *goal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("goal rows error: %w", err)
+	}
+	return goals, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGoal(row rowScanner) (*models.Goal, error) {
+	var (
+		goal         models.Goal
+		machineID    sql.NullString
+		exerciseName sql.NullString
+		reps         sql.NullInt64
+		weeks        sql.NullInt64
+		deadline     sql.NullTime
+		evaluatedAt  sql.NullTime
+		completedAt  sql.NullTime
+	)
+	if err := row.Scan(&goal.ID, &goal.UserID, &goal.Type, &goal.Title, &goal.Target, &machineID, &exerciseName, &reps, &weeks, &deadline,
+		&goal.Status, &goal.Current, &goal.ProgressPercent, &goal.StartedAt, &evaluatedAt, &completedAt, &goal.CreatedAt, &goal.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if machineID.Valid {
+		value := machineID.String
+		goal.MachineID = &value
+	}
+	if exerciseName.Valid {
+		value := exerciseName.String
+		goal.ExerciseName = &value
+	}
+	if reps.Valid {
+		value := int(reps.Int64)
+		goal.Reps = &value
+	}
+	if weeks.Valid {
+		value := int(weeks.Int64)
+		goal.Weeks = &value
+	}
+	if deadline.Valid {
+		value := deadline.Time.UTC()
+		goal.Deadline = &value
+	}
+	if evaluatedAt.Valid {
+		value := evaluatedAt.Time
+		goal.EvaluatedAt = &value
+	}
+	if completedAt.Valid {
+		value := completedAt.Time
+		goal.CompletedAt = &value
+	}
+	goal.Unit = definitions.Unit(goal.Type)
+	return &goal, nil
+}
@@ -0,0 +1,238 @@
+package gyms
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrClaimNotFound is returned when a claim does not exist.
+	ErrClaimNotFound = errors.New("claim not found")
+	// ErrClaimPending is returned when the user already has a pending claim on the gym.
+	ErrClaimPending = errors.New("claim already pending")
+	// ErrClaimResolved is returned when reviewing a claim that was already approved or rejected.
+	ErrClaimResolved = errors.New("claim already reviewed")
+	// ErrGymOwned is returned when claiming or approving a claim on a gym that already has an owner.
+	ErrGymOwned = errors.New("gym already has an owner")
+)
+
+const claimColumns = `
+	c.id, c.gym_id, c.user_id, c.status, c.message, c.reviewer_id, c.review_note, c.reviewed_at, c.created_at,
+	g.name, u.name`
+
+const claimJoins = `
+	FROM gym_claims c
+	JOIN gyms g ON g.id = c.gym_id
+	JOIN users u ON u.id = c.user_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// CreateClaim files a pending ownership claim. Claims on a gym that already
+// has an owner return ErrGymOwned.
+func (s *Store) CreateClaim(gymID, userID string, message *string) (*models.GymClaim, error) {
+	owner, err := s.OwnerID(gymID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		return nil, ErrGymOwned
+	}
+
+	claim := &models.GymClaim{
+		ID:        uuid.New().String(),
+		GymID:     gymID,
+		UserID:    userID,
+		Status:    models.GymClaimPending,
+		Message:   message,
+		CreatedAt: time.Now().UTC(),
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO gym_claims (id, gym_id, user_id, status, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, claim.ID, gymID, userID, claim.Status, message, claim.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case uniqueViolation:
+				return nil, ErrClaimPending
+			case foreignKeyViolation:
+				return nil, ErrGymNotFound
+			}
+		}
+		return nil, fmt.Errorf("failed to create claim: %w", err)
+	}
+
+	return claim, nil
+}
+
+// GetClaim retrieves a claim by ID
+func (s *Store) GetClaim(id string) (*models.GymClaim, error) {
+	claim, err := scanClaim(s.db.QueryRow(`SELECT `+claimColumns+claimJoins+` WHERE c.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrClaimNotFound
+		}
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+	return claim, nil
+}
+
+// ListClaims returns claims with the given status, oldest first so the
+// review queue is worked in order. An empty status lists every claim.
+func (s *Store) ListClaims(status string, limit int) ([]models.GymClaim, error) {
+	rows, err := s.db.Query(`SELECT `+claimColumns+claimJoins+`
+		WHERE ($1 = '' OR c.status = $1)
+		ORDER BY c.created_at ASC, c.id ASC
+		LIMIT $2
+	`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	return collectClaims(rows)
+}
+
+// ListClaimsByUser returns a user's claims, newest first.
+func (s *Store) ListClaimsByUser(userID string) ([]models.GymClaim, error) {
+	rows, err := s.db.Query(`SELECT `+claimColumns+claimJoins+`
+		WHERE c.user_id = $1
+		ORDER BY c.created_at DESC, c.id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	return collectClaims(rows)
+}
+
+// ReviewClaim approves or rejects a pending claim. Approving makes the
+// claimant the gym's owner and rejects the other pending claims on the gym.
+func (s *Store) ReviewClaim(id, reviewerID string, approve bool, note *string) (*models.GymClaim, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var gymID, userID, status string
+	err = tx.QueryRow(`SELECT gym_id, user_id, status FROM gym_claims WHERE id = $1 FOR UPDATE`, id).Scan(&gymID, &userID, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrClaimNotFound
+		}
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+	if status != models.GymClaimPending {
+		return nil, ErrClaimResolved
+	}
+
+	now := time.Now().UTC()
+	status = models.GymClaimRejected
+	if approve {
+		status = models.GymClaimApproved
+		result, err := tx.Exec(`
+			UPDATE gyms SET owner_id = $1, updated_at = $2
+			WHERE id = $3 AND (owner_id IS NULL OR owner_id = $1)
+		`, userID, now, gymID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set gym owner: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return nil, ErrGymOwned
+		}
+		_, err = tx.Exec(`
+			UPDATE gym_claims
+			SET status = 'rejected', reviewer_id = $1, review_note = 'another claim was approved', reviewed_at = $2
+			WHERE gym_id = $3 AND status = 'pending' AND id <> $4
+		`, reviewerID, now, gymID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to close competing claims: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE gym_claims SET status = $1, reviewer_id = $2, review_note = $3, reviewed_at = $4
+		WHERE id = $5
+	`, status, reviewerID, note, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review claim: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit claim review: %w", err)
+	}
+
+	return s.GetClaim(id)
+}
+
+func (s *Store) ExportClaimsByUser(userID string) ([]models.GymClaim, error) {
+	return s.ListClaimsByUser(userID)
+}
+
+func (s *Store) DeleteClaimsByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM gym_claims WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete claims: %w", err)
+	}
+	if _, err := s.db.Exec(`UPDATE gyms SET owner_id = NULL, updated_at = NOW() WHERE owner_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to release owned gyms: %w", err)
+	}
+	return nil
+}
+
+func collectClaims(rows *sql.Rows) ([]models.GymClaim, error) {
+	defer rows.Close()
+
+	claims := []models.GymClaim{}
+	for rows.Next() {
+		claim, err := scanClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claim: %w", err)
+		}
+		claims = append(claims, *claim)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate claims: %w", err)
+	}
+	return claims, nil
+}
+
+func scanClaim(row rowScanner) (*models.GymClaim, error) {
+	var (
+		claim      models.GymClaim
+		message    sql.NullString
+		reviewerID sql.NullString
+		reviewNote sql.NullString
+		reviewedAt sql.NullTime
+	)
+	err := row.Scan(
+		&claim.ID, &claim.GymID, &claim.UserID, &claim.Status, &message, &reviewerID, &reviewNote, &reviewedAt, &claim.CreatedAt,
+		&claim.GymName, &claim.UserName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if message.Valid {
+		value := message.String
+		claim.Message = &value
+	}
+	if reviewerID.Valid {
+		value := reviewerID.String
+		claim.ReviewerID = &value
+	}
+	if reviewNote.Valid {
+		value := reviewNote.String
+		claim.ReviewNote = &value
+	}
+	if reviewedAt.Valid {
+		value := reviewedAt.Time
+		claim.ReviewedAt = &value
+	}
+	return &claim, nil
+}
@@ -4,16 +4,21 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync"
 
 	"fitonex/backend/internal/models"
 	"fitonex/backend/internal/pagination"
 
-	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Store handles gym-related database operations
 type Store struct {
 	db *sql.DB
+
+	spatialMu      sync.Mutex
+	spatialChecked bool
+	earthIndex     bool
 }
 
 // New creates a new gyms store
@@ -22,13 +27,17 @@ func New(db *sql.DB) *Store {
 }
 
 // GetNearby retrieves gyms within a specified radius using Haversine formula with pagination.
-func (s *Store) GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor) (pagination.Paginated[models.NearbyGym], error) {
+// An indexed spatial prefilter limits the distance computation to nearby rows.
+func (s *Store) GetNearby(lat, lng, radiusKm float64, limit int, cursor *pagination.DistanceAscCursor, filter models.NearbyFilter) (pagination.Paginated[models.NearbyGym], error) {
 	if limit <= 0 {
 		return pagination.Paginated[models.NearbyGym]{}, pagination.ErrInvalidLimit
 	}
 
 	radiusMeters := radiusKm * 1000
 
+	args := []any{lat, lng, radiusMeters, limit + 1}
+	prefilter, args := s.spatialPrefilter(lat, lng, radiusMeters, args)
+
 	query := `
 WITH distance_base AS (
 	SELECT 
@@ -47,12 +56,12 @@ WITH distance_base AS (
 				)
 			)
 		) AS distance_m
-	FROM gyms g
+	FROM gyms g` + prefilter + `
 ),
 review_stats AS (
-	SELECT gym_id, AVG(rating)::float AS avg_rating
-	FROM gym_reviews
-	GROUP BY gym_id
+	SELECT gym_id, rating_sum::float / review_count AS avg_rating
+	FROM gym_review_stats
+	WHERE review_count > 0
 ),
 machine_stats AS (
 	SELECT gym_id, COUNT(*)::int AS machines_count
@@ -82,14 +91,14 @@ LEFT JOIN gym_price_cache pc ON pc.gym_id = b.id
 WHERE b.distance_m <= $3
 `
 
-	args := []any{lat, lng, radiusMeters, limit + 1}
 	if cursor != nil {
-		query += `
-	AND (
-		b.distance_m > $5 OR (b.distance_m = $5 AND b.id > $6)
-	)`
 		args = append(args, cursor.DistanceM, cursor.ID)
+		query += fmt.Sprintf(`
+	AND (
+		b.distance_m > $%d OR (b.distance_m = $%d AND b.id > $%d)
+	)`, len(args)-1, len(args)-1, len(args))
 	}
+	query, args = appendNearbyFilter(query, args, filter)
 
 	query += `
 ORDER BY b.distance_m ASC, b.id ASC
@@ -156,23 +165,94 @@ LIMIT $4
 	return page, nil
 }
 
+// appendNearbyFilter adds the filter's conditions to the nearby query. They
+// only narrow the candidate rows, so distance ordering and cursors are
+// unaffected.
+func appendNearbyFilter(query string, args []any, filter models.NearbyFilter) (string, []any) {
+	param := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(filter.MachineIDs) > 0 {
+		if filter.AnyMachine {
+			query += `
+	AND EXISTS (
+		SELECT 1 FROM gym_machines fm
+		WHERE fm.gym_id = b.id AND fm.machine_id = ANY(` + param(pq.Array(filter.MachineIDs)) + `::uuid[])
+	)`
+		} else {
+			query += `
+	AND (
+		SELECT COUNT(*) FROM gym_machines fm
+		WHERE fm.gym_id = b.id AND fm.machine_id = ANY(` + param(pq.Array(filter.MachineIDs)) + `::uuid[])
+	) = ` + param(len(filter.MachineIDs))
+		}
+	}
+
+	if len(filter.BodyParts) > 0 {
+		seen := make(map[string]bool, len(filter.BodyParts))
+		var bodyParts []string
+		for _, part := range filter.BodyParts {
+			part = strings.ToLower(strings.TrimSpace(part))
+			if part != "" && !seen[part] {
+				seen[part] = true
+				bodyParts = append(bodyParts, part)
+			}
+		}
+		query += `
+	AND (
+		SELECT COUNT(DISTINCT LOWER(fm.body_part))
+		FROM gym_machines fgm
+		JOIN machines fm ON fm.id = fgm.machine_id
+		WHERE fgm.gym_id = b.id AND LOWER(fm.body_part) = ANY(` + param(pq.Array(bodyParts)) + `)
+	) = ` + param(len(bodyParts))
+	}
+
+	if filter.MaxPriceCents != nil {
+		query += `
+	AND COALESCE(pc.price_from_cents, p.price_from_cents) <= ` + param(*filter.MaxPriceCents)
+	}
+
+	if filter.MinRating != nil {
+		query += `
+	AND r.avg_rating >= ` + param(*filter.MinRating)
+	}
+
+	if len(filter.Amenities) > 0 {
+		query += `
+	AND (
+		SELECT COUNT(*) FROM gym_amenities fa
+		WHERE fa.gym_id = b.id AND fa.amenity = ANY(` + param(pq.Array(filter.Amenities)) + `)
+	) = ` + param(len(filter.Amenities))
+	}
+
+	if filter.OpenAt != nil {
+		query += `
+	AND gym_open_at(b.id, ` + param(filter.OpenAt.UTC()) + `)`
+	}
+
+	return query, args
+}
+
 // GetByID retrieves a gym by ID
 func (s *Store) GetByID(id string) (*models.Gym, error) {
 	query := `
 	SELECT 
-		g.id, g.name, g.lat, g.lng, g.address, g.phone, g.website, g.created_at,
-		COALESCE(AVG(gr.rating), 0) as avg_rating,
-		COUNT(gr.id) as review_count,
+		g.id, g.name, g.lat, g.lng, g.address, g.phone, g.website, g.owner_id, g.created_at,
+		COALESCE(rs.rating_sum::float / NULLIF(rs.review_count, 0), 0) as avg_rating,
+		COALESCE(rs.review_count, 0) as review_count,
 		COUNT(DISTINCT gm.machine_id) as machine_count,
 		COALESCE(pc.price_from_cents,
 			(SELECT MIN(price_cents) FROM gym_prices WHERE gym_id = g.id)
-		) AS price_from_cents
+		) AS price_from_cents,
+		COALESCE((SELECT array_agg(amenity ORDER BY amenity) FROM gym_amenities WHERE gym_id = g.id), '{}') AS amenities
 	FROM gyms g
-	LEFT JOIN gym_reviews gr ON g.id = gr.gym_id
+	LEFT JOIN gym_review_stats rs ON rs.gym_id = g.id
 	LEFT JOIN gym_machines gm ON g.id = gm.gym_id
 	LEFT JOIN gym_price_cache pc ON pc.gym_id = g.id
 	WHERE g.id = $1
-	GROUP BY g.id, g.name, g.lat, g.lng, g.address, g.phone, g.website, g.created_at, pc.price_from_cents
+	GROUP BY g.id, g.name, g.lat, g.lng, g.address, g.phone, g.website, g.owner_id, g.created_at, pc.price_from_cents, rs.rating_sum, rs.review_count
 `
 
 	var gym models.Gym
@@ -182,12 +262,12 @@ func (s *Store) GetByID(id string) (*models.Gym, error) {
 
 	err := s.db.QueryRow(query, id).Scan(
 		&gym.ID, &gym.Name, &gym.Lat, &gym.Lng, &gym.Address,
-		&gym.Phone, &gym.Website, &gym.CreatedAt,
-		&avgRating, &reviewCount, &machineCount, &priceFrom,
+		&gym.Phone, &gym.Website, &gym.OwnerID, &gym.CreatedAt,
+		&avgRating, &reviewCount, &machineCount, &priceFrom, pq.Array(&gym.Amenities),
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("gym not found")
+			return nil, ErrGymNotFound
 		}
 		return nil, fmt.Errorf("failed to get gym: %w", err)
 	}
@@ -232,6 +312,7 @@ func (s *Store) GetMachines(gymID string) ([]models.Machine, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan machine: %w", err)
 		}
+		machine.Quantity = &quantity
 
 		machines = append(machines, machine)
 	}
@@ -271,116 +352,6 @@ func (s *Store) GetPrices(gymID string) ([]models.GymPrice, error) {
 	return prices, nil
 }
 
-// CreateReview creates a new gym review
-func (s *Store) CreateReview(gymID, userID string, rating int, comment string) (*models.GymReview, error) {
-	review := &models.GymReview{
-		ID:      uuid.New().String(),
-		GymID:   gymID,
-		UserID:  userID,
-		Rating:  rating,
-		Comment: &comment,
-	}
-
-	query := `
-		INSERT INTO gym_reviews (id, gym_id, user_id, rating, comment)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING created_at
-	`
-
-	err := s.db.QueryRow(query, review.ID, review.GymID, review.UserID, review.Rating, review.Comment).Scan(&review.CreatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create review: %w", err)
-	}
-
-	return review, nil
-}
-
-// GetReviews retrieves reviews for a gym with pagination
-func (s *Store) GetReviews(gymID, cursor string, limit int) ([]models.GymReview, string, error) {
-	var query string
-	var args []interface{}
-
-	if cursor == "" {
-		query = `
-			SELECT gr.id, gr.gym_id, gr.user_id, gr.rating, gr.comment, gr.created_at, u.name
-			FROM gym_reviews gr
-			JOIN users u ON gr.user_id = u.id
-			WHERE gr.gym_id = $1
-			ORDER BY gr.created_at DESC
-			LIMIT $2
-		`
-		args = []interface{}{gymID, limit + 1}
-	} else {
-		query = `
-			SELECT gr.id, gr.gym_id, gr.user_id, gr.rating, gr.comment, gr.created_at, u.name
-			FROM gym_reviews gr
-			JOIN users u ON gr.user_id = u.id
-			WHERE gr.gym_id = $1 AND gr.created_at < $2
-			ORDER BY gr.created_at DESC
-			LIMIT $3
-		`
-		args = []interface{}{gymID, cursor, limit + 1}
-	}
-
-	rows, err := s.db.Query(query, args...)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to query reviews: %w", err)
-	}
-	defer rows.Close()
-
-	var reviews []models.GymReview
-	var nextCursor string
-
-	for rows.Next() {
-		var review models.GymReview
-		var userName string
-
-		err := rows.Scan(
-			&review.ID, &review.GymID, &review.UserID, &review.Rating, 
-			&review.Comment, &review.CreatedAt, &userName,
-		)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to scan review: %w", err)
-		}
-
-		review.UserName = userName
-		reviews = append(reviews, review)
-	}
-
-	// Check if there are more results
-	if len(reviews) > limit {
-		nextCursor = reviews[limit-1].CreatedAt.Format("2006-01-02T15:04:05.999999Z07:00")
-		reviews = reviews[:limit]
-	}
-
-	return reviews, nextCursor, nil
-}
-
-func (s *Store) ExportReviewsByUser(userID string) ([]models.GymReview, error) {
-	rows, err := s.db.Query(`
-		SELECT id, gym_id, user_id, rating, comment, created_at
-		FROM gym_reviews WHERE user_id = $1 ORDER BY created_at DESC
-	`, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var reviews []models.GymReview
-	for rows.Next() {
-		var review models.GymReview
-		if err := rows.Scan(&review.ID, &review.GymID, &review.UserID, &review.Rating, &review.Comment, &review.CreatedAt); err != nil {
-			return nil, err
-		}
-		reviews = append(reviews, review)
-	}
-	return reviews, rows.Err()
-}
-
-func (s *Store) AnonymizeReviewsByUser(userID string) error {
-	_, err := s.db.Exec(`UPDATE gym_reviews SET comment = '[deleted]' WHERE user_id = $1`, userID)
-	return err
-}
-
 func (s *Store) Search(name string, limit int, cursor *pagination.ScoreDescCursor, prefix bool) (pagination.Paginated[models.GymSearchResult], error) {
 	if limit <= 0 {
 		return pagination.Paginated[models.GymSearchResult]{}, pagination.ErrInvalidLimit
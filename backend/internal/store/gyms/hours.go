@@ -0,0 +1,143 @@
+package gyms
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/gymhours"
+	"fitonex/backend/internal/models"
+)
+
+// GetHours retrieves a gym's opening hours. Holidays more than two days in
+// the past are left out; nothing earlier affects whether the gym is open
+// today in any timezone.
+func (s *Store) GetHours(gymID string) (*models.OpeningHours, error) {
+	hours := &models.OpeningHours{
+		Weekly:   map[string][]models.HoursInterval{},
+		Holidays: []models.GymHoliday{},
+	}
+	err := s.db.QueryRow(`SELECT timezone, open_24_7 FROM gyms WHERE id = $1`, gymID).Scan(&hours.Timezone, &hours.Open24x7)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGymNotFound
+		}
+		return nil, fmt.Errorf("failed to get gym hours: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT weekday, LEFT(opens_at::text, 5), LEFT(closes_at::text, 5)
+		FROM gym_hours
+		WHERE gym_id = $1
+		ORDER BY weekday, opens_at
+	`, gymID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gym hours: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var weekday int
+		var interval models.HoursInterval
+		if err := rows.Scan(&weekday, &interval.Opens, &interval.Closes); err != nil {
+			return nil, fmt.Errorf("failed to scan gym hours: %w", err)
+		}
+		key := gymhours.Weekdays[weekday]
+		hours.Weekly[key] = append(hours.Weekly[key], interval)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate gym hours: %w", err)
+	}
+
+	holidayRows, err := s.db.Query(`
+		SELECT h.day, h.name, LEFT(hh.opens_at::text, 5), LEFT(hh.closes_at::text, 5)
+		FROM gym_holidays h
+		LEFT JOIN gym_holiday_hours hh ON hh.gym_id = h.gym_id AND hh.day = h.day
+		WHERE h.gym_id = $1 AND h.day >= CURRENT_DATE - 2
+		ORDER BY h.day, hh.opens_at
+	`, gymID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gym holidays: %w", err)
+	}
+	defer holidayRows.Close()
+	for holidayRows.Next() {
+		var (
+			day           time.Time
+			name          string
+			opens, closes sql.NullString
+		)
+		if err := holidayRows.Scan(&day, &name, &opens, &closes); err != nil {
+			return nil, fmt.Errorf("failed to scan gym holiday: %w", err)
+		}
+		date := day.Format("2006-01-02")
+		if n := len(hours.Holidays); n == 0 || hours.Holidays[n-1].Date != date {
+			hours.Holidays = append(hours.Holidays, models.GymHoliday{Date: date, Name: name, Intervals: []models.HoursInterval{}})
+		}
+		if opens.Valid && closes.Valid {
+			holiday := &hours.Holidays[len(hours.Holidays)-1]
+			holiday.Intervals = append(holiday.Intervals, models.HoursInterval{Opens: opens.String, Closes: closes.String})
+		}
+	}
+	if err := holidayRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate gym holidays: %w", err)
+	}
+
+	return hours, nil
+}
+
+// SetHours replaces a gym's timezone, 24/7 flag, weekly schedule and
+// holidays. The hours must already have passed gymhours.Validate.
+func (s *Store) SetHours(gymID string, hours models.OpeningHours) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE gyms SET timezone = $1, open_24_7 = $2, updated_at = $3
+		WHERE id = $4
+	`, hours.Timezone, hours.Open24x7, time.Now().UTC(), gymID)
+	if err != nil {
+		return fmt.Errorf("failed to update gym hours: %w", err)
+	}
+	if err := expectRow(result); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM gym_hours WHERE gym_id = $1`, gymID); err != nil {
+		return fmt.Errorf("failed to clear gym hours: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM gym_holidays WHERE gym_id = $1`, gymID); err != nil {
+		return fmt.Errorf("failed to clear gym holidays: %w", err)
+	}
+
+	for day, intervals := range hours.Weekly {
+		weekday := int(gymhours.Weekday(day))
+		for _, interval := range intervals {
+			_, err := tx.Exec(`
+				INSERT INTO gym_hours (gym_id, weekday, opens_at, closes_at)
+				VALUES ($1, $2, $3::time, $4::time)
+			`, gymID, weekday, interval.Opens, interval.Closes)
+			if err != nil {
+				return fmt.Errorf("failed to save gym hours: %w", err)
+			}
+		}
+	}
+	for _, holiday := range hours.Holidays {
+		_, err := tx.Exec(`INSERT INTO gym_holidays (gym_id, day, name) VALUES ($1, $2::date, $3)`, gymID, holiday.Date, holiday.Name)
+		if err != nil {
+			return fmt.Errorf("failed to save gym holiday: %w", err)
+		}
+		for _, interval := range holiday.Intervals {
+			_, err := tx.Exec(`
+				INSERT INTO gym_holiday_hours (gym_id, day, opens_at, closes_at)
+				VALUES ($1, $2::date, $3::time, $4::time)
+			`, gymID, holiday.Date, interval.Opens, interval.Closes)
+			if err != nil {
+				return fmt.Errorf("failed to save gym holiday hours: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,240 @@
+package gyms
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+var (
+	// ErrGymNotFound is returned when a gym does not exist.
+	ErrGymNotFound = errors.New("gym not found")
+	// ErrPriceNotFound is returned when a gym has no plan with the given name.
+	ErrPriceNotFound = errors.New("price plan not found")
+	// ErrMachineNotFound is returned when a machine does not exist or is not in a gym's inventory.
+	ErrMachineNotFound = errors.New("machine not found")
+)
+
+// Create inserts a new gym.
+func (s *Store) Create(gym *models.Gym) error {
+	now := time.Now().UTC()
+	gym.ID = uuid.New().String()
+	gym.CreatedAt = now
+
+	_, err := s.db.Exec(`
+		INSERT INTO gyms (id, name, lat, lng, address, phone, website, owner_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`, gym.ID, gym.Name, gym.Lat, gym.Lng, gym.Address, gym.Phone, gym.Website, gym.OwnerID, now)
+	if err != nil {
+		return fmt.Errorf("failed to create gym: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a gym's details. Ownership is changed through claims.
+func (s *Store) Update(gym *models.Gym) error {
+	result, err := s.db.Exec(`
+		UPDATE gyms
+		SET name = $1, lat = $2, lng = $3, address = $4, phone = $5, website = $6, updated_at = $7
+		WHERE id = $8
+	`, gym.Name, gym.Lat, gym.Lng, gym.Address, gym.Phone, gym.Website, time.Now().UTC(), gym.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update gym: %w", err)
+	}
+	return expectRow(result)
+}
+
+// Delete removes a gym along with its prices, machines, reviews and claims.
+func (s *Store) Delete(id string) error {
+	result, err := s.db.Exec(`DELETE FROM gyms WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete gym: %w", err)
+	}
+	return expectRow(result)
+}
+
+// OwnerID returns the user who owns a gym, or nil when it is unclaimed.
+func (s *Store) OwnerID(gymID string) (*string, error) {
+	var owner sql.NullString
+	err := s.db.QueryRow(`SELECT owner_id FROM gyms WHERE id = $1`, gymID).Scan(&owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGymNotFound
+		}
+		return nil, fmt.Errorf("failed to get gym owner: %w", err)
+	}
+	if !owner.Valid {
+		return nil, nil
+	}
+	value := owner.String
+	return &value, nil
+}
+
+// ClearOwner releases a gym so it can be claimed again.
+func (s *Store) ClearOwner(gymID string) error {
+	result, err := s.db.Exec(`UPDATE gyms SET owner_id = NULL, updated_at = $1 WHERE id = $2`, time.Now().UTC(), gymID)
+	if err != nil {
+		return fmt.Errorf("failed to clear gym owner: %w", err)
+	}
+	return expectRow(result)
+}
+
+// SetPrice creates or replaces the plan with the price's name and refreshes
+// the gym's cached starting price.
+func (s *Store) SetPrice(price models.GymPrice) error {
+	return s.withPriceCache(price.GymID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO gym_prices (gym_id, plan_name, price_cents, period)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (gym_id, plan_name) DO UPDATE
+			SET price_cents = EXCLUDED.price_cents, period = EXCLUDED.period
+		`, price.GymID, price.PlanName, price.PriceCents, price.Period)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+				return ErrGymNotFound
+			}
+			return fmt.Errorf("failed to save price: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeletePrice removes a plan and refreshes the gym's cached starting price.
+func (s *Store) DeletePrice(gymID, planName string) error {
+	return s.withPriceCache(gymID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM gym_prices WHERE gym_id = $1 AND plan_name = $2`, gymID, planName)
+		if err != nil {
+			return fmt.Errorf("failed to delete price: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return ErrPriceNotFound
+		}
+		return nil
+	})
+}
+
+// withPriceCache runs fn and brings gym_price_cache in line with the gym's
+// prices in the same transaction, so nearby results never show a stale
+// starting price until the pricing job runs.
+func (s *Store) withPriceCache(gymID string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO gym_price_cache (gym_id, price_from_cents, updated_at)
+		SELECT gym_id, MIN(price_cents), NOW()
+		FROM gym_prices
+		WHERE gym_id = $1
+		GROUP BY gym_id
+		ON CONFLICT (gym_id) DO UPDATE
+		SET price_from_cents = EXCLUDED.price_from_cents,
+		    updated_at = EXCLUDED.updated_at
+	`, gymID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh price cache: %w", err)
+	}
+	_, err = tx.Exec(`
+		DELETE FROM gym_price_cache
+		WHERE gym_id = $1 AND NOT EXISTS (SELECT 1 FROM gym_prices WHERE gym_id = $1)
+	`, gymID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh price cache: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// SetMachine adds a machine to a gym's inventory or changes its quantity.
+func (s *Store) SetMachine(gymID, machineID string, quantity int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO gym_machines (gym_id, machine_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (gym_id, machine_id) DO UPDATE
+		SET quantity = EXCLUDED.quantity
+	`, gymID, machineID, quantity)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			if pqErr.Constraint == "gym_machines_gym_id_fkey" {
+				return ErrGymNotFound
+			}
+			return ErrMachineNotFound
+		}
+		return fmt.Errorf("failed to save gym machine: %w", err)
+	}
+	return nil
+}
+
+// RemoveMachine takes a machine out of a gym's inventory.
+func (s *Store) RemoveMachine(gymID, machineID string) error {
+	result, err := s.db.Exec(`DELETE FROM gym_machines WHERE gym_id = $1 AND machine_id = $2`, gymID, machineID)
+	if err != nil {
+		return fmt.Errorf("failed to remove gym machine: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrMachineNotFound
+	}
+	return nil
+}
+
+// SetAmenities replaces the amenities a gym lists.
+func (s *Store) SetAmenities(gymID string, amenities []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE gyms SET updated_at = $1 WHERE id = $2`, time.Now().UTC(), gymID)
+	if err != nil {
+		return fmt.Errorf("failed to update gym: %w", err)
+	}
+	if err := expectRow(result); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM gym_amenities WHERE gym_id = $1`, gymID); err != nil {
+		return fmt.Errorf("failed to clear amenities: %w", err)
+	}
+	if len(amenities) > 0 {
+		_, err := tx.Exec(`
+			INSERT INTO gym_amenities (gym_id, amenity)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT DO NOTHING
+		`, gymID, pq.Array(amenities))
+		if err != nil {
+			return fmt.Errorf("failed to save amenities: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func expectRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrGymNotFound
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package gyms
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrPhotoNotFound is returned when a review photo does not exist.
+	ErrPhotoNotFound = errors.New("review photo not found")
+	// ErrPhotoLimit is returned when a review already has the maximum number of photos.
+	ErrPhotoLimit = errors.New("review photo limit reached")
+	// ErrPhotoAttached is returned when an uploaded object is already attached.
+	ErrPhotoAttached = errors.New("photo already attached")
+)
+
+const reviewPhotoColumns = `id, review_id, object_key, thumb_key, large_key, content_type, bytes, width, height, created_at`
+
+// AddReviewPhoto attaches a photo to an active review unless the review
+// already has limit photos. The review row is locked so concurrent uploads
+// cannot go over the limit.
+func (s *Store) AddReviewPhoto(photo *models.GymReviewPhoto, limit int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked string
+	err = tx.QueryRow(`
+		SELECT id FROM gym_reviews WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, photo.ReviewID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrReviewNotFound
+		}
+		return fmt.Errorf("failed to lock review: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM gym_review_photos WHERE review_id = $1`, photo.ReviewID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count review photos: %w", err)
+	}
+	if count >= limit {
+		return ErrPhotoLimit
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO gym_review_photos (id, review_id, object_key, thumb_key, large_key, content_type, bytes, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (object_key) DO NOTHING
+		RETURNING created_at
+	`, photo.ID, photo.ReviewID, photo.ObjectKey, photo.ThumbKey, photo.LargeKey, photo.ContentType, photo.Bytes, photo.Width, photo.Height).Scan(&photo.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPhotoAttached
+		}
+		return fmt.Errorf("failed to add review photo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit review photo: %w", err)
+	}
+	return nil
+}
+
+// DeleteReviewPhoto detaches a photo from a review and returns it so the
+// caller can remove the stored objects.
+func (s *Store) DeleteReviewPhoto(reviewID, photoID string) (*models.GymReviewPhoto, error) {
+	photo, err := scanReviewPhoto(s.db.QueryRow(`
+		DELETE FROM gym_review_photos WHERE id = $1 AND review_id = $2
+		RETURNING `+reviewPhotoColumns, photoID, reviewID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("failed to delete review photo: %w", err)
+	}
+	return photo, nil
+}
+
+// DeleteReviewPhotosByUser detaches every photo on the user's reviews and
+// returns them so the caller can remove the stored objects.
+func (s *Store) DeleteReviewPhotosByUser(userID string) ([]models.GymReviewPhoto, error) {
+	rows, err := s.db.Query(`
+		DELETE FROM gym_review_photos
+		WHERE review_id IN (SELECT id FROM gym_reviews WHERE user_id = $1)
+		RETURNING `+reviewPhotoColumns, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete review photos: %w", err)
+	}
+	defer rows.Close()
+
+	photos := []models.GymReviewPhoto{}
+	for rows.Next() {
+		photo, err := scanReviewPhoto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review photo: %w", err)
+		}
+		photos = append(photos, *photo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review photos: %w", err)
+	}
+	return photos, nil
+}
+
+// attachPhotos fills in the photos of each review, oldest first.
+func (s *Store) attachPhotos(reviews ...*models.GymReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	ids := make([]string, len(reviews))
+	byID := make(map[string]*models.GymReview, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.ID
+		byID[review.ID] = review
+		review.Photos = []models.GymReviewPhoto{}
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+reviewPhotoColumns+`
+		FROM gym_review_photos
+		WHERE review_id = ANY($1::uuid[])
+		ORDER BY created_at, id
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query review photos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanReviewPhoto(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan review photo: %w", err)
+		}
+		if review, ok := byID[photo.ReviewID]; ok {
+			review.Photos = append(review.Photos, *photo)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate review photos: %w", err)
+	}
+	return nil
+}
+
+func scanReviewPhoto(row rowScanner) (*models.GymReviewPhoto, error) {
+	var photo models.GymReviewPhoto
+	err := row.Scan(
+		&photo.ID, &photo.ReviewID, &photo.ObjectKey, &photo.ThumbKey, &photo.LargeKey,
+		&photo.ContentType, &photo.Bytes, &photo.Width, &photo.Height, &photo.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
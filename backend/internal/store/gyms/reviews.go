@@ -0,0 +1,507 @@
+package gyms
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrReviewNotFound is returned when a review does not exist or has been deleted.
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrOwnReview is returned when a user votes on their own review.
+	ErrOwnReview = errors.New("cannot vote on your own review")
+)
+
+const reviewSelect = `
+	SELECT gr.id, gr.gym_id, gr.user_id, gr.rating, gr.comment, gr.helpful_count,
+		gr.created_at, gr.updated_at, gr.deleted_at, u.name
+	FROM gym_reviews gr
+	JOIN users u ON u.id = gr.user_id`
+
+// reviewSorts maps each sort to the column ordered on ahead of created_at;
+// newest has none.
+var reviewSorts = map[string]struct {
+	column    string
+	ascending bool
+}{
+	models.ReviewSortNewest:  {},
+	models.ReviewSortHelpful: {column: "gr.helpful_count"},
+	models.ReviewSortHighest: {column: "gr.rating"},
+	models.ReviewSortLowest:  {column: "gr.rating", ascending: true},
+}
+
+type reviewCursor struct {
+	Sort      string    `json:"sort"`
+	Key       int       `json:"key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
+// ValidReviewSort reports whether sort is a known review order.
+func ValidReviewSort(sort string) bool {
+	_, ok := reviewSorts[sort]
+	return ok
+}
+
+// SaveReview creates the user's review of a gym or, if they already have an
+// active one, edits it. created reports which of the two happened.
+func (s *Store) SaveReview(gymID, userID string, rating int, comment string) (review *models.GymReview, created bool, err error) {
+	err = s.withReviewStats(gymID, func(tx *sql.Tx) error {
+		current, err := scanReview(tx.QueryRow(reviewSelect+`
+			WHERE gr.gym_id = $1 AND gr.user_id = $2 AND gr.deleted_at IS NULL
+			FOR UPDATE OF gr
+		`, gymID, userID))
+		if err == nil {
+			review, err = editReview(tx, current, rating, comment)
+			return err
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to get review: %w", err)
+		}
+
+		review = &models.GymReview{
+			ID:      uuid.New().String(),
+			GymID:   gymID,
+			UserID:  userID,
+			Rating:  rating,
+			Comment: &comment,
+		}
+		created = true
+		err = tx.QueryRow(`
+			INSERT INTO gym_reviews (id, gym_id, user_id, rating, comment)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at
+		`, review.ID, review.GymID, review.UserID, review.Rating, review.Comment).Scan(&review.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.attachPhotos(review); err != nil {
+		return nil, false, err
+	}
+	return review, created, nil
+}
+
+// UpdateReview edits an active review, keeping the previous version in its
+// edit history.
+func (s *Store) UpdateReview(gymID, reviewID string, rating int, comment string) (*models.GymReview, error) {
+	var review *models.GymReview
+	err := s.withReviewStats(gymID, func(tx *sql.Tx) error {
+		current, err := scanReview(tx.QueryRow(reviewSelect+`
+			WHERE gr.id = $1 AND gr.gym_id = $2 AND gr.deleted_at IS NULL
+			FOR UPDATE OF gr
+		`, reviewID, gymID))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrReviewNotFound
+			}
+			return fmt.Errorf("failed to get review: %w", err)
+		}
+		review, err = editReview(tx, current, rating, comment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachPhotos(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview removes a review from the gym's listing and rating. Its edit
+// history is kept, and the author can review the gym again.
+func (s *Store) DeleteReview(gymID, reviewID string) error {
+	return s.withReviewStats(gymID, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE gym_reviews SET deleted_at = $1
+			WHERE id = $2 AND gym_id = $3 AND deleted_at IS NULL
+		`, time.Now().UTC(), reviewID, gymID)
+		if err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return ErrReviewNotFound
+		}
+		return nil
+	})
+}
+
+// GetReview retrieves an active review of a gym.
+func (s *Store) GetReview(gymID, reviewID string) (*models.GymReview, error) {
+	review, err := scanReview(s.db.QueryRow(reviewSelect+`
+		WHERE gr.id = $1 AND gr.gym_id = $2 AND gr.deleted_at IS NULL
+	`, reviewID, gymID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if err := s.attachPhotos(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetReviewEdits lists a review's earlier versions, most recent first.
+func (s *Store) GetReviewEdits(reviewID string) ([]models.GymReviewEdit, error) {
+	rows, err := s.db.Query(`
+		SELECT id, review_id, rating, comment, edited_at
+		FROM gym_review_edits
+		WHERE review_id = $1
+		ORDER BY edited_at DESC, id DESC
+	`, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review edits: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []models.GymReviewEdit{}
+	for rows.Next() {
+		var (
+			edit    models.GymReviewEdit
+			comment sql.NullString
+		)
+		if err := rows.Scan(&edit.ID, &edit.ReviewID, &edit.Rating, &comment, &edit.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review edit: %w", err)
+		}
+		if comment.Valid {
+			value := comment.String
+			edit.Comment = &value
+		}
+		edits = append(edits, edit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review edits: %w", err)
+	}
+	return edits, nil
+}
+
+// GetReviews retrieves a gym's active reviews in the given sort order with
+// pagination. Ties are broken newest first.
+func (s *Store) GetReviews(gymID, sort, cursor string, limit int) ([]models.GymReview, string, error) {
+	if sort == "" {
+		sort = models.ReviewSortNewest
+	}
+	order, ok := reviewSorts[sort]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown review sort %q", sort)
+	}
+
+	query := reviewSelect + `
+	WHERE gr.gym_id = $1 AND gr.deleted_at IS NULL`
+	args := []any{gymID}
+
+	if cursor != "" {
+		after, err := pagination.DecodeCursor[reviewCursor](cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if after.Sort != sort || after.ID == "" {
+			return nil, "", fmt.Errorf("decode cursor: %w", pagination.ErrInvalidCursor)
+		}
+		args = append(args, after.CreatedAt, after.ID)
+		condition := `(gr.created_at, gr.id) < ($2, $3)`
+		if order.column != "" {
+			args = append(args, after.Key)
+			operator := "<"
+			if order.ascending {
+				operator = ">"
+			}
+			condition = fmt.Sprintf(`(%[1]s %[2]s $4 OR (%[1]s = $4 AND %[3]s))`, order.column, operator, condition)
+		}
+		query += `
+	AND ` + condition
+	}
+
+	orderBy := "gr.created_at DESC, gr.id DESC"
+	if order.column != "" {
+		direction := "DESC"
+		if order.ascending {
+			direction = "ASC"
+		}
+		orderBy = order.column + " " + direction + ", " + orderBy
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(`
+	ORDER BY %s
+	LIMIT $%d`, orderBy, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []models.GymReview
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to iterate reviews: %w", err)
+	}
+
+	var nextCursor string
+	if len(reviews) > limit {
+		last := reviews[limit-1]
+		next := reviewCursor{Sort: sort, CreatedAt: last.CreatedAt.UTC(), ID: last.ID}
+		switch order.column {
+		case "gr.helpful_count":
+			next.Key = last.HelpfulCount
+		case "gr.rating":
+			next.Key = last.Rating
+		}
+		nextCursor, err = pagination.EncodeCursor(next)
+		if err != nil {
+			return nil, "", err
+		}
+		reviews = reviews[:limit]
+	}
+
+	page := make([]*models.GymReview, len(reviews))
+	for i := range reviews {
+		page[i] = &reviews[i]
+	}
+	if err := s.attachPhotos(page...); err != nil {
+		return nil, "", err
+	}
+
+	return reviews, nextCursor, nil
+}
+
+// VoteHelpful records the user's helpful vote on a review and returns the
+// review's new helpful count. Voting twice counts once.
+func (s *Store) VoteHelpful(gymID, reviewID, userID string) (int, error) {
+	return s.changeHelpfulVote(gymID, reviewID, userID, true)
+}
+
+// RemoveHelpfulVote withdraws the user's helpful vote on a review and returns
+// the review's new helpful count.
+func (s *Store) RemoveHelpfulVote(gymID, reviewID, userID string) (int, error) {
+	return s.changeHelpfulVote(gymID, reviewID, userID, false)
+}
+
+func (s *Store) changeHelpfulVote(gymID, reviewID, userID string, helpful bool) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		authorID string
+		count    int
+	)
+	err = tx.QueryRow(`
+		SELECT user_id, helpful_count FROM gym_reviews
+		WHERE id = $1 AND gym_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, reviewID, gymID).Scan(&authorID, &count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrReviewNotFound
+		}
+		return 0, fmt.Errorf("failed to get review: %w", err)
+	}
+	if authorID == userID {
+		return 0, ErrOwnReview
+	}
+
+	var result sql.Result
+	if helpful {
+		result, err = tx.Exec(`
+			INSERT INTO gym_review_votes (review_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (review_id, user_id) DO NOTHING
+		`, reviewID, userID)
+	} else {
+		result, err = tx.Exec(`DELETE FROM gym_review_votes WHERE review_id = $1 AND user_id = $2`, reviewID, userID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save helpful vote: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return count, tx.Commit()
+	}
+
+	delta := 1
+	if !helpful {
+		delta = -1
+	}
+	err = tx.QueryRow(`
+		UPDATE gym_reviews SET helpful_count = GREATEST(helpful_count + $1, 0)
+		WHERE id = $2
+		RETURNING helpful_count
+	`, delta, reviewID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update helpful count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit helpful vote: %w", err)
+	}
+	return count, nil
+}
+
+func (s *Store) ExportReviewsByUser(userID string) ([]models.GymReview, error) {
+	rows, err := s.db.Query(reviewSelect+`
+		WHERE gr.user_id = $1 ORDER BY gr.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var reviews []models.GymReview
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	exported := make([]*models.GymReview, len(reviews))
+	for i := range reviews {
+		exported[i] = &reviews[i]
+	}
+	return reviews, s.attachPhotos(exported...)
+}
+
+func (s *Store) AnonymizeReviewsByUser(userID string) error {
+	_, err := s.db.Exec(`UPDATE gym_reviews SET comment = '[deleted]' WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		UPDATE gym_review_edits SET comment = '[deleted]'
+		WHERE review_id IN (SELECT id FROM gym_reviews WHERE user_id = $1)
+	`, userID)
+	return err
+}
+
+func (s *Store) DeleteReviewVotesByUser(userID string) error {
+	_, err := s.db.Exec(`
+		WITH removed AS (
+			DELETE FROM gym_review_votes WHERE user_id = $1 RETURNING review_id
+		)
+		UPDATE gym_reviews gr SET helpful_count = GREATEST(gr.helpful_count - 1, 0)
+		FROM removed
+		WHERE gr.id = removed.review_id
+	`, userID)
+	return err
+}
+
+// withReviewStats runs fn with the gym locked against other review changes
+// and then recomputes gym_review_stats from the gym's active reviews in the
+// same transaction, so the average rating and review count always agree
+// with the reviews listed.
+func (s *Store) withReviewStats(gymID string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked string
+	if err := tx.QueryRow(`SELECT id FROM gyms WHERE id = $1 FOR NO KEY UPDATE`, gymID).Scan(&locked); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrGymNotFound
+		}
+		return fmt.Errorf("failed to lock gym: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO gym_review_stats (gym_id, review_count, rating_sum, updated_at)
+		SELECT $1::uuid, COUNT(*), COALESCE(SUM(rating), 0), NOW()
+		FROM gym_reviews
+		WHERE gym_id = $1::uuid AND deleted_at IS NULL
+		ON CONFLICT (gym_id) DO UPDATE
+		SET review_count = EXCLUDED.review_count,
+		    rating_sum = EXCLUDED.rating_sum,
+		    updated_at = EXCLUDED.updated_at
+	`, gymID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh review stats: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// editReview saves a new rating and comment for a review, recording the
+// version it replaces. Saving the same content again changes nothing.
+func editReview(tx *sql.Tx, review *models.GymReview, rating int, comment string) (*models.GymReview, error) {
+	if review.Rating == rating && review.Comment != nil && *review.Comment == comment {
+		return review, nil
+	}
+
+	now := time.Now().UTC()
+	_, err := tx.Exec(`
+		INSERT INTO gym_review_edits (id, review_id, rating, comment, edited_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.New().String(), review.ID, review.Rating, review.Comment, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save review history: %w", err)
+	}
+	_, err = tx.Exec(`
+		UPDATE gym_reviews SET rating = $1, comment = $2, updated_at = $3
+		WHERE id = $4
+	`, rating, comment, now, review.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	review.Rating = rating
+	review.Comment = &comment
+	review.UpdatedAt = &now
+	return review, nil
+}
+
+func scanReview(row rowScanner) (*models.GymReview, error) {
+	var (
+		review    models.GymReview
+		comment   sql.NullString
+		updatedAt sql.NullTime
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(
+		&review.ID, &review.GymID, &review.UserID, &review.Rating, &comment, &review.HelpfulCount,
+		&review.CreatedAt, &updatedAt, &deletedAt, &review.UserName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Valid {
+		value := comment.String
+		review.Comment = &value
+	}
+	if updatedAt.Valid {
+		value := updatedAt.Time
+		review.UpdatedAt = &value
+	}
+	if deletedAt.Valid {
+		value := deletedAt.Time
+		review.DeletedAt = &value
+	}
+	return &review, nil
+}
@@ -0,0 +1,44 @@
+package gyms
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+)
+
+func TestGetReviewsRejectsCursorFromAnotherSort(t *testing.T) {
+	cursor, err := pagination.EncodeCursor(reviewCursor{
+		Sort:      models.ReviewSortHelpful,
+		Key:       3,
+		CreatedAt: time.Now(),
+		ID:        "11111111-1111-1111-1111-111111111111",
+	})
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+
+	s := &Store{}
+	if _, _, err := s.GetReviews("gym", models.ReviewSortNewest, cursor, 10); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	if _, _, err := s.GetReviews("gym", models.ReviewSortNewest, "not-a-cursor", 10); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for garbage, got %v", err)
+	}
+	if _, _, err := s.GetReviews("gym", "oldest", "", 10); err == nil {
+		t.Fatal("expected an unknown sort to be rejected")
+	}
+}
+
+func TestValidReviewSort(t *testing.T) {
+	for _, sort := range []string{models.ReviewSortNewest, models.ReviewSortHelpful, models.ReviewSortHighest, models.ReviewSortLowest} {
+		if !ValidReviewSort(sort) {
+			t.Fatalf("expected %q to be valid", sort)
+		}
+	}
+	if ValidReviewSort("random") {
+		t.Fatal("expected an unknown sort to be invalid")
+	}
+}
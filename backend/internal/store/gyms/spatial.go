@@ -0,0 +1,105 @@
+package gyms
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// earthRadiusM matches the radius in the nearby query's Haversine
+	// distance.
+	earthRadiusM = 6371000.0
+	// boxPaddingDeg widens bounding boxes by about 10 cm so rounding never
+	// drops a gym sitting exactly on the search radius.
+	boxPaddingDeg = 1e-6
+)
+
+// geoBox is a latitude/longitude rectangle in degrees.
+type geoBox struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// boundingBoxes returns rectangles that together contain every point within
+// radiusM of (lat, lng). A circle crossing the antimeridian is split in two;
+// one reaching a pole spans every longitude.
+func boundingBoxes(lat, lng, radiusM float64) []geoBox {
+	angular := radiusM / earthRadiusM
+	deltaLat := angular*180/math.Pi + boxPaddingDeg
+	minLat, maxLat := lat-deltaLat, lat+deltaLat
+	if minLat <= -90 || maxLat >= 90 {
+		return []geoBox{{MinLat: math.Max(minLat, -90), MaxLat: math.Min(maxLat, 90), MinLng: -180, MaxLng: 180}}
+	}
+
+	// The widest longitude offset of a circle on a sphere is at
+	// asin(sin(angular) / cos(lat)), slightly poleward of the centre.
+	ratio := math.Sin(angular) / math.Cos(lat*math.Pi/180)
+	if ratio >= 1 {
+		return []geoBox{{MinLat: minLat, MaxLat: maxLat, MinLng: -180, MaxLng: 180}}
+	}
+	deltaLng := math.Asin(ratio)*180/math.Pi + boxPaddingDeg
+	minLng, maxLng := lng-deltaLng, lng+deltaLng
+
+	switch {
+	case minLng < -180:
+		return []geoBox{
+			{MinLat: minLat, MaxLat: maxLat, MinLng: minLng + 360, MaxLng: 180},
+			{MinLat: minLat, MaxLat: maxLat, MinLng: -180, MaxLng: maxLng},
+		}
+	case maxLng > 180:
+		return []geoBox{
+			{MinLat: minLat, MaxLat: maxLat, MinLng: minLng, MaxLng: 180},
+			{MinLat: minLat, MaxLat: maxLat, MinLng: -180, MaxLng: maxLng - 360},
+		}
+	}
+	return []geoBox{{MinLat: minLat, MaxLat: maxLat, MinLng: minLng, MaxLng: maxLng}}
+}
+
+func (b geoBox) contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lng >= b.MinLng && lng <= b.MaxLng
+}
+
+// spatialPrefilter returns the WHERE clause that narrows the gyms table to
+// candidates near $1/$2 within $3 meters through an index, before the exact
+// distance is computed. earthdistance's earth_box is used when idx_gyms_earth
+// exists; otherwise the circle's bounding boxes are matched against
+// idx_gyms_point. Both are supersets of the circle, so results and cursors
+// are the same either way.
+func (s *Store) spatialPrefilter(lat, lng, radiusM float64, args []any) (string, []any) {
+	if s.earthIndexed() {
+		// earth_box measures on earthdistance's own sphere, so the radius is
+		// scaled to cover the same angle as the Haversine radius.
+		return `
+	WHERE earth_box(ll_to_earth($1, $2), ($3 + 1) / 6371000.0 * earth()) @> ll_to_earth(g.lat, g.lng)`, args
+	}
+
+	boxes := boundingBoxes(lat, lng, radiusM)
+	conditions := make([]string, 0, len(boxes))
+	for _, box := range boxes {
+		args = append(args, box.MinLng, box.MinLat, box.MaxLng, box.MaxLat)
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf("point(g.lng, g.lat) <@ box(point($%d, $%d), point($%d, $%d))", n-3, n-2, n-1, n))
+	}
+	return `
+	WHERE ` + strings.Join(conditions, " OR "), args
+}
+
+// earthIndexed reports whether the earthdistance index is available. The
+// answer is looked up once; a failed lookup falls back to the point index
+// and is retried on the next search.
+func (s *Store) earthIndexed() bool {
+	s.spatialMu.Lock()
+	defer s.spatialMu.Unlock()
+	if s.spatialChecked {
+		return s.earthIndex
+	}
+	var exists bool
+	err := s.db.QueryRow(`SELECT to_regclass('idx_gyms_earth') IS NOT NULL`).Scan(&exists)
+	if err != nil {
+		return false
+	}
+	s.earthIndex = exists
+	s.spatialChecked = true
+	return exists
+}
@@ -0,0 +1,189 @@
+package gyms
+
+import (
+	"database/sql"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/migrations"
+)
+
+// destination returns the point distanceM away from (lat, lng) along the
+// bearing, on the same sphere as the nearby query.
+func destination(lat, lng, bearingDeg, distanceM float64) (float64, float64) {
+	phi, lambda := lat*math.Pi/180, lng*math.Pi/180
+	theta, delta := bearingDeg*math.Pi/180, distanceM/earthRadiusM
+	phi2 := math.Asin(math.Sin(phi)*math.Cos(delta) + math.Cos(phi)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi), math.Cos(delta)-math.Sin(phi)*math.Sin(phi2))
+	lng2 := math.Mod(lambda2*180/math.Pi+540, 360) - 180
+	return phi2 * 180 / math.Pi, lng2
+}
+
+func TestBoundingBoxesContainCircle(t *testing.T) {
+	centers := []struct {
+		name     string
+		lat, lng float64
+		boxes    int
+	}{
+		{"seattle", 47.61, -122.33, 1},
+		{"equator", 0, 0, 1},
+		{"east of antimeridian", -16.5, 179.9, 2},
+		{"west of antimeridian", 65.0, -179.95, 2},
+		{"high latitude", 78.2, 15.6, 1},
+		{"near pole", 89.9, 45, 1},
+	}
+	for _, center := range centers {
+		boxes := boundingBoxes(center.lat, center.lng, 50000)
+		if len(boxes) != center.boxes {
+			t.Fatalf("%s: expected %d boxes, got %+v", center.name, center.boxes, boxes)
+		}
+		for bearing := 0.0; bearing < 360; bearing += 7.5 {
+			for _, distance := range []float64{0, 12500, 49999, 50000} {
+				lat, lng := destination(center.lat, center.lng, bearing, distance)
+				inside := false
+				for _, box := range boxes {
+					inside = inside || box.contains(lat, lng)
+				}
+				if !inside {
+					t.Fatalf("%s: point %.6f,%.6f at %.0fm bearing %.1f is outside %+v", center.name, lat, lng, distance, bearing, boxes)
+				}
+			}
+		}
+	}
+
+	if boxes := boundingBoxes(89.9, 45, 50000); boxes[0].MinLng != -180 || boxes[0].MaxLng != 180 || boxes[0].MaxLat != 90 {
+		t.Fatalf("expected a polar search to span every longitude, got %+v", boxes)
+	}
+}
+
+func TestSpatialPrefilterNumbersBoxParams(t *testing.T) {
+	store := &Store{spatialChecked: true}
+	args := []any{-16.5, 179.99, 5000.0, 21}
+	clause, args := store.spatialPrefilter(-16.5, 179.99, 5000, args)
+
+	if len(args) != 12 {
+		t.Fatalf("expected two boxes of four params each, got %d args", len(args))
+	}
+	if !strings.Contains(clause, "box(point($5, $6), point($7, $8))") || !strings.Contains(clause, " OR point(g.lng, g.lat) <@ box(point($9, $10), point($11, $12))") {
+		t.Fatalf("unexpected clause %q", clause)
+	}
+
+	store.earthIndex = true
+	clause, args = store.spatialPrefilter(47.61, -122.33, 5000, []any{47.61, -122.33, 5000.0, 21})
+	if len(args) != 4 || !strings.Contains(clause, "earth_box(ll_to_earth($1, $2)") {
+		t.Fatalf("unexpected earthdistance clause %q with %d args", clause, len(args))
+	}
+}
+
+// BenchmarkGetNearby runs nearby searches over a few hundred thousand
+// synthetic gyms: one dense metro area and a sparse spread across a
+// continent. It migrates and seeds the database it is pointed at, so use a
+// disposable one:
+//
+//	NEARBY_BENCH_DATABASE_URL=postgres://localhost/fitonex_bench?sslmode=disable \
+//		go test ./internal/store/gyms -run '^$' -bench GetNearby
+func BenchmarkGetNearby(b *testing.B) {
+	dsn := os.Getenv("NEARBY_BENCH_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("NEARBY_BENCH_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		b.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	// One connection keeps the session settings of the seq_scan baseline.
+	db.SetMaxOpenConns(1)
+
+	if err := migrations.Up(db); err != nil {
+		b.Fatalf("migrate: %v", err)
+	}
+	seedBenchmarkGyms(b, db, 300000)
+
+	searches := []struct {
+		name     string
+		lat, lng float64
+		radiusKm float64
+	}{
+		{"metro_5km", 47.61, -122.33, 5},
+		{"metro_50km", 47.61, -122.33, 50},
+		{"sparse_5km", 39.1, -94.6, 5},
+		{"sparse_50km", 39.1, -94.6, 50},
+	}
+
+	store := New(db)
+	earthAvailable := store.earthIndexed()
+	backends := []struct {
+		name     string
+		earth    bool
+		settings string
+	}{
+		{"seq_scan", false, "SET enable_indexscan = off; SET enable_bitmapscan = off"},
+		{"point_index", false, "RESET enable_indexscan; RESET enable_bitmapscan"},
+		{"earth_index", true, "RESET enable_indexscan; RESET enable_bitmapscan"},
+	}
+
+	for _, backend := range backends {
+		if backend.earth && !earthAvailable {
+			b.Logf("skipping %s: earthdistance is not installed", backend.name)
+			continue
+		}
+		if _, err := db.Exec(backend.settings); err != nil {
+			b.Fatalf("%s: %v", backend.name, err)
+		}
+		store.earthIndex = backend.earth
+		for _, search := range searches {
+			search := search
+			b.Run(backend.name+"/"+search.name, func(b *testing.B) {
+				page, err := store.GetNearby(search.lat, search.lng, search.radiusKm, 20, nil, models.NearbyFilter{})
+				if err != nil {
+					b.Fatalf("GetNearby: %v", err)
+				}
+				if !page.HasMore {
+					b.Logf("only %d gyms in range", len(page.Items))
+				}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := store.GetNearby(search.lat, search.lng, search.radiusKm, 20, nil, models.NearbyFilter{}); err != nil {
+						b.Fatalf("GetNearby: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// seedBenchmarkGyms inserts n gyms with stable IDs; a sixth of them sit in
+// a metro area around Seattle and the rest spread over the continental US.
+func seedBenchmarkGyms(b *testing.B, db *sql.DB, n int) {
+	b.Helper()
+	var existing int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM gyms WHERE name LIKE 'Bench Gym %'`).Scan(&existing); err != nil {
+		b.Fatalf("count benchmark gyms: %v", err)
+	}
+	if existing >= n {
+		return
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO gyms (id, name, lat, lng, address)
+		SELECT
+			md5('bench-gym-' || i)::uuid,
+			'Bench Gym ' || i,
+			CASE WHEN i % 6 = 0 THEN 47.61 + (random() - 0.5) * 0.8 ELSE 25 + random() * 24 END,
+			CASE WHEN i % 6 = 0 THEN -122.33 + (random() - 0.5) * 1.2 ELSE -124 + random() * 57 END,
+			'Benchmark address ' || i
+		FROM generate_series(1, $1::int) AS i
+		ON CONFLICT (id) DO NOTHING
+	`, n)
+	if err != nil {
+		b.Fatalf("seed benchmark gyms: %v", err)
+	}
+	if _, err := db.Exec(`ANALYZE gyms`); err != nil {
+		b.Fatalf("analyze gyms: %v", err)
+	}
+	b.Logf("seeded %d benchmark gyms", n)
+}
@@ -0,0 +1,137 @@
+package gyms
+
+import (
+	"database/sql"
+	"fmt"
+
+	"fitonex/backend/internal/maptiles"
+	"fitonex/backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// tileCondition keeps gyms inside a tile's bounds ($1..$4). Tiles include
+// their south and west edges and exclude the others so a gym on a shared
+// edge lands in exactly one tile.
+const tileCondition = `point(g.lng, g.lat) <@ box(point($2, $1), point($4, $3))
+	AND g.lat < $3 AND g.lng < $4`
+
+// GetTile returns the markers for a map tile. Tiles holding at most maxGyms
+// gyms list them individually; denser tiles are split into a grid of
+// grid x grid cells, each reported as a cluster with its count and centroid,
+// or as the gym itself when it is alone in its cell.
+func (s *Store) GetTile(tile maptiles.Tile, grid, maxGyms int) (models.GymTile, error) {
+	result := models.GymTile{Gyms: []models.MapGym{}, Clusters: []models.GymCluster{}}
+	minLat, minLng, maxLat, maxLng := tile.Bounds()
+	bounds := []any{minLat, minLng, maxLat, maxLng}
+
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM gyms g WHERE `+tileCondition, bounds...).Scan(&count); err != nil {
+		return result, fmt.Errorf("failed to count tile gyms: %w", err)
+	}
+	if count == 0 {
+		return result, nil
+	}
+	if count <= maxGyms {
+		gyms, err := s.mapGyms(tileCondition, bounds...)
+		if err != nil {
+			return result, err
+		}
+		result.Gyms = gyms
+		return result, nil
+	}
+
+	// Cells follow the Mercator projection so they are square on screen.
+	rows, err := s.db.Query(`
+		SELECT cx, cy, COUNT(*), AVG(lat), AVG(lng), MIN(id::text)
+		FROM (
+			SELECT
+				g.id, g.lat, g.lng,
+				LEAST(GREATEST(FLOOR((g.lng + 180) / 360 * $5)::int - $6, 0), $8 - 1) AS cx,
+				LEAST(GREATEST(FLOOR((1 - ln(tan(radians(g.lat)) + 1 / cos(radians(g.lat))) / pi()) / 2 * $5)::int - $7, 0), $8 - 1) AS cy
+			FROM gyms g
+			WHERE `+tileCondition+`
+		) cells
+		GROUP BY cx, cy
+		ORDER BY cy, cx
+	`, minLat, minLng, maxLat, maxLng, float64(int(1)<<tile.Z)*float64(grid), tile.X*grid, tile.Y*grid, grid)
+	if err != nil {
+		return result, fmt.Errorf("failed to cluster tile gyms: %w", err)
+	}
+	defer rows.Close()
+
+	var singles []string
+	for rows.Next() {
+		var (
+			cx, cy  int
+			cluster models.GymCluster
+			firstID string
+		)
+		if err := rows.Scan(&cx, &cy, &cluster.Count, &cluster.Lat, &cluster.Lng, &firstID); err != nil {
+			return result, fmt.Errorf("failed to scan tile cluster: %w", err)
+		}
+		if cluster.Count == 1 {
+			singles = append(singles, firstID)
+			continue
+		}
+		cluster.ID = fmt.Sprintf("%s/%d/%d", tile.Key(), cx, cy)
+		result.Clusters = append(result.Clusters, cluster)
+	}
+	if err := rows.Err(); err != nil {
+		return result, fmt.Errorf("failed to iterate tile clusters: %w", err)
+	}
+
+	if len(singles) > 0 {
+		gyms, err := s.mapGyms(`g.id = ANY($1::uuid[])`, pq.Array(singles))
+		if err != nil {
+			return result, err
+		}
+		result.Gyms = gyms
+	}
+	return result, nil
+}
+
+func (s *Store) mapGyms(condition string, args ...any) ([]models.MapGym, error) {
+	rows, err := s.db.Query(`
+		SELECT
+			g.id, g.name, g.lat, g.lng,
+			rs.rating_sum::float / NULLIF(rs.review_count, 0) AS avg_rating,
+			COALESCE(pc.price_from_cents,
+				(SELECT MIN(price_cents) FROM gym_prices WHERE gym_id = g.id)
+			) AS price_from_cents
+		FROM gyms g
+		LEFT JOIN gym_price_cache pc ON pc.gym_id = g.id
+		LEFT JOIN gym_review_stats rs ON rs.gym_id = g.id
+		WHERE `+condition+`
+		ORDER BY g.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query map gyms: %w", err)
+	}
+	defer rows.Close()
+
+	gyms := []models.MapGym{}
+	for rows.Next() {
+		var (
+			gym            models.MapGym
+			avgRating      sql.NullFloat64
+			priceFromCents sql.NullInt64
+		)
+		if err := rows.Scan(&gym.ID, &gym.Name, &gym.Lat, &gym.Lng, &avgRating, &priceFromCents); err != nil {
+			return nil, fmt.Errorf("failed to scan map gym: %w", err)
+		}
+		if avgRating.Valid {
+			value := avgRating.Float64
+			gym.AvgRating = &value
+		}
+		if priceFromCents.Valid {
+			value := int(priceFromCents.Int64)
+			gym.PriceFromCents = &value
+		}
+		gyms = append(gyms, gym)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate map gyms: %w", err)
+	}
+	return gyms, nil
+}
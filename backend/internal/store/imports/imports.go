@@ -0,0 +1,212 @@
+package imports
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/workouts"
+
+	"github.com/google/uuid"
+)
+
+// ErrImportNotFound is returned when an import does not exist or belongs to another user.
+var ErrImportNotFound = errors.New("import not found")
+
+// Session is a workout waiting to be written by an import. Key identifies the
+// session in the source tracker so it is only ever imported once per user.
+type Session struct {
+	Key       string
+	Workout   models.Workout
+	Exercises []models.Exercise
+}
+
+// report is the JSONB payload kept alongside the import counters
+type report struct {
+	Mappings []models.ImportMapping `json:"mappings"`
+	Unmapped []models.ImportIssue   `json:"unmapped"`
+	Skipped  []models.ImportIssue   `json:"skipped"`
+}
+
+// Store handles workout import database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new imports store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create writes every session of an import in one transaction. A file that
+// was already imported by the user is not written again; the earlier import
+// is returned with Duplicate set. Sessions already imported from another file
+// are skipped and counted in SkippedSessions.
+func (s *Store) Create(imp *models.WorkoutImport, sessions []Session) (*models.WorkoutImport, error) {
+	imp.ID = uuid.New().String()
+	imp.CreatedAt = time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO workout_imports (id, user_id, source, file_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, file_hash) DO NOTHING
+	`, imp.ID, imp.UserID, imp.Source, imp.FileHash, imp.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	} else if affected == 0 {
+		existing, err := s.getByHash(tx, imp.UserID, imp.FileHash)
+		if err != nil {
+			return nil, err
+		}
+		existing.Duplicate = true
+		return existing, nil
+	}
+
+	imp.Workouts, imp.Exercises, imp.Sets, imp.SkippedSessions = 0, 0, 0, 0
+	for i := range sessions {
+		session := &sessions[i]
+
+		claimed, err := tx.Exec(`
+			INSERT INTO import_sessions (user_id, source_key, import_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, source_key) DO NOTHING
+		`, imp.UserID, session.Key, imp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record import session: %w", err)
+		}
+		if affected, err := claimed.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to record import session: %w", err)
+		} else if affected == 0 {
+			imp.SkippedSessions++
+			continue
+		}
+
+		session.Workout.UserID = imp.UserID
+		if err := workouts.InsertTx(tx, &session.Workout, session.Exercises); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`
+			UPDATE import_sessions SET workout_id = $3
+			WHERE user_id = $1 AND source_key = $2
+		`, imp.UserID, session.Key, session.Workout.ID); err != nil {
+			return nil, fmt.Errorf("failed to record import session: %w", err)
+		}
+
+		imp.Workouts++
+		imp.Exercises += len(session.Exercises)
+		for _, exercise := range session.Exercises {
+			imp.Sets += len(exercise.Sets)
+		}
+	}
+
+	payload, err := json.Marshal(report{Mappings: imp.Mappings, Unmapped: imp.Unmapped, Skipped: imp.Skipped})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import report: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE workout_imports
+		SET workouts = $2, exercises = $3, sets = $4, skipped_sessions = $5, report = $6
+		WHERE id = $1
+	`, imp.ID, imp.Workouts, imp.Exercises, imp.Sets, imp.SkippedSessions, payload); err != nil {
+		return nil, fmt.Errorf("failed to update import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return imp, nil
+}
+
+// GetByID retrieves one of the user's imports
+func (s *Store) GetByID(id, userID string) (*models.WorkoutImport, error) {
+	row := s.db.QueryRow(`
+		SELECT id, user_id, source, file_hash, workouts, exercises, sets, skipped_sessions, report, created_at
+		FROM workout_imports
+		WHERE id = $1 AND user_id = $2
+	`, id, userID)
+	return scanImport(row)
+}
+
+func (s *Store) ExportByUser(userID string) ([]models.WorkoutImport, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, source, file_hash, workouts, exercises, sets, skipped_sessions, report, created_at
+		FROM workout_imports
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export imports: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.WorkoutImport
+	for rows.Next() {
+		item, err := scanImport(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export imports: %w", err)
+	}
+
+	return items, nil
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM workout_imports WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete imports: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) getByHash(tx *sql.Tx, userID, fileHash string) (*models.WorkoutImport, error) {
+	row := tx.QueryRow(`
+		SELECT id, user_id, source, file_hash, workouts, exercises, sets, skipped_sessions, report, created_at
+		FROM workout_imports
+		WHERE user_id = $1 AND file_hash = $2
+	`, userID, fileHash)
+	return scanImport(row)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanImport(row rowScanner) (*models.WorkoutImport, error) {
+	imp := &models.WorkoutImport{}
+	var payload []byte
+
+	err := row.Scan(&imp.ID, &imp.UserID, &imp.Source, &imp.FileHash, &imp.Workouts, &imp.Exercises, &imp.Sets, &imp.SkippedSessions, &payload, &imp.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	var decoded report
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode import report: %w", err)
+	}
+	imp.Mappings = decoded.Mappings
+	imp.Unmapped = decoded.Unmapped
+	imp.Skipped = decoded.Skipped
+
+	return imp, nil
+}
@@ -2,11 +2,26 @@ package machines
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"fitonex/backend/internal/models"
 	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+var (
+	// ErrMachineNotFound is returned when a machine does not exist.
+	ErrMachineNotFound = errors.New("machine not found")
+	// ErrDuplicateName is returned when another machine already has the name.
+	ErrDuplicateName = errors.New("machine name already exists")
 )
 
 // Store handles machine-related database operations
@@ -79,25 +94,67 @@ func (s *Store) Search(query, bodyPart string, limit int) ([]models.Machine, err
 // GetByID retrieves a machine by ID
 func (s *Store) GetByID(id string) (*models.Machine, error) {
 	query := `
-		SELECT id, name, body_part, created_at
+		SELECT id, name, body_part, met::float8, created_at
 		FROM machines
 		WHERE id = $1
 	`
 
 	var machine models.Machine
+	var met sql.NullFloat64
 	err := s.db.QueryRow(query, id).Scan(
-		&machine.ID, &machine.Name, &machine.BodyPart, &machine.CreatedAt,
+		&machine.ID, &machine.Name, &machine.BodyPart, &met, &machine.CreatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("machine not found")
+			return nil, ErrMachineNotFound
 		}
 		return nil, fmt.Errorf("failed to get machine: %w", err)
 	}
+	if met.Valid {
+		value := met.Float64
+		machine.MET = &value
+	}
 
 	return &machine, nil
 }
 
+// GetByIDs retrieves machines keyed by ID. Unknown IDs are left out.
+func (s *Store) GetByIDs(ids []string) (map[string]models.Machine, error) {
+	machines := make(map[string]models.Machine, len(ids))
+	if len(ids) == 0 {
+		return machines, nil
+	}
+
+	query := `
+		SELECT id, name, body_part, met::float8, created_at
+		FROM machines
+		WHERE id = ANY($1::uuid[])
+	`
+	rows, err := s.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get machines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var machine models.Machine
+		var met sql.NullFloat64
+		if err := rows.Scan(&machine.ID, &machine.Name, &machine.BodyPart, &met, &machine.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan machine: %w", err)
+		}
+		if met.Valid {
+			value := met.Float64
+			machine.MET = &value
+		}
+		machines[machine.ID] = machine
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get machines: %w", err)
+	}
+
+	return machines, nil
+}
+
 // GetBodyParts retrieves all unique body parts
 func (s *Store) GetBodyParts() ([]string, error) {
 	query := `
@@ -200,3 +257,91 @@ WHERE score > 0.1
 	}
 	return page, nil
 }
+
+// BestMatches maps each name to its most similar machine by trigram similarity.
+// Names without a machine scoring at least minScore are left out of the result.
+func (s *Store) BestMatches(names []string, minScore float64) (map[string]models.MachineSearchResult, error) {
+	matches := make(map[string]models.MachineSearchResult, len(names))
+	if len(names) == 0 {
+		return matches, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The % operator can use the trigram index on machines.name, but only
+	// takes its cutoff from pg_trgm.similarity_threshold, which is set for
+	// this transaction alone.
+	if _, err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, strconv.FormatFloat(minScore, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("failed to set similarity threshold: %w", err)
+	}
+
+	query := `
+		SELECT DISTINCT ON (n.name) n.name, m.id, m.name, m.body_part, similarity(m.name, n.name) AS score
+		FROM unnest($1::text[]) AS n(name)
+		JOIN machines m ON m.name % n.name
+		ORDER BY n.name, score DESC, m.id ASC
+	`
+
+	rows, err := tx.Query(query, pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("failed to match machines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var item models.MachineSearchResult
+		if err := rows.Scan(&name, &item.ID, &item.Name, &item.BodyPart, &item.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan machine match: %w", err)
+		}
+		matches[name] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to match machines: %w", err)
+	}
+
+	return matches, nil
+}
+
+// Create adds a machine to the catalog
+func (s *Store) Create(machine *models.Machine) error {
+	machine.ID = uuid.New().String()
+	machine.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.Exec(`
+		INSERT INTO machines (id, name, body_part, met, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, machine.ID, machine.Name, machine.BodyPart, machine.MET, machine.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return ErrDuplicateName
+		}
+		return fmt.Errorf("failed to create machine: %w", err)
+	}
+	return nil
+}
+
+// Update replaces a machine's name, body part and MET value
+func (s *Store) Update(machine *models.Machine) error {
+	err := s.db.QueryRow(`
+		UPDATE machines SET name = $1, body_part = $2, met = $3
+		WHERE id = $4
+		RETURNING created_at
+	`, machine.Name, machine.BodyPart, machine.MET, machine.ID).Scan(&machine.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return ErrDuplicateName
+		}
+		if err == sql.ErrNoRows {
+			return ErrMachineNotFound
+		}
+		return fmt.Errorf("failed to update machine: %w", err)
+	}
+	return nil
+}
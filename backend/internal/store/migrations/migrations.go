@@ -154,6 +154,580 @@ func Up(db *sql.DB) error {
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 		)`,
 		"CREATE INDEX IF NOT EXISTS idx_password_resets_user ON password_resets(user_id)",
+		`CREATE TABLE IF NOT EXISTS personal_records (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			record_key TEXT NOT NULL,
+			machine_id UUID REFERENCES machines(id) ON DELETE SET NULL,
+			exercise_name TEXT NOT NULL,
+			record_type TEXT NOT NULL,
+			value NUMERIC(10,2) NOT NULL,
+			weight_kg NUMERIC(6,2),
+			reps INTEGER,
+			previous_value NUMERIC(10,2),
+			exercise_id UUID NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+			set_id UUID REFERENCES sets(id) ON DELETE SET NULL,
+			achieved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_personal_records_user_key ON personal_records(user_id, record_key, record_type, achieved_at DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_exercises_user_machine ON exercises(user_id, machine_id, created_at)",
+		`CREATE TABLE IF NOT EXISTS workout_templates (
+			id UUID PRIMARY KEY,
+			owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			description TEXT,
+			copied_from UUID REFERENCES workout_templates(id) ON DELETE SET NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_workout_templates_owner ON workout_templates(owner_id, created_at DESC, id DESC)",
+		`CREATE TABLE IF NOT EXISTS template_exercises (
+			id UUID PRIMARY KEY,
+			template_id UUID NOT NULL REFERENCES workout_templates(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			machine_id UUID REFERENCES machines(id) ON DELETE SET NULL,
+			name TEXT NOT NULL,
+			target_sets INTEGER NOT NULL CHECK (target_sets > 0),
+			target_reps INTEGER NOT NULL CHECK (target_reps > 0),
+			target_weight_kg NUMERIC(6,2) CHECK (target_weight_kg >= 0),
+			notes TEXT,
+			UNIQUE(template_id, position)
+		)`,
+		`CREATE TABLE IF NOT EXISTS training_programs (
+			id UUID PRIMARY KEY,
+			owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			description TEXT,
+			weeks INTEGER NOT NULL CHECK (weeks > 0),
+			start_date DATE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_training_programs_owner ON training_programs(owner_id, created_at DESC)",
+		`CREATE TABLE IF NOT EXISTS program_days (
+			id UUID PRIMARY KEY,
+			program_id UUID NOT NULL REFERENCES training_programs(id) ON DELETE CASCADE,
+			week INTEGER NOT NULL CHECK (week > 0),
+			day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+			template_id UUID NOT NULL REFERENCES workout_templates(id) ON DELETE RESTRICT,
+			UNIQUE(program_id, week, day_of_week)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_program_days_template ON program_days(template_id)",
+		"ALTER TABLE exercises ADD COLUMN IF NOT EXISTS workout_id UUID REFERENCES workouts(id) ON DELETE SET NULL",
+		"CREATE INDEX IF NOT EXISTS idx_exercises_workout ON exercises(workout_id, created_at) WHERE workout_id IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_workouts_user_created_id ON workouts(user_id, created_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_workouts_user_type_created ON workouts(user_id, type, created_at DESC, id DESC)",
+		`CREATE TABLE IF NOT EXISTS workout_imports (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			source TEXT NOT NULL,
+			file_hash TEXT NOT NULL,
+			workouts INTEGER NOT NULL DEFAULT 0,
+			exercises INTEGER NOT NULL DEFAULT 0,
+			sets INTEGER NOT NULL DEFAULT 0,
+			skipped_sessions INTEGER NOT NULL DEFAULT 0,
+			report JSONB NOT NULL DEFAULT '{}'::jsonb,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE(user_id, file_hash)
+		)`,
+		`CREATE TABLE IF NOT EXISTS import_sessions (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			source_key TEXT NOT NULL,
+			import_id UUID NOT NULL REFERENCES workout_imports(id) ON DELETE CASCADE,
+			workout_id UUID REFERENCES workouts(id) ON DELETE SET NULL,
+			PRIMARY KEY (user_id, source_key)
+		)`,
+		"ALTER TABLE sets ADD COLUMN IF NOT EXISTS set_type TEXT NOT NULL DEFAULT 'working' CHECK (set_type IN ('warmup', 'working', 'dropset', 'failure', 'amrap'))",
+		"ALTER TABLE sets ADD COLUMN IF NOT EXISTS rest_seconds INTEGER CHECK (rest_seconds >= 0)",
+		"ALTER TABLE sets ADD COLUMN IF NOT EXISTS duration_seconds INTEGER CHECK (duration_seconds > 0)",
+		"ALTER TABLE sets ADD COLUMN IF NOT EXISTS distance_meters NUMERIC(10,2) CHECK (distance_meters > 0)",
+		"ALTER TABLE exercises ADD COLUMN IF NOT EXISTS group_id TEXT",
+		"ALTER TABLE exercises ADD COLUMN IF NOT EXISTS group_type TEXT CHECK (group_type IN ('superset', 'circuit'))",
+		"CREATE INDEX IF NOT EXISTS idx_sets_exercise_working ON sets(exercise_id) INCLUDE (reps, weight_kg) WHERE set_type <> 'warmup'",
+		"DROP INDEX IF EXISTS idx_sets_exercise_load",
+		`CREATE TABLE IF NOT EXISTS exercise_catalog (
+			id UUID PRIMARY KEY,
+			slug TEXT UNIQUE NOT NULL,
+			name TEXT UNIQUE NOT NULL,
+			aliases TEXT[] NOT NULL DEFAULT '{}',
+			primary_muscles TEXT[] NOT NULL DEFAULT '{}',
+			secondary_muscles TEXT[] NOT NULL DEFAULT '{}',
+			equipment TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_exercise_catalog_name_trgm ON exercise_catalog USING gin (name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_exercise_catalog_primary_muscles ON exercise_catalog USING gin (primary_muscles)",
+		`CREATE TABLE IF NOT EXISTS exercise_catalog_machines (
+			catalog_id UUID NOT NULL REFERENCES exercise_catalog(id) ON DELETE CASCADE,
+			machine_id UUID NOT NULL REFERENCES machines(id) ON DELETE CASCADE,
+			PRIMARY KEY (catalog_id, machine_id)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_exercise_catalog_machines_machine ON exercise_catalog_machines(machine_id)",
+		`CREATE TABLE IF NOT EXISTS exercise_catalog_videos (
+			catalog_id UUID NOT NULL REFERENCES exercise_catalog(id) ON DELETE CASCADE,
+			video_id UUID NOT NULL REFERENCES instruction_videos(id) ON DELETE CASCADE,
+			PRIMARY KEY (catalog_id, video_id)
+		)`,
+		"ALTER TABLE exercises ADD COLUMN IF NOT EXISTS catalog_id UUID REFERENCES exercise_catalog(id) ON DELETE SET NULL",
+		"ALTER TABLE exercises ADD COLUMN IF NOT EXISTS catalog_checked_at TIMESTAMP WITH TIME ZONE",
+		"CREATE INDEX IF NOT EXISTS idx_exercises_catalog_unchecked ON exercises(created_at) WHERE catalog_id IS NULL AND catalog_checked_at IS NULL",
+		"CREATE SEQUENCE IF NOT EXISTS sync_seq",
+		`CREATE TABLE IF NOT EXISTS sync_entities (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			entity_type TEXT NOT NULL CHECK (entity_type IN ('workout', 'exercise', 'set', 'checkin')),
+			entity_id UUID NOT NULL,
+			tx_id BIGINT NOT NULL,
+			seq BIGINT NOT NULL,
+			deleted BOOLEAN NOT NULL DEFAULT false,
+			version_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			version_id TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (user_id, entity_type, entity_id)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_sync_entities_user_position ON sync_entities(user_id, tx_id, seq)",
+		`CREATE TABLE IF NOT EXISTS sync_mutations (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			mutation_id TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			entity_id UUID NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, mutation_id)
+		)`,
+		`CREATE OR REPLACE FUNCTION record_sync_change() RETURNS trigger AS $$
+		DECLARE
+			changed RECORD;
+			owner UUID;
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				changed := OLD;
+			ELSE
+				changed := NEW;
+			END IF;
+
+			IF TG_ARGV[0] = 'set' THEN
+				SELECT user_id INTO owner FROM exercises WHERE id = changed.exercise_id;
+			ELSE
+				owner := changed.user_id;
+			END IF;
+
+			PERFORM 1 FROM users WHERE id = owner;
+			IF NOT FOUND THEN
+				RETURN NULL;
+			END IF;
+
+			INSERT INTO sync_entities (user_id, entity_type, entity_id, tx_id, seq, deleted, version_at, version_id)
+			VALUES (owner, TG_ARGV[0], changed.id, txid_current(), nextval('sync_seq'), TG_OP = 'DELETE', NOW(), '')
+			ON CONFLICT (user_id, entity_type, entity_id) DO UPDATE
+			SET tx_id = EXCLUDED.tx_id,
+			    seq = EXCLUDED.seq,
+			    deleted = EXCLUDED.deleted,
+			    version_at = EXCLUDED.version_at,
+			    version_id = EXCLUDED.version_id;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql`,
+		"CREATE OR REPLACE TRIGGER workouts_sync AFTER INSERT OR UPDATE OR DELETE ON workouts FOR EACH ROW EXECUTE FUNCTION record_sync_change('workout')",
+		"CREATE OR REPLACE TRIGGER exercises_sync AFTER INSERT OR UPDATE OR DELETE ON exercises FOR EACH ROW EXECUTE FUNCTION record_sync_change('exercise')",
+		"CREATE OR REPLACE TRIGGER sets_sync AFTER INSERT OR UPDATE OR DELETE ON sets FOR EACH ROW EXECUTE FUNCTION record_sync_change('set')",
+		"CREATE OR REPLACE TRIGGER checkins_sync AFTER INSERT OR UPDATE OR DELETE ON checkins FOR EACH ROW EXECUTE FUNCTION record_sync_change('checkin')",
+		`CREATE OR REPLACE FUNCTION record_exercise_set_tombstones() RETURNS trigger AS $$
+		BEGIN
+			INSERT INTO sync_entities (user_id, entity_type, entity_id, tx_id, seq, deleted, version_at, version_id)
+			SELECT u.id, 'set', s.id, txid_current(), nextval('sync_seq'), TRUE, NOW(), ''
+			FROM sets s
+			JOIN users u ON u.id = OLD.user_id
+			WHERE s.exercise_id = OLD.id
+			ON CONFLICT (user_id, entity_type, entity_id) DO UPDATE
+			SET tx_id = EXCLUDED.tx_id,
+			    seq = EXCLUDED.seq,
+			    deleted = EXCLUDED.deleted,
+			    version_at = EXCLUDED.version_at,
+			    version_id = EXCLUDED.version_id;
+			RETURN OLD;
+		END;
+		$$ LANGUAGE plpgsql`,
+		"CREATE OR REPLACE TRIGGER exercises_sync_set_tombstones BEFORE DELETE ON exercises FOR EACH ROW EXECUTE FUNCTION record_exercise_set_tombstones()",
+		`CREATE TABLE IF NOT EXISTS workout_sessions (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL DEFAULT '',
+			gym_id UUID REFERENCES gyms(id) ON DELETE SET NULL,
+			status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'finished', 'discarded')),
+			exercises JSONB NOT NULL DEFAULT '[]'::jsonb,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			paused_at TIMESTAMP WITH TIME ZONE,
+			paused_seconds INTEGER NOT NULL DEFAULT 0 CHECK (paused_seconds >= 0),
+			last_activity_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			finished_at TIMESTAMP WITH TIME ZONE,
+			auto_finished BOOLEAN NOT NULL DEFAULT FALSE,
+			workout_id UUID REFERENCES workouts(id) ON DELETE SET NULL
+		)`,
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_sessions_one_live ON workout_sessions(user_id) WHERE status IN ('active', 'paused')",
+		"CREATE INDEX IF NOT EXISTS idx_workout_sessions_live_activity ON workout_sessions(last_activity_at) WHERE status IN ('active', 'paused')",
+		`CREATE TABLE IF NOT EXISTS training_load_days (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			session_load NUMERIC(10,1) NOT NULL DEFAULT 0,
+			set_effort NUMERIC(10,1) NOT NULL DEFAULT 0,
+			sets INTEGER NOT NULL DEFAULT 0,
+			hard_sets INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (user_id, day)
+		)`,
+		`CREATE TABLE IF NOT EXISTS training_muscle_days (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			body_part TEXT NOT NULL,
+			sets INTEGER NOT NULL,
+			PRIMARY KEY (user_id, day, body_part)
+		)`,
+		`CREATE TABLE IF NOT EXISTS training_load_dirty (
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			PRIMARY KEY (user_id, day)
+		)`,
+		`CREATE OR REPLACE FUNCTION mark_training_load_dirty() RETURNS trigger AS $$
+		DECLARE
+			owner UUID;
+			performed TIMESTAMP WITH TIME ZONE;
+			parent UUID;
+			parent_performed TIMESTAMP WITH TIME ZONE;
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				parent := NULL;
+				parent_performed := NULL;
+				IF TG_TABLE_NAME = 'sets' THEN
+					SELECT user_id, created_at, workout_id INTO owner, performed, parent FROM exercises WHERE id = OLD.exercise_id;
+				ELSIF TG_TABLE_NAME = 'exercises' THEN
+					owner := OLD.user_id;
+					performed := OLD.created_at;
+					parent := OLD.workout_id;
+				ELSE
+					owner := OLD.user_id;
+					performed := OLD.created_at;
+				END IF;
+				IF parent IS NOT NULL THEN
+					SELECT created_at INTO parent_performed FROM workouts WHERE id = parent;
+				END IF;
+				INSERT INTO training_load_dirty (user_id, day)
+				SELECT u.id, (d.at AT TIME ZONE 'UTC')::date
+				FROM users u, (VALUES (performed), (parent_performed)) AS d(at)
+				WHERE u.id = owner AND d.at IS NOT NULL
+				ON CONFLICT DO NOTHING;
+			END IF;
+
+			IF TG_OP <> 'DELETE' THEN
+				parent := NULL;
+				parent_performed := NULL;
+				IF TG_TABLE_NAME = 'sets' THEN
+					SELECT user_id, created_at, workout_id INTO owner, performed, parent FROM exercises WHERE id = NEW.exercise_id;
+				ELSIF TG_TABLE_NAME = 'exercises' THEN
+					owner := NEW.user_id;
+					performed := NEW.created_at;
+					parent := NEW.workout_id;
+				ELSE
+					owner := NEW.user_id;
+					performed := NEW.created_at;
+				END IF;
+				IF parent IS NOT NULL THEN
+					SELECT created_at INTO parent_performed FROM workouts WHERE id = parent;
+				END IF;
+				INSERT INTO training_load_dirty (user_id, day)
+				SELECT u.id, (d.at AT TIME ZONE 'UTC')::date
+				FROM users u, (VALUES (performed), (parent_performed)) AS d(at)
+				WHERE u.id = owner AND d.at IS NOT NULL
+				ON CONFLICT DO NOTHING;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql`,
+		`CREATE OR REPLACE FUNCTION mark_machine_training_load_dirty() RETURNS trigger AS $$
+		BEGIN
+			IF OLD.body_part IS DISTINCT FROM NEW.body_part THEN
+				INSERT INTO training_load_dirty (user_id, day)
+				SELECT DISTINCT user_id, (created_at AT TIME ZONE 'UTC')::date FROM exercises WHERE machine_id = NEW.id
+				ON CONFLICT DO NOTHING;
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql`,
+		"CREATE OR REPLACE TRIGGER workouts_training_load AFTER INSERT OR DELETE OR UPDATE OF user_id, duration, created_at ON workouts FOR EACH ROW EXECUTE FUNCTION mark_training_load_dirty()",
+		"CREATE OR REPLACE TRIGGER exercises_training_load AFTER INSERT OR DELETE OR UPDATE OF user_id, workout_id, machine_id, created_at ON exercises FOR EACH ROW EXECUTE FUNCTION mark_training_load_dirty()",
+		"CREATE OR REPLACE TRIGGER sets_training_load AFTER INSERT OR UPDATE OR DELETE ON sets FOR EACH ROW EXECUTE FUNCTION mark_training_load_dirty()",
+		"CREATE OR REPLACE TRIGGER machines_training_load AFTER UPDATE OF body_part ON machines FOR EACH ROW EXECUTE FUNCTION mark_machine_training_load_dirty()",
+		`INSERT INTO training_load_dirty (user_id, day)
+		SELECT user_id, (created_at AT TIME ZONE 'UTC')::date FROM exercises
+		WHERE NOT EXISTS (SELECT 1 FROM training_load_days) AND NOT EXISTS (SELECT 1 FROM training_load_dirty)
+		UNION
+		SELECT user_id, (created_at AT TIME ZONE 'UTC')::date FROM workouts
+		WHERE NOT EXISTS (SELECT 1 FROM training_load_days) AND NOT EXISTS (SELECT 1 FROM training_load_dirty)
+		ON CONFLICT DO NOTHING`,
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS weight_unit TEXT NOT NULL DEFAULT 'kg'",
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS length_unit TEXT NOT NULL DEFAULT 'cm'",
+		`CREATE TABLE IF NOT EXISTS body_metrics (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			metric_type TEXT NOT NULL,
+			value NUMERIC(7,2) NOT NULL CHECK (value > 0),
+			recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			notes TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_body_metrics_user_type ON body_metrics(user_id, metric_type, recorded_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_body_metrics_user_recorded ON body_metrics(user_id, recorded_at DESC, id DESC)",
+		`CREATE TABLE IF NOT EXISTS body_weight_goals (
+			user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			target_kg NUMERIC(7,2) NOT NULL CHECK (target_kg > 0),
+			start_kg NUMERIC(7,2),
+			target_date DATE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS goals (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			goal_type TEXT NOT NULL,
+			title TEXT NOT NULL,
+			target NUMERIC(8,2) NOT NULL CHECK (target > 0),
+			machine_id UUID REFERENCES machines(id) ON DELETE SET NULL,
+			exercise_name TEXT,
+			reps INTEGER,
+			weeks INTEGER,
+			deadline DATE,
+			status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'expired')),
+			current_value NUMERIC(10,2) NOT NULL DEFAULT 0,
+			progress_percent NUMERIC(5,1) NOT NULL DEFAULT 0,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			evaluated_at TIMESTAMP WITH TIME ZONE,
+			completed_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_goals_user_status ON goals(user_id, status)",
+		"CREATE INDEX IF NOT EXISTS idx_goals_active_evaluated ON goals(evaluated_at) WHERE status = 'active'",
+		"ALTER TABLE machines ADD COLUMN IF NOT EXISTS met NUMERIC(4,1) CHECK (met > 0)",
+		`CREATE TABLE IF NOT EXISTS cardio_tracks (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			workout_id UUID REFERENCES workouts(id) ON DELETE CASCADE,
+			format TEXT NOT NULL CHECK (format IN ('gpx', 'tcx')),
+			sport TEXT NOT NULL,
+			name TEXT NOT NULL,
+			file_key TEXT NOT NULL,
+			file_hash TEXT NOT NULL,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			duration_seconds INTEGER NOT NULL CHECK (duration_seconds >= 0),
+			distance_m NUMERIC(10,1) NOT NULL DEFAULT 0,
+			elevation_gain_m NUMERIC(8,1) NOT NULL DEFAULT 0,
+			avg_pace_seconds_per_km NUMERIC(8,1),
+			avg_speed_kmh NUMERIC(6,2),
+			avg_heart_rate INTEGER,
+			max_heart_rate INTEGER,
+			points INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, file_hash)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_cardio_tracks_workout ON cardio_tracks(workout_id)",
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'))",
+		"ALTER TABLE gyms ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL",
+		"ALTER TABLE gyms ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()",
+		"CREATE INDEX IF NOT EXISTS idx_gyms_owner ON gyms(owner_id)",
+		`CREATE TABLE IF NOT EXISTS gym_claims (
+			id UUID PRIMARY KEY,
+			gym_id UUID NOT NULL REFERENCES gyms(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+			message TEXT,
+			reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			review_note TEXT,
+			reviewed_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_gym_claims_pending ON gym_claims(gym_id, user_id) WHERE status = 'pending'",
+		"CREATE INDEX IF NOT EXISTS idx_gym_claims_status_created ON gym_claims(status, created_at)",
+		"CREATE INDEX IF NOT EXISTS idx_gym_claims_user ON gym_claims(user_id, created_at DESC)",
+		"ALTER TABLE gyms ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC'",
+		"ALTER TABLE gyms ADD COLUMN IF NOT EXISTS open_24_7 BOOLEAN NOT NULL DEFAULT false",
+		`CREATE TABLE IF NOT EXISTS gym_hours (
+			gym_id UUID NOT NULL REFERENCES gyms(id) ON DELETE CASCADE,
+			weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+			opens_at TIME NOT NULL,
+			closes_at TIME NOT NULL CHECK (closes_at <> opens_at),
+			PRIMARY KEY (gym_id, weekday, opens_at)
+		)`,
+		`CREATE TABLE IF NOT EXISTS gym_holidays (
+			gym_id UUID NOT NULL REFERENCES gyms(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (gym_id, day)
+		)`,
+		`CREATE TABLE IF NOT EXISTS gym_holiday_hours (
+			gym_id UUID NOT NULL,
+			day DATE NOT NULL,
+			opens_at TIME NOT NULL,
+			closes_at TIME NOT NULL CHECK (closes_at <> opens_at),
+			PRIMARY KEY (gym_id, day, opens_at),
+			FOREIGN KEY (gym_id, day) REFERENCES gym_holidays(gym_id, day) ON DELETE CASCADE
+		)`,
+		`CREATE OR REPLACE FUNCTION gym_open_at(target UUID, moment TIMESTAMP WITH TIME ZONE) RETURNS BOOLEAN AS $$
+		DECLARE
+			tz TEXT;
+			always_open BOOLEAN;
+			local_at TIMESTAMP;
+		BEGIN
+			SELECT timezone, open_24_7 INTO tz, always_open FROM gyms WHERE id = target;
+			IF NOT FOUND THEN
+				RETURN false;
+			END IF;
+			local_at := moment AT TIME ZONE tz;
+
+			RETURN EXISTS (
+				WITH days AS (
+					SELECT local_at::date AS day, true AS today
+					UNION ALL
+					SELECT local_at::date - 1, false
+				),
+				intervals AS (
+					SELECT d.today, hh.opens_at, hh.closes_at
+					FROM days d
+					JOIN gym_holiday_hours hh ON hh.gym_id = target AND hh.day = d.day
+					UNION ALL
+					SELECT d.today, TIME '00:00', TIME '24:00'
+					FROM days d
+					WHERE always_open
+					  AND NOT EXISTS (SELECT 1 FROM gym_holidays h WHERE h.gym_id = target AND h.day = d.day)
+					UNION ALL
+					SELECT d.today, wh.opens_at, wh.closes_at
+					FROM days d
+					JOIN gym_hours wh ON wh.gym_id = target AND wh.weekday = EXTRACT(DOW FROM d.day)
+					WHERE NOT always_open
+					  AND NOT EXISTS (SELECT 1 FROM gym_holidays h WHERE h.gym_id = target AND h.day = d.day)
+				)
+				SELECT 1 FROM intervals i
+				WHERE (i.today AND local_at::time >= i.opens_at AND (i.closes_at < i.opens_at OR local_at::time < i.closes_at))
+				   OR (NOT i.today AND i.closes_at < i.opens_at AND local_at::time < i.closes_at)
+			);
+		END;
+		$$ LANGUAGE plpgsql STABLE`,
+		`CREATE TABLE IF NOT EXISTS gym_amenities (
+			gym_id UUID NOT NULL REFERENCES gyms(id) ON DELETE CASCADE,
+			amenity TEXT NOT NULL,
+			PRIMARY KEY (gym_id, amenity)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_machines_body_part_lower ON machines(LOWER(body_part))",
+		"CREATE INDEX IF NOT EXISTS idx_gyms_point ON gyms USING gist (point(lng, lat))",
+		`DO $$
+		BEGIN
+			CREATE EXTENSION IF NOT EXISTS cube;
+			CREATE EXTENSION IF NOT EXISTS earthdistance;
+			CREATE INDEX IF NOT EXISTS idx_gyms_earth ON gyms USING gist (ll_to_earth(lat, lng));
+		EXCEPTION WHEN OTHERS THEN
+			RAISE NOTICE 'earthdistance is unavailable, nearby search will use idx_gyms_point: %', SQLERRM;
+		END;
+		$$`,
+		"ALTER TABLE gym_reviews ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE",
+		"ALTER TABLE gym_reviews ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE",
+		"ALTER TABLE gym_reviews ADD COLUMN IF NOT EXISTS helpful_count INTEGER NOT NULL DEFAULT 0",
+		`UPDATE gym_reviews gr SET deleted_at = NOW()
+		WHERE gr.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM gym_reviews newer
+			WHERE newer.gym_id = gr.gym_id AND newer.user_id = gr.user_id AND newer.deleted_at IS NULL
+			  AND (newer.created_at, newer.id) > (gr.created_at, gr.id)
+		)`,
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_gym_reviews_active ON gym_reviews(gym_id, user_id) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_gym_reviews_helpful ON gym_reviews(gym_id, helpful_count DESC, created_at DESC) WHERE deleted_at IS NULL",
+		`CREATE TABLE IF NOT EXISTS gym_review_edits (
+			id UUID PRIMARY KEY,
+			review_id UUID NOT NULL REFERENCES gym_reviews(id) ON DELETE CASCADE,
+			rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			comment TEXT,
+			edited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_gym_review_edits_review ON gym_review_edits(review_id, edited_at DESC)",
+		`CREATE TABLE IF NOT EXISTS gym_review_votes (
+			review_id UUID NOT NULL REFERENCES gym_reviews(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (review_id, user_id)
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_gym_review_votes_user ON gym_review_votes(user_id)",
+		`CREATE TABLE IF NOT EXISTS gym_review_stats (
+			gym_id UUID PRIMARY KEY REFERENCES gyms(id) ON DELETE CASCADE,
+			review_count INTEGER NOT NULL DEFAULT 0,
+			rating_sum INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		`INSERT INTO gym_review_stats (gym_id, review_count, rating_sum)
+		SELECT gym_id, COUNT(*), SUM(rating)
+		FROM gym_reviews
+		WHERE deleted_at IS NULL
+		GROUP BY gym_id
+		ON CONFLICT (gym_id) DO NOTHING`,
+		`CREATE TABLE IF NOT EXISTS gym_review_photos (
+			id UUID PRIMARY KEY,
+			review_id UUID NOT NULL REFERENCES gym_reviews(id) ON DELETE CASCADE,
+			object_key TEXT NOT NULL UNIQUE,
+			thumb_key TEXT NOT NULL,
+			large_key TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			bytes BIGINT NOT NULL,
+			width INTEGER NOT NULL,
+			height INTEGER NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_gym_review_photos_review ON gym_review_photos(review_id, created_at)",
+		`INSERT INTO workout_templates (id, owner_id, name, description, copied_from, created_at, updated_at)
+		SELECT DISTINCT md5(p.owner_id::text || ':' || t.id::text)::uuid, p.owner_id, t.name, t.description, t.id, NOW(), NOW()
+		FROM program_days pd
+		JOIN training_programs p ON p.id = pd.program_id
+		JOIN workout_templates t ON t.id = pd.template_id
+		WHERE t.owner_id <> p.owner_id
+		ON CONFLICT (id) DO NOTHING`,
+		`INSERT INTO template_exercises (id, template_id, position, machine_id, name, target_sets, target_reps, target_weight_kg, notes)
+		SELECT md5(copies.id::text || ':' || te.id::text)::uuid, copies.id, te.position, te.machine_id, te.name,
+			te.target_sets, te.target_reps, te.target_weight_kg, te.notes
+		FROM (
+			SELECT DISTINCT md5(p.owner_id::text || ':' || t.id::text)::uuid AS id, t.id AS source_id
+			FROM program_days pd
+			JOIN training_programs p ON p.id = pd.program_id
+			JOIN workout_templates t ON t.id = pd.template_id
+			WHERE t.owner_id <> p.owner_id
+		) copies
+		JOIN template_exercises te ON te.template_id = copies.source_id
+		ON CONFLICT (id) DO NOTHING`,
+		`UPDATE program_days pd
+		SET template_id = md5(p.owner_id::text || ':' || t.id::text)::uuid
+		FROM training_programs p, workout_templates t
+		WHERE p.id = pd.program_id AND t.id = pd.template_id AND t.owner_id <> p.owner_id`,
+		`DO $$
+		BEGIN
+			IF EXISTS (
+				SELECT 1 FROM pg_constraint
+				WHERE conname = 'program_days_template_id_fkey' AND confdeltype = 'c'
+			) THEN
+				ALTER TABLE program_days DROP CONSTRAINT program_days_template_id_fkey;
+				ALTER TABLE program_days ADD CONSTRAINT program_days_template_id_fkey
+					FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE RESTRICT;
+			END IF;
+		END $$`,
+		"ALTER TABLE workouts ADD COLUMN IF NOT EXISTS planned BOOLEAN NOT NULL DEFAULT FALSE",
+		`CREATE TABLE IF NOT EXISTS planned_exercises (
+			id UUID PRIMARY KEY,
+			workout_id UUID NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+			position INTEGER NOT NULL,
+			gym_id UUID REFERENCES gyms(id) ON DELETE SET NULL,
+			machine_id UUID REFERENCES machines(id) ON DELETE SET NULL,
+			name TEXT NOT NULL,
+			sets INTEGER NOT NULL CHECK (sets > 0),
+			reps INTEGER NOT NULL CHECK (reps > 0),
+			rest_seconds INTEGER NOT NULL DEFAULT 0 CHECK (rest_seconds >= 0),
+			UNIQUE(workout_id, position)
+		)`,
+		"CREATE OR REPLACE TRIGGER workouts_training_load AFTER INSERT OR DELETE OR UPDATE OF user_id, duration, created_at, planned ON workouts FOR EACH ROW EXECUTE FUNCTION mark_training_load_dirty()",
 }
 
 	for _, stmt := range statements {
@@ -168,10 +742,46 @@ func Up(db *sql.DB) error {
 // Down rolls back the last migration
 func Down(db *sql.DB) error {
 	tables := []string{
+		"DROP TABLE IF EXISTS planned_exercises",
+		"DROP TABLE IF EXISTS gym_review_photos",
+		"DROP TABLE IF EXISTS gym_review_stats",
+		"DROP TABLE IF EXISTS gym_review_votes",
+		"DROP TABLE IF EXISTS gym_review_edits",
+		"DROP TABLE IF EXISTS gym_amenities",
+		"DROP FUNCTION IF EXISTS gym_open_at(UUID, TIMESTAMP WITH TIME ZONE)",
+		"DROP TABLE IF EXISTS gym_holiday_hours",
+		"DROP TABLE IF EXISTS gym_holidays",
+		"DROP TABLE IF EXISTS gym_hours",
+		"DROP TABLE IF EXISTS gym_claims",
+		"DROP TABLE IF EXISTS cardio_tracks",
+		"DROP TABLE IF EXISTS goals",
+		"DROP TABLE IF EXISTS body_weight_goals",
+		"DROP TABLE IF EXISTS body_metrics",
+		"DROP FUNCTION IF EXISTS mark_machine_training_load_dirty() CASCADE",
+		"DROP FUNCTION IF EXISTS mark_training_load_dirty() CASCADE",
+		"DROP TABLE IF EXISTS training_load_dirty",
+		"DROP TABLE IF EXISTS training_muscle_days",
+		"DROP TABLE IF EXISTS training_load_days",
+		"DROP TABLE IF EXISTS workout_sessions",
+		"DROP TABLE IF EXISTS sync_mutations",
+		"DROP TABLE IF EXISTS sync_entities",
+		"DROP FUNCTION IF EXISTS record_exercise_set_tombstones() CASCADE",
+		"DROP FUNCTION IF EXISTS record_sync_change() CASCADE",
+		"DROP SEQUENCE IF EXISTS sync_seq",
+		"DROP TABLE IF EXISTS exercise_catalog_videos",
+		"DROP TABLE IF EXISTS exercise_catalog_machines",
+		"DROP TABLE IF EXISTS import_sessions",
+		"DROP TABLE IF EXISTS workout_imports",
+		"DROP TABLE IF EXISTS program_days",
+		"DROP TABLE IF EXISTS training_programs",
+		"DROP TABLE IF EXISTS template_exercises",
+		"DROP TABLE IF EXISTS workout_templates",
+		"DROP TABLE IF EXISTS personal_records",
 		"DROP TABLE IF EXISTS gym_price_cache",
 		"DROP TABLE IF EXISTS moderation_reports",
 		"DROP TABLE IF EXISTS sets",
 		"DROP TABLE IF EXISTS exercises",
+		"DROP TABLE IF EXISTS exercise_catalog",
 		"DROP TABLE IF EXISTS checkins",
 		"DROP TABLE IF EXISTS video_likes",
 		"DROP TABLE IF EXISTS instruction_videos",
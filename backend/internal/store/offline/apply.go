@@ -0,0 +1,204 @@
+package offline
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const foreignKeyViolation = "23503"
+
+func applyMutation(tx *sql.Tx, userID string, mutation models.SyncMutation) error {
+	switch mutation.Entity {
+	case models.SyncEntityWorkout:
+		if mutation.Op == models.SyncOpDelete {
+			return execOwned(tx, `DELETE FROM workouts WHERE id = $1 AND user_id = $2`, mutation.EntityID, userID)
+		}
+		return upsertWorkout(tx, userID, mutation)
+	case models.SyncEntityExercise:
+		if mutation.Op == models.SyncOpDelete {
+			return execOwned(tx, `DELETE FROM exercises WHERE id = $1 AND user_id = $2`, mutation.EntityID, userID)
+		}
+		return upsertExercise(tx, userID, mutation)
+	case models.SyncEntitySet:
+		if mutation.Op == models.SyncOpDelete {
+			return execOwned(tx, `
+				DELETE FROM sets s USING exercises e
+				WHERE s.id = $1 AND s.exercise_id = e.id AND e.user_id = $2
+			`, mutation.EntityID, userID)
+		}
+		return upsertSet(tx, userID, mutation)
+	case models.SyncEntityCheckin:
+		if mutation.Op == models.SyncOpDelete {
+			return execOwned(tx, `DELETE FROM checkins WHERE id = $1 AND user_id = $2`, mutation.EntityID, userID)
+		}
+		return upsertCheckin(tx, userID, mutation)
+	default:
+		return rejected("unknown entity " + mutation.Entity)
+	}
+}
+
+// execOwned runs a delete scoped to the user. Deleting a row that is already
+// gone is fine: the tombstone is recorded either way.
+func execOwned(tx *sql.Tx, query string, args ...interface{}) error {
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to apply sync delete: %w", err)
+	}
+	return nil
+}
+
+// upsertOwned runs an insert whose ON CONFLICT update is limited to the
+// user's own rows. No affected row means the ID belongs to someone else.
+func upsertOwned(tx *sql.Tx, query string, args ...interface{}) error {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return rejected("a referenced gym, machine, catalog exercise or workout does not exist")
+		}
+		return fmt.Errorf("failed to apply sync upsert: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return rejected("entity_id is already in use")
+	}
+	return nil
+}
+
+func upsertWorkout(tx *sql.Tx, userID string, mutation models.SyncMutation) error {
+	workout := mutation.Workout
+	createdAt := workout.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = mutation.UpdatedAt
+	}
+
+	return upsertOwned(tx, `
+		INSERT INTO workouts (id, user_id, name, description, duration, type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name,
+		    description = EXCLUDED.description,
+		    duration = EXCLUDED.duration,
+		    type = EXCLUDED.type,
+		    created_at = EXCLUDED.created_at,
+		    updated_at = NOW()
+		WHERE workouts.user_id = EXCLUDED.user_id
+	`, mutation.EntityID, userID, workout.Name, workout.Description, workout.Duration, workout.Type, createdAt.UTC())
+}
+
+func upsertExercise(tx *sql.Tx, userID string, mutation models.SyncMutation) error {
+	exercise := mutation.Exercise
+	createdAt := exercise.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = mutation.UpdatedAt
+	}
+
+	if exercise.WorkoutID != nil {
+		var exists int
+		err := tx.QueryRow(`SELECT 1 FROM workouts WHERE id = $1 AND user_id = $2`, *exercise.WorkoutID, userID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return rejected("workout not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check workout: %w", err)
+		}
+		// Logging into a planned workout performs it when the exercise was done.
+		if _, err := tx.Exec(`
+			UPDATE workouts SET planned = FALSE, created_at = $2, updated_at = NOW()
+			WHERE id = $1 AND planned
+		`, *exercise.WorkoutID, createdAt.UTC()); err != nil {
+			return fmt.Errorf("failed to perform planned workout: %w", err)
+		}
+	}
+
+	var groupID, groupType interface{}
+	if exercise.Group != nil {
+		groupID, groupType = exercise.Group.ID, exercise.Group.Type
+	}
+
+	return upsertOwned(tx, `
+		INSERT INTO exercises (id, user_id, workout_id, gym_id, machine_id, catalog_id, name, group_id, group_type, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE
+		SET workout_id = EXCLUDED.workout_id,
+		    gym_id = EXCLUDED.gym_id,
+		    machine_id = EXCLUDED.machine_id,
+		    catalog_id = EXCLUDED.catalog_id,
+		    name = EXCLUDED.name,
+		    group_id = EXCLUDED.group_id,
+		    group_type = EXCLUDED.group_type,
+		    created_at = EXCLUDED.created_at
+		WHERE exercises.user_id = EXCLUDED.user_id
+	`, mutation.EntityID, userID, exercise.WorkoutID, exercise.GymID, exercise.MachineID, exercise.CatalogID,
+		exercise.Name, groupID, groupType, createdAt.UTC())
+}
+
+func upsertSet(tx *sql.Tx, userID string, mutation models.SyncMutation) error {
+	set := mutation.Set
+
+	var exists int
+	err := tx.QueryRow(`SELECT 1 FROM exercises WHERE id = $1 AND user_id = $2 FOR UPDATE`, set.ExerciseID, userID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rejected("exercise not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check exercise: %w", err)
+	}
+
+	// A set without an index is appended; the exercise lock above keeps
+	// concurrent appends from picking the same slot.
+	var setIndex interface{}
+	if set.SetIndex > 0 {
+		setIndex = set.SetIndex
+	}
+
+	return upsertOwned(tx, `
+		INSERT INTO sets (id, exercise_id, set_index, reps, weight_kg, rpe, notes, set_type, rest_seconds, duration_seconds, distance_meters)
+		VALUES (
+			$1, $2,
+			COALESCE($3, (SELECT COALESCE(MAX(set_index), 0) + 1 FROM sets WHERE exercise_id = $2)),
+			$4, $5, $6, $7, $8, $9, $10, $11
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET exercise_id = EXCLUDED.exercise_id,
+		    set_index = COALESCE($3, sets.set_index),
+		    reps = EXCLUDED.reps,
+		    weight_kg = EXCLUDED.weight_kg,
+		    rpe = EXCLUDED.rpe,
+		    notes = EXCLUDED.notes,
+		    set_type = EXCLUDED.set_type,
+		    rest_seconds = EXCLUDED.rest_seconds,
+		    duration_seconds = EXCLUDED.duration_seconds,
+		    distance_meters = EXCLUDED.distance_meters
+		WHERE sets.exercise_id IN (SELECT id FROM exercises WHERE user_id = $12)
+	`, mutation.EntityID, set.ExerciseID, setIndex, set.Reps, set.WeightKg, set.RPE, set.Notes, set.Type,
+		set.RestSeconds, set.DurationSeconds, set.DistanceMeters, userID)
+}
+
+func upsertCheckin(tx *sql.Tx, userID string, mutation models.SyncMutation) error {
+	day := mutation.Checkin.Day
+
+	var existingID string
+	err := tx.QueryRow(`SELECT id FROM checkins WHERE user_id = $1 AND day = $2`, userID, day).Scan(&existingID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check existing check-in: %w", err)
+	}
+	if err == nil && existingID != mutation.EntityID {
+		return conflict("already checked in on this day")
+	}
+
+	return upsertOwned(tx, `
+		INSERT INTO checkins (id, user_id, day)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET day = EXCLUDED.day
+		WHERE checkins.user_id = EXCLUDED.user_id
+	`, mutation.EntityID, userID, day)
+}
@@ -0,0 +1,226 @@
+package offline
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+// outcomeError ends a mutation without applying it, such as one that points
+// at another user's row. The outcome is recorded so replays get the same answer.
+type outcomeError struct {
+	status string
+	reason string
+}
+
+func (e outcomeError) Error() string {
+	return e.reason
+}
+
+func rejected(reason string) error {
+	return outcomeError{status: models.SyncStatusRejected, reason: reason}
+}
+
+func conflict(reason string) error {
+	return outcomeError{status: models.SyncStatusConflict, reason: reason}
+}
+
+// Position is a point in a user's change feed. Changes are ordered by the
+// writing transaction first so a slow transaction cannot commit behind a
+// position that was already handed out.
+type Position struct {
+	TxID int64 `json:"t"`
+	Seq  int64 `json:"s"`
+}
+
+// EntityChange is the latest recorded state of one synced entity
+type EntityChange struct {
+	Entity   string
+	EntityID string
+	Deleted  bool
+	Position Position
+}
+
+// Store handles offline sync database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new offline sync store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Apply runs one mutation in its own transaction. A mutation ID that was
+// already processed returns the stored result without touching any rows.
+// Conflicts are resolved last-writer-wins on (updated_at, mutation ID), so
+// every replica settles on the same state whatever order mutations arrive in.
+func (s *Store) Apply(userID string, mutation models.SyncMutation) (models.SyncResult, error) {
+	result := models.SyncResult{MutationID: mutation.ID, EntityID: mutation.EntityID}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	claimed, err := tx.Exec(`
+		INSERT INTO sync_mutations (user_id, mutation_id, entity_type, entity_id, status)
+		VALUES ($1, $2, $3, $4, 'pending')
+		ON CONFLICT (user_id, mutation_id) DO NOTHING
+	`, userID, mutation.ID, mutation.Entity, mutation.EntityID)
+	if err != nil {
+		return result, fmt.Errorf("failed to record sync mutation: %w", err)
+	}
+	if rows, err := claimed.RowsAffected(); err != nil {
+		return result, fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rows == 0 {
+		tx.Rollback()
+		return s.storedResult(userID, mutation)
+	}
+
+	// Serialize writers of the same entity so the version check below holds.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1 || ':' || $2 || ':' || $3))`, userID, mutation.Entity, mutation.EntityID); err != nil {
+		return result, fmt.Errorf("failed to lock sync entity: %w", err)
+	}
+
+	var (
+		versionAt time.Time
+		versionID string
+	)
+	err = tx.QueryRow(`
+		SELECT version_at, version_id
+		FROM sync_entities
+		WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3
+	`, userID, mutation.Entity, mutation.EntityID).Scan(&versionAt, &versionID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result.Status = models.SyncStatusApplied
+	case err != nil:
+		return result, fmt.Errorf("failed to get sync version: %w", err)
+	case Newer(mutation.UpdatedAt, mutation.ID, versionAt, versionID):
+		result.Status = models.SyncStatusApplied
+	default:
+		result.Status = models.SyncStatusConflict
+		result.Error = "a newer change to this entity already exists"
+	}
+
+	if result.Status == models.SyncStatusApplied {
+		if _, err := tx.Exec(`SAVEPOINT sync_apply`); err != nil {
+			return result, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		if err := applyMutation(tx, userID, mutation); err != nil {
+			var outcome outcomeError
+			if !errors.As(err, &outcome) {
+				return result, err
+			}
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT sync_apply`); err != nil {
+				return result, fmt.Errorf("failed to roll back sync mutation: %w", err)
+			}
+			result.Status = outcome.status
+			result.Error = outcome.reason
+		}
+	}
+
+	if result.Status == models.SyncStatusApplied {
+		if _, err := tx.Exec(`
+			INSERT INTO sync_entities (user_id, entity_type, entity_id, tx_id, seq, deleted, version_at, version_id)
+			VALUES ($1, $2, $3, txid_current(), nextval('sync_seq'), $4, $5, $6)
+			ON CONFLICT (user_id, entity_type, entity_id) DO UPDATE
+			SET tx_id = EXCLUDED.tx_id,
+			    seq = EXCLUDED.seq,
+			    deleted = EXCLUDED.deleted,
+			    version_at = EXCLUDED.version_at,
+			    version_id = EXCLUDED.version_id
+		`, userID, mutation.Entity, mutation.EntityID, mutation.Op == models.SyncOpDelete, mutation.UpdatedAt.UTC(), mutation.ID); err != nil {
+			return result, fmt.Errorf("failed to record sync version: %w", err)
+		}
+	}
+
+	var errText interface{}
+	if result.Error != "" {
+		errText = result.Error
+	}
+	if _, err := tx.Exec(`
+		UPDATE sync_mutations SET status = $3, error = $4
+		WHERE user_id = $1 AND mutation_id = $2
+	`, userID, mutation.ID, result.Status, errText); err != nil {
+		return result, fmt.Errorf("failed to record sync result: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Store) storedResult(userID string, mutation models.SyncMutation) (models.SyncResult, error) {
+	result := models.SyncResult{MutationID: mutation.ID, Replayed: true}
+
+	var errText sql.NullString
+	if err := s.db.QueryRow(`
+		SELECT entity_id, status, error
+		FROM sync_mutations
+		WHERE user_id = $1 AND mutation_id = $2
+	`, userID, mutation.ID).Scan(&result.EntityID, &result.Status, &errText); err != nil {
+		return result, fmt.Errorf("failed to get sync mutation: %w", err)
+	}
+	if errText.Valid {
+		result.Error = errText.String
+	}
+	return result, nil
+}
+
+// Newer reports whether a write stamped (at, id) wins over the stored version.
+// Equal timestamps fall back to comparing IDs so the outcome never depends on
+// arrival order.
+func Newer(at time.Time, id string, versionAt time.Time, versionID string) bool {
+	if !at.Equal(versionAt) {
+		return at.After(versionAt)
+	}
+	return id > versionID
+}
+
+// Changes returns up to limit+1 entity changes after the given position,
+// oldest first. Only transactions older than every transaction still running
+// are included, which keeps positions safe to resume from.
+func (s *Store) Changes(userID string, after Position, limit int) ([]EntityChange, error) {
+	rows, err := s.db.Query(`
+		SELECT entity_type, entity_id, deleted, tx_id, seq
+		FROM sync_entities
+		WHERE user_id = $1
+			AND (tx_id, seq) > ($2, $3)
+			AND tx_id < txid_snapshot_xmin(txid_current_snapshot())
+		ORDER BY tx_id ASC, seq ASC
+		LIMIT $4
+	`, userID, after.TxID, after.Seq, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []EntityChange
+	for rows.Next() {
+		var change EntityChange
+		if err := rows.Scan(&change.Entity, &change.EntityID, &change.Deleted, &change.Position.TxID, &change.Position.Seq); err != nil {
+			return nil, fmt.Errorf("failed to scan sync change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sync change rows error: %w", err)
+	}
+
+	return changes, nil
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM sync_entities WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM sync_mutations WHERE user_id = $1`, userID)
+	return err
+}
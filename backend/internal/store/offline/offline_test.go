@@ -0,0 +1,102 @@
+package offline
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestNewerIsDeterministic(t *testing.T) {
+	at := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	if !Newer(at.Add(time.Second), "a", at, "z") {
+		t.Fatalf("later timestamp should win")
+	}
+	if Newer(at, "z", at.Add(time.Second), "a") {
+		t.Fatalf("earlier timestamp should lose")
+	}
+	if !Newer(at, "b", at, "a") || Newer(at, "a", at, "b") {
+		t.Fatalf("equal timestamps should be decided by mutation id")
+	}
+	if Newer(at, "a", at, "a") {
+		t.Fatalf("a mutation must not win over itself")
+	}
+}
+
+func TestApplyReplayReturnsStoredResult(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO sync_mutations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT entity_id, status, error").
+		WithArgs("user-1", "m-1").
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "status", "error"}).
+			AddRow("9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d", models.SyncStatusConflict, "a newer change to this entity already exists"))
+
+	result, err := New(db).Apply("user-1", models.SyncMutation{
+		ID:        "m-1",
+		Entity:    models.SyncEntityWorkout,
+		Op:        models.SyncOpDelete,
+		EntityID:  "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d",
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !result.Replayed || result.Status != models.SyncStatusConflict {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestApplyRecordsConflictForOlderWrite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	stored := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO sync_mutations").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("pg_advisory_xact_lock").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version_at, version_id").
+		WillReturnRows(sqlmock.NewRows([]string{"version_at", "version_id"}).AddRow(stored, ""))
+	mock.ExpectExec("UPDATE sync_mutations").
+		WithArgs("user-1", "m-2", models.SyncStatusConflict, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := New(db).Apply("user-1", models.SyncMutation{
+		ID:        "m-2",
+		Entity:    models.SyncEntityCheckin,
+		Op:        models.SyncOpDelete,
+		EntityID:  "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d",
+		UpdatedAt: stored.Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if result.Status != models.SyncStatusConflict || result.Replayed {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package programs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrProgramNotFound is returned when a program does not exist or is not owned by the caller.
+var ErrProgramNotFound = errors.New("program not found")
+
+// Store handles training program database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new programs store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create stores a program and its scheduled days.
+func (s *Store) Create(ownerID, name string, description *string, weeks int, startDate *time.Time, days []models.ProgramDay) (*models.TrainingProgram, error) {
+	now := time.Now().UTC()
+	program := &models.TrainingProgram{
+		ID:          uuid.New().String(),
+		OwnerID:     ownerID,
+		Name:        name,
+		Description: description,
+		Weeks:       weeks,
+		StartDate:   startDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO training_programs (id, owner_id, name, description, weeks, start_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, program.ID, program.OwnerID, program.Name, program.Description, program.Weeks, program.StartDate, program.CreatedAt, program.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create program: %w", err)
+	}
+
+	if err := insertDays(tx, program.ID, days); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetByID(program.ID, ownerID)
+}
+
+// GetByID returns a program owned by the caller with its days.
+func (s *Store) GetByID(id, ownerID string) (*models.TrainingProgram, error) {
+	program := &models.TrainingProgram{}
+	var (
+		description sql.NullString
+		startDate   sql.NullTime
+	)
+
+	err := s.db.QueryRow(`
+		SELECT id, owner_id, name, description, weeks, start_date, created_at, updated_at
+		FROM training_programs
+		WHERE id = $1 AND owner_id = $2
+	`, id, ownerID).Scan(&program.ID, &program.OwnerID, &program.Name, &description, &program.Weeks, &startDate, &program.CreatedAt, &program.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProgramNotFound
+		}
+		return nil, fmt.Errorf("failed to get program: %w", err)
+	}
+	if description.Valid {
+		program.Description = &description.String
+	}
+	if startDate.Valid {
+		value := startDate.Time.UTC()
+		program.StartDate = &value
+	}
+
+	days, err := s.getDays(program.ID)
+	if err != nil {
+		return nil, err
+	}
+	program.Days = days
+
+	return program, nil
+}
+
+// ListByOwner returns all programs of a user, newest first.
+func (s *Store) ListByOwner(ownerID string) ([]models.TrainingProgram, error) {
+	rows, err := s.db.Query(`SELECT id FROM training_programs WHERE owner_id = $1 ORDER BY created_at DESC, id DESC`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query programs: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan program: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("program rows error: %w", err)
+	}
+
+	items := make([]models.TrainingProgram, 0, len(ids))
+	for _, id := range ids {
+		program, err := s.GetByID(id, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *program)
+	}
+	return items, nil
+}
+
+// Update replaces a program's details and schedule.
+func (s *Store) Update(id, ownerID, name string, description *string, weeks int, startDate *time.Time, days []models.ProgramDay) (*models.TrainingProgram, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE training_programs
+		SET name = $1, description = $2, weeks = $3, start_date = $4, updated_at = $5
+		WHERE id = $6 AND owner_id = $7
+	`, name, description, weeks, startDate, time.Now().UTC(), id, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update program: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrProgramNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM program_days WHERE program_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear program days: %w", err)
+	}
+	if err := insertDays(tx, id, days); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetByID(id, ownerID)
+}
+
+// Delete removes a program owned by the caller.
+func (s *Store) Delete(id, ownerID string) error {
+	result, err := s.db.Exec(`DELETE FROM training_programs WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete program: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrProgramNotFound
+	}
+	return nil
+}
+
+func (s *Store) ExportByUser(userID string) ([]models.TrainingProgram, error) {
+	return s.ListByOwner(userID)
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	_, err := s.db.Exec(`DELETE FROM training_programs WHERE owner_id = $1`, userID)
+	return err
+}
+
+// Schedule resolves program days to calendar dates. Week 1 starts on the
+// Monday of the week containing start.
+func Schedule(program models.TrainingProgram, start time.Time) []models.ScheduledWorkout {
+	start = start.UTC()
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+
+	schedule := make([]models.ScheduledWorkout, 0, len(program.Days))
+	for _, programDay := range program.Days {
+		if programDay.Week < 1 || programDay.Week > program.Weeks {
+			continue
+		}
+		schedule = append(schedule, models.ScheduledWorkout{
+			Date:         monday.AddDate(0, 0, (programDay.Week-1)*7+programDay.DayOfWeek-1),
+			Week:         programDay.Week,
+			DayOfWeek:    programDay.DayOfWeek,
+			TemplateID:   programDay.TemplateID,
+			TemplateName: programDay.TemplateName,
+		})
+	}
+
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].Date.Before(schedule[j].Date)
+	})
+	return schedule
+}
+
+func insertDays(tx *sql.Tx, programID string, days []models.ProgramDay) error {
+	for i := range days {
+		days[i].ID = uuid.New().String()
+		days[i].ProgramID = programID
+
+		day := days[i]
+		if _, err := tx.Exec(`
+			INSERT INTO program_days (id, program_id, week, day_of_week, template_id)
+			VALUES ($1, $2, $3, $4, $5)
+		`, day.ID, day.ProgramID, day.Week, day.DayOfWeek, day.TemplateID); err != nil {
+			return fmt.Errorf("failed to create program day: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) getDays(programID string) ([]models.ProgramDay, error) {
+	rows, err := s.db.Query(`
+		SELECT pd.id, pd.program_id, pd.week, pd.day_of_week, pd.template_id, wt.name
+		FROM program_days pd
+		JOIN workout_templates wt ON wt.id = pd.template_id
+		WHERE pd.program_id = $1
+		ORDER BY pd.week ASC, pd.day_of_week ASC
+	`, programID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query program days: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.ProgramDay{}
+	for rows.Next() {
+		var day models.ProgramDay
+		if err := rows.Scan(&day.ID, &day.ProgramID, &day.Week, &day.DayOfWeek, &day.TemplateID, &day.TemplateName); err != nil {
+			return nil, fmt.Errorf("failed to scan program day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("program day rows error: %w", err)
+	}
+	return days, nil
+}
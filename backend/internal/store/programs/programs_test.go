@@ -0,0 +1,40 @@
+package programs
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestScheduleStartsOnMondayOfStartWeek(t *testing.T) {
+	program := models.TrainingProgram{
+		Weeks: 2,
+		Days: []models.ProgramDay{
+			{Week: 2, DayOfWeek: 1, TemplateID: "push"},
+			{Week: 1, DayOfWeek: 3, TemplateID: "pull"},
+			{Week: 1, DayOfWeek: 1, TemplateID: "push"},
+			{Week: 3, DayOfWeek: 1, TemplateID: "ignored"},
+		},
+	}
+	start := time.Date(2024, 5, 2, 15, 0, 0, 0, time.UTC) // Thursday
+
+	schedule := Schedule(program, start)
+
+	want := []struct {
+		date     time.Time
+		template string
+	}{
+		{time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC), "push"},
+		{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "pull"},
+		{time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), "push"},
+	}
+	if len(schedule) != len(want) {
+		t.Fatalf("expected %d scheduled workouts, got %+v", len(want), schedule)
+	}
+	for i, expected := range want {
+		if !schedule[i].Date.Equal(expected.date) || schedule[i].TemplateID != expected.template {
+			t.Fatalf("entry %d: expected %s on %v, got %+v", i, expected.template, expected.date, schedule[i])
+		}
+	}
+}
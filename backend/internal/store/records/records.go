@@ -0,0 +1,228 @@
+package records
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+)
+
+// loadGroup partitions rep records by load; other record types have a single best.
+const loadGroup = `CASE WHEN record_type = 'max_reps' THEN COALESCE(weight_kg, -1) END`
+
+const recordColumns = `
+	id, user_id, record_key, machine_id, exercise_name, record_type, value,
+	weight_kg, reps, previous_value, exercise_id, set_id, achieved_at
+`
+
+// Store handles personal record database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new records store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// currentQuery selects the best record per type (and per load for rep
+// records) for a record key.
+const currentQuery = `
+	SELECT DISTINCT ON (record_type, ` + loadGroup + `)` + recordColumns + `
+	FROM personal_records
+	WHERE user_id = $1 AND record_key = $2
+	ORDER BY record_type, ` + loadGroup + `, value DESC, achieved_at ASC
+`
+
+// Current returns the best record per type (and per load for rep records) for a record key.
+func (s *Store) Current(userID, recordKey string) ([]models.PersonalRecord, error) {
+	return queryRecords(s.db, currentQuery, userID, recordKey)
+}
+
+// ListCurrent returns the current bests for every lift of a user, optionally narrowed to one record key.
+func (s *Store) ListCurrent(userID, recordKey string) ([]models.PersonalRecord, error) {
+	query := `
+		SELECT DISTINCT ON (record_key, record_type, ` + loadGroup + `)` + recordColumns + `
+		FROM personal_records
+		WHERE user_id = $1 AND ($2 = '' OR record_key = $2)
+		ORDER BY record_key, record_type, ` + loadGroup + `, value DESC, achieved_at ASC
+	`
+	return s.query(query, userID, recordKey)
+}
+
+// Insert stores the records detect finds against the current records for a
+// record key. The key is locked for the transaction, so concurrent logs of
+// the same lift see each other's records instead of both inserting one.
+func (s *Store) Insert(userID, recordKey string, detect func(current []models.PersonalRecord) []models.PersonalRecord) ([]models.PersonalRecord, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockRecordKey(tx, userID, recordKey); err != nil {
+		return nil, err
+	}
+
+	current, err := queryRecords(tx, currentQuery, userID, recordKey)
+	if err != nil {
+		return nil, err
+	}
+
+	records := detect(current)
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	if err := insertRecords(tx, userID, records); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return records, nil
+}
+
+// ReplaceForKey rewrites the full record history for a record key.
+func (s *Store) ReplaceForKey(userID, recordKey string, records []models.PersonalRecord) ([]models.PersonalRecord, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockRecordKey(tx, userID, recordKey); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM personal_records WHERE user_id = $1 AND record_key = $2`, userID, recordKey); err != nil {
+		return nil, fmt.Errorf("failed to clear personal records: %w", err)
+	}
+
+	if err := insertRecords(tx, userID, records); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return records, nil
+}
+
+// ListHistory returns records for a key ordered by achieved_at DESC.
+func (s *Store) ListHistory(userID, recordKey string, limit int, cursor *pagination.TimeDescCursor) (pagination.Paginated[models.PersonalRecord], error) {
+	if limit <= 0 {
+		return pagination.Paginated[models.PersonalRecord]{}, pagination.ErrInvalidLimit
+	}
+
+	query := `SELECT` + recordColumns + `
+		FROM personal_records
+		WHERE user_id = $1 AND record_key = $2
+	`
+	args := []any{userID, recordKey}
+	if cursor != nil {
+		query += ` AND (achieved_at < $3 OR (achieved_at = $3 AND id < $4))`
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY achieved_at DESC, id DESC LIMIT $%d", len(args))
+
+	items, err := s.query(query, args...)
+	if err != nil {
+		return pagination.Paginated[models.PersonalRecord]{}, err
+	}
+
+	return pagination.TimeDescPage(items, limit, func(item models.PersonalRecord) pagination.TimeDescCursor {
+		return pagination.TimeDescCursor{CreatedAt: item.AchievedAt, ID: item.ID}
+	})
+}
+
+// lockRecordKey serializes record writes for one user's lift until the
+// transaction ends.
+func lockRecordKey(tx *sql.Tx, userID, recordKey string) error {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('personal_records:' || $1 || ':' || $2))`, userID, recordKey); err != nil {
+		return fmt.Errorf("failed to lock personal records: %w", err)
+	}
+	return nil
+}
+
+func insertRecords(tx *sql.Tx, userID string, records []models.PersonalRecord) error {
+	now := time.Now().UTC()
+	for i := range records {
+		records[i].ID = uuid.New().String()
+		records[i].UserID = userID
+		record := records[i]
+
+		if _, err := tx.Exec(`
+			INSERT INTO personal_records (
+				id, user_id, record_key, machine_id, exercise_name, record_type, value,
+				weight_kg, reps, previous_value, exercise_id, set_id, achieved_at, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, record.ID, record.UserID, record.RecordKey, record.MachineID, record.ExerciseName, record.RecordType, record.Value,
+			record.WeightKg, record.Reps, record.PreviousValue, record.ExerciseID, record.SetID, record.AchievedAt.UTC(), now); err != nil {
+			return fmt.Errorf("failed to create personal record: %w", err)
+		}
+	}
+	return nil
+}
+
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func (s *Store) query(query string, args ...any) ([]models.PersonalRecord, error) {
+	return queryRecords(s.db, query, args...)
+}
+
+func queryRecords(q queryer, query string, args ...any) ([]models.PersonalRecord, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query personal records: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.PersonalRecord
+	for rows.Next() {
+		var (
+			record    models.PersonalRecord
+			machineID sql.NullString
+			setID     sql.NullString
+		)
+		if err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.RecordKey,
+			&machineID,
+			&record.ExerciseName,
+			&record.RecordType,
+			&record.Value,
+			&record.WeightKg,
+			&record.Reps,
+			&record.PreviousValue,
+			&record.ExerciseID,
+			&setID,
+			&record.AchievedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan personal record: %w", err)
+		}
+		record.AchievedAt = record.AchievedAt.UTC()
+		if machineID.Valid {
+			value := machineID.String
+			record.MachineID = &value
+		}
+		if setID.Valid {
+			value := setID.String
+			record.SetID = &value
+		}
+		items = append(items, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("personal record rows error: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,54 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestInsertDetectsUnderRecordKeyLock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").
+		WithArgs("user-1", "name:bench press").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM personal_records").
+		WithArgs("user-1", "name:bench press").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "record_key", "machine_id", "exercise_name", "record_type", "value",
+			"weight_kg", "reps", "previous_value", "exercise_id", "set_id", "achieved_at",
+		}))
+	mock.ExpectExec("INSERT INTO personal_records").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var seen []models.PersonalRecord
+	records, err := New(db).Insert("user-1", "name:bench press", func(current []models.PersonalRecord) []models.PersonalRecord {
+		seen = current
+		return []models.PersonalRecord{{
+			RecordKey:  "name:bench press",
+			RecordType: models.RecordTypeMaxWeight,
+			Value:      100,
+			ExerciseID: "exercise-1",
+			AchievedAt: time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC),
+		}}
+	})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if len(seen) != 0 || len(records) != 1 || records[0].UserID != "user-1" || records[0].ID == "" {
+		t.Fatalf("unexpected records %+v (current %+v)", records, seen)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
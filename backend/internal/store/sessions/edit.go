@@ -0,0 +1,136 @@
+package sessions
+
+import (
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/strength"
+
+	"github.com/google/uuid"
+)
+
+func pause(session *models.WorkoutSession, now time.Time) {
+	if session.Status == models.SessionStatusPaused {
+		return
+	}
+	session.Status = models.SessionStatusPaused
+	session.PausedAt = &now
+}
+
+// resume folds a running pause into PausedSeconds.
+func resume(session *models.WorkoutSession, now time.Time) {
+	if session.PausedAt != nil {
+		if paused := now.Sub(*session.PausedAt); paused > 0 {
+			session.PausedSeconds += int(paused / time.Second)
+		}
+		session.PausedAt = nil
+	}
+	if session.Status == models.SessionStatusPaused {
+		session.Status = models.SessionStatusActive
+	}
+}
+
+func appendSet(session *models.WorkoutSession, exerciseID string, exercise models.Exercise, set models.Set, now time.Time) error {
+	var target *models.Exercise
+	if exerciseID != "" {
+		target = findExercise(session, exerciseID)
+		if target == nil {
+			return ErrExerciseNotFound
+		}
+	} else if n := len(session.Exercises); n > 0 && sameLift(session.Exercises[n-1], exercise) {
+		target = &session.Exercises[n-1]
+	}
+
+	if target == nil {
+		if len(session.Exercises) >= MaxExercises {
+			return ErrSessionFull
+		}
+		exercise.ID = uuid.New().String()
+		exercise.UserID = ""
+		exercise.WorkoutID = nil
+		exercise.CreatedAt = now
+		exercise.Sets = nil
+		session.Exercises = append(session.Exercises, exercise)
+		target = &session.Exercises[len(session.Exercises)-1]
+	}
+	if len(target.Sets) >= MaxSetsPerExercise {
+		return ErrSessionFull
+	}
+
+	resume(session, now)
+
+	set.ID = uuid.New().String()
+	set.ExerciseID = target.ID
+	set.SetIndex = len(target.Sets) + 1
+	target.Sets = append(target.Sets, set)
+	return nil
+}
+
+func updateSet(session *models.WorkoutSession, setID string, set models.Set) error {
+	for i := range session.Exercises {
+		sets := session.Exercises[i].Sets
+		for j := range sets {
+			if sets[j].ID != setID {
+				continue
+			}
+			set.ID = sets[j].ID
+			set.ExerciseID = sets[j].ExerciseID
+			set.SetIndex = sets[j].SetIndex
+			sets[j] = set
+			return nil
+		}
+	}
+	return ErrSetNotFound
+}
+
+func deleteSet(session *models.WorkoutSession, setID string) error {
+	for i := range session.Exercises {
+		exercise := &session.Exercises[i]
+		for j := range exercise.Sets {
+			if exercise.Sets[j].ID != setID {
+				continue
+			}
+			exercise.Sets = append(exercise.Sets[:j], exercise.Sets[j+1:]...)
+			if len(exercise.Sets) == 0 {
+				session.Exercises = append(session.Exercises[:i], session.Exercises[i+1:]...)
+				return nil
+			}
+			for k := range exercise.Sets {
+				exercise.Sets[k].SetIndex = k + 1
+			}
+			return nil
+		}
+	}
+	return ErrSetNotFound
+}
+
+// materialize copies the session's exercises for writing as a workout. Sets
+// are copied too since inserting them assigns new IDs in place.
+func materialize(session *models.WorkoutSession) []models.Exercise {
+	items := make([]models.Exercise, 0, len(session.Exercises))
+	for _, exercise := range session.Exercises {
+		if len(exercise.Sets) == 0 {
+			continue
+		}
+		item := exercise
+		item.Sets = append([]models.Set(nil), exercise.Sets...)
+		if item.GymID == nil {
+			item.GymID = session.GymID
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func findExercise(session *models.WorkoutSession, id string) *models.Exercise {
+	for i := range session.Exercises {
+		if session.Exercises[i].ID == id {
+			return &session.Exercises[i]
+		}
+	}
+	return nil
+}
+
+func sameLift(a, b models.Exercise) bool {
+	return strength.RecordKey(a.MachineID, a.Name) == strength.RecordKey(b.MachineID, b.Name)
+}
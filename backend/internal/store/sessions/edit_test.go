@@ -0,0 +1,113 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestElapsedExcludesPauses(t *testing.T) {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	session := &models.WorkoutSession{Status: models.SessionStatusActive, StartedAt: start}
+
+	pause(session, start.Add(20*time.Minute))
+	if got := session.Elapsed(start.Add(30 * time.Minute)); got != 20*time.Minute {
+		t.Fatalf("elapsed during pause = %v, want 20m", got)
+	}
+
+	resume(session, start.Add(30*time.Minute))
+	if session.PausedSeconds != 600 || session.PausedAt != nil || session.Status != models.SessionStatusActive {
+		t.Fatalf("unexpected session after resume %+v", session)
+	}
+	if got := session.Elapsed(start.Add(time.Hour)); got != 50*time.Minute {
+		t.Fatalf("elapsed after resume = %v, want 50m", got)
+	}
+
+	finished := start.Add(70 * time.Minute)
+	session.FinishedAt = &finished
+	if got := session.Elapsed(start.Add(5 * time.Hour)); got != time.Hour {
+		t.Fatalf("finished sessions must stop counting, got %v", got)
+	}
+}
+
+func TestAppendSetGroupsSameLift(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	session := &models.WorkoutSession{Status: models.SessionStatusPaused, StartedAt: now.Add(-time.Hour), PausedAt: &now}
+
+	bench := models.Exercise{Name: "Bench Press"}
+	for i := 0; i < 2; i++ {
+		if err := appendSet(session, "", bench, models.Set{Reps: 8}, now); err != nil {
+			t.Fatalf("appendSet: %v", err)
+		}
+	}
+	if err := appendSet(session, "", models.Exercise{Name: "Row"}, models.Set{Reps: 10}, now); err != nil {
+		t.Fatalf("appendSet: %v", err)
+	}
+	if err := appendSet(session, session.Exercises[0].ID, models.Exercise{}, models.Set{Reps: 6}, now); err != nil {
+		t.Fatalf("appendSet by id: %v", err)
+	}
+
+	if len(session.Exercises) != 2 || len(session.Exercises[0].Sets) != 3 || len(session.Exercises[1].Sets) != 1 {
+		t.Fatalf("unexpected exercises %+v", session.Exercises)
+	}
+	if got := session.Exercises[0].Sets[2]; got.SetIndex != 3 || got.ExerciseID != session.Exercises[0].ID {
+		t.Fatalf("unexpected appended set %+v", got)
+	}
+	if session.Status != models.SessionStatusActive {
+		t.Fatalf("logging a set should resume the session")
+	}
+
+	if err := appendSet(session, "missing", models.Exercise{}, models.Set{Reps: 1}, now); !errors.Is(err, ErrExerciseNotFound) {
+		t.Fatalf("expected ErrExerciseNotFound, got %v", err)
+	}
+}
+
+func TestDeleteSetReindexesAndDropsEmptyExercises(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	session := &models.WorkoutSession{Status: models.SessionStatusActive, StartedAt: now}
+
+	for _, name := range []string{"Squat", "Squat", "Lunge"} {
+		if err := appendSet(session, "", models.Exercise{Name: name}, models.Set{Reps: 5}, now); err != nil {
+			t.Fatalf("appendSet: %v", err)
+		}
+	}
+
+	if err := deleteSet(session, session.Exercises[0].Sets[0].ID); err != nil {
+		t.Fatalf("deleteSet: %v", err)
+	}
+	if session.Exercises[0].Sets[0].SetIndex != 1 {
+		t.Fatalf("sets should be reindexed, got %+v", session.Exercises[0].Sets)
+	}
+
+	if err := deleteSet(session, session.Exercises[1].Sets[0].ID); err != nil {
+		t.Fatalf("deleteSet: %v", err)
+	}
+	if len(session.Exercises) != 1 || session.Exercises[0].Name != "Squat" {
+		t.Fatalf("empty exercise should be removed, got %+v", session.Exercises)
+	}
+
+	if err := deleteSet(session, "missing"); !errors.Is(err, ErrSetNotFound) {
+		t.Fatalf("expected ErrSetNotFound, got %v", err)
+	}
+}
+
+func TestMaterializeCopiesSets(t *testing.T) {
+	gymID := "gym-1"
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	session := &models.WorkoutSession{Status: models.SessionStatusActive, StartedAt: now, GymID: &gymID}
+	if err := appendSet(session, "", models.Exercise{Name: "Squat"}, models.Set{Reps: 5}, now); err != nil {
+		t.Fatalf("appendSet: %v", err)
+	}
+
+	items := materialize(session)
+	if len(items) != 1 || items[0].GymID == nil || *items[0].GymID != gymID {
+		t.Fatalf("unexpected items %+v", items)
+	}
+
+	items[0].Sets[0].ID = "changed"
+	if session.Exercises[0].Sets[0].ID == "changed" {
+		t.Fatalf("materialized sets must not alias the session")
+	}
+}
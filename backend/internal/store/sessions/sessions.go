@@ -0,0 +1,507 @@
+package sessions
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/workouts"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+
+	// MaxExercises and MaxSetsPerExercise bound what one session can hold.
+	MaxExercises       = 50
+	MaxSetsPerExercise = 50
+)
+
+var (
+	// ErrSessionNotFound is returned when a session does not exist or belongs to another user.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionInProgress is returned when starting a session while another one is live.
+	ErrSessionInProgress = errors.New("a workout session is already in progress")
+	// ErrSessionClosed is returned when changing a finished or discarded session.
+	ErrSessionClosed = errors.New("session is no longer active")
+	// ErrSessionEmpty is returned when finishing a session without any sets.
+	ErrSessionEmpty = errors.New("session has no sets")
+	// ErrSessionFull is returned when a session has reached its exercise or set limit.
+	ErrSessionFull = errors.New("session has too many exercises or sets")
+	// ErrExerciseNotFound is returned when a set targets an exercise not in the session.
+	ErrExerciseNotFound = errors.New("exercise not found")
+	// ErrSetNotFound is returned when a set is not in the session.
+	ErrSetNotFound = errors.New("set not found")
+	// ErrGymNotFound is returned when the session's gym does not exist.
+	ErrGymNotFound = errors.New("gym not found")
+)
+
+const sessionColumns = `id, user_id, name, type, gym_id, status, exercises, started_at, paused_at,
+	paused_seconds, last_activity_at, finished_at, auto_finished, workout_id`
+
+// Store handles live workout session database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new workout sessions store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Start opens a new session for the user. Only one session can be live per
+// user; starting a second one returns ErrSessionInProgress.
+func (s *Store) Start(userID, name, workoutType string, gymID *string) (*models.WorkoutSession, error) {
+	now := time.Now().UTC()
+	session := &models.WorkoutSession{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Name:           name,
+		Type:           workoutType,
+		GymID:          gymID,
+		Status:         models.SessionStatusActive,
+		StartedAt:      now,
+		LastActivityAt: now,
+		Exercises:      []models.Exercise{},
+	}
+
+	_, err := s.db.Exec(`
+		INSERT INTO workout_sessions (id, user_id, name, type, gym_id, status, started_at, last_activity_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, session.ID, userID, name, workoutType, gymID, session.Status, now, now)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case uniqueViolation:
+				return nil, ErrSessionInProgress
+			case foreignKeyViolation:
+				return nil, ErrGymNotFound
+			}
+		}
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetActive returns the user's live session, active or paused.
+func (s *Store) GetActive(userID string) (*models.WorkoutSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM workout_sessions WHERE user_id = $1 AND status IN ('active', 'paused')`
+	session, err := scanSession(s.db.QueryRow(query, userID))
+	if err != nil {
+		return nil, err
+	}
+	session.ElapsedSeconds = elapsedSeconds(session, time.Now().UTC())
+	return session, nil
+}
+
+// GetByID retrieves a session by ID
+func (s *Store) GetByID(id, userID string) (*models.WorkoutSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM workout_sessions WHERE id = $1 AND user_id = $2`
+	session, err := scanSession(s.db.QueryRow(query, id, userID))
+	if err != nil {
+		return nil, err
+	}
+	session.ElapsedSeconds = elapsedSeconds(session, time.Now().UTC())
+	return session, nil
+}
+
+// Update changes the session's name, type and gym.
+func (s *Store) Update(id, userID, name, workoutType string, gymID *string) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, _ time.Time) error {
+		session.Name = name
+		session.Type = workoutType
+		session.GymID = gymID
+		return nil
+	})
+}
+
+// AppendSet logs a set in the session. With an exerciseID the set goes to
+// that session exercise; otherwise it continues the last exercise when that
+// is the same lift, or starts a new one. Logging a set resumes a paused session.
+func (s *Store) AppendSet(id, userID, exerciseID string, exercise models.Exercise, set models.Set) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, now time.Time) error {
+		return appendSet(session, exerciseID, exercise, set, now)
+	})
+}
+
+// UpdateSet replaces the values of a logged set.
+func (s *Store) UpdateSet(id, userID, setID string, set models.Set) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, _ time.Time) error {
+		return updateSet(session, setID, set)
+	})
+}
+
+// DeleteSet removes a logged set. An exercise left without sets is removed too.
+func (s *Store) DeleteSet(id, userID, setID string) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, _ time.Time) error {
+		return deleteSet(session, setID)
+	})
+}
+
+// Pause stops the session clock. Pausing a paused session changes nothing.
+func (s *Store) Pause(id, userID string) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, now time.Time) error {
+		pause(session, now)
+		return nil
+	})
+}
+
+// Resume restarts the session clock. Resuming an active session changes nothing.
+func (s *Store) Resume(id, userID string) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, now time.Time) error {
+		resume(session, now)
+		return nil
+	})
+}
+
+// Discard closes the session without writing a workout.
+func (s *Store) Discard(id, userID string) (*models.WorkoutSession, error) {
+	return s.mutate(id, userID, func(_ *sql.Tx, session *models.WorkoutSession, now time.Time) error {
+		resume(session, now)
+		session.Status = models.SessionStatusDiscarded
+		session.FinishedAt = &now
+		return nil
+	})
+}
+
+// Finish closes the session and writes it out as a workout with its
+// exercises and sets, all in one transaction.
+func (s *Store) Finish(id, userID string) (*models.WorkoutSession, *models.Workout, error) {
+	var workout *models.Workout
+	session, err := s.mutate(id, userID, func(tx *sql.Tx, session *models.WorkoutSession, now time.Time) error {
+		var err error
+		workout, err = finishTx(tx, session, now, false)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, workout, nil
+}
+
+// AbandonedWorkout is the workout written for a session that was finished
+// because it went idle.
+type AbandonedWorkout struct {
+	SessionID string
+	Workout   models.Workout
+}
+
+// FinishAbandoned closes up to limit live sessions with no activity since
+// idleSince. Sessions with sets are finished as of their last activity and
+// their workouts returned; empty ones are discarded.
+func (s *Store) FinishAbandoned(idleSince time.Time, limit int) ([]AbandonedWorkout, int, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id
+		FROM workout_sessions
+		WHERE status IN ('active', 'paused') AND last_activity_at < $1
+		ORDER BY last_activity_at
+		LIMIT $2
+	`, idleSince, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query abandoned sessions: %w", err)
+	}
+
+	type candidate struct{ id, userID string }
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.id, &c.userID); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to scan abandoned session: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, 0, fmt.Errorf("abandoned session rows error: %w", err)
+	}
+	rows.Close()
+
+	var finished []AbandonedWorkout
+	discarded := 0
+	for _, c := range candidates {
+		workout, err := s.finishAbandoned(c.id, c.userID, idleSince)
+		if err != nil {
+			return finished, discarded, err
+		}
+		if workout != nil {
+			finished = append(finished, AbandonedWorkout{SessionID: c.id, Workout: *workout})
+		} else {
+			discarded++
+		}
+	}
+
+	return finished, discarded, nil
+}
+
+// finishAbandoned closes one idle session. The session is re-checked under
+// lock since the user may have come back to it in the meantime.
+func (s *Store) finishAbandoned(id, userID string, idleSince time.Time) (*models.Workout, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	session, err := lockSession(tx, id, userID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsLive() || !session.LastActivityAt.Before(idleSince) {
+		return nil, nil
+	}
+
+	end := session.LastActivityAt
+	workout, err := finishTx(tx, session, end, true)
+	if errors.Is(err, ErrSessionEmpty) {
+		resume(session, end)
+		session.Status = models.SessionStatusDiscarded
+		session.FinishedAt = &end
+		session.AutoFinished = true
+		workout, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveSession(tx, session); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return workout, nil
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	_, err := s.db.Exec(`DELETE FROM workout_sessions WHERE user_id = $1`, userID)
+	return err
+}
+
+// mutate applies a change to a live session under a row lock and records the
+// activity. Closed sessions return ErrSessionClosed.
+func (s *Store) mutate(id, userID string, change func(tx *sql.Tx, session *models.WorkoutSession, now time.Time) error) (*models.WorkoutSession, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	session, err := lockSession(tx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsLive() {
+		return nil, ErrSessionClosed
+	}
+
+	now := time.Now().UTC()
+	if err := change(tx, session, now); err != nil {
+		return nil, err
+	}
+	session.LastActivityAt = now
+
+	if err := saveSession(tx, session); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	session.ElapsedSeconds = elapsedSeconds(session, now)
+	return session, nil
+}
+
+// finishTx closes the session as of end and writes its workout. The session
+// row itself is saved by the caller.
+func finishTx(tx *sql.Tx, session *models.WorkoutSession, end time.Time, auto bool) (*models.Workout, error) {
+	items := materialize(session)
+	if len(items) == 0 {
+		return nil, ErrSessionEmpty
+	}
+
+	resume(session, end)
+	session.Status = models.SessionStatusFinished
+	session.FinishedAt = &end
+	session.AutoFinished = auto
+
+	if err := dropMissingReferences(tx, items); err != nil {
+		return nil, err
+	}
+
+	workout := &models.Workout{
+		UserID:    session.UserID,
+		Name:      session.Name,
+		Duration:  int(math.Round(session.Elapsed(end).Minutes())),
+		Type:      session.Type,
+		CreatedAt: session.StartedAt,
+	}
+	if err := workouts.InsertTx(tx, workout, items); err != nil {
+		return nil, err
+	}
+	session.WorkoutID = &workout.ID
+
+	workouts.AttachExercises(workout, items)
+	return workout, nil
+}
+
+// dropMissingReferences clears gym and machine IDs that were deleted while
+// the session was running, so finishing never fails on a stale reference.
+func dropMissingReferences(tx *sql.Tx, items []models.Exercise) error {
+	var gymIDs, machineIDs []string
+	for _, item := range items {
+		if item.GymID != nil {
+			gymIDs = append(gymIDs, *item.GymID)
+		}
+		if item.MachineID != nil {
+			machineIDs = append(machineIDs, *item.MachineID)
+		}
+	}
+
+	gyms, err := existingIDs(tx, `SELECT id::text FROM gyms WHERE id = ANY($1::uuid[])`, gymIDs)
+	if err != nil {
+		return err
+	}
+	machines, err := existingIDs(tx, `SELECT id::text FROM machines WHERE id = ANY($1::uuid[])`, machineIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		if items[i].GymID != nil && !gyms[*items[i].GymID] {
+			items[i].GymID = nil
+		}
+		if items[i].MachineID != nil && !machines[*items[i].MachineID] {
+			items[i].MachineID = nil
+		}
+	}
+	return nil
+}
+
+// existingIDs returns which of ids the query finds. IDs that are not UUIDs
+// cannot exist and are left out so the uuid[] cast does not fail.
+func existingIDs(tx *sql.Tx, query string, ids []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	if len(valid) == 0 {
+		return found, nil
+	}
+
+	rows, err := tx.Query(query, pq.Array(valid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check session references: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan session reference: %w", err)
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("session reference rows error: %w", err)
+	}
+	return found, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func lockSession(tx *sql.Tx, id, userID string) (*models.WorkoutSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM workout_sessions WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	return scanSession(tx.QueryRow(query, id, userID))
+}
+
+func scanSession(row rowScanner) (*models.WorkoutSession, error) {
+	session := &models.WorkoutSession{}
+	var (
+		gymID      sql.NullString
+		workoutID  sql.NullString
+		exercises  []byte
+		pausedAt   sql.NullTime
+		finishedAt sql.NullTime
+	)
+
+	err := row.Scan(&session.ID, &session.UserID, &session.Name, &session.Type, &gymID, &session.Status, &exercises,
+		&session.StartedAt, &pausedAt, &session.PausedSeconds, &session.LastActivityAt, &finishedAt,
+		&session.AutoFinished, &workoutID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if gymID.Valid {
+		value := gymID.String
+		session.GymID = &value
+	}
+	if workoutID.Valid {
+		value := workoutID.String
+		session.WorkoutID = &value
+	}
+	if pausedAt.Valid {
+		value := pausedAt.Time
+		session.PausedAt = &value
+	}
+	if finishedAt.Valid {
+		value := finishedAt.Time
+		session.FinishedAt = &value
+	}
+
+	if err := json.Unmarshal(exercises, &session.Exercises); err != nil {
+		return nil, fmt.Errorf("failed to decode session exercises: %w", err)
+	}
+	if session.Exercises == nil {
+		session.Exercises = []models.Exercise{}
+	}
+
+	return session, nil
+}
+
+func saveSession(tx *sql.Tx, session *models.WorkoutSession) error {
+	exercises, err := json.Marshal(session.Exercises)
+	if err != nil {
+		return fmt.Errorf("failed to encode session exercises: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE workout_sessions
+		SET name = $3, type = $4, gym_id = $5, status = $6, exercises = $7, paused_at = $8, paused_seconds = $9,
+		    last_activity_at = $10, finished_at = $11, auto_finished = $12, workout_id = $13
+		WHERE id = $1 AND user_id = $2
+	`, session.ID, session.UserID, session.Name, session.Type, session.GymID, session.Status, exercises,
+		session.PausedAt, session.PausedSeconds, session.LastActivityAt, session.FinishedAt, session.AutoFinished, session.WorkoutID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrGymNotFound
+		}
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+func elapsedSeconds(session *models.WorkoutSession, now time.Time) int {
+	return int(session.Elapsed(now) / time.Second)
+}
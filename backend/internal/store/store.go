@@ -5,12 +5,23 @@ import (
 	"fmt"
 
 	"fitonex/backend/internal/config"
+	"fitonex/backend/internal/store/bodymetrics"
+	"fitonex/backend/internal/store/catalog"
 	"fitonex/backend/internal/store/checkins"
 	"fitonex/backend/internal/store/exercises"
+	"fitonex/backend/internal/store/goals"
 	"fitonex/backend/internal/store/gyms"
+	"fitonex/backend/internal/store/imports"
 	"fitonex/backend/internal/store/machines"
 	"fitonex/backend/internal/store/moderation"
+	"fitonex/backend/internal/store/offline"
+	"fitonex/backend/internal/store/programs"
+	"fitonex/backend/internal/store/records"
+	"fitonex/backend/internal/store/sessions"
 	"fitonex/backend/internal/store/social"
+	"fitonex/backend/internal/store/templates"
+	"fitonex/backend/internal/store/tracks"
+	"fitonex/backend/internal/store/training"
 	"fitonex/backend/internal/store/migrations"
 	"fitonex/backend/internal/store/users"
 	"fitonex/backend/internal/store/videos"
@@ -32,6 +43,17 @@ type Store struct {
     Exercises  *exercises.Store
     Moderation *moderation.Store
     Comments   *social.Store
+    Records    *records.Store
+    Templates  *templates.Store
+    Programs   *programs.Store
+    Imports    *imports.Store
+    Catalog    *catalog.Store
+    Sync       *offline.Store
+    Sessions   *sessions.Store
+    Training   *training.Store
+    Body       *bodymetrics.Store
+    Goals      *goals.Store
+    Tracks     *tracks.Store
 }
 
 // New creates a new store instance
@@ -65,6 +87,17 @@ func (s *Store) Connect() error {
     s.Exercises = exercises.New(s.db)
     s.Moderation = moderation.New(s.db)
     s.Comments = social.New(s.db)
+    s.Records = records.New(s.db)
+    s.Templates = templates.New(s.db)
+    s.Programs = programs.New(s.db)
+    s.Imports = imports.New(s.db)
+    s.Catalog = catalog.New(s.db)
+    s.Sync = offline.New(s.db)
+    s.Sessions = sessions.New(s.db)
+    s.Training = training.New(s.db)
+    s.Body = bodymetrics.New(s.db)
+    s.Goals = goals.New(s.db)
+    s.Tracks = tracks.New(s.db)
 
 	return nil
 }
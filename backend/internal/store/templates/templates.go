@@ -0,0 +1,350 @@
+package templates
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const foreignKeyViolation = "23503"
+
+var (
+	// ErrTemplateNotFound is returned when a template does not exist or is not owned by the caller.
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTemplateInUse is returned when deleting a template that a program still schedules.
+	ErrTemplateInUse = errors.New("template is scheduled in a program")
+)
+
+// Store handles workout template database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new templates store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create stores a template with its ordered exercises.
+func (s *Store) Create(ownerID, name string, description, copiedFrom *string, exercises []models.TemplateExercise) (*models.WorkoutTemplate, error) {
+	now := time.Now().UTC()
+	template := &models.WorkoutTemplate{
+		ID:          uuid.New().String(),
+		OwnerID:     ownerID,
+		Name:        name,
+		Description: description,
+		CopiedFrom:  copiedFrom,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO workout_templates (id, owner_id, name, description, copied_from, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, template.ID, template.OwnerID, template.Name, template.Description, template.CopiedFrom, template.CreatedAt, template.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	if err := insertExercises(tx, template.ID, exercises); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	template.Exercises = exercises
+	return template, nil
+}
+
+// GetByID returns any template by ID. Templates are shareable, so ownership is not checked.
+func (s *Store) GetByID(id string) (*models.WorkoutTemplate, error) {
+	template := &models.WorkoutTemplate{}
+	var description, copiedFrom sql.NullString
+
+	err := s.db.QueryRow(`
+		SELECT id, owner_id, name, description, copied_from, created_at, updated_at
+		FROM workout_templates
+		WHERE id = $1
+	`, id).Scan(&template.ID, &template.OwnerID, &template.Name, &description, &copiedFrom, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if description.Valid {
+		template.Description = &description.String
+	}
+	if copiedFrom.Valid {
+		template.CopiedFrom = &copiedFrom.String
+	}
+
+	exercises, err := s.getExercises(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	template.Exercises = exercises
+
+	return template, nil
+}
+
+// GetCopy returns the owner's most recent copy of a template.
+func (s *Store) GetCopy(ownerID, sourceID string) (*models.WorkoutTemplate, error) {
+	var id string
+	err := s.db.QueryRow(`
+		SELECT id FROM workout_templates
+		WHERE owner_id = $1 AND copied_from = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, ownerID, sourceID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get template copy: %w", err)
+	}
+	return s.GetByID(id)
+}
+
+// ListByOwner returns the caller's templates ordered by created_at desc.
+func (s *Store) ListByOwner(ownerID string, limit int, cursor *pagination.TimeDescCursor) (pagination.Paginated[models.WorkoutTemplate], error) {
+	if limit <= 0 {
+		return pagination.Paginated[models.WorkoutTemplate]{}, pagination.ErrInvalidLimit
+	}
+
+	query := `
+		SELECT id, owner_id, name, description, copied_from, created_at, updated_at
+		FROM workout_templates
+		WHERE owner_id = $1
+	`
+	args := []interface{}{ownerID}
+	if cursor != nil {
+		query += ` AND (created_at < $2 OR (created_at = $2 AND id < $3))`
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return pagination.Paginated[models.WorkoutTemplate]{}, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.WorkoutTemplate
+	for rows.Next() {
+		var (
+			template    models.WorkoutTemplate
+			description sql.NullString
+			copiedFrom  sql.NullString
+		)
+		if err := rows.Scan(&template.ID, &template.OwnerID, &template.Name, &description, &copiedFrom, &template.CreatedAt, &template.UpdatedAt); err != nil {
+			return pagination.Paginated[models.WorkoutTemplate]{}, fmt.Errorf("failed to scan template: %w", err)
+		}
+		if description.Valid {
+			value := description.String
+			template.Description = &value
+		}
+		if copiedFrom.Valid {
+			value := copiedFrom.String
+			template.CopiedFrom = &value
+		}
+		items = append(items, template)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Paginated[models.WorkoutTemplate]{}, fmt.Errorf("template rows error: %w", err)
+	}
+
+	page, err := pagination.TimeDescPage(items, limit, func(item models.WorkoutTemplate) pagination.TimeDescCursor {
+		return pagination.TimeDescCursor{CreatedAt: item.CreatedAt, ID: item.ID}
+	})
+	if err != nil {
+		return pagination.Paginated[models.WorkoutTemplate]{}, err
+	}
+
+	for i := range page.Items {
+		exercises, err := s.getExercises(page.Items[i].ID)
+		if err != nil {
+			return pagination.Paginated[models.WorkoutTemplate]{}, err
+		}
+		page.Items[i].Exercises = exercises
+	}
+
+	return page, nil
+}
+
+// Update replaces a template's details and exercises.
+func (s *Store) Update(id, ownerID, name string, description *string, exercises []models.TemplateExercise) (*models.WorkoutTemplate, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE workout_templates
+		SET name = $1, description = $2, updated_at = $3
+		WHERE id = $4 AND owner_id = $5
+	`, name, description, time.Now().UTC(), id, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrTemplateNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM template_exercises WHERE template_id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear template exercises: %w", err)
+	}
+	if err := insertExercises(tx, id, exercises); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetByID(id)
+}
+
+// Delete removes a template owned by the caller.
+func (s *Store) Delete(id, ownerID string) error {
+	result, err := s.db.Exec(`DELETE FROM workout_templates WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrTemplateInUse
+		}
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// ExportByUser returns every template a user owns, newest first, with its
+// exercises.
+func (s *Store) ExportByUser(userID string) ([]models.WorkoutTemplate, error) {
+	rows, err := s.db.Query(`SELECT id FROM workout_templates WHERE owner_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export templates: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export templates: %w", err)
+	}
+
+	items := make([]models.WorkoutTemplate, 0, len(ids))
+	for _, id := range ids {
+		template, err := s.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *template)
+	}
+	return items, nil
+}
+
+// DeleteByUser removes every template a user owns.
+func (s *Store) DeleteByUser(userID string) error {
+	if _, err := s.db.Exec(`DELETE FROM workout_templates WHERE owner_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete templates: %w", err)
+	}
+	return nil
+}
+
+func insertExercises(tx *sql.Tx, templateID string, exercises []models.TemplateExercise) error {
+	for i := range exercises {
+		exercises[i].ID = uuid.New().String()
+		exercises[i].TemplateID = templateID
+		exercises[i].Position = i + 1
+
+		exercise := exercises[i]
+		if _, err := tx.Exec(`
+			INSERT INTO template_exercises (id, template_id, position, machine_id, name, target_sets, target_reps, target_weight_kg, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, exercise.ID, exercise.TemplateID, exercise.Position, exercise.MachineID, exercise.Name,
+			exercise.TargetSets, exercise.TargetReps, exercise.TargetWeightKg, exercise.Notes); err != nil {
+			return fmt.Errorf("failed to create template exercise: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) getExercises(templateID string) ([]models.TemplateExercise, error) {
+	rows, err := s.db.Query(`
+		SELECT te.id, te.template_id, te.position, te.machine_id, te.name, te.target_sets, te.target_reps,
+		       te.target_weight_kg, te.notes, m.name
+		FROM template_exercises te
+		LEFT JOIN machines m ON m.id = te.machine_id
+		WHERE te.template_id = $1
+		ORDER BY te.position ASC
+	`, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query template exercises: %w", err)
+	}
+	defer rows.Close()
+
+	exercises := []models.TemplateExercise{}
+	for rows.Next() {
+		var (
+			exercise    models.TemplateExercise
+			machineID   sql.NullString
+			notes       sql.NullString
+			machineName sql.NullString
+		)
+		if err := rows.Scan(&exercise.ID, &exercise.TemplateID, &exercise.Position, &machineID, &exercise.Name,
+			&exercise.TargetSets, &exercise.TargetReps, &exercise.TargetWeightKg, &notes, &machineName); err != nil {
+			return nil, fmt.Errorf("failed to scan template exercise: %w", err)
+		}
+		if machineID.Valid {
+			value := machineID.String
+			exercise.MachineID = &value
+		}
+		if notes.Valid {
+			value := notes.String
+			exercise.Notes = &value
+		}
+		if machineName.Valid {
+			value := machineName.String
+			exercise.MachineName = &value
+		}
+		exercises = append(exercises, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("template exercise rows error: %w", err)
+	}
+	return exercises, nil
+}
@@ -0,0 +1,193 @@
+package tracks
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/store/workouts"
+
+	"github.com/google/uuid"
+)
+
+// ErrTrackNotFound is returned when a track does not exist or belongs to another user.
+var ErrTrackNotFound = errors.New("track not found")
+
+const trackColumns = `id, user_id, workout_id, format, sport, name, file_key, file_hash, started_at, duration_seconds,
+	distance_m::float8, elevation_gain_m::float8, avg_pace_seconds_per_km::float8, avg_speed_kmh::float8,
+	avg_heart_rate, max_heart_rate, points, created_at`
+
+// Store handles cardio track database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new tracks store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create writes a track together with the cardio workout built from it in
+// one transaction. A file the user already imported is not written again;
+// the earlier track is returned with Duplicate set.
+func (s *Store) Create(track *models.CardioTrack, workout *models.Workout, items []models.Exercise) (*models.CardioTrack, error) {
+	track.ID = uuid.New().String()
+	track.CreatedAt = time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	summary := track.Summary
+	result, err := tx.Exec(`
+		INSERT INTO cardio_tracks (
+			id, user_id, format, sport, name, file_key, file_hash, started_at, duration_seconds, distance_m,
+			elevation_gain_m, avg_pace_seconds_per_km, avg_speed_kmh, avg_heart_rate, max_heart_rate, points, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (user_id, file_hash) DO NOTHING
+	`, track.ID, track.UserID, track.Format, track.Sport, track.Name, track.FileKey, track.FileHash, summary.StartedAt,
+		summary.DurationSeconds, summary.DistanceMeters, summary.ElevationGainMeters, summary.AvgPaceSecondsPerKm,
+		summary.AvgSpeedKmh, summary.AvgHeartRate, summary.MaxHeartRate, summary.Points, track.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create track: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to create track: %w", err)
+	} else if affected == 0 {
+		existing, err := scanTrack(tx.QueryRow(`SELECT `+trackColumns+` FROM cardio_tracks WHERE user_id = $1 AND file_hash = $2`, track.UserID, track.FileHash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+		existing.Duplicate = true
+		return existing, nil
+	}
+
+	workout.UserID = track.UserID
+	if err := workouts.InsertTx(tx, workout, items); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE cardio_tracks SET workout_id = $2 WHERE id = $1`, track.ID, workout.ID); err != nil {
+		return nil, fmt.Errorf("failed to link track workout: %w", err)
+	}
+	track.WorkoutID = workout.ID
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	workouts.AttachExercises(workout, items)
+	return track, nil
+}
+
+// GetByID retrieves one of the user's tracks
+func (s *Store) GetByID(id, userID string) (*models.CardioTrack, error) {
+	track, err := scanTrack(s.db.QueryRow(`SELECT `+trackColumns+` FROM cardio_tracks WHERE id = $1 AND user_id = $2`, id, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrackNotFound
+		}
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	return track, nil
+}
+
+// GetByHash retrieves the user's track imported from the file with the given
+// hash, or ErrTrackNotFound
+func (s *Store) GetByHash(userID, fileHash string) (*models.CardioTrack, error) {
+	track, err := scanTrack(s.db.QueryRow(`SELECT `+trackColumns+` FROM cardio_tracks WHERE user_id = $1 AND file_hash = $2`, userID, fileHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrackNotFound
+		}
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	return track, nil
+}
+
+func (s *Store) ExportByUser(userID string) ([]models.CardioTrack, error) {
+	rows, err := s.db.Query(`SELECT `+trackColumns+` FROM cardio_tracks WHERE user_id = $1 ORDER BY started_at, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.CardioTrack
+	for rows.Next() {
+		track, err := scanTrack(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan track: %w", err)
+		}
+		items = append(items, *track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export tracks: %w", err)
+	}
+	return items, nil
+}
+
+// DeleteByUser removes a user's tracks and returns their file keys so the
+// caller can remove the stored files.
+func (s *Store) DeleteByUser(userID string) ([]string, error) {
+	rows, err := s.db.Query(`DELETE FROM cardio_tracks WHERE user_id = $1 RETURNING file_key`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tracks: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan track file key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to delete tracks: %w", err)
+	}
+	return keys, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTrack(row rowScanner) (*models.CardioTrack, error) {
+	var (
+		track     models.CardioTrack
+		workoutID sql.NullString
+		pace      sql.NullFloat64
+		speed     sql.NullFloat64
+		avgHR     sql.NullInt64
+		maxHR     sql.NullInt64
+	)
+	summary := &track.Summary
+	if err := row.Scan(&track.ID, &track.UserID, &workoutID, &track.Format, &track.Sport, &track.Name, &track.FileKey, &track.FileHash,
+		&summary.StartedAt, &summary.DurationSeconds, &summary.DistanceMeters, &summary.ElevationGainMeters, &pace, &speed,
+		&avgHR, &maxHR, &summary.Points, &track.CreatedAt); err != nil {
+		return nil, err
+	}
+	track.WorkoutID = workoutID.String
+	summary.StartedAt = summary.StartedAt.UTC()
+	if pace.Valid {
+		value := pace.Float64
+		summary.AvgPaceSecondsPerKm = &value
+	}
+	if speed.Valid {
+		value := speed.Float64
+		summary.AvgSpeedKmh = &value
+	}
+	if avgHR.Valid {
+		value := int(avgHR.Int64)
+		summary.AvgHeartRate = &value
+	}
+	if maxHR.Valid {
+		value := int(maxHR.Int64)
+		summary.MaxHeartRate = &value
+	}
+	return &track, nil
+}
@@ -0,0 +1,166 @@
+package training
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	// LoadSpikeRatio is the acute:chronic ratio above which a week is flagged.
+	// Ratios past 1.5 are where injury risk climbs in most workload studies.
+	LoadSpikeRatio = 1.5
+	// MuscleSpikeRatio and MuscleSpikeMinSets flag a body part whose weekly
+	// sets jump past its four-week average; small weeks are ignored.
+	MuscleSpikeRatio   = 1.5
+	MuscleSpikeMinSets = 10
+)
+
+// WeekStart returns the Monday of the week containing t, in UTC.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// buildInsights turns daily aggregates into weekly series. days and muscles
+// start chronicWeeks before query.From so the first reported week has a
+// chronic load to compare against.
+func buildInsights(query models.TrainingLoadQuery, days []dayLoad, muscles []muscleWeek) models.TrainingInsights {
+	insights := models.TrainingInsights{
+		Metric:   query.Metric,
+		From:     query.From,
+		To:       query.To,
+		Weeks:    []models.TrainingLoadWeek{},
+		Muscles:  []models.MuscleLoad{},
+		Sessions: []models.SessionLoad{},
+		Warnings: []models.TrainingWarning{},
+	}
+
+	var weeks []time.Time
+	for week := query.From.AddDate(0, 0, -7*chronicWeeks); week.Before(query.To); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, week)
+	}
+	index := make(map[time.Time]int, len(weeks))
+	for i, week := range weeks {
+		index[week] = i
+	}
+
+	buckets := make([]models.TrainingLoadWeek, len(weeks))
+	for i, week := range weeks {
+		buckets[i].WeekStart = week
+	}
+	for _, item := range days {
+		position, ok := index[WeekStart(item.day)]
+		if !ok {
+			continue
+		}
+		buckets[position].SessionLoad += item.sessionLoad
+		buckets[position].SetEffort += item.setEffort
+		buckets[position].Sets += item.sets
+		buckets[position].HardSets += item.hardSets
+	}
+
+	metric := func(week models.TrainingLoadWeek) float64 {
+		if query.Metric == models.TrainingMetricSetEffort {
+			return week.SetEffort
+		}
+		return week.SessionLoad
+	}
+
+	for i := chronicWeeks; i < len(buckets); i++ {
+		week := buckets[i]
+		week.SessionLoad = round1(week.SessionLoad)
+		week.SetEffort = round1(week.SetEffort)
+		week.Acute = round1(metric(buckets[i]))
+
+		chronic := 0.0
+		for j := i - chronicWeeks; j < i; j++ {
+			chronic += metric(buckets[j])
+		}
+		chronic /= chronicWeeks
+		week.Chronic = round1(chronic)
+
+		if chronic > 0 {
+			ratio := round2(metric(buckets[i]) / chronic)
+			week.ACWR = &ratio
+			if ratio > LoadSpikeRatio {
+				insights.Warnings = append(insights.Warnings, models.TrainingWarning{
+					Type:      models.TrainingWarningLoadSpike,
+					WeekStart: week.WeekStart,
+					Value:     ratio,
+					Message:   fmt.Sprintf("Training load is %.1fx your four-week average", ratio),
+				})
+			}
+		}
+
+		insights.Weeks = append(insights.Weeks, week)
+	}
+
+	byPart := map[string][]int{}
+	var parts []string
+	for _, item := range muscles {
+		position, ok := index[WeekStart(item.week)]
+		if !ok {
+			continue
+		}
+		counts, ok := byPart[item.bodyPart]
+		if !ok {
+			counts = make([]int, len(weeks))
+			byPart[item.bodyPart] = counts
+			parts = append(parts, item.bodyPart)
+		}
+		counts[position] += item.sets
+	}
+
+	for _, part := range parts {
+		counts := byPart[part]
+		load := models.MuscleLoad{BodyPart: part, Weeks: make([]models.MuscleWeekSets, 0, len(weeks)-chronicWeeks)}
+		for i := chronicWeeks; i < len(weeks); i++ {
+			load.Weeks = append(load.Weeks, models.MuscleWeekSets{WeekStart: weeks[i], Sets: counts[i]})
+			load.TotalSets += counts[i]
+
+			average := 0.0
+			for j := i - chronicWeeks; j < i; j++ {
+				average += float64(counts[j])
+			}
+			average /= chronicWeeks
+			if counts[i] >= MuscleSpikeMinSets && average > 0 && float64(counts[i]) > MuscleSpikeRatio*average {
+				bodyPart := part
+				insights.Warnings = append(insights.Warnings, models.TrainingWarning{
+					Type:      models.TrainingWarningMuscleSpike,
+					WeekStart: weeks[i],
+					BodyPart:  &bodyPart,
+					Value:     float64(counts[i]),
+					Message:   fmt.Sprintf("%d %s sets against a four-week average of %.1f", counts[i], part, average),
+				})
+			}
+		}
+		if load.TotalSets > 0 {
+			insights.Muscles = append(insights.Muscles, load)
+		}
+	}
+
+	sort.SliceStable(insights.Muscles, func(i, j int) bool {
+		if insights.Muscles[i].TotalSets != insights.Muscles[j].TotalSets {
+			return insights.Muscles[i].TotalSets > insights.Muscles[j].TotalSets
+		}
+		return insights.Muscles[i].BodyPart < insights.Muscles[j].BodyPart
+	})
+	sort.SliceStable(insights.Warnings, func(i, j int) bool {
+		return insights.Warnings[i].WeekStart.After(insights.Warnings[j].WeekStart)
+	})
+
+	return insights
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,88 @@
+package training
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2024, 5, 5, 23, 30, 0, 0, time.UTC)
+	if got := WeekStart(sunday); !got.Equal(time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("WeekStart(sunday) = %v", got)
+	}
+	monday := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	if got := WeekStart(monday); !got.Equal(monday) {
+		t.Fatalf("WeekStart(monday) = %v", got)
+	}
+}
+
+func TestBuildInsightsComputesACWR(t *testing.T) {
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	query := models.TrainingLoadQuery{Metric: models.TrainingMetricSessionLoad, From: from, To: from.AddDate(0, 0, 14)}
+
+	var days []dayLoad
+	// Four steady weeks of 400 before the range, then 400 and a spike to 1000.
+	for week := -4; week < 2; week++ {
+		load := 400.0
+		if week == 1 {
+			load = 1000
+		}
+		days = append(days, dayLoad{day: from.AddDate(0, 0, 7*week+2), sessionLoad: load, setEffort: 100, sets: 12, hardSets: 6})
+	}
+
+	insights := buildInsights(query, days, nil)
+	if len(insights.Weeks) != 2 {
+		t.Fatalf("expected 2 reported weeks, got %d", len(insights.Weeks))
+	}
+
+	first := insights.Weeks[0]
+	if first.Acute != 400 || first.Chronic != 400 || first.ACWR == nil || *first.ACWR != 1 {
+		t.Fatalf("unexpected first week %+v", first)
+	}
+
+	second := insights.Weeks[1]
+	if second.ACWR == nil || *second.ACWR != 2.5 {
+		t.Fatalf("unexpected second week %+v", second)
+	}
+	if len(insights.Warnings) != 1 || insights.Warnings[0].Type != models.TrainingWarningLoadSpike || !insights.Warnings[0].WeekStart.Equal(second.WeekStart) {
+		t.Fatalf("expected one load spike warning, got %+v", insights.Warnings)
+	}
+}
+
+func TestBuildInsightsWithoutHistoryHasNoRatio(t *testing.T) {
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	query := models.TrainingLoadQuery{Metric: models.TrainingMetricSetEffort, From: from, To: from.AddDate(0, 0, 7)}
+
+	insights := buildInsights(query, []dayLoad{{day: from, setEffort: 80, sets: 10}}, nil)
+	if len(insights.Weeks) != 1 || insights.Weeks[0].ACWR != nil || insights.Weeks[0].Acute != 80 {
+		t.Fatalf("unexpected weeks %+v", insights.Weeks)
+	}
+	if len(insights.Warnings) != 0 {
+		t.Fatalf("a first week must not warn, got %+v", insights.Warnings)
+	}
+}
+
+func TestBuildInsightsFlagsMuscleSpikes(t *testing.T) {
+	from := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	query := models.TrainingLoadQuery{Metric: models.TrainingMetricSessionLoad, From: from, To: from.AddDate(0, 0, 7)}
+
+	var muscles []muscleWeek
+	for week := -4; week < 0; week++ {
+		muscles = append(muscles, muscleWeek{week: from.AddDate(0, 0, 7*week), bodyPart: "chest", sets: 8})
+		muscles = append(muscles, muscleWeek{week: from.AddDate(0, 0, 7*week), bodyPart: "back", sets: 2})
+	}
+	muscles = append(muscles,
+		muscleWeek{week: from, bodyPart: "chest", sets: 20},
+		muscleWeek{week: from, bodyPart: "back", sets: 6},
+	)
+
+	insights := buildInsights(query, nil, muscles)
+	if len(insights.Muscles) != 2 || insights.Muscles[0].BodyPart != "chest" || insights.Muscles[0].TotalSets != 20 {
+		t.Fatalf("unexpected muscles %+v", insights.Muscles)
+	}
+	if len(insights.Warnings) != 1 || insights.Warnings[0].BodyPart == nil || *insights.Warnings[0].BodyPart != "chest" {
+		t.Fatalf("expected a chest spike only, got %+v", insights.Warnings)
+	}
+}
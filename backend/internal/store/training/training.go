@@ -0,0 +1,320 @@
+package training
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+const (
+	// HardSetRPE is the RPE from which a working set counts as a hard set,
+	// roughly three reps or fewer from failure.
+	HardSetRPE = 7
+
+	// chronicWeeks is how many weeks before the current one make up the
+	// chronic load.
+	chronicWeeks = 4
+
+	maxSessionLoads = 100
+)
+
+// dayLoad is one row of training_load_days
+type dayLoad struct {
+	day         time.Time
+	sessionLoad float64
+	setEffort   float64
+	sets        int
+	hardSets    int
+}
+
+// muscleWeek is the working set count of one body part in one week
+type muscleWeek struct {
+	week     time.Time
+	bodyPart string
+	sets     int
+}
+
+// Store handles training load database operations
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a new training load store
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Refresh recomputes the daily aggregates of every day the user changed since
+// the last refresh. Database triggers mark a day as changed whenever a
+// workout, exercise or set on it is written, so only those days are rebuilt.
+func (s *Store) Refresh(userID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`DELETE FROM training_load_dirty WHERE user_id = $1 RETURNING day`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to claim training load days: %w", err)
+	}
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan training load day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("training load day rows error: %w", err)
+	}
+	rows.Close()
+
+	for _, day := range days {
+		if err := refreshDay(tx, userID, day); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RefreshPending refreshes up to limit users with changed days and returns
+// how many were refreshed.
+func (s *Store) RefreshPending(limit int) (int, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT user_id FROM training_load_dirty LIMIT $1`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query pending training load: %w", err)
+	}
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan pending training load: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("pending training load rows error: %w", err)
+	}
+	rows.Close()
+
+	for i, userID := range userIDs {
+		if err := s.Refresh(userID); err != nil {
+			return i, err
+		}
+	}
+	return len(userIDs), nil
+}
+
+func refreshDay(tx *sql.Tx, userID string, day time.Time) error {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	start, end := day, day.AddDate(0, 0, 1)
+
+	// Session load uses the workout's day; set counts use the day each
+	// exercise was performed. Warm-ups are left out of both.
+	if _, err := tx.Exec(`
+		INSERT INTO training_load_days (user_id, day, session_load, set_effort, sets, hard_sets, updated_at)
+		SELECT $1, $2, loads.session_load, effort.set_effort, effort.sets, effort.hard_sets, NOW()
+		FROM (
+			SELECT COALESCE(SUM(per_workout.session_rpe * per_workout.duration), 0) AS session_load
+			FROM (
+				SELECT w.duration, AVG(s.rpe) AS session_rpe
+				FROM workouts w
+				JOIN exercises e ON e.workout_id = w.id
+				JOIN sets s ON s.exercise_id = e.id
+				WHERE w.user_id = $1 AND w.created_at >= $3 AND w.created_at < $4 AND NOT w.planned
+					AND s.set_type <> 'warmup' AND s.rpe IS NOT NULL
+				GROUP BY w.id, w.duration
+			) per_workout
+		) loads,
+		(
+			SELECT COUNT(s.id) AS sets,
+				COALESCE(SUM(s.rpe), 0) AS set_effort,
+				COUNT(s.id) FILTER (WHERE s.rpe >= $5) AS hard_sets
+			FROM exercises e
+			JOIN sets s ON s.exercise_id = e.id
+			WHERE e.user_id = $1 AND e.created_at >= $3 AND e.created_at < $4 AND s.set_type <> 'warmup'
+		) effort
+		ON CONFLICT (user_id, day) DO UPDATE
+		SET session_load = EXCLUDED.session_load,
+		    set_effort = EXCLUDED.set_effort,
+		    sets = EXCLUDED.sets,
+		    hard_sets = EXCLUDED.hard_sets,
+		    updated_at = EXCLUDED.updated_at
+	`, userID, day, start, end, HardSetRPE); err != nil {
+		return fmt.Errorf("failed to refresh training load: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM training_load_days
+		WHERE user_id = $1 AND day = $2 AND sets = 0 AND session_load = 0
+	`, userID, day); err != nil {
+		return fmt.Errorf("failed to refresh training load: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM training_muscle_days WHERE user_id = $1 AND day = $2`, userID, day); err != nil {
+		return fmt.Errorf("failed to refresh muscle sets: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO training_muscle_days (user_id, day, body_part, sets)
+		SELECT $1, $2, COALESCE(LOWER(m.body_part), 'other'), COUNT(s.id)
+		FROM exercises e
+		JOIN sets s ON s.exercise_id = e.id
+		LEFT JOIN machines m ON m.id = e.machine_id
+		WHERE e.user_id = $1 AND e.created_at >= $3 AND e.created_at < $4 AND s.set_type <> 'warmup'
+		GROUP BY 3
+	`, userID, day, start, end); err != nil {
+		return fmt.Errorf("failed to refresh muscle sets: %w", err)
+	}
+
+	return nil
+}
+
+// Insights refreshes the user's changed days and reports weekly load, the
+// acute:chronic workload ratio, weekly sets per body part, per-workout load
+// and warnings for the weeks in [query.From, query.To).
+func (s *Store) Insights(userID string, query models.TrainingLoadQuery) (*models.TrainingInsights, error) {
+	if err := s.Refresh(userID); err != nil {
+		return nil, err
+	}
+
+	// Chronic load for the first reported week needs the weeks before it.
+	since := query.From.AddDate(0, 0, -7*chronicWeeks)
+
+	days, err := s.loadDays(userID, since, query.To)
+	if err != nil {
+		return nil, err
+	}
+	muscles, err := s.muscleWeeks(userID, since, query.To)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessionLoads(userID, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := buildInsights(query, days, muscles)
+	insights.Sessions = sessions
+	return &insights, nil
+}
+
+func (s *Store) loadDays(userID string, from, to time.Time) ([]dayLoad, error) {
+	rows, err := s.db.Query(`
+		SELECT day, session_load, set_effort, sets, hard_sets
+		FROM training_load_days
+		WHERE user_id = $1 AND day >= $2 AND day < $3
+		ORDER BY day
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query training load: %w", err)
+	}
+	defer rows.Close()
+
+	var days []dayLoad
+	for rows.Next() {
+		var item dayLoad
+		if err := rows.Scan(&item.day, &item.sessionLoad, &item.setEffort, &item.sets, &item.hardSets); err != nil {
+			return nil, fmt.Errorf("failed to scan training load: %w", err)
+		}
+		days = append(days, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("training load rows error: %w", err)
+	}
+	return days, nil
+}
+
+func (s *Store) muscleWeeks(userID string, from, to time.Time) ([]muscleWeek, error) {
+	rows, err := s.db.Query(`
+		SELECT date_trunc('week', day)::date AS week, body_part, SUM(sets)
+		FROM training_muscle_days
+		WHERE user_id = $1 AND day >= $2 AND day < $3
+		GROUP BY week, body_part
+		ORDER BY body_part, week
+	`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query muscle sets: %w", err)
+	}
+	defer rows.Close()
+
+	var weeks []muscleWeek
+	for rows.Next() {
+		var item muscleWeek
+		if err := rows.Scan(&item.week, &item.bodyPart, &item.sets); err != nil {
+			return nil, fmt.Errorf("failed to scan muscle sets: %w", err)
+		}
+		weeks = append(weeks, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("muscle set rows error: %w", err)
+	}
+	return weeks, nil
+}
+
+// sessionLoads returns the load of the most recent workouts in the range
+func (s *Store) sessionLoads(userID string, from, to time.Time) ([]models.SessionLoad, error) {
+	rows, err := s.db.Query(`
+		SELECT w.id, w.name, w.created_at, w.duration,
+			AVG(s.rpe) FILTER (WHERE s.set_type <> 'warmup') AS session_rpe,
+			COALESCE(SUM(s.rpe) FILTER (WHERE s.set_type <> 'warmup'), 0) AS set_effort,
+			COUNT(s.id) FILTER (WHERE s.set_type <> 'warmup') AS sets
+		FROM workouts w
+		LEFT JOIN exercises e ON e.workout_id = w.id
+		LEFT JOIN sets s ON s.exercise_id = e.id
+		WHERE w.user_id = $1 AND w.created_at >= $2 AND w.created_at < $3 AND NOT w.planned
+		GROUP BY w.id
+		ORDER BY w.created_at DESC, w.id DESC
+		LIMIT $4
+	`, userID, from, to, maxSessionLoads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session loads: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.SessionLoad{}
+	for rows.Next() {
+		var (
+			item       models.SessionLoad
+			sessionRPE sql.NullFloat64
+		)
+		if err := rows.Scan(&item.WorkoutID, &item.Name, &item.PerformedAt, &item.DurationMinutes, &sessionRPE, &item.SetEffort, &item.Sets); err != nil {
+			return nil, fmt.Errorf("failed to scan session load: %w", err)
+		}
+		if sessionRPE.Valid {
+			value := round1(sessionRPE.Float64)
+			item.SessionRPE = &value
+			item.Load = round1(sessionRPE.Float64 * float64(item.DurationMinutes))
+		}
+		item.SetEffort = round1(item.SetEffort)
+		sessions = append(sessions, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("session load rows error: %w", err)
+	}
+	return sessions, nil
+}
+
+func (s *Store) DeleteByUser(userID string) error {
+	for _, query := range []string{
+		`DELETE FROM training_load_dirty WHERE user_id = $1`,
+		`DELETE FROM training_muscle_days WHERE user_id = $1`,
+		`DELETE FROM training_load_days WHERE user_id = $1`,
+	} {
+		if _, err := s.db.Exec(query, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -31,12 +31,15 @@ func (s *Store) Create(email, password, name string) (*models.User, error) {
 
 	now := time.Now().UTC()
 	user := &models.User{
-		ID:        uuid.New().String(),
-		Email:     email,
-		Name:      name,
-		Password:  string(hashedPassword),
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:         uuid.New().String(),
+		Email:      email,
+		Name:       name,
+		Password:   string(hashedPassword),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		WeightUnit: models.UnitKg,
+		LengthUnit: models.UnitCm,
+		Role:       models.RoleUser,
 	}
 
 	query := `
@@ -54,13 +57,13 @@ func (s *Store) Create(email, password, name string) (*models.User, error) {
 
 // GetByID retrieves a user by ID
 func (s *Store) GetByID(id string) (*models.User, error) {
-	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at FROM users WHERE id = $1`
+	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at, weight_unit, length_unit, role FROM users WHERE id = $1`
 	return s.queryUser(query, id)
 }
 
 // GetByEmail retrieves a user by email
 func (s *Store) GetByEmail(email string) (*models.User, error) {
-	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at FROM users WHERE email = $1`
+	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at, weight_unit, length_unit, role FROM users WHERE email = $1`
 	return s.queryUser(query, email)
 }
 
@@ -89,12 +92,24 @@ func (s *Store) Update(id, name, email string) (*models.User, error) {
 		UPDATE users 
 		SET name = $1, email = $2, updated_at = $3
 		WHERE id = $4
-		RETURNING id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at
+		RETURNING id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at, weight_unit, length_unit, role
 	`
 
 	return s.queryUserRow(query, name, email, time.Now().UTC(), id)
 }
 
+// UpdateUnits sets the units a user's measurements are shown in
+func (s *Store) UpdateUnits(id, weightUnit, lengthUnit string) (*models.User, error) {
+	query := `
+		UPDATE users
+		SET weight_unit = $1, length_unit = $2, updated_at = $3
+		WHERE id = $4
+		RETURNING id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at, weight_unit, length_unit, role
+	`
+
+	return s.queryUserRow(query, weightUnit, lengthUnit, time.Now().UTC(), id)
+}
+
 func (s *Store) queryUser(query string, args ...any) (*models.User, error) {
 	return s.queryUserRow(query, args...)
 }
@@ -113,6 +128,9 @@ func (s *Store) queryUserRow(query string, args ...any) (*models.User, error) {
 		&user.OAuthProvider,
 		&user.OAuthID,
 		&user.DeletedAt,
+		&user.WeightUnit,
+		&user.LengthUnit,
+		&user.Role,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -146,7 +164,7 @@ func (s *Store) IsPremium(userID string) (bool, error) {
 }
 
 func (s *Store) GetByOAuth(provider, oauthID string) (*models.User, error) {
-	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at FROM users WHERE oauth_provider = $1 AND oauth_id = $2`
+	query := `SELECT id, email, name, password, created_at, updated_at, premium_until, oauth_provider, oauth_id, deleted_at, weight_unit, length_unit, role FROM users WHERE oauth_provider = $1 AND oauth_id = $2`
 	return s.queryUser(query, provider, oauthID)
 }
 
@@ -9,6 +9,7 @@ import (
 	"fitonex/backend/internal/pagination"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // ErrVideoNotFound indicates the requested video was not found.
@@ -266,6 +267,92 @@ func (s *Store) GetByID(id string) (*models.InstructionVideo, error) {
 	return &video, nil
 }
 
+// MostLikedByMachines returns the most-liked video of each machine, keyed by
+// machine ID. Ties go to the newest video; machines without videos are absent.
+func (s *Store) MostLikedByMachines(machineIDs []string) (map[string]models.InstructionVideo, error) {
+	videos := make(map[string]models.InstructionVideo, len(machineIDs))
+	if len(machineIDs) == 0 {
+		return videos, nil
+	}
+
+	query := `
+		SELECT DISTINCT ON (iv.machine_id)
+			iv.id,
+			iv.machine_id,
+			iv.uploader_id,
+			iv.title,
+			iv.description,
+			iv.video_key,
+			iv.thumb_key,
+			iv.duration_sec,
+			iv.premium_only,
+			iv.likes_count,
+			iv.created_at,
+			u.name AS uploader_name,
+			m.name AS machine_name
+		FROM instruction_videos iv
+		JOIN users u ON iv.uploader_id = u.id
+		JOIN machines m ON iv.machine_id = m.id
+		WHERE iv.machine_id = ANY($1)
+		ORDER BY iv.machine_id, iv.likes_count DESC, iv.created_at DESC, iv.id DESC
+	`
+
+	rows, err := s.db.Query(query, pq.Array(machineIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query most liked videos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			video     models.InstructionVideo
+			desc      sql.NullString
+			thumb     sql.NullString
+			duration  sql.NullInt64
+			likeCount int64
+		)
+		if err := rows.Scan(
+			&video.ID,
+			&video.MachineID,
+			&video.UploaderID,
+			&video.Title,
+			&desc,
+			&video.VideoKey,
+			&thumb,
+			&duration,
+			&video.PremiumOnly,
+			&likeCount,
+			&video.CreatedAt,
+			&video.UploaderName,
+			&video.MachineName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+
+		if desc.Valid {
+			value := desc.String
+			video.Description = &value
+		}
+		if thumb.Valid {
+			value := thumb.String
+			video.ThumbKey = &value
+		}
+		if duration.Valid {
+			value := int(duration.Int64)
+			video.DurationSec = &value
+		}
+		video.LikeCount = int(likeCount)
+
+		videos[video.MachineID] = video
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("videos rows error: %w", err)
+	}
+
+	return videos, nil
+}
+
 // LikeVideo records a like for a video.
 func (s *Store) LikeVideo(videoID, userID string) error {
 	query := `
@@ -2,14 +2,23 @@ package workouts
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"fitonex/backend/internal/models"
+	"fitonex/backend/internal/pagination"
+	"fitonex/backend/internal/store/exercises"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrWorkoutNotFound is returned when a workout does not exist or belongs to another user.
+var ErrWorkoutNotFound = errors.New("workout not found")
+
 // Store handles workout-related database operations
 type Store struct {
 	db *sql.DB
@@ -20,48 +29,163 @@ func New(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
-// Create creates a new workout
-func (s *Store) Create(userID, name, description string, duration int, workoutType string) (*models.Workout, error) {
+// Create creates a new workout together with any exercises logged in it.
+// The workout and its exercises are written in a single transaction.
+func (s *Store) Create(userID, name, description string, duration int, workoutType string, items []models.Exercise) (*models.Workout, error) {
 	workout := &models.Workout{
-		ID:          uuid.New().String(),
 		UserID:      userID,
 		Name:        name,
 		Description: description,
 		Duration:    duration,
 		Type:        workoutType,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
 	}
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := InsertTx(tx, workout, items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	AttachExercises(workout, items)
+	return workout, nil
+}
+
+// CreatePlanned creates a workout that has not been performed yet, with the
+// exercises it plans to do. Logging its first exercise makes it performed.
+func (s *Store) CreatePlanned(userID, name, description string, duration int, workoutType string, planned []models.PlannedExercise) (*models.Workout, error) {
+	workout := &models.Workout{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+		Duration:    duration,
+		Type:        workoutType,
+		Planned:     true,
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := InsertTx(tx, workout, nil); err != nil {
+		return nil, err
+	}
+	for i := range planned {
+		planned[i].ID = uuid.New().String()
+		planned[i].WorkoutID = workout.ID
+		planned[i].Position = i + 1
+		_, err := tx.Exec(`
+			INSERT INTO planned_exercises (id, workout_id, position, gym_id, machine_id, name, sets, reps, rest_seconds)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, planned[i].ID, planned[i].WorkoutID, planned[i].Position, planned[i].GymID, planned[i].MachineID,
+			planned[i].Name, planned[i].Sets, planned[i].Reps, planned[i].RestSeconds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create planned exercise: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	AttachExercises(workout, nil)
+	workout.PlannedExercises = planned
+	return workout, nil
+}
+
+// ListPlannedExercises returns the planned exercises of the given workouts,
+// keyed by workout ID and in plan order.
+func (s *Store) ListPlannedExercises(workoutIDs []string) (map[string][]models.PlannedExercise, error) {
+	byWorkout := make(map[string][]models.PlannedExercise)
+	if len(workoutIDs) == 0 {
+		return byWorkout, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, workout_id, position, gym_id, machine_id, name, sets, reps, rest_seconds
+		FROM planned_exercises
+		WHERE workout_id = ANY($1::uuid[])
+		ORDER BY workout_id, position
+	`, pq.Array(workoutIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list planned exercises: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.PlannedExercise
+		if err := rows.Scan(&item.ID, &item.WorkoutID, &item.Position, &item.GymID, &item.MachineID,
+			&item.Name, &item.Sets, &item.Reps, &item.RestSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan planned exercise: %w", err)
+		}
+		byWorkout[item.WorkoutID] = append(byWorkout[item.WorkoutID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list planned exercises: %w", err)
+	}
+	return byWorkout, nil
+}
+
+// InsertTx writes a workout and its exercises inside an existing transaction.
+// IDs are assigned here; a zero CreatedAt defaults to now.
+func InsertTx(tx *sql.Tx, workout *models.Workout, items []models.Exercise) error {
+	workout.ID = uuid.New().String()
+	if workout.CreatedAt.IsZero() {
+		workout.CreatedAt = time.Now().UTC()
+	}
+	workout.UpdatedAt = workout.CreatedAt
+
 	query := `
-		INSERT INTO workouts (id, user_id, name, description, duration, type, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO workouts (id, user_id, name, description, duration, type, planned, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := s.db.Exec(query, workout.ID, workout.UserID, workout.Name, workout.Description, workout.Duration, workout.Type, workout.CreatedAt, workout.UpdatedAt)
+	_, err := tx.Exec(query, workout.ID, workout.UserID, workout.Name, workout.Description, workout.Duration, workout.Type, workout.Planned, workout.CreatedAt, workout.UpdatedAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create workout: %w", err)
+		return fmt.Errorf("failed to create workout: %w", err)
 	}
 
-	return workout, nil
+	for i := range items {
+		items[i].ID = uuid.New().String()
+		items[i].UserID = workout.UserID
+		items[i].WorkoutID = &workout.ID
+		if items[i].CreatedAt.IsZero() {
+			// Keep the logged order when exercises share the workout's timestamp.
+			items[i].CreatedAt = workout.CreatedAt.Add(time.Duration(i) * time.Millisecond)
+		}
+		if err := exercises.InsertTx(tx, &items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // GetByID retrieves a workout by ID
 func (s *Store) GetByID(id, userID string) (*models.Workout, error) {
 	workout := &models.Workout{}
 	query := `
-		SELECT id, user_id, name, description, duration, type, created_at, updated_at 
+		SELECT id, user_id, name, description, duration, type, planned, created_at, updated_at 
 		FROM workouts 
 		WHERE id = $1 AND user_id = $2
 	`
 
 	err := s.db.QueryRow(query, id, userID).Scan(
 		&workout.ID, &workout.UserID, &workout.Name, &workout.Description, 
-		&workout.Duration, &workout.Type, &workout.CreatedAt, &workout.UpdatedAt,
+		&workout.Duration, &workout.Type, &workout.Planned, &workout.CreatedAt, &workout.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("workout not found")
+			return nil, ErrWorkoutNotFound
 		}
 		return nil, fmt.Errorf("failed to get workout: %w", err)
 	}
@@ -69,63 +193,172 @@ func (s *Store) GetByID(id, userID string) (*models.Workout, error) {
 	return workout, nil
 }
 
-// GetByUserID retrieves workouts for a user with cursor-based pagination
-func (s *Store) GetByUserID(userID, cursor string, limit int) ([]models.Workout, string, error) {
-	var query string
-	var args []interface{}
-
-	if cursor == "" {
-		// First page
-		query = `
-			SELECT id, user_id, name, description, duration, type, created_at, updated_at
-			FROM workouts 
-			WHERE user_id = $1
-			ORDER BY created_at DESC
-			LIMIT $2
-		`
-		args = []interface{}{userID, limit + 1}
-	} else {
-		// Subsequent pages
-		query = `
-			SELECT id, user_id, name, description, duration, type, created_at, updated_at
-			FROM workouts 
-			WHERE user_id = $1 AND created_at < $2
-			ORDER BY created_at DESC
-			LIMIT $3
-		`
-		args = []interface{}{userID, cursor, limit + 1}
+// ListBetween retrieves a user's performed workouts created in [from, to),
+// oldest first
+func (s *Store) ListBetween(userID string, from, to time.Time) ([]models.Workout, error) {
+	query := `
+		SELECT id, user_id, name, description, duration, type, planned, created_at, updated_at
+		FROM workouts
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3 AND NOT planned
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := s.db.Query(query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workouts: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.Workout
+	for rows.Next() {
+		var workout models.Workout
+		if err := rows.Scan(&workout.ID, &workout.UserID, &workout.Name, &workout.Description, &workout.Duration, &workout.Type, &workout.Planned, &workout.CreatedAt, &workout.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workout: %w", err)
+		}
+		items = append(items, workout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list workouts: %w", err)
+	}
+	return items, nil
+}
+
+// Sort orders for List
+const (
+	SortNewest  = "newest"
+	SortOldest  = "oldest"
+	SortLongest = "longest"
+)
+
+// ListFilter narrows and orders a workout listing. Planned lists the
+// workouts not yet performed instead of the performed ones.
+type ListFilter struct {
+	Planned bool
+	Type    *string
+	From *time.Time // inclusive
+	To   *time.Time // exclusive
+	Sort string
+}
+
+// List retrieves workouts for a user with cursor-based pagination. The cursor
+// type depends on the sort: TimeDescCursor for newest, TimeAscCursor for
+// oldest and ScoreDescCursor (score = duration) for longest. A legacy RFC3339
+// cursor is still accepted for the newest sort.
+func (s *Store) List(userID string, filter ListFilter, limit int, cursor string) (pagination.Paginated[models.Workout], error) {
+	if limit <= 0 {
+		return pagination.Paginated[models.Workout]{}, pagination.ErrInvalidLimit
 	}
 
+	query := `
+		SELECT id, user_id, name, description, duration, type, planned, created_at, updated_at
+		FROM workouts
+		WHERE user_id = $1 AND planned = $2
+	`
+	args := []interface{}{userID, filter.Planned}
+
+	if filter.Type != nil {
+		args = append(args, *filter.Type)
+		query += fmt.Sprintf(" AND type = $%d", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, filter.From.UTC())
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, filter.To.UTC())
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	var order string
+	switch filter.Sort {
+	case "", SortNewest:
+		order = "created_at DESC, id DESC"
+		if legacy, ok := ParseLegacyCursor(cursor); ok {
+			// Legacy cursors carry no ID, so keep their original strict comparison.
+			args = append(args, legacy)
+			query += fmt.Sprintf(" AND created_at < $%d", len(args))
+		} else if cursor != "" {
+			after, err := pagination.DecodeCursor[pagination.TimeDescCursor](cursor)
+			if err != nil {
+				return pagination.Paginated[models.Workout]{}, err
+			}
+			args = append(args, after.CreatedAt, after.ID)
+			query += fmt.Sprintf(" AND (created_at < $%d OR (created_at = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args))
+		}
+	case SortOldest:
+		order = "created_at ASC, id ASC"
+		if cursor != "" {
+			after, err := pagination.DecodeCursor[pagination.TimeAscCursor](cursor)
+			if err != nil {
+				return pagination.Paginated[models.Workout]{}, err
+			}
+			args = append(args, after.CreatedAt, after.ID)
+			query += fmt.Sprintf(" AND (created_at > $%d OR (created_at = $%d AND id > $%d))", len(args)-1, len(args)-1, len(args))
+		}
+	case SortLongest:
+		order = "duration DESC, id DESC"
+		if cursor != "" {
+			after, err := pagination.DecodeCursor[pagination.ScoreDescCursor](cursor)
+			if err != nil {
+				return pagination.Paginated[models.Workout]{}, err
+			}
+			args = append(args, int(after.Score), after.ID)
+			query += fmt.Sprintf(" AND (duration < $%d OR (duration = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args))
+		}
+	default:
+		return pagination.Paginated[models.Workout]{}, fmt.Errorf("unsupported workout sort %q", filter.Sort)
+	}
+
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", order, len(args))
+
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to query workouts: %w", err)
+		return pagination.Paginated[models.Workout]{}, fmt.Errorf("failed to query workouts: %w", err)
 	}
 	defer rows.Close()
 
 	var workouts []models.Workout
-	var nextCursor string
-
 	for rows.Next() {
 		var workout models.Workout
 		err := rows.Scan(
 			&workout.ID, &workout.UserID, &workout.Name, &workout.Description,
-			&workout.Duration, &workout.Type, &workout.CreatedAt, &workout.UpdatedAt,
+			&workout.Duration, &workout.Type, &workout.Planned, &workout.CreatedAt, &workout.UpdatedAt,
 		)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to scan workout: %w", err)
+			return pagination.Paginated[models.Workout]{}, fmt.Errorf("failed to scan workout: %w", err)
 		}
-
+		workout.CreatedAt = workout.CreatedAt.UTC()
 		workouts = append(workouts, workout)
 	}
+	if err := rows.Err(); err != nil {
+		return pagination.Paginated[models.Workout]{}, fmt.Errorf("workout rows error: %w", err)
+	}
 
-	// Check if there are more results
-	if len(workouts) > limit {
-		// Remove the extra item and set cursor for next page
-		nextCursor = workouts[limit-1].CreatedAt.Format(time.RFC3339Nano)
-		workouts = workouts[:limit]
+	switch filter.Sort {
+	case SortOldest:
+		return pagination.TimeAscPage(workouts, limit, func(item models.Workout) pagination.TimeAscCursor {
+			return pagination.TimeAscCursor{CreatedAt: item.CreatedAt, ID: item.ID}
+		})
+	case SortLongest:
+		return pagination.ScoreDescPage(workouts, limit, func(item models.Workout) pagination.ScoreDescCursor {
+			return pagination.ScoreDescCursor{Score: float64(item.Duration), ID: item.ID}
+		})
+	default:
+		return pagination.TimeDescPage(workouts, limit, func(item models.Workout) pagination.TimeDescCursor {
+			return pagination.TimeDescCursor{CreatedAt: item.CreatedAt, ID: item.ID}
+		})
 	}
+}
 
-	return workouts, nextCursor, nil
+// ParseLegacyCursor recognizes the raw RFC3339 created_at cursors returned
+// before workouts moved to opaque cursors. They are still accepted for the
+// newest sort while clients migrate.
+func ParseLegacyCursor(cursor string) (time.Time, bool) {
+	value, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(cursor))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return value.UTC(), true
 }
 
 // Update updates a workout
@@ -134,15 +367,18 @@ func (s *Store) Update(id, userID, name, description string, duration int, worko
 		UPDATE workouts 
 		SET name = $1, description = $2, duration = $3, type = $4, updated_at = $5
 		WHERE id = $6 AND user_id = $7
-		RETURNING id, user_id, name, description, duration, type, created_at, updated_at
+		RETURNING id, user_id, name, description, duration, type, planned, created_at, updated_at
 	`
 
 	workout := &models.Workout{}
 	err := s.db.QueryRow(query, name, description, duration, workoutType, time.Now(), id, userID).Scan(
 		&workout.ID, &workout.UserID, &workout.Name, &workout.Description,
-		&workout.Duration, &workout.Type, &workout.CreatedAt, &workout.UpdatedAt,
+		&workout.Duration, &workout.Type, &workout.Planned, &workout.CreatedAt, &workout.UpdatedAt,
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWorkoutNotFound
+		}
 		return nil, fmt.Errorf("failed to update workout: %w", err)
 	}
 
@@ -163,31 +399,154 @@ func (s *Store) Delete(id, userID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("workout not found")
+		return ErrWorkoutNotFound
+	}
+
+	return nil
+}
+
+// AttachExercises embeds a workout's exercises and computes its totals. The
+// planned duration wins; otherwise it is the time between the first and last
+// logged exercise. Warm-up sets are counted apart from sets, reps and volume.
+// A planned workout has not been performed, so its totals stay zero.
+func AttachExercises(workout *models.Workout, items []models.Exercise) {
+	if items == nil {
+		items = []models.Exercise{}
+	}
+	workout.Exercises = items
+
+	totals := &models.WorkoutTotals{
+		DurationMinutes: workout.Duration,
+		Exercises:       len(items),
+	}
+	if workout.Planned {
+		totals.DurationMinutes = 0
+	}
+
+	var first, last time.Time
+	for _, exercise := range items {
+		if first.IsZero() || exercise.CreatedAt.Before(first) {
+			first = exercise.CreatedAt
+		}
+		if exercise.CreatedAt.After(last) {
+			last = exercise.CreatedAt
+		}
+		for _, set := range exercise.Sets {
+			if set.DistanceMeters != nil {
+				totals.DistanceMeters += *set.DistanceMeters
+			}
+			if set.IsWarmup() {
+				totals.WarmupSets++
+				continue
+			}
+			totals.Sets++
+			totals.Reps += set.Reps
+			if set.WeightKg != nil {
+				totals.VolumeKg += float64(set.Reps) * *set.WeightKg
+			}
+		}
+	}
+	totals.VolumeKg = math.Round(totals.VolumeKg*100) / 100
+	totals.DistanceMeters = math.Round(totals.DistanceMeters*100) / 100
+
+	if totals.DurationMinutes == 0 && last.After(first) {
+		totals.DurationMinutes = int(math.Ceil(last.Sub(first).Minutes()))
+	}
+
+	workout.Totals = totals
+}
+
+// Stream calls fn for every performed workout created in [from, to), oldest first,
+// with totals aggregated from its sets. Warm-ups are counted separately and
+// excluded from the other totals. Volume is in kilograms. Returning an error
+// from fn stops the stream.
+func (s *Store) Stream(userID string, from, to *time.Time, fn func(models.WorkoutExportRow) error) error {
+	query := `
+		SELECT
+			w.id,
+			w.name,
+			COALESCE(w.description, ''),
+			w.type,
+			w.duration,
+			w.created_at,
+			COUNT(DISTINCT e.id),
+			COUNT(st.id) FILTER (WHERE st.set_type <> 'warmup'),
+			COUNT(st.id) FILTER (WHERE st.set_type = 'warmup'),
+			COALESCE(SUM(st.reps) FILTER (WHERE st.set_type <> 'warmup'), 0),
+			COALESCE(SUM(st.reps * st.weight_kg) FILTER (WHERE st.set_type <> 'warmup'), 0),
+			COALESCE(SUM(st.distance_meters), 0)
+		FROM workouts w
+		LEFT JOIN exercises e ON e.workout_id = w.id
+		LEFT JOIN sets st ON st.exercise_id = e.id
+		WHERE w.user_id = $1 AND NOT w.planned
+	`
+	args := []interface{}{userID}
+	if from != nil {
+		args = append(args, *from)
+		query += fmt.Sprintf(" AND w.created_at >= $%d", len(args))
+	}
+	if to != nil {
+		args = append(args, *to)
+		query += fmt.Sprintf(" AND w.created_at < $%d", len(args))
+	}
+	query += " GROUP BY w.id ORDER BY w.created_at ASC, w.id ASC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query workouts for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.WorkoutExportRow
+		if err := rows.Scan(
+			&row.ID, &row.Name, &row.Description, &row.Type, &row.DurationMinutes, &row.CreatedAt,
+			&row.Exercises, &row.Sets, &row.WarmupSets, &row.Reps, &row.Volume, &row.DistanceMeters,
+		); err != nil {
+			return fmt.Errorf("failed to scan workout for export: %w", err)
+		}
+		row.CreatedAt = row.CreatedAt.UTC()
+		row.Volume = math.Round(row.Volume*100) / 100
+		row.Unit = models.UnitKg
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("export rows error: %w", err)
 	}
 
 	return nil
 }
 
+// ExportByUser returns every workout of a user, newest first, without
+// exercises.
 func (s *Store) ExportByUser(userID string) ([]models.Workout, error) {
-	rows, err := s.db.Query(`SELECT id, user_id, name, description, duration, type, created_at, updated_at FROM workouts WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	rows, err := s.db.Query(`SELECT id, user_id, name, description, duration, type, planned, created_at, updated_at FROM workouts WHERE user_id = $1 ORDER BY created_at DESC`, userID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to export workouts: %w", err)
 	}
 	defer rows.Close()
 
 	var items []models.Workout
 	for rows.Next() {
 		var workout models.Workout
-		if err := rows.Scan(&workout.ID, &workout.UserID, &workout.Name, &workout.Description, &workout.Duration, &workout.Type, &workout.CreatedAt, &workout.UpdatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&workout.ID, &workout.UserID, &workout.Name, &workout.Description, &workout.Duration, &workout.Type, &workout.Planned, &workout.CreatedAt, &workout.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workout: %w", err)
 		}
 		items = append(items, workout)
 	}
-	return items, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to export workouts: %w", err)
+	}
+	return items, nil
 }
 
+// DeleteByUser removes every workout of a user.
 func (s *Store) DeleteByUser(userID string) error {
-	_, err := s.db.Exec(`DELETE FROM workouts WHERE user_id = $1`, userID)
-	return err
+	if _, err := s.db.Exec(`DELETE FROM workouts WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete workouts: %w", err)
+	}
+	return nil
 }
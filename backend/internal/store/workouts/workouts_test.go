@@ -0,0 +1,98 @@
+package workouts
+
+import (
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestAttachExercisesComputesTotals(t *testing.T) {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	heavy := 100.0
+	light := 20.0
+	workout := &models.Workout{ID: "workout-1"}
+
+	AttachExercises(workout, []models.Exercise{
+		{ID: "a", CreatedAt: start, Sets: []models.Set{{Reps: 5, WeightKg: &heavy}, {Reps: 5, WeightKg: &heavy}}},
+		{ID: "b", CreatedAt: start.Add(42*time.Minute + 10*time.Second), Sets: []models.Set{{Reps: 12, WeightKg: &light}, {Reps: 15}}},
+	})
+
+	totals := workout.Totals
+	if totals == nil {
+		t.Fatal("expected totals")
+	}
+	if totals.Exercises != 2 || totals.Sets != 4 || totals.Reps != 37 {
+		t.Fatalf("unexpected counts %+v", totals)
+	}
+	if totals.VolumeKg != 1240 {
+		t.Fatalf("expected volume 1240, got %v", totals.VolumeKg)
+	}
+	if totals.DurationMinutes != 43 {
+		t.Fatalf("expected duration from exercise span, got %d", totals.DurationMinutes)
+	}
+}
+
+func TestAttachExercisesSkipsWarmups(t *testing.T) {
+	bar := 20.0
+	working := 80.0
+	distance := 1500.0
+	workout := &models.Workout{}
+
+	AttachExercises(workout, []models.Exercise{
+		{ID: "a", Sets: []models.Set{
+			{Reps: 10, WeightKg: &bar, Type: models.SetTypeWarmup},
+			{Reps: 5, WeightKg: &working, Type: models.SetTypeWorking},
+			{Reps: 8, WeightKg: &working, Type: models.SetTypeAMRAP},
+		}},
+		{ID: "b", Sets: []models.Set{{DistanceMeters: &distance, Type: models.SetTypeWorking}}},
+	})
+
+	totals := workout.Totals
+	if totals.Sets != 3 || totals.WarmupSets != 1 || totals.Reps != 13 {
+		t.Fatalf("unexpected counts %+v", totals)
+	}
+	if totals.VolumeKg != 1040 {
+		t.Fatalf("expected warm-ups to be left out of volume, got %v", totals.VolumeKg)
+	}
+	if totals.DistanceMeters != 1500 {
+		t.Fatalf("expected distance 1500, got %v", totals.DistanceMeters)
+	}
+}
+
+func TestAttachExercisesPrefersPlannedDuration(t *testing.T) {
+	workout := &models.Workout{Duration: 60}
+
+	AttachExercises(workout, nil)
+
+	if workout.Exercises == nil || len(workout.Exercises) != 0 {
+		t.Fatalf("expected an empty exercise list, got %v", workout.Exercises)
+	}
+	if workout.Totals.DurationMinutes != 60 || workout.Totals.Sets != 0 {
+		t.Fatalf("unexpected totals %+v", workout.Totals)
+	}
+}
+
+func TestParseLegacyCursor(t *testing.T) {
+	value, ok := ParseLegacyCursor("2024-05-01T18:30:00.123456Z")
+	if !ok || !value.Equal(time.Date(2024, 5, 1, 18, 30, 0, 123456000, time.UTC)) {
+		t.Fatalf("expected legacy cursor to parse, got %v %v", value, ok)
+	}
+
+	if _, ok := ParseLegacyCursor("eyJjcmVhdGVkX2F0IjoiMjAyNC0wNS0wMVQxODozMDowMFoiLCJpZCI6IjEifQ=="); ok {
+		t.Fatal("expected opaque cursor not to be treated as legacy")
+	}
+	if _, ok := ParseLegacyCursor(""); ok {
+		t.Fatal("expected empty cursor not to be treated as legacy")
+	}
+}
+
+func TestAttachExercisesPlannedHasNoTotals(t *testing.T) {
+	workout := &models.Workout{Duration: 45, Planned: true}
+
+	AttachExercises(workout, nil)
+
+	if totals := workout.Totals; totals.DurationMinutes != 0 || totals.Exercises != 0 || totals.Sets != 0 {
+		t.Fatalf("expected a planned workout to have zero totals, got %+v", totals)
+	}
+}
@@ -0,0 +1,199 @@
+package strength
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"fitonex/backend/internal/models"
+)
+
+// brzyckiMaxReps is the rep count above which Epley is used instead of Brzycki.
+const brzyckiMaxReps = 10
+
+// Epley estimates a one-rep max using weight * (1 + reps/30).
+func Epley(weightKg float64, reps int) float64 {
+	if weightKg <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weightKg
+	}
+	return weightKg * (1 + float64(reps)/30)
+}
+
+// Brzycki estimates a one-rep max using weight * 36 / (37 - reps).
+func Brzycki(weightKg float64, reps int) float64 {
+	if weightKg <= 0 || reps <= 0 || reps >= 37 {
+		return 0
+	}
+	return weightKg * 36 / float64(37-reps)
+}
+
+// EstimatedOneRepMax uses Brzycki for low rep sets where it is more accurate
+// and falls back to Epley for higher rep ranges.
+func EstimatedOneRepMax(weightKg float64, reps int) float64 {
+	if reps <= brzyckiMaxReps {
+		return round2(Brzycki(weightKg, reps))
+	}
+	return round2(Epley(weightKg, reps))
+}
+
+// RecordKey identifies the lift a record belongs to: the machine when one is
+// set, otherwise the normalized exercise name.
+func RecordKey(machineID *string, name string) string {
+	if machineID != nil && *machineID != "" {
+		return "machine:" + *machineID
+	}
+	return "name:" + strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Detect compares an exercise's sets with the current bests for its record key
+// and returns the records it beats. Returned records have no ID or user set.
+func Detect(exercise models.Exercise, current []models.PersonalRecord) []models.PersonalRecord {
+	bests := make(map[string]models.PersonalRecord, len(current))
+	for _, record := range current {
+		key := bestKey(record.RecordType, record.WeightKg)
+		if existing, ok := bests[key]; !ok || record.Value > existing.Value {
+			bests[key] = record
+		}
+	}
+
+	var records []models.PersonalRecord
+	for _, candidate := range candidates(exercise) {
+		previous, ok := bests[bestKey(candidate.RecordType, candidate.WeightKg)]
+		if ok && candidate.Value <= previous.Value {
+			continue
+		}
+		if ok {
+			value := previous.Value
+			candidate.PreviousValue = &value
+		}
+		records = append(records, candidate)
+	}
+	return records
+}
+
+// Replay walks a lift's history in chronological order and returns every
+// record that was set along the way.
+func Replay(exercises []models.Exercise) []models.PersonalRecord {
+	ordered := make([]models.Exercise, len(exercises))
+	copy(ordered, exercises)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+
+	var history []models.PersonalRecord
+	for _, exercise := range ordered {
+		history = append(history, Detect(exercise, history)...)
+	}
+	return history
+}
+
+func candidates(exercise models.Exercise) []models.PersonalRecord {
+	var (
+		result     []models.PersonalRecord
+		heaviest   *models.PersonalRecord
+		bestE1RM   *models.PersonalRecord
+		repsByLoad = map[string]*models.PersonalRecord{}
+		loadOrder  []string
+		volume     float64
+	)
+
+	base := models.PersonalRecord{
+		RecordKey:    RecordKey(exercise.MachineID, exercise.Name),
+		MachineID:    exercise.MachineID,
+		ExerciseName: exercise.Name,
+		ExerciseID:   exercise.ID,
+		AchievedAt:   exercise.CreatedAt,
+	}
+
+	for _, set := range exercise.Sets {
+		// Warm-ups and timed or distance-only sets never set strength records.
+		if set.Reps <= 0 || set.IsWarmup() {
+			continue
+		}
+		setID := set.ID
+		reps := set.Reps
+
+		var weight *float64
+		if set.WeightKg != nil && *set.WeightKg > 0 {
+			value := round2(*set.WeightKg)
+			weight = &value
+		}
+
+		key := bestKey(models.RecordTypeMaxReps, weight)
+		if existing, ok := repsByLoad[key]; !ok || float64(reps) > existing.Value {
+			record := base
+			record.RecordType = models.RecordTypeMaxReps
+			record.Value = float64(reps)
+			record.WeightKg = weight
+			record.Reps = &reps
+			record.SetID = &setID
+			if !ok {
+				loadOrder = append(loadOrder, key)
+			}
+			repsByLoad[key] = &record
+		}
+
+		if weight == nil {
+			continue
+		}
+
+		volume += *weight * float64(reps)
+
+		if heaviest == nil || *weight > heaviest.Value {
+			record := base
+			record.RecordType = models.RecordTypeMaxWeight
+			record.Value = *weight
+			record.WeightKg = weight
+			record.Reps = &reps
+			record.SetID = &setID
+			heaviest = &record
+		}
+
+		if e1rm := EstimatedOneRepMax(*weight, reps); e1rm > 0 && (bestE1RM == nil || e1rm > bestE1RM.Value) {
+			record := base
+			record.RecordType = models.RecordTypeE1RM
+			record.Value = e1rm
+			record.WeightKg = weight
+			record.Reps = &reps
+			record.SetID = &setID
+			bestE1RM = &record
+		}
+	}
+
+	if heaviest != nil {
+		result = append(result, *heaviest)
+	}
+	if bestE1RM != nil {
+		result = append(result, *bestE1RM)
+	}
+	for _, key := range loadOrder {
+		result = append(result, *repsByLoad[key])
+	}
+	if volume > 0 {
+		record := base
+		record.RecordType = models.RecordTypeMaxVolume
+		record.Value = round2(volume)
+		result = append(result, record)
+	}
+	return result
+}
+
+// bestKey groups records that compete with each other. Rep records compete
+// only with records at the same load.
+func bestKey(recordType string, weightKg *float64) string {
+	if recordType != models.RecordTypeMaxReps {
+		return recordType
+	}
+	if weightKg == nil {
+		return recordType + "@bodyweight"
+	}
+	return fmt.Sprintf("%s@%.2f", recordType, *weightKg)
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
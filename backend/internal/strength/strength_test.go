@@ -0,0 +1,147 @@
+package strength
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"fitonex/backend/internal/models"
+)
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	cases := []struct {
+		weight float64
+		reps   int
+		want   float64
+	}{
+		{100, 1, 100},
+		{100, 5, 112.5},
+		{100, 10, 133.33},
+		{100, 12, 140},
+		{0, 5, 0},
+		{100, 0, 0},
+	}
+
+	for _, tc := range cases {
+		got := EstimatedOneRepMax(tc.weight, tc.reps)
+		if math.Abs(got-tc.want) > 0.01 {
+			t.Fatalf("EstimatedOneRepMax(%v, %d) = %v, want %v", tc.weight, tc.reps, got, tc.want)
+		}
+	}
+}
+
+func TestRecordKey(t *testing.T) {
+	machineID := "machine-1"
+	if key := RecordKey(&machineID, "Bench"); key != "machine:machine-1" {
+		t.Fatalf("unexpected machine key %q", key)
+	}
+	if key := RecordKey(nil, "  Bench   Press "); key != "name:bench press" {
+		t.Fatalf("unexpected name key %q", key)
+	}
+}
+
+func TestDetectFirstExerciseSetsAllRecords(t *testing.T) {
+	exercise := exerciseWithSets("ex-1", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		set("s1", 5, 100),
+		set("s2", 8, 90),
+	)
+
+	records := Detect(exercise, nil)
+
+	byType := recordsByType(records)
+	if byType[models.RecordTypeMaxWeight].Value != 100 {
+		t.Fatalf("expected max weight 100, got %+v", byType[models.RecordTypeMaxWeight])
+	}
+	if byType[models.RecordTypeE1RM].Value != 112.5 {
+		t.Fatalf("expected e1rm 112.5, got %+v", byType[models.RecordTypeE1RM])
+	}
+	if byType[models.RecordTypeMaxVolume].Value != 1220 {
+		t.Fatalf("expected volume 1220, got %+v", byType[models.RecordTypeMaxVolume])
+	}
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records (weight, e1rm, 2 rep records, volume), got %d", len(records))
+	}
+	for _, record := range records {
+		if record.PreviousValue != nil {
+			t.Fatalf("expected no previous value on first records, got %+v", record)
+		}
+	}
+}
+
+func TestDetectOnlyReturnsImprovements(t *testing.T) {
+	first := exerciseWithSets("ex-1", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), set("s1", 5, 100))
+	current := Detect(first, nil)
+
+	second := exerciseWithSets("ex-2", time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC),
+		set("s2", 6, 100),
+		set("s3", 2, 95),
+	)
+	records := Detect(second, current)
+
+	byType := recordsByType(records)
+	if _, ok := byType[models.RecordTypeMaxWeight]; ok {
+		t.Fatal("did not expect a max weight record for an equal weight")
+	}
+	reps, ok := byType[models.RecordTypeMaxReps]
+	if !ok || reps.Value != 6 || reps.PreviousValue == nil || *reps.PreviousValue != 5 {
+		t.Fatalf("expected rep record 6 over 5, got %+v", reps)
+	}
+	if _, ok := byType[models.RecordTypeE1RM]; !ok {
+		t.Fatal("expected e1rm record")
+	}
+	if _, ok := byType[models.RecordTypeMaxVolume]; !ok {
+		t.Fatal("expected volume record")
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d: %+v", len(records), records)
+	}
+}
+
+func TestDetectIgnoresWarmups(t *testing.T) {
+	warmup := set("s1", 3, 140)
+	warmup.Type = models.SetTypeWarmup
+	exercise := exerciseWithSets("ex-1", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), warmup, set("s2", 5, 100))
+
+	byType := recordsByType(Detect(exercise, nil))
+	if byType[models.RecordTypeMaxWeight].Value != 100 {
+		t.Fatalf("expected the warm-up to be ignored, got %+v", byType[models.RecordTypeMaxWeight])
+	}
+	if byType[models.RecordTypeMaxVolume].Value != 500 {
+		t.Fatalf("expected volume 500, got %+v", byType[models.RecordTypeMaxVolume])
+	}
+}
+
+func TestReplayIsChronological(t *testing.T) {
+	later := exerciseWithSets("ex-2", time.Date(2024, 5, 8, 10, 0, 0, 0, time.UTC), set("s2", 5, 110))
+	earlier := exerciseWithSets("ex-1", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), set("s1", 5, 100))
+
+	history := Replay([]models.Exercise{later, earlier})
+
+	var weights []float64
+	for _, record := range history {
+		if record.RecordType == models.RecordTypeMaxWeight {
+			weights = append(weights, record.Value)
+		}
+	}
+	if len(weights) != 2 || weights[0] != 100 || weights[1] != 110 {
+		t.Fatalf("expected weight records 100 then 110, got %v", weights)
+	}
+}
+
+func exerciseWithSets(id string, at time.Time, sets ...models.Set) models.Exercise {
+	return models.Exercise{ID: id, Name: "Bench Press", CreatedAt: at, Sets: sets}
+}
+
+func set(id string, reps int, weight float64) models.Set {
+	return models.Set{ID: id, Reps: reps, WeightKg: &weight}
+}
+
+func recordsByType(records []models.PersonalRecord) map[string]models.PersonalRecord {
+	result := map[string]models.PersonalRecord{}
+	for _, record := range records {
+		if existing, ok := result[record.RecordType]; !ok || record.Value > existing.Value {
+			result[record.RecordType] = record
+		}
+	}
+	return result
+}